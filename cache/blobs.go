@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -33,8 +34,9 @@ var ErrNoBlobs = errors.Errorf("no blobs for snapshot")
 // computeBlobChain ensures every ref in a parent chain has an associated blob in the content store. If
 // a blob is missing and createIfNeeded is true, then the blob will be created, otherwise ErrNoBlobs will
 // be returned. Caller must hold a lease when calling this function.
-// If forceCompression is specified but the blob of compressionType doesn't exist, this function creates it.
-func (sr *immutableRef) computeBlobChain(ctx context.Context, createIfNeeded bool, compressionType compression.Type, forceCompression bool, s session.Group) error {
+// If the blob needs to be converted to the compression type of comp but the converted blob doesn't exist,
+// this function creates it.
+func (sr *immutableRef) computeBlobChain(ctx context.Context, createIfNeeded bool, comp compression.Config, s session.Group) error {
 	if _, ok := leases.FromContext(ctx); !ok {
 		return errors.Errorf("missing lease requirement for computeBlobChain")
 	}
@@ -47,16 +49,16 @@ func (sr *immutableRef) computeBlobChain(ctx context.Context, createIfNeeded boo
 		ctx = winlayers.UseWindowsLayerMode(ctx)
 	}
 
-	return computeBlobChain(ctx, sr, createIfNeeded, compressionType, forceCompression, s)
+	return computeBlobChain(ctx, sr, createIfNeeded, comp, s)
 }
 
 type compressor func(dest io.Writer, requiredMediaType string) (io.WriteCloser, error)
 
-func computeBlobChain(ctx context.Context, sr *immutableRef, createIfNeeded bool, compressionType compression.Type, forceCompression bool, s session.Group) error {
+func computeBlobChain(ctx context.Context, sr *immutableRef, createIfNeeded bool, comp compression.Config, s session.Group) error {
 	eg, ctx := errgroup.WithContext(ctx)
 	if sr.parent != nil {
 		eg.Go(func() error {
-			return computeBlobChain(ctx, sr.parent, createIfNeeded, compressionType, forceCompression, s)
+			return computeBlobChain(ctx, sr.parent, createIfNeeded, comp, s)
 		})
 	}
 
@@ -72,19 +74,25 @@ func computeBlobChain(ctx context.Context, sr *immutableRef, createIfNeeded bool
 			var mediaType string
 			var compressorFunc compressor
 			var finalize func(context.Context, content.Store) (map[string]string, error)
-			switch compressionType {
+			switch comp.Type {
 			case compression.Uncompressed:
 				mediaType = ocispecs.MediaTypeImageLayer
 			case compression.Gzip:
+				if comp.Level != nil {
+					compressorFunc = gzipWriter(*comp.Level)
+				}
 				mediaType = ocispecs.MediaTypeImageLayerGzip
 			case compression.EStargz:
-				compressorFunc, finalize = compressEStargz()
+				compressorFunc, finalize = compressEStargz(comp.Level)
 				mediaType = ocispecs.MediaTypeImageLayerGzip
 			case compression.Zstd:
-				compressorFunc = zstdWriter
+				compressorFunc = zstdWriter(comp.Level)
+				mediaType = ocispecs.MediaTypeImageLayer + "+zstd"
+			case compression.ZstdChunked:
+				compressorFunc, finalize = compressZstdChunked(comp.Level)
 				mediaType = ocispecs.MediaTypeImageLayer + "+zstd"
 			default:
-				return nil, errors.Errorf("unknown layer compression type: %q", compressionType)
+				return nil, errors.Errorf("unknown layer compression type: %q", comp.Type)
 			}
 
 			var lower []mount.Mount
@@ -187,7 +195,7 @@ func computeBlobChain(ctx context.Context, sr *immutableRef, createIfNeeded bool
 				return nil, errors.Errorf("unknown layer compression type")
 			}
 
-			if err := sr.setBlob(ctx, compressionType, desc); err != nil {
+			if err := sr.setBlob(ctx, comp, desc); err != nil {
 				return nil, err
 			}
 
@@ -196,9 +204,9 @@ func computeBlobChain(ctx context.Context, sr *immutableRef, createIfNeeded bool
 		if err != nil {
 			return err
 		}
-		if forceCompression {
-			if err := ensureCompression(ctx, sr, compressionType, s); err != nil {
-				return errors.Wrapf(err, "failed to ensure compression type of %q", compressionType)
+		if comp.Force {
+			if err := ensureCompression(ctx, sr, comp, s); err != nil {
+				return errors.Wrapf(err, "failed to ensure compression type of %q", comp.Type)
 			}
 		}
 		return nil
@@ -213,7 +221,7 @@ func computeBlobChain(ctx context.Context, sr *immutableRef, createIfNeeded bool
 // setBlob associates a blob with the cache record.
 // A lease must be held for the blob when calling this function
 // Caller should call Info() for knowing what current values are actually set
-func (sr *immutableRef) setBlob(ctx context.Context, compressionType compression.Type, desc ocispecs.Descriptor) error {
+func (sr *immutableRef) setBlob(ctx context.Context, comp compression.Config, desc ocispecs.Descriptor) error {
 	if _, ok := leases.FromContext(ctx); !ok {
 		return errors.Errorf("missing lease requirement for setBlob")
 	}
@@ -226,7 +234,7 @@ func (sr *immutableRef) setBlob(ctx context.Context, compressionType compression
 		return err
 	}
 
-	if compressionType == compression.UnknownCompression {
+	if comp.Type == compression.UnknownCompression {
 		return errors.Errorf("unhandled layer media type: %q", desc.MediaType)
 	}
 
@@ -256,7 +264,7 @@ func (sr *immutableRef) setBlob(ctx context.Context, compressionType compression
 		return err
 	}
 
-	if err := sr.addCompressionBlob(ctx, desc, compressionType); err != nil {
+	if err := sr.addCompressionBlob(ctx, desc, comp); err != nil {
 		return err
 	}
 	return nil
@@ -305,16 +313,17 @@ func isTypeWindows(sr *immutableRef) bool {
 	return false
 }
 
-// ensureCompression ensures the specified ref has the blob of the specified compression Type.
-func ensureCompression(ctx context.Context, ref *immutableRef, compressionType compression.Type, s session.Group) error {
-	_, err := g.Do(ctx, fmt.Sprintf("%s-%d", ref.ID(), compressionType), func(ctx context.Context) (interface{}, error) {
+// ensureCompression ensures the specified ref has the blob of the specified compression Type
+// and level.
+func ensureCompression(ctx context.Context, ref *immutableRef, comp compression.Config, s session.Group) error {
+	_, err := g.Do(ctx, fmt.Sprintf("%s-%s", ref.ID(), compressionVariant(comp)), func(ctx context.Context) (interface{}, error) {
 		desc, err := ref.ociDesc(ctx, ref.descHandlers)
 		if err != nil {
 			return nil, err
 		}
 
 		// Resolve converters
-		layerConvertFunc, err := getConverter(ctx, ref.cm.ContentStore, desc, compressionType)
+		layerConvertFunc, err := getConverter(ctx, ref.cm.ContentStore, desc, comp)
 		if err != nil {
 			return nil, err
 		} else if layerConvertFunc == nil {
@@ -324,11 +333,11 @@ func ensureCompression(ctx context.Context, ref *immutableRef, compressionType c
 				// This ref can be used as the specified compressionType. Keep it lazy.
 				return nil, nil
 			}
-			return nil, ref.addCompressionBlob(ctx, desc, compressionType)
+			return nil, ref.addCompressionBlob(ctx, desc, comp)
 		}
 
 		// First, lookup local content store
-		if _, err := ref.getCompressionBlob(ctx, compressionType); err == nil {
+		if _, err := ref.getCompressionBlob(ctx, comp); err == nil {
 			return nil, nil // found the compression variant. no need to convert.
 		}
 
@@ -347,7 +356,7 @@ func ensureCompression(ctx context.Context, ref *immutableRef, compressionType c
 		}
 
 		// Start to track converted layer
-		if err := ref.addCompressionBlob(ctx, *newDesc, compressionType); err != nil {
+		if err := ref.addCompressionBlob(ctx, *newDesc, comp); err != nil {
 			return nil, errors.Wrapf(err, "failed to add compression blob")
 		}
 		return nil, nil
@@ -355,6 +364,17 @@ func ensureCompression(ctx context.Context, ref *immutableRef, compressionType c
 	return err
 }
 
-func zstdWriter(dest io.Writer, requiredMediaType string) (io.WriteCloser, error) {
-	return zstd.NewWriter(dest)
+func gzipWriter(level int) compressor {
+	return func(dest io.Writer, requiredMediaType string) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(dest, level)
+	}
+}
+
+func zstdWriter(level *int) compressor {
+	return func(dest io.Writer, requiredMediaType string) (io.WriteCloser, error) {
+		if level != nil {
+			return zstd.NewWriter(dest, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(*level)))
+		}
+		return zstd.NewWriter(dest)
+	}
 }
@@ -22,6 +22,8 @@ import (
 	"github.com/containerd/continuity/devices"
 	"github.com/containerd/continuity/fs"
 	"github.com/containerd/continuity/sysx"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/moby/buildkit/util/usernslayers"
 	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
@@ -51,7 +53,7 @@ func (sr *immutableRef) tryComputeOverlayBlob(ctx context.Context, lower, upper
 				return ctdcompression.CompressStream(dest, ctdcompression.Gzip)
 			}
 		case ocispecs.MediaTypeImageLayer + "+zstd":
-			compressorFunc = zstdWriter
+			compressorFunc = zstdWriter(nil)
 		default:
 			return emptyDesc, false, errors.Errorf("unsupported diff media type: %v", mediaType)
 		}
@@ -78,7 +80,7 @@ func (sr *immutableRef) tryComputeOverlayBlob(ctx context.Context, lower, upper
 		if err != nil {
 			return emptyDesc, false, errors.Wrap(err, "failed to get compressed stream")
 		}
-		err = writeOverlayUpperdir(ctx, io.MultiWriter(compressed, dgstr.Hash()), upperdir, lower)
+		err = writeOverlayUpperdir(ctx, io.MultiWriter(compressed, dgstr.Hash()), upperdir, lower, sr.cm.IdentityMapping())
 		compressed.Close()
 		if err != nil {
 			return emptyDesc, false, errors.Wrap(err, "failed to write compressed diff")
@@ -88,7 +90,7 @@ func (sr *immutableRef) tryComputeOverlayBlob(ctx context.Context, lower, upper
 		}
 		labels[containerdUncompressed] = dgstr.Digest().String()
 	} else {
-		if err = writeOverlayUpperdir(ctx, cw, upperdir, lower); err != nil {
+		if err = writeOverlayUpperdir(ctx, cw, upperdir, lower, sr.cm.IdentityMapping()); err != nil {
 			return emptyDesc, false, errors.Wrap(err, "failed to write diff")
 		}
 	}
@@ -214,7 +216,18 @@ func getOverlayLayers(m mount.Mount) ([]string, error) {
 
 // writeOverlayUpperdir writes a layer tar archive into the specified writer, based on
 // the diff information stored in the upperdir.
-func writeOverlayUpperdir(ctx context.Context, w io.Writer, upperdir string, lower []mount.Mount) error {
+func writeOverlayUpperdir(ctx context.Context, w io.Writer, upperdir string, lower []mount.Mount, idmap *idtools.IdentityMapping) (err error) {
+	if idmap != nil {
+		// layers are written with the ownership inside the user namespace
+		tw := usernslayers.NewWriter(w, idmap)
+		defer func() {
+			if err1 := tw.Close(); err == nil {
+				err = err1
+			}
+		}()
+		w = tw
+	}
+
 	emptyLower, err := ioutil.TempDir("", "buildkit") // empty directory used for the lower of diff view
 	if err != nil {
 		return errors.Wrapf(err, "failed to create temp dir")
@@ -12,7 +12,6 @@ import (
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/images/converter"
 	"github.com/containerd/containerd/labels"
-	"github.com/klauspost/compress/zstd"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/util/compression"
 	digest "github.com/opencontainers/go-digest"
@@ -20,6 +19,17 @@ import (
 	"github.com/pkg/errors"
 )
 
+// needsRecompression indicates whether the layer desc needs to be converted to
+// be compressed with comp. Layers that already use the compression type are
+// recompressed if a compression level is set, as the level they were
+// compressed with is not known.
+func needsRecompression(ctx context.Context, cs content.Store, desc ocispecs.Descriptor, comp compression.Config) (bool, error) {
+	if comp.Level != nil && comp.Type != compression.Uncompressed && images.IsLayerType(desc.MediaType) {
+		return true, nil
+	}
+	return needsConversion(ctx, cs, desc, comp.Type)
+}
+
 // needsConversion indicates whether a conversion is needed for the specified descriptor to
 // be the compressionType.
 func needsConversion(ctx context.Context, cs content.Store, desc ocispecs.Descriptor, compressionType compression.Type) (bool, error) {
@@ -38,6 +48,7 @@ func needsConversion(ctx context.Context, cs content.Store, desc ocispecs.Descri
 			return false, nil
 		}
 	case compression.Zstd:
+		// zstd:chunked blobs are valid zstd streams
 		if !images.IsLayerType(mediaType) || compression.FromMediaType(mediaType) == compression.Zstd {
 			return false, nil
 		}
@@ -49,6 +60,14 @@ func needsConversion(ctx context.Context, cs content.Store, desc ocispecs.Descri
 		if !images.IsLayerType(mediaType) || esgz {
 			return false, nil
 		}
+	case compression.ZstdChunked:
+		chunked, err := isZstdChunked(ctx, cs, desc.Digest)
+		if err != nil {
+			return false, err
+		}
+		if !images.IsLayerType(mediaType) || chunked {
+			return false, nil
+		}
 	default:
 		return false, fmt.Errorf("unknown compression type during conversion: %q", compressionType)
 	}
@@ -57,15 +76,18 @@ func needsConversion(ctx context.Context, cs content.Store, desc ocispecs.Descri
 
 // getConverter returns converter function according to the specified compression type.
 // If no conversion is needed, this returns nil without error.
-func getConverter(ctx context.Context, cs content.Store, desc ocispecs.Descriptor, compressionType compression.Type) (converter.ConvertFunc, error) {
-	if needs, err := needsConversion(ctx, cs, desc, compressionType); err != nil {
+func getConverter(ctx context.Context, cs content.Store, desc ocispecs.Descriptor, comp compression.Config) (converter.ConvertFunc, error) {
+	if needs, err := needsRecompression(ctx, cs, desc, comp); err != nil {
 		return nil, errors.Wrapf(err, "failed to determine conversion needs")
 	} else if !needs {
 		// No conversion. No need to return an error here.
 		return nil, nil
 	}
 
-	c := conversion{target: compressionType}
+	c, err := newConversion(comp)
+	if err != nil {
+		return nil, err
+	}
 
 	from := compression.FromMediaType(desc.MediaType)
 	switch from {
@@ -96,27 +118,44 @@ func getConverter(ctx context.Context, cs content.Store, desc ocispecs.Descripto
 		return nil, errors.Errorf("unsupported source compression type %q from mediatype %q", from, desc.MediaType)
 	}
 
-	switch compressionType {
+	return c.convert, nil
+}
+
+// newConversion returns a conversion that compresses to the compression type
+// and level of comp. The decompressor needs to be set by the caller if the
+// source is compressed.
+func newConversion(comp compression.Config) (*conversion, error) {
+	c := &conversion{target: comp.Type}
+	switch comp.Type {
 	case compression.Uncompressed:
 	case compression.Gzip:
 		c.compress = func(w io.Writer) (io.WriteCloser, error) {
+			if comp.Level != nil {
+				return gzip.NewWriterLevel(w, *comp.Level)
+			}
 			return gzip.NewWriter(w), nil
 		}
 	case compression.Zstd:
 		c.compress = func(w io.Writer) (io.WriteCloser, error) {
-			return zstd.NewWriter(w)
+			return zstdWriter(comp.Level)(w, comp.Type.DefaultMediaType())
 		}
 	case compression.EStargz:
-		compressorFunc, finalize := compressEStargz()
+		compressorFunc, finalize := compressEStargz(comp.Level)
+		c.compress = func(w io.Writer) (io.WriteCloser, error) {
+			return compressorFunc(w, comp.Type.DefaultMediaType())
+		}
+		c.finalize = finalize
+	case compression.ZstdChunked:
+		compressorFunc, finalize := compressZstdChunked(comp.Level)
 		c.compress = func(w io.Writer) (io.WriteCloser, error) {
-			return compressorFunc(w, ocispecs.MediaTypeImageLayerGzip)
+			return compressorFunc(w, comp.Type.DefaultMediaType())
 		}
 		c.finalize = finalize
 	default:
-		return nil, errors.Errorf("unknown target compression type during conversion: %q", compressionType)
+		return nil, errors.Errorf("unknown target compression type during conversion: %q", comp.Type)
 	}
 
-	return (&c).convert, nil
+	return c, nil
 }
 
 type conversion struct {
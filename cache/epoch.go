@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	cdcompression "github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/labels"
+	"github.com/containerd/stargz-snapshotter/estargz"
+	"github.com/containerd/stargz-snapshotter/estargz/zstdchunked"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/compression"
+	"github.com/moby/buildkit/util/contentutil"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// RewriteLayerTimestamps returns a remote whose layers don't contain files
+// with timestamps after epoch. Layers that contain such files are rewritten
+// with the same compression and written to cs, other layers are kept as is.
+func RewriteLayerTimestamps(ctx context.Context, cs content.Store, remote *solver.Remote, epoch time.Time) (*solver.Remote, error) {
+	var changed bool
+	mprovider := contentutil.NewMultiProvider(remote.Provider)
+	descs := make([]ocispecs.Descriptor, len(remote.Descriptors))
+	for i, desc := range remote.Descriptors {
+		newDesc, err := rewriteLayerTimestamps(ctx, cs, remote.Provider, desc, epoch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to rewrite timestamps of layer %s", desc.Digest)
+		}
+		if newDesc == nil {
+			descs[i] = desc
+			continue
+		}
+		descs[i] = *newDesc
+		mprovider.Add(newDesc.Digest, cs)
+		changed = true
+	}
+	if !changed {
+		return remote, nil
+	}
+	return &solver.Remote{Descriptors: descs, Provider: mprovider}, nil
+}
+
+// rewriteLayerTimestamps rewrites a single layer. It returns nil if the layer
+// doesn't need to be changed.
+func rewriteLayerTimestamps(ctx context.Context, cs content.Store, provider content.Provider, desc ocispecs.Descriptor, epoch time.Time) (*ocispecs.Descriptor, error) {
+	if !images.IsLayerType(desc.MediaType) {
+		return nil, nil
+	}
+	target := compression.FromMediaType(desc.MediaType)
+	var esgz bool
+	if _, ok := desc.Annotations[zstdchunked.ManifestChecksumAnnotation]; ok {
+		// zstd:chunked keeps the TOC in skippable frames that are ignored by
+		// the zstd decompressor
+		target = compression.ZstdChunked
+	} else if _, ok := desc.Annotations[estargz.TOCJSONDigestAnnotation]; ok {
+		target = compression.EStargz
+		esgz = true
+	}
+
+	ra, err := provider.ReaderAt(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer ra.Close()
+
+	open := func() (io.ReadCloser, error) {
+		sr := io.NewSectionReader(ra, 0, ra.Size())
+		if esgz {
+			return decompressEStargz(sr)
+		}
+		return cdcompression.DecompressStream(sr)
+	}
+
+	latest, err := layerLatestTimestamp(ctx, cs, desc.Digest, open)
+	if err != nil || !latest.After(epoch) {
+		return nil, err
+	}
+
+	c, err := newConversion(compression.New(target))
+	if err != nil {
+		return nil, err
+	}
+	diffID := digest.Canonical.Digester()
+	c.decompress = func(ctx context.Context, _ ocispecs.Descriptor) (io.ReadCloser, error) {
+		rc, err := open()
+		if err != nil {
+			return nil, err
+		}
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(rewriteTarTimestamps(rc, io.MultiWriter(pw, diffID.Hash()), epoch))
+			rc.Close()
+		}()
+		return pr, nil
+	}
+	newDesc, err := c.convert(ctx, cs, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the media type of the original layer so the manifest doesn't mix
+	// docker and oci types
+	newDesc.MediaType = desc.MediaType
+	if newDesc.Annotations == nil {
+		newDesc.Annotations = make(map[string]string)
+	}
+	newDesc.Annotations[labels.LabelUncompressed] = diffID.Digest().String()
+	if err := setLatestTimestampLabel(ctx, cs, newDesc.Digest, epoch); err != nil {
+		return nil, err
+	}
+	return newDesc, nil
+}
+
+const latestTimestampLabel = "buildkit.io/layer/latest-timestamp"
+
+// layerLatestTimestamp returns the latest timestamp of the files in the layer
+// blob dgst. The result is cached in the labels of the blob if it is in cs.
+func layerLatestTimestamp(ctx context.Context, cs content.Store, dgst digest.Digest, open func() (io.ReadCloser, error)) (time.Time, error) {
+	info, err := cs.Info(ctx, dgst)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return time.Time{}, err
+	}
+	if v, ok := info.Labels[latestTimestampLabel]; ok {
+		if ns, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(0, ns).UTC(), nil
+		}
+	}
+
+	rc, err := open()
+	if err != nil {
+		return time.Time{}, err
+	}
+	latest, err := latestTimestamp(rc)
+	rc.Close()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if info.Digest == "" {
+		// the blob is only in the provider of the remote
+		return latest, nil
+	}
+	if err := setLatestTimestampLabel(ctx, cs, dgst, latest); err != nil {
+		return time.Time{}, err
+	}
+	return latest, nil
+}
+
+func setLatestTimestampLabel(ctx context.Context, cs content.Store, dgst digest.Digest, tm time.Time) error {
+	_, err := cs.Update(ctx, content.Info{
+		Digest: dgst,
+		Labels: map[string]string{latestTimestampLabel: strconv.FormatInt(tm.UnixNano(), 10)},
+	}, "labels."+latestTimestampLabel)
+	return err
+}
+
+func latestTimestamp(r io.Reader) (time.Time, error) {
+	var latest time.Time
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return latest, nil
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, tm := range []time.Time{hdr.ModTime, hdr.AccessTime, hdr.ChangeTime} {
+			if tm.After(latest) {
+				latest = tm
+			}
+		}
+	}
+}
+
+// rewriteTarTimestamps copies the tar stream from r to w, clamping all
+// timestamps that are after epoch.
+func rewriteTarTimestamps(r io.Reader, w io.Writer, epoch time.Time) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		clampHeader(hdr, epoch)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func clampHeader(hdr *tar.Header, epoch time.Time) {
+	if hdr.ModTime.After(epoch) {
+		hdr.ModTime = epoch
+	}
+	if hdr.AccessTime.After(epoch) {
+		hdr.AccessTime = epoch
+	}
+	if hdr.ChangeTime.After(epoch) {
+		hdr.ChangeTime = epoch
+	}
+}
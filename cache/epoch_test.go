@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	ctdcompression "github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteLayerTimestamps(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+
+	tmpdir, err := ioutil.TempDir("", "rewrite-timestamps")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cs, err := local.NewLabeledStore(tmpdir, &memoryLabelStore{labels: map[digest.Digest]map[string]string{}})
+	require.NoError(t, err)
+
+	epoch := time.Unix(1600000000, 0).UTC()
+	old := time.Unix(1500000000, 0).UTC()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, tm := range map[string]time.Time{"old": old, "new": time.Now()} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(name)),
+			ModTime:  tm,
+			Format:   tar.FormatPAX,
+		}))
+		_, err := tw.Write([]byte(name))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	desc := ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageLayerGzip,
+		Digest:    digest.FromBytes(buf.Bytes()),
+		Size:      int64(buf.Len()),
+	}
+	require.NoError(t, content.WriteBlob(ctx, cs, "test-layer", bytes.NewReader(buf.Bytes()), desc))
+
+	remote := &solver.Remote{Descriptors: []ocispecs.Descriptor{desc}, Provider: cs}
+	res, err := RewriteLayerTimestamps(ctx, cs, remote, epoch)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Descriptors))
+
+	newDesc := res.Descriptors[0]
+	require.NotEqual(t, desc.Digest, newDesc.Digest)
+	require.Equal(t, desc.MediaType, newDesc.MediaType)
+
+	dt, err := content.ReadBlob(ctx, res.Provider, newDesc)
+	require.NoError(t, err)
+	r, err := ctdcompression.DecompressStream(bytes.NewReader(dt))
+	require.NoError(t, err)
+	uncompressed, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, digest.FromBytes(uncompressed).String(), newDesc.Annotations["containerd.io/uncompressed"])
+
+	times := map[string]time.Time{}
+	tr := tar.NewReader(bytes.NewReader(uncompressed))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		times[hdr.Name] = hdr.ModTime.UTC()
+	}
+	require.Equal(t, map[string]time.Time{"old": old, "new": epoch}, times)
+
+	// rewriting is deterministic
+	res2, err := RewriteLayerTimestamps(ctx, cs, remote, epoch)
+	require.NoError(t, err)
+	require.Equal(t, newDesc.Digest, res2.Descriptors[0].Digest)
+
+	// layers without newer timestamps are kept
+	res3, err := RewriteLayerTimestamps(ctx, cs, res, epoch)
+	require.NoError(t, err)
+	require.Equal(t, newDesc.Digest, res3.Descriptors[0].Digest)
+
+	// the latest timestamps of the layers are cached in their labels
+	info, err := cs.Info(ctx, newDesc.Digest)
+	require.NoError(t, err)
+	require.Equal(t, strconv.FormatInt(epoch.UnixNano(), 10), info.Labels[latestTimestampLabel])
+
+	info, err = cs.Info(ctx, desc.Digest)
+	require.NoError(t, err)
+	require.Contains(t, info.Labels, latestTimestampLabel)
+
+	// and the layer is not read again if the label is set
+	_, err = cs.Update(ctx, content.Info{
+		Digest: desc.Digest,
+		Labels: map[string]string{latestTimestampLabel: strconv.FormatInt(old.UnixNano(), 10)},
+	}, "labels."+latestTimestampLabel)
+	require.NoError(t, err)
+	res4, err := RewriteLayerTimestamps(ctx, cs, remote, epoch)
+	require.NoError(t, err)
+	require.Equal(t, desc.Digest, res4.Descriptors[0].Digest)
+}
+
+type memoryLabelStore struct {
+	mu     sync.Mutex
+	labels map[digest.Digest]map[string]string
+}
+
+func (s *memoryLabelStore) Get(dgst digest.Digest) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.labels[dgst], nil
+}
+
+func (s *memoryLabelStore) Set(dgst digest.Digest, labels map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labels[dgst] = labels
+	return nil
+}
+
+func (s *memoryLabelStore) Update(dgst digest.Digest, update map[string]string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	labels := map[string]string{}
+	for k, v := range s.labels[dgst] {
+		labels[k] = v
+	}
+	for k, v := range update {
+		if v == "" {
+			delete(labels, k)
+		} else {
+			labels[k] = v
+		}
+	}
+	s.labels[dgst] = labels
+	return labels, nil
+}
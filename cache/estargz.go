@@ -10,7 +10,10 @@ import (
 
 	cdcompression "github.com/containerd/containerd/archive/compression"
 	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/stargz-snapshotter/estargz"
+	"github.com/containerd/stargz-snapshotter/estargz/zstdchunked"
+	"github.com/klauspost/compress/zstd"
 	"github.com/moby/buildkit/util/compression"
 	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
@@ -19,15 +22,46 @@ import (
 
 var eStargzAnnotations = []string{estargz.TOCJSONDigestAnnotation, estargz.StoreUncompressedSizeAnnotation}
 
+var zstdChunkedAnnotations = []string{zstdchunked.ManifestChecksumAnnotation, zstdchunked.ManifestPositionAnnotation}
+
 // compressEStargz writes the passed blobs stream as an eStargz-compressed blob.
 // finalize function finalizes the written blob metadata and returns all eStargz annotations.
-func compressEStargz() (compressorFunc compressor, finalize func(context.Context, content.Store) (map[string]string, error)) {
+func compressEStargz(level *int) (compressorFunc compressor, finalize func(context.Context, content.Store) (map[string]string, error)) {
+	return compressWithTOC(compression.Gzip, func(w io.Writer) (*estargz.Writer, map[string]string) {
+		if level != nil {
+			return estargz.NewWriterLevel(w, *level), nil
+		}
+		return estargz.NewWriter(w), nil
+	})
+}
+
+// compressZstdChunked writes the passed blobs stream as a zstd:chunked blob.
+// finalize function finalizes the written blob metadata and returns the
+// annotations needed for lazy pulling.
+func compressZstdChunked(level *int) (compressorFunc compressor, finalize func(context.Context, content.Store) (map[string]string, error)) {
+	return compressWithTOC(compression.Zstd, func(w io.Writer) (*estargz.Writer, map[string]string) {
+		metadata := make(map[string]string)
+		c := &zstdchunked.Compressor{
+			CompressionLevel: zstd.SpeedDefault,
+			Metadata:         metadata,
+		}
+		if level != nil {
+			c.CompressionLevel = zstd.EncoderLevelFromZstd(*level)
+		}
+		return estargz.NewWriterWithCompressor(w, c), metadata
+	})
+}
+
+// compressWithTOC writes the passed blobs stream with the writer returned by
+// newWriter. The metadata map returned by newWriter is filled by the writer
+// when it is closed and returned with the annotations of finalize.
+func compressWithTOC(ct compression.Type, newWriter func(io.Writer) (*estargz.Writer, map[string]string)) (compressorFunc compressor, finalize func(context.Context, content.Store) (map[string]string, error)) {
 	var cInfo *compressionInfo
 	var writeErr error
 	var mu sync.Mutex
 	return func(dest io.Writer, requiredMediaType string) (io.WriteCloser, error) {
-			if compression.FromMediaType(requiredMediaType) != compression.Gzip {
-				return nil, fmt.Errorf("unsupported media type for estargz compressor %q", requiredMediaType)
+			if compression.FromMediaType(requiredMediaType) != ct {
+				return nil, fmt.Errorf("unsupported media type for %s compressor %q", ct, requiredMediaType)
 			}
 			done := make(chan struct{})
 			pr, pw := io.Pipe()
@@ -43,7 +77,7 @@ func compressEStargz() (compressorFunc compressor, finalize func(context.Context
 
 				blobInfoW, bInfoCh := calculateBlobInfo()
 				defer blobInfoW.Close()
-				w := estargz.NewWriter(io.MultiWriter(dest, blobInfoW))
+				w, metadata := newWriter(io.MultiWriter(dest, blobInfoW))
 
 				// Using lossless API here to make sure that decompressEStargz provides the exact
 				// same tar as the original.
@@ -66,7 +100,7 @@ func compressEStargz() (compressorFunc compressor, finalize func(context.Context
 				}
 				bInfo := <-bInfoCh
 				mu.Lock()
-				cInfo = &compressionInfo{bInfo, tocDgst}
+				cInfo = &compressionInfo{bInfo, tocDgst, metadata}
 				mu.Unlock()
 				pr.Close()
 				return nil
@@ -101,6 +135,9 @@ func compressEStargz() (compressorFunc compressor, finalize func(context.Context
 
 			// Fill annotations
 			a := make(map[string]string)
+			for k, v := range cInfo.metadata {
+				a[k] = v
+			}
 			a[estargz.TOCJSONDigestAnnotation] = cInfo.tocDigest.String()
 			a[estargz.StoreUncompressedSizeAnnotation] = fmt.Sprintf("%d", cInfo.uncompressedSize)
 			a[containerdUncompressed] = cInfo.uncompressedDigest.String()
@@ -169,6 +206,52 @@ func isEStargz(ctx context.Context, cs content.Store, dgst digest.Digest) (bool,
 	return res, nil
 }
 
+const zstdChunkedLabel = "buildkit.io/compression/zstd-chunked"
+
+// isZstdChunked returns true when the specified digest of content exists in
+// the content store and it's zstd:chunked.
+func isZstdChunked(ctx context.Context, cs content.Store, dgst digest.Digest) (bool, error) {
+	info, err := cs.Info(ctx, dgst)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if v, ok := info.Labels[zstdChunkedLabel]; ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+
+	r, err := cs.ReaderAt(ctx, ocispecs.Descriptor{Digest: dgst})
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	var res bool
+	decompressor := new(zstdchunked.Decompressor)
+	if r.Size() >= decompressor.FooterSize() {
+		footer := make([]byte, decompressor.FooterSize())
+		if _, err := r.ReadAt(footer, r.Size()-int64(len(footer))); err != nil {
+			return false, err
+		}
+		_, _, _, err = decompressor.ParseFooter(footer)
+		res = err == nil
+	}
+
+	if info.Labels == nil {
+		info.Labels = make(map[string]string)
+	}
+	info.Labels[zstdChunkedLabel] = strconv.FormatBool(res) // cache the result
+	if _, err := cs.Update(ctx, info, "labels."+zstdChunkedLabel); err != nil {
+		return false, err
+	}
+
+	return res, nil
+}
+
 func decompressEStargz(r *io.SectionReader) (io.ReadCloser, error) {
 	return estargz.Unpack(r, new(estargz.GzipDecompressor))
 }
@@ -209,6 +292,7 @@ func (c *counter) size() (n int64) {
 type compressionInfo struct {
 	blobInfo
 	tocDigest digest.Digest
+	metadata  map[string]string
 }
 
 type blobInfo struct {
@@ -42,6 +42,10 @@ type ManagerOpt struct {
 	Applier         diff.Applier
 	Differ          diff.Comparer
 	MetadataStore   *metadata.Store
+	// RemoteSnapshotter is set if the snapshotter can mount layers lazily
+	// from the registry, e.g. the stargz snapshotter. A snapshotter named
+	// "stargz" is always treated as a remote snapshotter.
+	RemoteSnapshotter bool
 }
 
 type Accessor interface {
@@ -96,6 +100,12 @@ func NewManager(opt ManagerOpt) (Manager, error) {
 	return cm, nil
 }
 
+// remoteSnapshots returns true if lazy refs may be mounted as remote
+// snapshots instead of being pulled and unpacked.
+func (cm *cacheManager) remoteSnapshots() bool {
+	return cm.RemoteSnapshotter || cm.Snapshotter.Name() == "stargz"
+}
+
 func (cm *cacheManager) GetByBlob(ctx context.Context, desc ocispecs.Descriptor, parent ImmutableRef, opts ...RefOption) (ir ImmutableRef, rerr error) {
 	diffID, err := diffIDFromDescriptor(desc)
 	if err != nil {
@@ -511,7 +521,7 @@ func (cm *cacheManager) New(ctx context.Context, s ImmutableRef, sess session.Gr
 		return nil, errors.Wrapf(err, "failed to add snapshot %s to lease", id)
 	}
 
-	if cm.Snapshotter.Name() == "stargz" && parent != nil {
+	if cm.remoteSnapshots() && parent != nil {
 		if rerr := parent.withRemoteSnapshotLabelsStargzMode(ctx, sess, func() {
 			err = cm.Snapshotter.Prepare(ctx, id, parentSnapshotID)
 		}); rerr != nil {
@@ -700,10 +710,11 @@ func (cm *cacheManager) prune(ctx context.Context, ch chan client.UsageInfo, opt
 			}
 
 			c := &client.UsageInfo{
-				ID:         cr.ID(),
-				Mutable:    cr.mutable,
-				RecordType: recordType,
-				Shared:     shared,
+				ID:           cr.ID(),
+				Mutable:      cr.mutable,
+				RecordType:   recordType,
+				Shared:       shared,
+				CacheMountID: cr.GetCacheMountID(),
 			}
 
 			usageCount, lastUsedAt := cr.getLastUsed()
@@ -795,14 +806,15 @@ func (cm *cacheManager) prune(ctx context.Context, ch chan client.UsageInfo, opt
 		usageCount, lastUsedAt := cr.getLastUsed()
 
 		c := client.UsageInfo{
-			ID:          cr.ID(),
-			Mutable:     cr.mutable,
-			InUse:       len(cr.refs) > 0,
-			Size:        cr.getSize(),
-			CreatedAt:   cr.GetCreatedAt(),
-			Description: cr.GetDescription(),
-			LastUsedAt:  lastUsedAt,
-			UsageCount:  usageCount,
+			ID:           cr.ID(),
+			Mutable:      cr.mutable,
+			InUse:        len(cr.refs) > 0,
+			Size:         cr.getSize(),
+			CreatedAt:    cr.GetCreatedAt(),
+			Description:  cr.GetDescription(),
+			LastUsedAt:   lastUsedAt,
+			UsageCount:   usageCount,
+			CacheMountID: cr.GetCacheMountID(),
 		}
 
 		if cr.parent != nil {
@@ -872,18 +884,19 @@ func (cm *cacheManager) markShared(m map[string]*cacheUsageInfo) error {
 }
 
 type cacheUsageInfo struct {
-	refs        int
-	parent      string
-	size        int64
-	mutable     bool
-	createdAt   time.Time
-	usageCount  int
-	lastUsedAt  *time.Time
-	description string
-	doubleRef   bool
-	recordType  client.UsageRecordType
-	shared      bool
-	parentChain []digest.Digest
+	refs         int
+	parent       string
+	size         int64
+	mutable      bool
+	createdAt    time.Time
+	usageCount   int
+	lastUsedAt   *time.Time
+	description  string
+	doubleRef    bool
+	recordType   client.UsageRecordType
+	shared       bool
+	parentChain  []digest.Digest
+	cacheMountID string
 }
 
 func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo) ([]*client.UsageInfo, error) {
@@ -907,16 +920,17 @@ func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo)
 
 		usageCount, lastUsedAt := cr.getLastUsed()
 		c := &cacheUsageInfo{
-			refs:        len(cr.refs),
-			mutable:     cr.mutable,
-			size:        cr.getSize(),
-			createdAt:   cr.GetCreatedAt(),
-			usageCount:  usageCount,
-			lastUsedAt:  lastUsedAt,
-			description: cr.GetDescription(),
-			doubleRef:   cr.equalImmutable != nil,
-			recordType:  cr.GetRecordType(),
-			parentChain: cr.parentChain(),
+			refs:         len(cr.refs),
+			mutable:      cr.mutable,
+			size:         cr.getSize(),
+			createdAt:    cr.GetCreatedAt(),
+			usageCount:   usageCount,
+			lastUsedAt:   lastUsedAt,
+			description:  cr.GetDescription(),
+			doubleRef:    cr.equalImmutable != nil,
+			recordType:   cr.GetRecordType(),
+			parentChain:  cr.parentChain(),
+			cacheMountID: cr.GetCacheMountID(),
 		}
 		if c.recordType == "" {
 			c.recordType = client.UsageRecordTypeRegular
@@ -957,17 +971,18 @@ func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo)
 	var du []*client.UsageInfo
 	for id, cr := range m {
 		c := &client.UsageInfo{
-			ID:          id,
-			Mutable:     cr.mutable,
-			InUse:       cr.refs > 0,
-			Size:        cr.size,
-			Parent:      cr.parent,
-			CreatedAt:   cr.createdAt,
-			Description: cr.description,
-			LastUsedAt:  cr.lastUsedAt,
-			UsageCount:  cr.usageCount,
-			RecordType:  cr.recordType,
-			Shared:      cr.shared,
+			ID:           id,
+			Mutable:      cr.mutable,
+			InUse:        cr.refs > 0,
+			Size:         cr.size,
+			Parent:       cr.parent,
+			CreatedAt:    cr.createdAt,
+			Description:  cr.description,
+			LastUsedAt:   cr.lastUsedAt,
+			UsageCount:   cr.usageCount,
+			RecordType:   cr.recordType,
+			Shared:       cr.shared,
+			CacheMountID: cr.cacheMountID,
 		}
 		if filter.Match(adaptUsageInfo(c)) {
 			du = append(du, c)
@@ -1110,6 +1125,13 @@ func adaptUsageInfo(info *client.UsageInfo) filters.Adaptor {
 
 		switch fieldpath[0] {
 		case "id":
+			// cache mounts are matched by the ID of the mount, the record ID
+			// is still available as recordid
+			if info.RecordType == client.UsageRecordTypeCacheMount && info.CacheMountID != "" {
+				return info.CacheMountID, true
+			}
+			return info.ID, info.ID != ""
+		case "recordid":
 			return info.ID, info.ID != ""
 		case "parent":
 			return info.Parent, info.Parent != ""
@@ -29,6 +29,7 @@ import (
 	"github.com/containerd/containerd/snapshots"
 	"github.com/containerd/containerd/snapshots/native"
 	"github.com/containerd/stargz-snapshotter/estargz"
+	"github.com/containerd/stargz-snapshotter/estargz/zstdchunked"
 	"github.com/klauspost/compress/zstd"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/client"
@@ -524,7 +525,7 @@ func TestExtractOnMutable(t *testing.T) {
 	if compressionType == compression.UnknownCompression {
 		t.Errorf("unhandled layer media type: %q", desc.MediaType)
 	}
-	err = snap.(*immutableRef).setBlob(leaseCtx, compressionType, desc)
+	err = snap.(*immutableRef).setBlob(leaseCtx, compression.New(compressionType), desc)
 	done(context.TODO())
 	require.NoError(t, err)
 	err = snap.(*immutableRef).setChains(leaseCtx)
@@ -636,7 +637,7 @@ func TestSetBlob(t *testing.T) {
 	err = content.WriteBlob(ctx, co.cs, "ref1", bytes.NewBuffer(b), desc)
 	require.NoError(t, err)
 
-	err = snap.(*immutableRef).setBlob(ctx, compression.UnknownCompression, ocispecs.Descriptor{
+	err = snap.(*immutableRef).setBlob(ctx, compression.New(compression.UnknownCompression), ocispecs.Descriptor{
 		Digest: digest.FromBytes([]byte("foobar")),
 		Annotations: map[string]string{
 			"containerd.io/uncompressed": digest.FromBytes([]byte("foobar2")).String(),
@@ -648,7 +649,7 @@ func TestSetBlob(t *testing.T) {
 	if compressionType == compression.UnknownCompression {
 		t.Errorf("unhandled layer media type: %q", desc.MediaType)
 	}
-	err = snap.(*immutableRef).setBlob(ctx, compressionType, desc)
+	err = snap.(*immutableRef).setBlob(ctx, compression.New(compressionType), desc)
 	require.NoError(t, err)
 	err = snap.(*immutableRef).setChains(ctx)
 	require.NoError(t, err)
@@ -678,7 +679,7 @@ func TestSetBlob(t *testing.T) {
 	if compressionType2 == compression.UnknownCompression {
 		t.Errorf("unhandled layer media type: %q", desc2.MediaType)
 	}
-	err = snap2.(*immutableRef).setBlob(ctx, compressionType2, desc2)
+	err = snap2.(*immutableRef).setBlob(ctx, compression.New(compressionType2), desc2)
 	require.NoError(t, err)
 	err = snap2.(*immutableRef).setChains(ctx)
 	require.NoError(t, err)
@@ -879,6 +880,67 @@ func TestPrune(t *testing.T) {
 	require.Equal(t, 0, len(dirs))
 }
 
+func TestPruneCacheMountID(t *testing.T) {
+	t.Parallel()
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:     snapshotter,
+		snapshotterName: "native",
+	})
+	require.NoError(t, err)
+
+	defer cleanup()
+	cm := co.manager
+
+	var ids []string
+	for _, mountID := range []string{"foo", "bar"} {
+		active, err := cm.New(ctx, nil, nil, CachePolicyRetain)
+		require.NoError(t, err)
+		require.NoError(t, active.SetRecordType(client.UsageRecordTypeCacheMount))
+		require.NoError(t, active.SetCacheMountID(mountID))
+		ids = append(ids, active.ID())
+		require.NoError(t, active.Release(ctx))
+	}
+
+	du, err := cm.DiskUsage(ctx, client.DiskUsageInfo{Filter: []string{"id==foo"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(du))
+	require.Equal(t, ids[0], du[0].ID)
+	require.Equal(t, "foo", du[0].CacheMountID)
+
+	du, err = cm.DiskUsage(ctx, client.DiskUsageInfo{Filter: []string{"id==" + ids[1]}})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(du))
+
+	du, err = cm.DiskUsage(ctx, client.DiskUsageInfo{Filter: []string{"recordid==" + ids[1]}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(du))
+	require.Equal(t, "bar", du[0].CacheMountID)
+
+	du, err = cm.DiskUsage(ctx, client.DiskUsageInfo{Filter: []string{"id!=bar"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(du))
+	require.Equal(t, ids[0], du[0].ID)
+
+	buf := pruneResultBuffer()
+	err = cm.Prune(ctx, buf.C, client.PruneInfo{Filter: []string{"type==exec.cachemount,id==foo"}})
+	buf.close()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(buf.all))
+	require.Equal(t, ids[0], buf.all[0].ID)
+	require.Equal(t, "foo", buf.all[0].CacheMountID)
+
+	checkDiskUsage(ctx, t, cm, 0, 1)
+}
+
 func TestLazyCommit(t *testing.T) {
 	t.Parallel()
 
@@ -1079,7 +1141,7 @@ func TestConversion(t *testing.T) {
 
 	// Tests all combination of the conversions from type i to type j preserve
 	// the uncompressed digest.
-	allCompression := []compression.Type{compression.Uncompressed, compression.Gzip, compression.EStargz, compression.Zstd}
+	allCompression := []compression.Type{compression.Uncompressed, compression.Gzip, compression.EStargz, compression.Zstd, compression.ZstdChunked}
 	eg, egctx := errgroup.WithContext(ctx)
 	for _, orgDesc := range []ocispecs.Descriptor{orgDescGo, orgDescSys} {
 		for _, i := range allCompression {
@@ -1089,7 +1151,7 @@ func TestConversion(t *testing.T) {
 					testName := fmt.Sprintf("%s=>%s", i, j)
 
 					// Prepare the source compression type
-					convertFunc, err := getConverter(egctx, store, orgDesc, i)
+					convertFunc, err := getConverter(egctx, store, orgDesc, compression.New(i))
 					require.NoError(t, err, testName)
 					srcDesc := &orgDesc
 					if convertFunc != nil {
@@ -1098,7 +1160,7 @@ func TestConversion(t *testing.T) {
 					}
 
 					// Convert the blob
-					convertFunc, err = getConverter(egctx, store, *srcDesc, j)
+					convertFunc, err = getConverter(egctx, store, *srcDesc, compression.New(j))
 					require.NoError(t, err, testName)
 					resDesc := srcDesc
 					if convertFunc != nil {
@@ -1107,7 +1169,7 @@ func TestConversion(t *testing.T) {
 					}
 
 					// Check the uncompressed digest is the same as the original
-					convertFunc, err = getConverter(egctx, store, *resDesc, compression.Uncompressed)
+					convertFunc, err = getConverter(egctx, store, *resDesc, compression.New(compression.Uncompressed))
 					require.NoError(t, err, testName)
 					recreatedDesc := resDesc
 					if convertFunc != nil {
@@ -1123,6 +1185,104 @@ func TestConversion(t *testing.T) {
 	require.NoError(t, eg.Wait())
 }
 
+func TestCompressionLevelVariant(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	cs, err := local.NewLabeledStore(t.TempDir(), &memoryLabelStore{labels: map[digest.Digest]map[string]string{}})
+	require.NoError(t, err)
+
+	dt, desc, err := mapToBlob(map[string]string{"foo": "bar"}, true)
+	require.NoError(t, err)
+	require.NoError(t, content.WriteBlob(ctx, cs, "layer", bytes.NewReader(dt), desc))
+
+	level := 9
+	comp := compression.Config{Type: compression.Gzip, Level: &level}
+	require.Equal(t, "gzip", compressionVariant(compression.New(compression.Gzip)))
+	require.Equal(t, "gzip.level9", compressionVariant(comp))
+
+	// the level of an existing blob is unknown so it is recompressed
+	needs, err := needsRecompression(ctx, cs, desc, compression.New(compression.Gzip))
+	require.NoError(t, err)
+	require.False(t, needs)
+	needs, err = needsRecompression(ctx, cs, desc, comp)
+	require.NoError(t, err)
+	require.True(t, needs)
+
+	convertFunc, err := getConverter(ctx, cs, desc, comp)
+	require.NoError(t, err)
+	require.NotNil(t, convertFunc)
+	newDesc, err := convertFunc(ctx, cs, desc)
+	require.NoError(t, err)
+	require.Equal(t, ocispecs.MediaTypeImageLayerGzip, newDesc.MediaType)
+	require.NotEqual(t, desc.Digest, newDesc.Digest)
+}
+
+func TestSetBlobCompressionLevel(t *testing.T) {
+	t.Parallel()
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(t.TempDir(), "snapshots"))
+	require.NoError(t, err)
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:     snapshotter,
+		snapshotterName: "native",
+	})
+	require.NoError(t, err)
+	defer cleanup()
+
+	active, err := co.manager.New(ctx, nil, nil)
+	require.NoError(t, err)
+	snap, err := active.Commit(ctx)
+	require.NoError(t, err)
+	defer snap.Release(context.TODO())
+
+	b, desc, err := mapToBlob(map[string]string{"foo": "bar"}, true)
+	require.NoError(t, err)
+	require.NoError(t, content.WriteBlob(ctx, co.cs, "ref1", bytes.NewBuffer(b), desc))
+
+	leaseCtx, done, err := leaseutil.WithLease(ctx, co.lm, leases.WithExpiration(0))
+	require.NoError(t, err)
+	defer done(context.TODO())
+
+	// the blob is recorded under the variant it is looked up by
+	level := 9
+	comp := compression.Config{Type: compression.Gzip, Level: &level}
+	require.NoError(t, snap.(*immutableRef).setBlob(leaseCtx, comp, desc))
+	bDesc, err := snap.(*immutableRef).getCompressionBlob(leaseCtx, comp)
+	require.NoError(t, err)
+	require.Equal(t, desc.Digest, bDesc.Digest)
+}
+
+func TestNeedsConversionZstdChunked(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	cs, err := local.NewLabeledStore(t.TempDir(), &memoryLabelStore{labels: map[digest.Digest]map[string]string{}})
+	require.NoError(t, err)
+
+	dt, desc, err := mapToBlob(map[string]string{"foo": "bar"}, false)
+	require.NoError(t, err)
+	require.NoError(t, content.WriteBlob(ctx, cs, "layer", bytes.NewReader(dt), desc))
+
+	convertFunc, err := getConverter(ctx, cs, desc, compression.New(compression.ZstdChunked))
+	require.NoError(t, err)
+	require.NotNil(t, convertFunc)
+	chunkedDesc, err := convertFunc(ctx, cs, desc)
+	require.NoError(t, err)
+
+	// zstd:chunked blobs are already valid zstd
+	needs, err := needsConversion(ctx, cs, *chunkedDesc, compression.Zstd)
+	require.NoError(t, err)
+	require.False(t, needs)
+	needs, err = needsConversion(ctx, cs, *chunkedDesc, compression.ZstdChunked)
+	require.NoError(t, err)
+	require.False(t, needs)
+	needs, err = needsConversion(ctx, cs, *chunkedDesc, compression.Gzip)
+	require.NoError(t, err)
+	require.True(t, needs)
+}
+
 func TestGetRemote(t *testing.T) {
 	t.Parallel()
 	// windows fails when lazy blob is being extracted with "invalid windows mount type: 'bind'"
@@ -1188,6 +1348,10 @@ func TestGetRemote(t *testing.T) {
 		zstdDigest, err := zstdBlobDigest(uncompressedBlobBytes)
 		require.NoError(t, err)
 		expectedContent[zstdDigest] = struct{}{}
+
+		zstdChunkedDigest, err := zstdChunkedBlobDigest(uncompressedBlobBytes)
+		require.NoError(t, err)
+		expectedContent[zstdChunkedDigest] = struct{}{}
 	}
 
 	// Create 3 levels of mutable refs, where each parent ref has 2 children (this tests parallel creation of
@@ -1230,6 +1394,10 @@ func TestGetRemote(t *testing.T) {
 				require.NoError(t, err)
 				expectedContent[zstdDigest] = struct{}{}
 
+				zstdChunkedDigest, err := zstdChunkedBlobDigest(uncompressedBlobBytes)
+				require.NoError(t, err)
+				expectedContent[zstdChunkedDigest] = struct{}{}
+
 				f.Close()
 				err = lm.Unmount()
 				require.NoError(t, err)
@@ -1253,10 +1421,10 @@ func TestGetRemote(t *testing.T) {
 	eg, egctx := errgroup.WithContext(ctx)
 	for _, ir := range refs {
 		ir := ir.(*immutableRef)
-		for _, compressionType := range []compression.Type{compression.Uncompressed, compression.Gzip, compression.EStargz, compression.Zstd} {
+		for _, compressionType := range []compression.Type{compression.Uncompressed, compression.Gzip, compression.EStargz, compression.Zstd, compression.ZstdChunked} {
 			compressionType := compressionType
 			eg.Go(func() error {
-				remote, err := ir.GetRemote(egctx, true, compressionType, true, nil)
+				remote, err := ir.GetRemote(egctx, true, compression.Config{Type: compressionType, Force: true}, nil)
 				require.NoError(t, err)
 				refChain := ir.parentRefChain()
 				for i, desc := range remote.Descriptors {
@@ -1267,7 +1435,7 @@ func TestGetRemote(t *testing.T) {
 						require.Equal(t, ocispecs.MediaTypeImageLayerGzip, desc.MediaType)
 					case compression.EStargz:
 						require.Equal(t, ocispecs.MediaTypeImageLayerGzip, desc.MediaType)
-					case compression.Zstd:
+					case compression.Zstd, compression.ZstdChunked:
 						require.Equal(t, ocispecs.MediaTypeImageLayer+"+zstd", desc.MediaType)
 					default:
 						require.Fail(t, "unhandled media type", compressionType)
@@ -1284,7 +1452,7 @@ func TestGetRemote(t *testing.T) {
 					if needs {
 						require.False(t, isLazy, "layer %q requires conversion so it must be unlazied", desc.Digest)
 					}
-					bDesc, err := r.getCompressionBlob(egctx, compressionType)
+					bDesc, err := r.getCompressionBlob(egctx, compression.New(compressionType))
 					if isLazy {
 						require.Error(t, err)
 					} else {
@@ -1347,7 +1515,13 @@ func checkDescriptor(ctx context.Context, t *testing.T, cs content.Store, desc o
 	uncompressedDgst, ok := desc.Annotations[containerdUncompressed]
 	require.True(t, ok, "uncompressed digest annotation not found: %q", desc.Digest)
 	var uncompressedSize int64
-	if compressionType == compression.EStargz {
+	if compressionType == compression.ZstdChunked {
+		for _, k := range zstdChunkedAnnotations {
+			_, ok := desc.Annotations[k]
+			require.True(t, ok, "%s annotation not found: %q", k, desc.Digest)
+		}
+	}
+	if compressionType == compression.EStargz || compressionType == compression.ZstdChunked {
 		_, ok := desc.Annotations[estargz.TOCJSONDigestAnnotation]
 		require.True(t, ok, "toc digest annotation not found: %q", desc.Digest)
 		uncompressedSizeS, ok := desc.Annotations[estargz.StoreUncompressedSizeAnnotation]
@@ -1372,7 +1546,7 @@ func checkDescriptor(ctx context.Context, t *testing.T, cs content.Store, desc o
 	_, err = io.Copy(io.MultiWriter(diffID.Hash(), c), decompressR)
 	require.NoError(t, err)
 	require.Equal(t, diffID.Digest().String(), uncompressedDgst)
-	if compressionType == compression.EStargz {
+	if compressionType == compression.EStargz || compressionType == compression.ZstdChunked {
 		require.Equal(t, c.size(), uncompressedSize)
 	}
 }
@@ -1419,6 +1593,18 @@ func zstdBlobDigest(uncompressedBlobBytes []byte) (digest.Digest, error) {
 	return digest.FromBytes(b.Bytes()), nil
 }
 
+func zstdChunkedBlobDigest(uncompressedBlobBytes []byte) (digest.Digest, error) {
+	dgstr := digest.Canonical.Digester()
+	w := estargz.NewWriterWithCompressor(dgstr.Hash(), &zstdchunked.Compressor{CompressionLevel: zstd.SpeedDefault})
+	if err := w.AppendTarLossLess(bytes.NewReader(uncompressedBlobBytes)); err != nil {
+		return "", err
+	}
+	if _, err := w.Close(); err != nil {
+		return "", err
+	}
+	return dgstr.Digest(), nil
+}
+
 func checkNumBlobs(ctx context.Context, t *testing.T, cs content.Store, expected int) {
 	c := 0
 	err := cs.Walk(ctx, func(_ content.Info) error {
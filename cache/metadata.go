@@ -22,6 +22,7 @@ const keyLastUsedAt = "cache.lastUsedAt"
 const keyUsageCount = "cache.usageCount"
 const keyLayerType = "cache.layerType"
 const keyRecordType = "cache.recordType"
+const keyCacheMountID = "cache.cacheMountID"
 const keyCommitted = "snapshot.committed"
 const keyParent = "cache.parent"
 const keyDiffID = "cache.diffID"
@@ -63,6 +64,11 @@ type RefMetadata interface {
 	GetRecordType() client.UsageRecordType
 	SetRecordType(client.UsageRecordType) error
 
+	// GetCacheMountID returns the ID of the cache mount the record
+	// belongs to, if any
+	GetCacheMountID() string
+	SetCacheMountID(string) error
+
 	GetEqualMutable() (RefMetadata, bool)
 
 	// generic getters/setters for external packages
@@ -171,6 +177,14 @@ func (md *cacheMetadata) SetRecordType(value client.UsageRecordType) error {
 	return md.setValue(keyRecordType, value, "")
 }
 
+func (md *cacheMetadata) GetCacheMountID() string {
+	return md.GetString(keyCacheMountID)
+}
+
+func (md *cacheMetadata) SetCacheMountID(id string) error {
+	return md.setValue(keyCacheMountID, id, "")
+}
+
 func (md *cacheMetadata) queueRecordType(value client.UsageRecordType) error {
 	return md.queueValue(keyRecordType, value, "")
 }
@@ -42,7 +42,7 @@ type ImmutableRef interface {
 	Clone() ImmutableRef
 
 	Extract(ctx context.Context, s session.Group) error // +progress
-	GetRemote(ctx context.Context, createIfNeeded bool, compressionType compression.Type, forceCompression bool, s session.Group) (*solver.Remote, error)
+	GetRemote(ctx context.Context, createIfNeeded bool, comp compression.Config, s session.Group) (*solver.Remote, error)
 }
 
 type MutableRef interface {
@@ -372,17 +372,27 @@ const (
 	compressionVariantMediaTypeLabel         = "buildkit.io/compression/mediatype"
 )
 
-func compressionVariantDigestLabel(compressionType compression.Type) string {
-	return compressionVariantDigestLabelPrefix + compressionType.String()
+// compressionVariant returns the name of the blob variant compressed with
+// comp. Blobs compressed with the default level of the compression type don't
+// have the level in their name.
+func compressionVariant(comp compression.Config) string {
+	if comp.Level == nil {
+		return comp.Type.String()
+	}
+	return fmt.Sprintf("%s.level%d", comp.Type, *comp.Level)
+}
+
+func compressionVariantDigestLabel(comp compression.Config) string {
+	return compressionVariantDigestLabelPrefix + compressionVariant(comp)
 }
 
-func (sr *immutableRef) getCompressionBlob(ctx context.Context, compressionType compression.Type) (ocispecs.Descriptor, error) {
+func (sr *immutableRef) getCompressionBlob(ctx context.Context, comp compression.Config) (ocispecs.Descriptor, error) {
 	cs := sr.cm.ContentStore
 	info, err := cs.Info(ctx, sr.getBlob())
 	if err != nil {
 		return ocispecs.Descriptor{}, err
 	}
-	dgstS, ok := info.Labels[compressionVariantDigestLabel(compressionType)]
+	dgstS, ok := info.Labels[compressionVariantDigestLabel(comp)]
 	if ok {
 		dgst, err := digest.Parse(dgstS)
 		if err != nil {
@@ -393,7 +403,7 @@ func (sr *immutableRef) getCompressionBlob(ctx context.Context, compressionType
 	return ocispecs.Descriptor{}, errdefs.ErrNotFound
 }
 
-func (sr *immutableRef) addCompressionBlob(ctx context.Context, desc ocispecs.Descriptor, compressionType compression.Type) error {
+func (sr *immutableRef) addCompressionBlob(ctx context.Context, desc ocispecs.Descriptor, comp compression.Config) error {
 	cs := sr.cm.ContentStore
 	if err := sr.cm.ManagerOpt.LeaseManager.AddResource(ctx, leases.Lease{ID: sr.ID()}, leases.Resource{
 		ID:   desc.Digest.String(),
@@ -408,7 +418,7 @@ func (sr *immutableRef) addCompressionBlob(ctx context.Context, desc ocispecs.De
 	if info.Labels == nil {
 		info.Labels = make(map[string]string)
 	}
-	cachedVariantLabel := compressionVariantDigestLabel(compressionType)
+	cachedVariantLabel := compressionVariantDigestLabel(comp)
 	info.Labels[cachedVariantLabel] = desc.Digest.String()
 	if _, err := cs.Update(ctx, info, "labels."+cachedVariantLabel); err != nil {
 		return err
@@ -439,7 +449,7 @@ func filterAnnotationsForSave(a map[string]string) (b map[string]string) {
 	if a == nil {
 		return nil
 	}
-	for _, k := range append(eStargzAnnotations, containerdUncompressed) {
+	for _, k := range append(append(eStargzAnnotations, zstdChunkedAnnotations...), containerdUncompressed) {
 		v, ok := a[k]
 		if !ok {
 			continue
@@ -501,7 +511,7 @@ func (sr *immutableRef) Mount(ctx context.Context, readonly bool, s session.Grou
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 
-	if sr.cm.Snapshotter.Name() == "stargz" {
+	if sr.cm.remoteSnapshots() {
 		var (
 			m    snapshot.Mountable
 			rerr error
@@ -532,7 +542,7 @@ func (sr *immutableRef) Extract(ctx context.Context, s session.Group) (rerr erro
 		ctx = winlayers.UseWindowsLayerMode(ctx)
 	}
 
-	if sr.cm.Snapshotter.Name() == "stargz" {
+	if sr.cm.remoteSnapshots() {
 		if err := sr.withRemoteSnapshotLabelsStargzMode(ctx, s, func() {
 			if rerr = sr.prepareRemoteSnapshotsStargzMode(ctx, s); rerr != nil {
 				return
@@ -647,6 +657,15 @@ func (sr *immutableRef) prepareRemoteSnapshotsStargzMode(ctx context.Context, s
 						continue
 					}
 				}
+				logrus.Debugf("failed to prepare remote snapshot for %s, falling back to pulling: %v", r.getBlob(), err)
+			} else {
+				// The snapshotter couldn't mount the layer remotely (e.g. the
+				// layer has no TOC or the registry doesn't support range
+				// requests) and created a local snapshot instead. Remove it;
+				// the layer is pulled and unpacked by extract.
+				if err := r.cm.Snapshotter.Remove(ctx, key); err != nil && !errdefs.IsNotFound(err) {
+					logrus.Warn(errors.Wrapf(err, "failed to remove tmp snapshot %s", key))
+				}
 			}
 
 			// This layer and all upper layers cannot be prepared without unlazying.
@@ -929,7 +948,7 @@ func (sr *mutableRef) Mount(ctx context.Context, readonly bool, s session.Group)
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 
-	if sr.cm.Snapshotter.Name() == "stargz" && sr.parent != nil {
+	if sr.cm.remoteSnapshots() && sr.parent != nil {
 		var (
 			m    snapshot.Mountable
 			rerr error
@@ -27,14 +27,14 @@ type Unlazier interface {
 
 // GetRemote gets a *solver.Remote from content store for this ref (potentially pulling lazily).
 // Note: Use WorkerRef.GetRemote instead as moby integration requires custom GetRemote implementation.
-func (sr *immutableRef) GetRemote(ctx context.Context, createIfNeeded bool, compressionType compression.Type, forceCompression bool, s session.Group) (*solver.Remote, error) {
+func (sr *immutableRef) GetRemote(ctx context.Context, createIfNeeded bool, comp compression.Config, s session.Group) (*solver.Remote, error) {
 	ctx, done, err := leaseutil.WithLease(ctx, sr.cm.LeaseManager, leaseutil.MakeTemporary)
 	if err != nil {
 		return nil, err
 	}
 	defer done(ctx)
 
-	err = sr.computeBlobChain(ctx, createIfNeeded, compressionType, forceCompression, s)
+	err = sr.computeBlobChain(ctx, createIfNeeded, comp, s)
 	if err != nil {
 		return nil, err
 	}
@@ -108,21 +108,24 @@ func (sr *immutableRef) GetRemote(ctx context.Context, createIfNeeded bool, comp
 			}
 		}
 
-		if forceCompression {
-			if needs, err := needsConversion(ctx, sr.cm.ContentStore, desc, compressionType); err != nil {
+		if comp.Force {
+			if needs, err := needsRecompression(ctx, sr.cm.ContentStore, desc, comp); err != nil {
 				return nil, err
 			} else if needs {
 				// ensure the compression type.
 				// compressed blob must be created and stored in the content store.
-				blobDesc, err := ref.getCompressionBlob(ctx, compressionType)
+				blobDesc, err := ref.getCompressionBlob(ctx, comp)
 				if err != nil {
-					return nil, errors.Wrapf(err, "compression blob for %q not found", compressionType)
+					return nil, errors.Wrapf(err, "compression blob for %q not found", comp.Type)
 				}
 				newDesc := desc
 				newDesc.MediaType = blobDesc.MediaType
 				newDesc.Digest = blobDesc.Digest
 				newDesc.Size = blobDesc.Size
 				newDesc.Annotations = nil
+				if len(addAnnotations) > 0 {
+					newDesc.Annotations = make(map[string]string)
+				}
 				for _, k := range addAnnotations {
 					newDesc.Annotations[k] = desc.Annotations[k]
 				}
@@ -235,7 +238,7 @@ func (p lazyRefProvider) Unlazy(ctx context.Context) error {
 			return nil, errors.Errorf("unhandled layer media type: %q", p.desc.MediaType)
 		}
 
-		if err := p.ref.addCompressionBlob(ctx, p.desc, compressionType); err != nil {
+		if err := p.ref.addCompressionBlob(ctx, p.desc, compression.New(compressionType)); err != nil {
 			return nil, err
 		}
 		return nil, nil
@@ -47,10 +47,20 @@ type Exporter interface {
 	Finalize(ctx context.Context) (map[string]string, error)
 }
 
+// CacheMountExporter is implemented by the exporters that can include the
+// contents of cache mounts in the exported cache.
+type CacheMountExporter interface {
+	AddCacheMount(id string, remote *solver.Remote)
+}
+
 const (
 	// ExportResponseManifestDesc is a key for the map returned from Exporter.Finalize.
 	// The map value is a JSON string of an OCI desciptor of a manifest.
 	ExporterResponseManifestDesc = "cache.manifest"
+
+	// AttrCacheMounts is the attribute of cache exporters and importers that
+	// enables exporting and importing the contents of cache mounts.
+	AttrCacheMounts = "cache-mounts"
 )
 
 type contentCacheExporter struct {
@@ -66,6 +76,10 @@ func NewExporter(ingester content.Ingester, ref string, oci bool) Exporter {
 	return &contentCacheExporter{CacheExporterTarget: cc, chains: cc, ingester: ingester, oci: oci, ref: ref}
 }
 
+func (ce *contentCacheExporter) AddCacheMount(id string, remote *solver.Remote) {
+	ce.chains.AddCacheMount(id, remote)
+}
+
 func (ce *contentCacheExporter) Finalize(ctx context.Context) (map[string]string, error) {
 	res := make(map[string]string)
 	config, descs, err := ce.chains.Marshal()
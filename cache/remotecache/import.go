@@ -28,6 +28,14 @@ type Importer interface {
 	Resolve(ctx context.Context, desc ocispecs.Descriptor, id string, w worker.Worker) (solver.CacheManager, error)
 }
 
+// CacheMountImporter is implemented by the importers that can seed cache
+// mounts with the contents included in the imported cache.
+type CacheMountImporter interface {
+	// ResolveCacheMounts returns the contents of the cache mounts by cache
+	// mount ID.
+	ResolveCacheMounts(ctx context.Context, desc ocispecs.Descriptor) (map[string]*solver.Remote, error)
+}
+
 type DistributionSourceLabelSetter interface {
 	SetDistributionSourceLabel(context.Context, digest.Digest) error
 	SetDistributionSourceAnnotation(desc ocispecs.Descriptor) ocispecs.Descriptor
@@ -42,14 +50,65 @@ type contentCacheImporter struct {
 }
 
 func (ci *contentCacheImporter) Resolve(ctx context.Context, desc ocispecs.Descriptor, id string, w worker.Worker) (solver.CacheManager, error) {
-	dt, err := readBlob(ctx, ci.provider, desc)
+	dt, configDesc, allLayers, err := ci.readManifest(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if configDesc.Digest == "" {
+		return ci.importInlineCache(ctx, dt, id, w)
+	}
+
+	dt, err = readBlob(ctx, ci.provider, configDesc)
 	if err != nil {
 		return nil, err
 	}
 
+	cc := v1.NewCacheChains()
+	if err := v1.Parse(dt, allLayers, cc); err != nil {
+		return nil, err
+	}
+
+	keysStorage, resultStorage, err := v1.NewCacheKeyStorage(cc, w)
+	if err != nil {
+		return nil, err
+	}
+	return solver.NewCacheManager(ctx, id, keysStorage, resultStorage), nil
+}
+
+func (ci *contentCacheImporter) ResolveCacheMounts(ctx context.Context, desc ocispecs.Descriptor) (map[string]*solver.Remote, error) {
+	_, configDesc, allLayers, err := ci.readManifest(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	if configDesc.Digest == "" {
+		// inline cache does not contain cache mounts
+		return nil, nil
+	}
+
+	dt, err := readBlob(ctx, ci.provider, configDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	var config v1.CacheConfig
+	if err := json.Unmarshal(dt, &config); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return v1.ParseCacheMounts(config, allLayers)
+}
+
+// readManifest reads the cache manifest desc and returns its contents, the
+// descriptor of the cache config and the layers in the manifest.
+func (ci *contentCacheImporter) readManifest(ctx context.Context, desc ocispecs.Descriptor) ([]byte, ocispecs.Descriptor, v1.DescriptorProvider, error) {
+	dt, err := readBlob(ctx, ci.provider, desc)
+	if err != nil {
+		return nil, ocispecs.Descriptor{}, nil, err
+	}
+
 	var mfst ocispecs.Index
 	if err := json.Unmarshal(dt, &mfst); err != nil {
-		return nil, err
+		return nil, ocispecs.Descriptor{}, nil, err
 	}
 
 	allLayers := v1.DescriptorProvider{}
@@ -76,25 +135,7 @@ func (ci *contentCacheImporter) Resolve(ctx context.Context, desc ocispecs.Descr
 		}
 	}
 
-	if configDesc.Digest == "" {
-		return ci.importInlineCache(ctx, dt, id, w)
-	}
-
-	dt, err = readBlob(ctx, ci.provider, configDesc)
-	if err != nil {
-		return nil, err
-	}
-
-	cc := v1.NewCacheChains()
-	if err := v1.Parse(dt, allLayers, cc); err != nil {
-		return nil, err
-	}
-
-	keysStorage, resultStorage, err := v1.NewCacheKeyStorage(cc, w)
-	if err != nil {
-		return nil, err
-	}
-	return solver.NewCacheManager(ctx, id, keysStorage, resultStorage), nil
+	return dt, configDesc, allLayers, nil
 }
 
 func readBlob(ctx context.Context, provider content.Provider, desc ocispecs.Descriptor) ([]byte, error) {
@@ -18,6 +18,19 @@ func NewCacheChains() *CacheChains {
 type CacheChains struct {
 	items   []*item
 	visited map[interface{}]struct{}
+
+	cacheMountsMu sync.Mutex
+	cacheMounts   map[string]*solver.Remote
+}
+
+// AddCacheMount adds the contents of the cache mount with id to the chains.
+func (c *CacheChains) AddCacheMount(id string, remote *solver.Remote) {
+	c.cacheMountsMu.Lock()
+	defer c.cacheMountsMu.Unlock()
+	if c.cacheMounts == nil {
+		c.cacheMounts = map[string]*solver.Remote{}
+	}
+	c.cacheMounts[id] = remote
 }
 
 func (c *CacheChains) Add(dgst digest.Digest) solver.CacheExporterRecord {
@@ -92,9 +105,24 @@ func (c *CacheChains) Marshal() (*CacheConfig, DescriptorProvider, error) {
 		}
 	}
 
+	var cacheMounts []CacheMount
+	c.cacheMountsMu.Lock()
+	for id, r := range c.cacheMounts {
+		chainID := marshalRemote(r, st)
+		if chainID == "" {
+			continue
+		}
+		cacheMounts = append(cacheMounts, CacheMount{
+			ID:         id,
+			LayerIndex: st.chainsByID[chainID],
+		})
+	}
+	c.cacheMountsMu.Unlock()
+
 	cc := CacheConfig{
-		Layers:  st.layers,
-		Records: st.records,
+		Layers:      st.layers,
+		Records:     st.records,
+		CacheMounts: cacheMounts,
 	}
 	sortConfig(&cc)
 
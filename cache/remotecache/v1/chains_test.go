@@ -94,3 +94,54 @@ func TestSimpleMarshal(t *testing.T) {
 func dgst(s string) digest.Digest {
 	return digest.FromBytes([]byte(s))
 }
+
+func TestMarshalCacheMounts(t *testing.T) {
+	cc := NewCacheChains()
+
+	foo := cc.Add(outputKey(dgst("foo"), 0))
+	foo.AddResult(time.Now(), &solver.Remote{
+		Descriptors: []ocispecs.Descriptor{{
+			Digest: dgst("d0"),
+		}},
+	})
+	cc.AddCacheMount("mycache", &solver.Remote{
+		Descriptors: []ocispecs.Descriptor{{
+			Digest: dgst("d1"),
+		}},
+	})
+	cc.AddCacheMount("other", &solver.Remote{
+		Descriptors: []ocispecs.Descriptor{{
+			Digest: dgst("d0"),
+		}},
+	})
+
+	cfg, descs, err := cc.Marshal()
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(cfg.Layers))
+	require.Equal(t, 2, len(cfg.CacheMounts))
+	require.Equal(t, "mycache", cfg.CacheMounts[0].ID)
+	require.Equal(t, dgst("d1"), cfg.Layers[cfg.CacheMounts[0].LayerIndex].Blob)
+	require.Equal(t, "other", cfg.CacheMounts[1].ID)
+	require.Equal(t, dgst("d0"), cfg.Layers[cfg.CacheMounts[1].LayerIndex].Blob)
+	require.Equal(t, cfg.Records[0].Results[0].LayerIndex, cfg.CacheMounts[1].LayerIndex)
+
+	dt, err := json.Marshal(cfg)
+	require.NoError(t, err)
+
+	var newCfg CacheConfig
+	err = json.Unmarshal(dt, &newCfg)
+	require.NoError(t, err)
+
+	remotes, err := ParseCacheMounts(newCfg, descs)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(remotes))
+	require.Equal(t, 1, len(remotes["mycache"].Descriptors))
+	require.Equal(t, dgst("d1"), remotes["mycache"].Descriptors[0].Digest)
+
+	delete(descs, dgst("d1"))
+	remotes, err = ParseCacheMounts(newCfg, descs)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(remotes))
+	require.Equal(t, dgst("d0"), remotes["other"].Descriptors[0].Digest)
+}
@@ -29,6 +29,25 @@ func ParseConfig(config CacheConfig, provider DescriptorProvider, t solver.Cache
 	return nil
 }
 
+// ParseCacheMounts returns the contents of the cache mounts in config by
+// cache mount ID. Cache mounts with blobs missing from provider are skipped.
+func ParseCacheMounts(config CacheConfig, provider DescriptorProvider) (map[string]*solver.Remote, error) {
+	m := map[string]*solver.Remote{}
+	for _, cm := range config.CacheMounts {
+		if cm.ID == "" {
+			return nil, errors.Errorf("invalid cache mount without ID")
+		}
+		remote, err := getRemoteChain(config.Layers, cm.LayerIndex, provider, map[int]struct{}{})
+		if err != nil {
+			return nil, err
+		}
+		if remote != nil {
+			m[cm.ID] = remote
+		}
+	}
+	return m, nil
+}
+
 func parseRecord(cc CacheConfig, idx int, provider DescriptorProvider, t solver.CacheExporterTarget, cache map[int]solver.CacheExporterRecord) (solver.CacheExporterRecord, error) {
 	if r, ok := cache[idx]; ok {
 		if r == nil {
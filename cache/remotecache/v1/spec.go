@@ -9,8 +9,9 @@ import (
 const CacheConfigMediaTypeV0 = "application/vnd.buildkit.cacheconfig.v0"
 
 type CacheConfig struct {
-	Layers  []CacheLayer  `json:"layers,omitempty"`
-	Records []CacheRecord `json:"records,omitempty"`
+	Layers      []CacheLayer  `json:"layers,omitempty"`
+	Records     []CacheRecord `json:"records,omitempty"`
+	CacheMounts []CacheMount  `json:"cacheMounts,omitempty"`
 }
 
 type CacheLayer struct {
@@ -41,3 +42,10 @@ type CacheInput struct {
 	Selector  string `json:"selector,omitempty"`
 	LinkIndex int    `json:"link"`
 }
+
+// CacheMount points to the layer chain holding the contents of the cache
+// mount with ID.
+type CacheMount struct {
+	ID         string `json:"id"`
+	LayerIndex int    `json:"layer"`
+}
@@ -111,6 +111,13 @@ func sortConfig(cc *CacheConfig) {
 		records[i] = r.r
 	}
 
+	for i := range cc.CacheMounts {
+		cc.CacheMounts[i].LayerIndex = unsortedLayers[cc.CacheMounts[i].LayerIndex].newIndex
+	}
+	sort.Slice(cc.CacheMounts, func(i, j int) bool {
+		return cc.CacheMounts[i].ID < cc.CacheMounts[j].ID
+	})
+
 	cc.Layers = layers
 	cc.Records = records
 }
@@ -0,0 +1,17 @@
+package client
+
+// WithCacheNamespace scopes disk usage and prune requests to the records of
+// a cache namespace.
+func WithCacheNamespace(ns string) CacheNamespace {
+	return CacheNamespace(ns)
+}
+
+type CacheNamespace string
+
+func (ns CacheNamespace) SetDiskUsageOption(di *DiskUsageInfo) {
+	di.CacheNamespace = string(ns)
+}
+
+func (ns CacheNamespace) SetPruneOption(pi *PruneInfo) {
+	pi.CacheNamespace = string(ns)
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// CancelVertex cancels the vertex with digest dgst in the solve with ref. The
+// vertex and the steps depending on it fail with an
+// errdefs.VertexCanceledError while the other steps of the solve, e.g. other
+// stages requested by a frontend, keep running.
+func (c *Client) CancelVertex(ctx context.Context, ref string, dgst digest.Digest) error {
+	_, err := c.controlClient().CancelVertex(ctx, &controlapi.CancelVertexRequest{
+		Ref:    ref,
+		Digest: dgst,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel vertex")
+	}
+	return nil
+}
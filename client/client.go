@@ -46,6 +46,8 @@ func New(ctx context.Context, address string, opts ...ClientOpt) (*Client, error
 	var unary []grpc.UnaryClientInterceptor
 	var stream []grpc.StreamClientInterceptor
 
+	var bearerToken *withBearerToken
+
 	var customTracer bool // allows manually setting disabling tracing even if tracer in context
 	var tracerProvider trace.TracerProvider
 	var tracerDelegate TracerDelegate
@@ -73,6 +75,9 @@ func New(ctx context.Context, address string, opts ...ClientOpt) (*Client, error
 		if wt, ok := o.(*withTracerDelegate); ok {
 			tracerDelegate = wt
 		}
+		if wb, ok := o.(*withBearerToken); ok {
+			bearerToken = wb
+		}
 	}
 
 	if !customTracer {
@@ -100,6 +105,15 @@ func New(ctx context.Context, address string, opts ...ClientOpt) (*Client, error
 	if address == "" {
 		address = appdefaults.Address
 	}
+	if bearerToken != nil {
+		var creds credentials.PerRPCCredentials = bearerToken
+		// the token may be sent without TLS only if the connection doesn't
+		// leave the host
+		if needDialer && isLocalAddress(address) {
+			creds = &localBearerToken{bearerToken}
+		}
+		gopts = append(gopts, grpc.WithPerRPCCredentials(creds))
+	}
 
 	// grpc-go uses a slightly different naming scheme: https://github.com/grpc/grpc/blob/master/doc/naming.md
 	// This will end up setting rfc non-complient :authority header to address string (e.g. tcp://127.0.0.1:1234).
@@ -222,6 +236,46 @@ func loadCredentials(opts *withCredentials) (grpc.DialOption, error) {
 	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
 }
 
+type withBearerToken struct {
+	token string
+}
+
+// WithBearerToken sends token in the authorization metadata of every request
+// so that buildkitd can map the client to an identity.
+func WithBearerToken(token string) ClientOpt {
+	return &withBearerToken{token: token}
+}
+
+func (w *withBearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + w.token}, nil
+}
+
+func (w *withBearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// localBearerToken sends the bearer token over connections that are not
+// using TLS, like unix sockets and connection helpers.
+type localBearerToken struct {
+	*withBearerToken
+}
+
+func (w *localBearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// isLocalAddress returns if the connection to address doesn't leave the host.
+func isLocalAddress(address string) bool {
+	if ch, err := connhelper.GetConnectionHelper(address); err == nil && ch != nil {
+		return true
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "unix" || u.Scheme == "npipe"
+}
+
 func WithTracerProvider(t trace.TracerProvider) ClientOpt {
 	return &withTracer{t}
 }
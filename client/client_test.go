@@ -4,9 +4,12 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -38,12 +41,15 @@ import (
 	"github.com/moby/buildkit/session/sshforward/sshprovider"
 	"github.com/moby/buildkit/solver/errdefs"
 	"github.com/moby/buildkit/solver/pb"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
 	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/cosign"
 	"github.com/moby/buildkit/util/entitlements"
 	"github.com/moby/buildkit/util/testutil"
 	"github.com/moby/buildkit/util/testutil/echoserver"
 	"github.com/moby/buildkit/util/testutil/httpserver"
 	"github.com/moby/buildkit/util/testutil/integration"
+	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -95,6 +101,7 @@ func TestIntegration(t *testing.T) {
 		testReadonlyRootFS,
 		testBasicRegistryCacheImportExport,
 		testBasicLocalCacheImportExport,
+		testLocalCacheMountImportExport,
 		testCachedMounts,
 		testCopyFromEmptyImage,
 		testProxyEnv,
@@ -128,11 +135,23 @@ func TestIntegration(t *testing.T) {
 		testSourceMapFromRef,
 		testLazyImagePush,
 		testStargzLazyPull,
+		testZstdChunkedLazyPull,
 		testFileOpInputSwap,
 		testRelativeMountpoint,
 		testLocalSourceDiffer,
 		testBuildExportZstd,
 		testPullZstdImage,
+		testSourcePolicy,
+		testExportedOwnership,
+		testCacheNamespaces,
+		testSourceDateEpoch,
+		testExportAnnotations,
+		testPushSignature,
+		testPushMultipleNames,
+		testCancelVertex,
+		testSolveTimeout,
+		testFrontendWarnings,
+		testBuildHistory,
 	}, mirrors)
 
 	integration.Run(t, []integration.Test{
@@ -290,6 +309,169 @@ func testExportBusyboxLocal(t *testing.T, sb integration.Sandbox) {
 	require.True(t, os.SameFile(fi, fi2))
 }
 
+func testSourcePolicy(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Scratch().File(llb.Copy(llb.Image("busybox:latest"), "/etc/alpine-release", "/alpine-release"))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	t.Run("deny", func(t *testing.T) {
+		_, err := c.Solve(sb.Context(), def, SolveOpt{
+			SourcePolicy: &spb.Policy{
+				Rules: []*spb.Rule{
+					{
+						Action: spb.PolicyAction_DENY,
+						Selector: &spb.Selector{
+							Identifier: "docker-image://docker.io/library/busybox:*",
+						},
+					},
+				},
+			},
+		}, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "denied by policy")
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		destDir, err := ioutil.TempDir("", "buildkit")
+		require.NoError(t, err)
+		defer os.RemoveAll(destDir)
+
+		_, err = c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:      ExporterLocal,
+					OutputDir: destDir,
+				},
+			},
+			SourcePolicy: &spb.Policy{
+				Rules: []*spb.Rule{
+					{
+						Action: spb.PolicyAction_CONVERT,
+						Selector: &spb.Selector{
+							Identifier: "docker-image://docker.io/library/busybox:latest",
+							MatchType:  spb.MatchType_EXACT,
+						},
+						Updates: &spb.Update{
+							Identifier: "docker-image://docker.io/library/alpine:latest",
+						},
+					},
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(destDir, "alpine-release"))
+		require.NoError(t, err)
+	})
+
+	t.Run("frontend", func(t *testing.T) {
+		frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+			st := llb.Image("busybox:latest")
+			def, err := st.Marshal(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.Solve(ctx, gateway.SolveRequest{
+				Definition: def.ToPB(),
+			})
+		}
+
+		_, err := c.Build(sb.Context(), SolveOpt{
+			SourcePolicy: &spb.Policy{
+				Rules: []*spb.Rule{
+					{
+						Action: spb.PolicyAction_DENY,
+						Selector: &spb.Selector{
+							Identifier: "docker-image://*",
+						},
+					},
+				},
+			},
+		}, "", frontend, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "denied by policy")
+	})
+
+	t.Run("resolve image config", func(t *testing.T) {
+		// the short name is normalized before it is matched
+		frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+			_, _, err := c.ResolveImageConfig(ctx, "busybox", llb.ResolveImageConfigOpt{})
+			return nil, err
+		}
+
+		_, err := c.Build(sb.Context(), SolveOpt{
+			SourcePolicy: &spb.Policy{
+				Rules: []*spb.Rule{
+					{
+						Action: spb.PolicyAction_DENY,
+						Selector: &spb.Selector{
+							Identifier: "docker-image://docker.io/library/busybox:*",
+						},
+					},
+				},
+			},
+		}, "", frontend, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "denied by policy")
+	})
+}
+
+func testBuildHistory(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Image("busybox:latest").Run(llb.Shlex(`sh -c "echo foo > /foo # ` + identity.NewID() + `"`))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	ref := identity.NewID()
+	_, err = c.Solve(sb.Context(), def, SolveOpt{Ref: ref}, nil)
+	require.NoError(t, err)
+
+	// the record is saved after the progress of the build has ended
+	var records []*BuildHistoryRecord
+	for i := 0; i < 20; i++ {
+		records, err = c.BuildHistory(sb.Context(), ref)
+		require.NoError(t, err)
+		if len(records) > 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.Equal(t, 1, len(records))
+
+	rec := records[0]
+	require.Equal(t, ref, rec.Ref)
+	require.Equal(t, "", rec.Error)
+	require.NotNil(t, rec.CreatedAt)
+	require.NotNil(t, rec.CompletedAt)
+
+	var execVertex *Vertex
+	for _, v := range rec.Vertexes {
+		if strings.Contains(v.Name, "echo foo") {
+			execVertex = v
+		}
+	}
+	require.NotNil(t, execVertex)
+	require.NotNil(t, execVertex.Completed)
+
+	records, err = c.BuildHistory(sb.Context(), "")
+	require.NoError(t, err)
+	var found bool
+	for _, r := range records {
+		if r.Ref == ref {
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
 func testHostnameLookup(t *testing.T, sb integration.Sandbox) {
 	if sb.Rootless() {
 		t.SkipNow()
@@ -601,6 +783,76 @@ func testPushByDigest(t *testing.T, sb integration.Sandbox) {
 	require.True(t, desc.Size > 0)
 }
 
+func testPushMultipleNames(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	registry, err := sb.NewRegistry()
+	if errors.Is(err, integration.ErrorRequirements) {
+		t.Skip(err.Error())
+	}
+	require.NoError(t, err)
+
+	solve := func(data, names string) (*SolveResponse, error) {
+		def, err := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte(data))).Marshal(sb.Context())
+		require.NoError(t, err)
+		return c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type: "image",
+					Attrs: map[string]string{
+						"name":          names,
+						"push":          "true",
+						"push-retries":  "0",
+						"push-rollback": "true",
+					},
+				},
+			},
+		}, nil)
+	}
+
+	foo := registry + "/buildkit/foo:latest"
+	bar := registry + "/buildkit/bar:latest"
+
+	resp, err := solve("first", foo)
+	require.NoError(t, err)
+	first := resp.ExporterResponse[exptypes.ExporterImageDigestKey]
+
+	// a failing destination must not update any of the tags
+	_, err = solve("second", foo+",127.0.0.1:1/buildkit/unreachable:latest")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no tags were updated")
+
+	desc, _, err := contentutil.ProviderFromRef(foo)
+	require.NoError(t, err)
+	require.Equal(t, first, desc.Digest.String())
+
+	resp, err = solve("second", foo+","+bar)
+	require.NoError(t, err)
+	second := resp.ExporterResponse[exptypes.ExporterImageDigestKey]
+	require.NotEqual(t, first, second)
+
+	var results []exptypes.PushResult
+	require.NoError(t, json.Unmarshal([]byte(resp.ExporterResponse[exptypes.ExporterImagePushKey]), &results))
+	require.Equal(t, 2, len(results))
+	require.Equal(t, foo, results[0].Name)
+	require.Equal(t, exptypes.PushStatusPushed, results[0].Status)
+	require.Equal(t, second, results[0].Digest.String())
+	require.NotNil(t, results[0].Previous)
+	require.Equal(t, first, results[0].Previous.Digest.String())
+	require.Equal(t, bar, results[1].Name)
+	require.Nil(t, results[1].Previous)
+
+	for _, name := range []string{foo, bar} {
+		desc, _, err := contentutil.ProviderFromRef(name)
+		require.NoError(t, err)
+		require.Equal(t, second, desc.Digest.String())
+	}
+}
+
 func testSecurityMode(t *testing.T, sb integration.Sandbox) {
 	var command string
 	mode := llb.SecurityModeSandbox
@@ -1837,6 +2089,190 @@ func testFrontendUseSolveResults(t *testing.T, sb integration.Sandbox) {
 	require.Equal(t, dt, []byte("data"))
 }
 
+func testCancelVertex(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	ref := identity.NewID()
+	id := identity.NewID()
+	busybox := llb.Image("busybox:latest")
+
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		def, err := busybox.Run(llb.Shlex(`sh -c "sleep 60 # ` + id + `"`)).Marshal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		_, err = c.Solve(ctx, gateway.SolveRequest{
+			Definition: def.ToPB(),
+			Evaluate:   true,
+		})
+		var cerr *errdefs.VertexCanceledError
+		if !errors.As(err, &cerr) {
+			return nil, errors.Errorf("expected vertex canceled error, got %+v", err)
+		}
+
+		// the rest of the build keeps running
+		def, err = llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("data"))).Marshal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return c.Solve(ctx, gateway.SolveRequest{
+			Definition: def.ToPB(),
+		})
+	}
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	status := make(chan *SolveStatus)
+	eg, ctx := errgroup.WithContext(sb.Context())
+	eg.Go(func() error {
+		canceled := false
+		for st := range status {
+			for _, v := range st.Vertexes {
+				if !canceled && v.Started != nil && strings.Contains(v.Name, id) {
+					if err := c.CancelVertex(ctx, ref, v.Digest); err != nil {
+						return err
+					}
+					canceled = true
+				}
+			}
+		}
+		if !canceled {
+			return errors.New("vertex was not started")
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		_, err := c.Build(ctx, SolveOpt{
+			Ref: ref,
+			Exports: []ExportEntry{
+				{
+					Type:      ExporterLocal,
+					OutputDir: destDir,
+				},
+			},
+		}, "", frontend, status)
+		return err
+	})
+	require.NoError(t, eg.Wait())
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), dt)
+}
+
+func testSolveTimeout(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+
+	// timeout of a single vertex
+	st := busybox.Run(llb.Shlex(`sh -c "sleep 60 # `+identity.NewID()+`"`), llb.WithTimeout(2*time.Second))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.Error(t, err)
+	var terr *errdefs.VertexTimeoutError
+	require.True(t, errors.As(err, &terr))
+	require.Equal(t, int64(2*time.Second), terr.Timeout)
+
+	// deadline of the solve
+	st = busybox.Run(llb.Shlex(`sh -c "sleep 60 # ` + identity.NewID() + `"`))
+	def, err = st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{Timeout: 2 * time.Second}, nil)
+	require.Error(t, err)
+	terr = nil
+	require.True(t, errors.As(err, &terr))
+	require.Equal(t, int64(0), terr.Timeout)
+
+	// the timeout also stops a frontend that doesn't return
+	start := time.Now()
+	_, err = c.Build(sb.Context(), SolveOpt{Timeout: 2 * time.Second}, "", func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, nil)
+	require.Error(t, err)
+	require.True(t, time.Since(start) < time.Minute)
+}
+
+func testFrontendWarnings(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	src := []byte("line1\nline2\nline3\n")
+
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		def, err := llb.Scratch().File(llb.Mkfile("foo", 0600, src)).Marshal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			Definition: def.ToPB(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		dgst, err := def.Head()
+		if err != nil {
+			return nil, err
+		}
+		err = c.Warn(ctx, dgst, "this is a warning", gateway.WarnOpts{
+			Level:  2,
+			Detail: [][]byte{[]byte("warning detail")},
+			URL:    "https://example.com/warning",
+			SourceInfo: &pb.SourceInfo{
+				Filename: "foo",
+				Data:     src,
+			},
+			Range: []*pb.Range{{
+				Start: pb.Position{Line: 2},
+				End:   pb.Position{Line: 2},
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	status := make(chan *SolveStatus)
+	var warnings []*VertexWarning
+	eg, ctx := errgroup.WithContext(sb.Context())
+	eg.Go(func() error {
+		for st := range status {
+			warnings = append(warnings, st.Warnings...)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		_, err := c.Build(ctx, SolveOpt{}, "", frontend, status)
+		return err
+	})
+	require.NoError(t, eg.Wait())
+
+	require.Equal(t, 1, len(warnings))
+	w := warnings[0]
+	require.Equal(t, "this is a warning", string(w.Short))
+	require.Equal(t, 2, w.Level)
+	require.Equal(t, [][]byte{[]byte("warning detail")}, w.Detail)
+	require.Equal(t, "https://example.com/warning", w.URL)
+	require.Equal(t, "foo", w.SourceInfo.Filename)
+	require.Equal(t, src, w.SourceInfo.Data)
+	require.Equal(t, int32(2), w.Range[0].Start.Line)
+	require.NotEmpty(t, w.Vertex)
+}
+
 func skipDockerd(t *testing.T, sb integration.Sandbox) {
 	// TODO: remove me once dockerd supports the image and exporter.
 	t.Helper()
@@ -1930,37 +2366,446 @@ func testTarExporterWithSocketCopy(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 }
 
-// moby/buildkit#1418
-func testTarExporterSymlink(t *testing.T, sb integration.Sandbox) {
+// testExportedOwnership checks that exported files keep the ownership from
+// inside the build containers, also when the worker remaps user namespaces.
+func testCacheNamespaces(t *testing.T, sb integration.Sandbox) {
 	requiresLinux(t)
 	c, err := New(sb.Context(), sb.Address())
 	require.NoError(t, err)
 	defer c.Close()
 
-	busybox := llb.Image("busybox:latest")
-	st := llb.Scratch()
+	st := llb.Image("busybox:latest").Run(llb.Shlex(`sh -c "ls /cache > out; touch /cache/marker; head -c 32 /dev/urandom | md5sum >> out"`), llb.Dir("/wd"))
+	st.AddMount("/cache", llb.Scratch(), llb.AsPersistentCacheDir("nscache", llb.CacheMountShared))
+	out := st.AddMount("/wd", llb.Scratch())
 
-	run := func(cmd string) {
-		st = busybox.Run(llb.Shlex(cmd), llb.Dir("/wd")).AddMount("/wd", st)
+	def, err := out.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	build := func(ns string) string {
+		destDir, err := ioutil.TempDir("", "buildkit")
+		require.NoError(t, err)
+		defer os.RemoveAll(destDir)
+
+		_, err = c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:      ExporterLocal,
+					OutputDir: destDir,
+				},
+			},
+			CacheNamespace: ns,
+		}, nil)
+		require.NoError(t, err)
+
+		dt, err := ioutil.ReadFile(filepath.Join(destDir, "out"))
+		require.NoError(t, err)
+		return string(dt)
 	}
 
-	run(`sh -c "echo -n first > foo;ln -s foo bar"`)
+	outA := build("team-a")
+	require.NotContains(t, outA, "marker")
 
-	def, err := st.Marshal(sb.Context())
+	// same namespace uses the cache
+	require.Equal(t, outA, build("team-a"))
+
+	// other namespaces don't see the cache keys or cache mounts of team-a
+	outB := build("team-b")
+	require.NotEqual(t, outA, outB)
+	require.NotContains(t, outB, "marker")
+
+	duA, err := c.DiskUsage(sb.Context(), WithCacheNamespace("team-a"))
 	require.NoError(t, err)
+	require.NotEqual(t, 0, len(duA))
 
-	var buf bytes.Buffer
-	_, err = c.Solve(sb.Context(), def, SolveOpt{
-		Exports: []ExportEntry{
-			{
-				Type:   ExporterTar,
-				Output: fixedWriteCloser(&nopWriteCloser{&buf}),
-			},
-		},
-	}, nil)
+	var found bool
+	for _, r := range duA {
+		if r.RecordType == UsageRecordTypeCacheMount {
+			require.Equal(t, "namespace/team-a/nscache", r.CacheMountID)
+			found = true
+		}
+	}
+	require.True(t, found)
+
+	duB, err := c.DiskUsage(sb.Context(), WithCacheNamespace("team-b"))
 	require.NoError(t, err)
+	require.NotEqual(t, 0, len(duB))
+	for _, b := range duB {
+		for _, a := range duA {
+			require.NotEqual(t, a.ID, b.ID)
+		}
+	}
 
-	m, err := testutil.ReadTarToMap(buf.Bytes(), false)
+	err = c.Prune(sb.Context(), nil, PruneAll, WithCacheNamespace("team-b"))
+	require.NoError(t, err)
+
+	du, err := c.DiskUsage(sb.Context(), WithCacheNamespace("team-a"))
+	require.NoError(t, err)
+	require.Equal(t, len(duA), len(du))
+
+	_, err = c.DiskUsage(sb.Context(), WithCacheNamespace("../invalid"))
+	require.Error(t, err)
+
+	// builds can't use the cache mounts of a namespace by setting its ID
+	forged := llb.Image("busybox:latest").Run(llb.Shlex(`ls /cache`))
+	forged.AddMount("/cache", llb.Scratch(), llb.AsPersistentCacheDir("namespace/team-a/nscache", llb.CacheMountShared))
+	def, err = forged.Root().Marshal(sb.Context())
+	require.NoError(t, err)
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "reserved prefix")
+
+	checkAllReleasable(t, c, sb, true)
+}
+
+func testSourceDateEpoch(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	st := llb.Scratch().File(llb.Mkdir("dir", 0755).Mkfile("dir/foo", 0644, []byte("foo")), llb.IgnoreCache)
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	export := func(typ string) ([]byte, map[string]string) {
+		var buf bytes.Buffer
+		res, err := c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:   typ,
+					Attrs:  map[string]string{"source-date-epoch": fmt.Sprintf("%d", tm.Unix())},
+					Output: fixedWriteCloser(&nopWriteCloser{&buf}),
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
+		return buf.Bytes(), res.ExporterResponse
+	}
+
+	dt, _ := export(ExporterTar)
+	m, err := testutil.ReadTarToMap(dt, false)
+	require.NoError(t, err)
+	require.Equal(t, tm, m["dir/foo"].Header.ModTime.UTC())
+	require.Equal(t, tm, m["dir/"].Header.ModTime.UTC())
+
+	dt, resp := export(ExporterOCI)
+	m, err = testutil.ReadTarToMap(dt, false)
+	require.NoError(t, err)
+
+	var mfst ocispecs.Manifest
+	err = json.Unmarshal(m["blobs/sha256/"+strings.TrimPrefix(resp[exptypes.ExporterImageDigestKey], "sha256:")].Data, &mfst)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(mfst.Layers))
+
+	var img ocispecs.Image
+	err = json.Unmarshal(m["blobs/sha256/"+mfst.Config.Digest.Hex()].Data, &img)
+	require.NoError(t, err)
+	require.Equal(t, tm, img.Created.UTC())
+	for _, h := range img.History {
+		require.Equal(t, tm, h.Created.UTC())
+	}
+
+	lm, err := testutil.ReadTarToMap(m["blobs/sha256/"+mfst.Layers[0].Digest.Hex()].Data, true)
+	require.NoError(t, err)
+	require.Equal(t, tm, lm["dir/foo"].Header.ModTime.UTC())
+
+	// make sure the files of the next build get a different mtime
+	time.Sleep(time.Second)
+
+	dt2, resp2 := export(ExporterOCI)
+	require.Equal(t, resp[exptypes.ExporterImageDigestKey], resp2[exptypes.ExporterImageDigestKey])
+	require.Equal(t, dt, dt2)
+}
+
+func testExportAnnotations(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Scratch().File(llb.Mkfile("foo", 0644, []byte("foo")))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type: ExporterOCI,
+				Attrs: map[string]string{
+					"annotation." + ocispecs.AnnotationTitle:                     "title",
+					"annotation-manifest-descriptor." + ocispecs.AnnotationTitle: "descriptor",
+					"annotation-index-descriptor." + ocispecs.AnnotationSource:   "source",
+				},
+				Output: fixedWriteCloser(&nopWriteCloser{&buf}),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	m, err := testutil.ReadTarToMap(buf.Bytes(), false)
+	require.NoError(t, err)
+
+	var index ocispecs.Index
+	err = json.Unmarshal(m["index.json"].Data, &index)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(index.Manifests))
+	require.Equal(t, "descriptor", index.Manifests[0].Annotations[ocispecs.AnnotationTitle])
+	require.Equal(t, "source", index.Manifests[0].Annotations[ocispecs.AnnotationSource])
+
+	var mfst ocispecs.Manifest
+	err = json.Unmarshal(m["blobs/sha256/"+index.Manifests[0].Digest.Hex()].Data, &mfst)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{ocispecs.AnnotationTitle: "title"}, mfst.Annotations)
+
+	// docker media types can't carry annotations
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:   ExporterDocker,
+				Attrs:  map[string]string{"annotation." + ocispecs.AnnotationTitle: "title"},
+				Output: fixedWriteCloser(&nopWriteCloser{&bytes.Buffer{}}),
+			},
+		},
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "oci-mediatypes")
+}
+
+func testPushSignature(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	registry, err := sb.NewRegistry()
+	if errors.Is(err, integration.ErrorRequirements) {
+		t.Skip(err.Error())
+	}
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	dt, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: dt})
+
+	st := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("data")))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	name := registry + "/buildkit/testsign:latest"
+
+	resp, err := c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type: ExporterImage,
+				Attrs: map[string]string{
+					"name":                  name,
+					"push":                  "true",
+					"sign.secret":           "cosign",
+					"sign.annotation.build": "test",
+				},
+			},
+		},
+		Session: []session.Attachable{secretsprovider.FromMap(map[string][]byte{
+			"cosign": keyPEM,
+		})},
+	}, nil)
+	require.NoError(t, err)
+
+	dgst, err := digest.Parse(resp.ExporterResponse[exptypes.ExporterImageDigestKey])
+	require.NoError(t, err)
+
+	desc, provider, err := contentutil.ProviderFromRef(registry + "/buildkit/testsign:" + cosign.SignatureTag(dgst))
+	require.NoError(t, err)
+	require.Equal(t, ocispecs.MediaTypeImageManifest, desc.MediaType)
+
+	dt, err = content.ReadBlob(sb.Context(), provider, desc)
+	require.NoError(t, err)
+	var mfst ocispecs.Manifest
+	err = json.Unmarshal(dt, &mfst)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(mfst.Layers))
+	require.Equal(t, cosign.MediaTypeSimpleSigning, mfst.Layers[0].MediaType)
+
+	payload, err := content.ReadBlob(sb.Context(), provider, mfst.Layers[0])
+	require.NoError(t, err)
+	require.Contains(t, string(payload), dgst.String())
+	require.Contains(t, string(payload), `"build":"test"`)
+
+	sig, err := base64.StdEncoding.DecodeString(mfst.Layers[0].Annotations[cosign.AnnotationSignature])
+	require.NoError(t, err)
+	require.NoError(t, cosign.Verify(key.Public(), payload, sig))
+
+	// signatures of other signers are kept
+	key2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	dt, err = x509.MarshalPKCS8PrivateKey(key2)
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type: ExporterImage,
+				Attrs: map[string]string{
+					"name":        name,
+					"push":        "true",
+					"sign.secret": "cosign",
+				},
+			},
+		},
+		Session: []session.Attachable{secretsprovider.FromMap(map[string][]byte{
+			"cosign": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: dt}),
+		})},
+	}, nil)
+	require.NoError(t, err)
+
+	desc, provider, err = contentutil.ProviderFromRef(registry + "/buildkit/testsign:" + cosign.SignatureTag(dgst))
+	require.NoError(t, err)
+	dt, err = content.ReadBlob(sb.Context(), provider, desc)
+	require.NoError(t, err)
+	var merged ocispecs.Manifest
+	err = json.Unmarshal(dt, &merged)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(merged.Layers))
+	require.Equal(t, mfst.Layers[0], merged.Layers[0])
+
+	payload, err = content.ReadBlob(sb.Context(), provider, merged.Layers[1])
+	require.NoError(t, err)
+	sig, err = base64.StdEncoding.DecodeString(merged.Layers[1].Annotations[cosign.AnnotationSignature])
+	require.NoError(t, err)
+	require.NoError(t, cosign.Verify(key2.Public(), payload, sig))
+
+	// signing requires push
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type: ExporterImage,
+				Attrs: map[string]string{
+					"name":        name,
+					"sign.secret": "cosign",
+				},
+			},
+		},
+	}, nil)
+	require.Error(t, err)
+}
+
+func testExportedOwnership(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+	st := llb.Scratch().
+		File(llb.Mkfile("mkfile", 0644, []byte("mkfile"), llb.WithUIDGID(2000, 3000)))
+
+	run := func(cmd string) {
+		st = busybox.Run(llb.Shlex(cmd), llb.Dir("/wd")).AddMount("/wd", st)
+	}
+
+	run(`sh -c "echo -n exec > exec && chown 1000:1001 exec && echo -n root > root"`)
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	checkOwner := func(m map[string]*testutil.TarItem, name string, uid, gid int) {
+		item, ok := m[name]
+		require.True(t, ok, "missing %s", name)
+		require.Equal(t, uid, item.Header.Uid, "uid of %s", name)
+		require.Equal(t, gid, item.Header.Gid, "gid of %s", name)
+	}
+
+	var buf bytes.Buffer
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:   ExporterTar,
+				Output: fixedWriteCloser(&nopWriteCloser{&buf}),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	m, err := testutil.ReadTarToMap(buf.Bytes(), false)
+	require.NoError(t, err)
+	checkOwner(m, "mkfile", 2000, 3000)
+	checkOwner(m, "exec", 1000, 1001)
+	checkOwner(m, "root", 0, 0)
+
+	buf.Reset()
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:   ExporterOCI,
+				Output: fixedWriteCloser(&nopWriteCloser{&buf}),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	m, err = testutil.ReadTarToMap(buf.Bytes(), false)
+	require.NoError(t, err)
+
+	var index ocispecs.Index
+	err = json.Unmarshal(m["index.json"].Data, &index)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(index.Manifests))
+
+	var mfst ocispecs.Manifest
+	err = json.Unmarshal(m["blobs/sha256/"+index.Manifests[0].Digest.Hex()].Data, &mfst)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(mfst.Layers))
+
+	layer, ok := m["blobs/sha256/"+mfst.Layers[0].Digest.Hex()]
+	require.True(t, ok)
+	lm, err := testutil.ReadTarToMap(layer.Data, true)
+	require.NoError(t, err)
+	checkOwner(lm, "mkfile", 2000, 3000)
+
+	layer, ok = m["blobs/sha256/"+mfst.Layers[1].Digest.Hex()]
+	require.True(t, ok)
+	lm, err = testutil.ReadTarToMap(layer.Data, true)
+	require.NoError(t, err)
+	checkOwner(lm, "exec", 1000, 1001)
+	checkOwner(lm, "root", 0, 0)
+}
+
+// moby/buildkit#1418
+func testTarExporterSymlink(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+	st := llb.Scratch()
+
+	run := func(cmd string) {
+		st = busybox.Run(llb.Shlex(cmd), llb.Dir("/wd")).AddMount("/wd", st)
+	}
+
+	run(`sh -c "echo -n first > foo;ln -s foo bar"`)
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:   ExporterTar,
+				Output: fixedWriteCloser(&nopWriteCloser{&buf}),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	m, err := testutil.ReadTarToMap(buf.Bytes(), false)
 	require.NoError(t, err)
 
 	item, ok := m["foo"]
@@ -2518,6 +3363,14 @@ func testBuildPushAndValidate(t *testing.T, sb integration.Sandbox) {
 }
 
 func testStargzLazyPull(t *testing.T, sb integration.Sandbox) {
+	testLazyPull(t, sb, "estargz")
+}
+
+func testZstdChunkedLazyPull(t *testing.T, sb integration.Sandbox) {
+	testLazyPull(t, sb, "zstd:chunked")
+}
+
+func testLazyPull(t *testing.T, sb integration.Sandbox, compression string) {
 	skipDockerd(t, sb)
 	requiresLinux(t)
 
@@ -2557,7 +3410,7 @@ func testStargzLazyPull(t *testing.T, sb integration.Sandbox) {
 				Attrs: map[string]string{
 					"name":              sgzImage,
 					"push":              "true",
-					"compression":       "estargz",
+					"compression":       compression,
 					"oci-mediatypes":    "true",
 					"force-compression": "true",
 				},
@@ -2907,6 +3760,75 @@ func testBasicLocalCacheImportExport(t *testing.T, sb integration.Sandbox) {
 	testBasicCacheImportExport(t, sb, []CacheOptionsEntry{im}, []CacheOptionsEntry{ex})
 }
 
+func testLocalCacheMountImportExport(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	dir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	busybox := llb.Image("busybox:latest")
+	cacheMount := llb.AsPersistentCacheDir("mycache", llb.CacheMountShared)
+
+	run := busybox.Run(llb.Shlex(`sh -c "echo -n foobar > /cache/data"`))
+	run.AddMount("/cache", llb.Scratch(), cacheMount)
+
+	def, err := run.Root().Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		CacheExports: []CacheOptionsEntry{{
+			Type: "local",
+			Attrs: map[string]string{
+				"dest":         dir,
+				"cache-mounts": "true",
+			},
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	err = c.Prune(sb.Context(), nil, PruneAll)
+	require.NoError(t, err)
+
+	checkAllRemoved(t, c, sb)
+
+	run = busybox.Run(llb.Shlex(`sh -c "cp /cache/data /out/data"`))
+	run.AddMount("/cache", llb.Scratch(), cacheMount)
+	out := run.AddMount("/out", llb.Scratch())
+
+	def, err = out.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:      ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+		CacheImports: []CacheOptionsEntry{{
+			Type: "local",
+			Attrs: map[string]string{
+				"src":          dir,
+				"cache-mounts": "true",
+			},
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "data"))
+	require.NoError(t, err)
+	require.Equal(t, "foobar", string(dt))
+}
+
 func testBasicInlineCacheImportExport(t *testing.T, sb integration.Sandbox) {
 	skipDockerd(t, sb)
 	requiresLinux(t)
@@ -22,6 +22,9 @@ type UsageInfo struct {
 	Description string
 	RecordType  UsageRecordType
 	Shared      bool
+
+	// CacheMountID is the ID of the cache mount the record belongs to
+	CacheMountID string
 }
 
 func (c *Client) DiskUsage(ctx context.Context, opts ...DiskUsageOption) ([]*UsageInfo, error) {
@@ -30,7 +33,7 @@ func (c *Client) DiskUsage(ctx context.Context, opts ...DiskUsageOption) ([]*Usa
 		o.SetDiskUsageOption(info)
 	}
 
-	req := &controlapi.DiskUsageRequest{Filter: info.Filter}
+	req := &controlapi.DiskUsageRequest{Filter: info.Filter, CacheNamespace: info.CacheNamespace}
 	resp, err := c.controlClient().DiskUsage(ctx, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to call diskusage")
@@ -40,17 +43,18 @@ func (c *Client) DiskUsage(ctx context.Context, opts ...DiskUsageOption) ([]*Usa
 
 	for _, d := range resp.Record {
 		du = append(du, &UsageInfo{
-			ID:          d.ID,
-			Mutable:     d.Mutable,
-			InUse:       d.InUse,
-			Size:        d.Size_,
-			Parent:      d.Parent,
-			CreatedAt:   d.CreatedAt,
-			Description: d.Description,
-			UsageCount:  int(d.UsageCount),
-			LastUsedAt:  d.LastUsedAt,
-			RecordType:  UsageRecordType(d.RecordType),
-			Shared:      d.Shared,
+			ID:           d.ID,
+			Mutable:      d.Mutable,
+			InUse:        d.InUse,
+			Size:         d.Size_,
+			Parent:       d.Parent,
+			CreatedAt:    d.CreatedAt,
+			Description:  d.Description,
+			UsageCount:   int(d.UsageCount),
+			LastUsedAt:   d.LastUsedAt,
+			RecordType:   UsageRecordType(d.RecordType),
+			Shared:       d.Shared,
+			CacheMountID: d.CacheMountID,
 		})
 	}
 
@@ -69,7 +73,8 @@ type DiskUsageOption interface {
 }
 
 type DiskUsageInfo struct {
-	Filter []string
+	Filter         []string
+	CacheNamespace string
 }
 
 type UsageRecordType string
@@ -3,6 +3,7 @@ package client
 import (
 	"time"
 
+	"github.com/moby/buildkit/solver/pb"
 	digest "github.com/opencontainers/go-digest"
 )
 
@@ -34,10 +35,36 @@ type VertexLog struct {
 	Timestamp time.Time
 }
 
+// VertexResources is the resource usage of a process run by a vertex.
+type VertexResources struct {
+	Vertex         digest.Digest
+	Timestamp      time.Time
+	CPUNanos       int64
+	CPUUserNanos   int64
+	CPUSystemNanos int64
+	MemoryPeak     int64
+	IOReadBytes    int64
+	IOWriteBytes   int64
+	PidsPeak       int64
+}
+
+// VertexWarning is a non-fatal warning reported by a frontend for a vertex.
+type VertexWarning struct {
+	Vertex     digest.Digest
+	Level      int
+	Short      []byte
+	Detail     [][]byte
+	URL        string
+	SourceInfo *pb.SourceInfo
+	Range      []*pb.Range
+}
+
 type SolveStatus struct {
-	Vertexes []*Vertex
-	Statuses []*VertexStatus
-	Logs     []*VertexLog
+	Vertexes  []*Vertex
+	Statuses  []*VertexStatus
+	Logs      []*VertexLog
+	Resources []*VertexResources
+	Warnings  []*VertexWarning
 }
 
 type SolveResponse struct {
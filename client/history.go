@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// BuildHistoryRecord is the record of a finished build kept by the daemon.
+type BuildHistoryRecord struct {
+	Ref           string
+	Frontend      string
+	FrontendAttrs map[string]string
+	// Owner is the identity that started the build, if authorization is
+	// enabled.
+	Owner       string
+	CreatedAt   *time.Time
+	CompletedAt *time.Time
+	Error       string
+	Vertexes    []*Vertex
+	Resources   []*VertexResources
+}
+
+// BuildHistory returns the records of the finished builds, sorted from the
+// oldest to the newest. If ref is set, only the record of that build is
+// returned.
+func (c *Client) BuildHistory(ctx context.Context, ref string) ([]*BuildHistoryRecord, error) {
+	resp, err := c.controlClient().ListBuildHistory(ctx, &controlapi.BuildHistoryRequest{Ref: ref})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list build history")
+	}
+
+	var records []*BuildHistoryRecord
+	for _, r := range resp.Records {
+		rec := &BuildHistoryRecord{
+			Ref:           r.Ref,
+			Frontend:      r.Frontend,
+			FrontendAttrs: r.FrontendAttrs,
+			Owner:         r.Owner,
+			CreatedAt:     r.CreatedAt,
+			CompletedAt:   r.CompletedAt,
+			Error:         r.Error,
+		}
+		for _, v := range r.Vertexes {
+			rec.Vertexes = append(rec.Vertexes, &Vertex{
+				Digest:    v.Digest,
+				Inputs:    v.Inputs,
+				Name:      v.Name,
+				Started:   v.Started,
+				Completed: v.Completed,
+				Error:     v.Error,
+				Cached:    v.Cached,
+			})
+		}
+		for _, v := range r.Resources {
+			rec.Resources = append(rec.Resources, &VertexResources{
+				Vertex:         v.Vertex,
+				Timestamp:      v.Timestamp,
+				CPUNanos:       v.CpuNanos,
+				CPUUserNanos:   v.CpuUserNanos,
+				CPUSystemNanos: v.CpuSystemNanos,
+				MemoryPeak:     v.MemoryPeak,
+				IOReadBytes:    v.IoReadBytes,
+				IOWriteBytes:   v.IoWriteBytes,
+				PidsPeak:       v.PidsPeak,
+			})
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
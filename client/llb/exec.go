@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"sort"
+	"time"
 
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/system"
@@ -44,6 +45,7 @@ type mount struct {
 	cacheID      string
 	tmpfs        bool
 	cacheSharing CacheMountSharingMode
+	cacheLimits  *CacheMountLimits
 	noOutput     bool
 }
 
@@ -57,6 +59,7 @@ type ExecOp struct {
 	isValidated bool
 	secrets     []SecretInfo
 	ssh         []SSHInfo
+	cdiDevices  []CDIDeviceInfo
 }
 
 func (e *ExecOp) AddMount(target string, source Output, opt ...MountOption) Output {
@@ -204,6 +207,15 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 		meta.ExtraHosts = hosts
 	}
 
+	if len(e.cdiDevices) > 0 {
+		devices := make([]*pb.CDIDevice, len(e.cdiDevices))
+		for i, d := range e.cdiDevices {
+			devices[i] = &pb.CDIDevice{Name: d.Name, Optional: d.Optional}
+		}
+		meta.CdiDevices = devices
+		addCap(&e.constraints, pb.CapExecMetaCDI)
+	}
+
 	network, err := getNetwork(e.base)(ctx, c)
 	if err != nil {
 		return "", nil, nil, nil, err
@@ -247,6 +259,9 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 		if m.cacheID != "" {
 			addCap(&e.constraints, pb.CapExecMountCache)
 			addCap(&e.constraints, pb.CapExecMountCacheSharing)
+			if m.cacheLimits != nil {
+				addCap(&e.constraints, pb.CapExecMountCacheLimits)
+			}
 		} else if m.tmpfs {
 			addCap(&e.constraints, pb.CapExecMountTmpfs)
 		} else if m.source != nil {
@@ -330,6 +345,13 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 			case CacheMountLocked:
 				pm.CacheOpt.Sharing = pb.CacheSharingOpt_LOCKED
 			}
+			if l := m.cacheLimits; l != nil {
+				pm.CacheOpt.MaxSize = l.MaxSize
+				pm.CacheOpt.MaxAge = int64(l.MaxAge)
+				if l.PrunePolicy == CacheMountPruneReset {
+					pm.CacheOpt.PrunePolicy = pb.CachePrunePolicy_RESET
+				}
+			}
 		}
 		if m.tmpfs {
 			pm.MountType = pb.MountType_TMPFS
@@ -453,6 +475,26 @@ func AsPersistentCacheDir(id string, sharing CacheMountSharingMode) MountOption
 	}
 }
 
+// CacheMountLimits are the limits of a cache mount. They are applied when the
+// mount is released after the exec.
+type CacheMountLimits struct {
+	// MaxSize in bytes, 0 means no limit
+	MaxSize int64
+	// MaxAge removes the files that haven't been used for longer, 0 means
+	// no limit
+	MaxAge time.Duration
+	// PrunePolicy defines how the mount is reduced when it exceeds MaxSize
+	PrunePolicy CacheMountPrunePolicy
+}
+
+// WithCacheMountLimits sets the limits of a cache mount created with
+// AsPersistentCacheDir.
+func WithCacheMountLimits(l CacheMountLimits) MountOption {
+	return func(m *mount) {
+		m.cacheLimits = &l
+	}
+}
+
 func Tmpfs() MountOption {
 	return func(m *mount) {
 		m.tmpfs = true
@@ -613,6 +655,44 @@ func SecretFileOpt(uid, gid, mode int) SecretOption {
 	})
 }
 
+// AddCDIDevice exposes a CDI device from the host to the exec process. The
+// name can be fully qualified (vendor.com/class=name) or a short device name
+// that uniquely identifies a device known to the daemon.
+func AddCDIDevice(opts ...CDIDeviceOption) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		d := &CDIDeviceInfo{}
+		for _, opt := range opts {
+			opt.SetCDIDeviceOption(d)
+		}
+		ei.CDIDevices = append(ei.CDIDevices, *d)
+	})
+}
+
+type CDIDeviceOption interface {
+	SetCDIDeviceOption(*CDIDeviceInfo)
+}
+
+type cdiDeviceOptionFunc func(*CDIDeviceInfo)
+
+func (fn cdiDeviceOptionFunc) SetCDIDeviceOption(di *CDIDeviceInfo) {
+	fn(di)
+}
+
+func CDIDeviceName(name string) CDIDeviceOption {
+	return cdiDeviceOptionFunc(func(di *CDIDeviceInfo) {
+		di.Name = name
+	})
+}
+
+var CDIDeviceOptional = cdiDeviceOptionFunc(func(di *CDIDeviceInfo) {
+	di.Optional = true
+})
+
+type CDIDeviceInfo struct {
+	Name     string
+	Optional bool
+}
+
 func ReadonlyRootFS() RunOption {
 	return runOptionFunc(func(ei *ExecInfo) {
 		ei.ReadonlyRootFS = true
@@ -633,6 +713,7 @@ type ExecInfo struct {
 	ProxyEnv       *ProxyEnv
 	Secrets        []SecretInfo
 	SSH            []SSHInfo
+	CDIDevices     []CDIDeviceInfo
 }
 
 type MountInfo struct {
@@ -657,6 +738,15 @@ const (
 	CacheMountLocked
 )
 
+type CacheMountPrunePolicy int
+
+const (
+	// CacheMountPruneTrim removes the least recently used files
+	CacheMountPruneTrim CacheMountPrunePolicy = iota
+	// CacheMountPruneReset removes all the contents of the mount
+	CacheMountPruneReset
+)
+
 const (
 	NetModeSandbox = pb.NetMode_UNSET
 	NetModeHost    = pb.NetMode_HOST
@@ -3,6 +3,7 @@ package llb
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/stretchr/testify/require"
@@ -50,3 +51,59 @@ func TestValidGetMountIndex(t *testing.T) {
 	require.NoError(t, err, "failed to getIndex")
 	require.Equal(t, pb.OutputIndex(1), mountIndex, "unexpected mount index")
 }
+
+func TestExecCDIDevices(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(
+		Shlex("args"),
+		AddCDIDevice(CDIDeviceName("vendor1.com/device=foo")),
+		AddCDIDevice(CDIDeviceName("bar"), CDIDeviceOptional),
+	).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, idx := last(t, arr)
+	require.Equal(t, 0, idx)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []*pb.CDIDevice{
+		{Name: "vendor1.com/device=foo"},
+		{Name: "bar", Optional: true},
+	}, exec.Meta.CdiDevices)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaCDI]
+	require.True(t, ok)
+}
+
+func TestCacheMountLimits(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(
+		Shlex("args"),
+		AddMount("/cache", Scratch(), AsPersistentCacheDir("mycache", CacheMountShared), WithCacheMountLimits(CacheMountLimits{
+			MaxSize:     1 << 20,
+			MaxAge:      time.Hour,
+			PrunePolicy: CacheMountPruneReset,
+		})),
+	).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, 2, len(exec.Mounts))
+	require.Equal(t, &pb.CacheOpt{
+		ID:          "mycache",
+		Sharing:     pb.CacheSharingOpt_SHARED,
+		MaxSize:     1 << 20,
+		MaxAge:      int64(time.Hour),
+		PrunePolicy: pb.CachePrunePolicy_RESET,
+	}, exec.Mounts[1].CacheOpt)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMountCacheLimits]
+	require.True(t, ok)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/identity"
@@ -161,6 +162,9 @@ func (s State) Marshal(ctx context.Context, co ...ConstraintsOpt) (*Definition,
 		if m.ExportCache != nil {
 			md.Caps[pb.CapMetaExportCache] = true
 		}
+		if m.Timeout != 0 {
+			md.Caps[pb.CapMetaTimeout] = true
+		}
 	}
 
 	def.Metadata[dgst] = md
@@ -259,6 +263,7 @@ func (s State) Run(ro ...RunOption) ExecState {
 	}
 	exec.secrets = ei.Secrets
 	exec.ssh = ei.SSH
+	exec.cdiDevices = ei.CDIDevices
 
 	return ExecState{
 		State: s.WithOutput(exec.Output()),
@@ -483,6 +488,9 @@ func mergeMetadata(m1, m2 pb.OpMetadata) pb.OpMetadata {
 	if m2.ExportCache != nil {
 		m1.ExportCache = m2.ExportCache
 	}
+	if m2.Timeout != 0 {
+		m1.Timeout = m2.Timeout
+	}
 
 	for k := range m2.Caps {
 		if m1.Caps == nil {
@@ -544,6 +552,14 @@ func WithoutDefaultExportCache() ConstraintsOpt {
 	})
 }
 
+// WithTimeout sets the maximum duration the vertex is allowed to run for.
+// The vertex fails when it runs longer.
+func WithTimeout(d time.Duration) ConstraintsOpt {
+	return constraintsOptFunc(func(c *Constraints) {
+		c.Metadata.Timeout = int64(d)
+	})
+}
+
 // WithCaps exposes supported LLB caps to the marshaler
 func WithCaps(caps apicaps.CapSet) ConstraintsOpt {
 	return constraintsOptFunc(func(c *Constraints) {
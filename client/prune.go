@@ -19,6 +19,8 @@ func (c *Client) Prune(ctx context.Context, ch chan UsageInfo, opts ...PruneOpti
 		Filter:       info.Filter,
 		KeepDuration: int64(info.KeepDuration),
 		KeepBytes:    int64(info.KeepBytes),
+
+		CacheNamespace: info.CacheNamespace,
 	}
 	if info.All {
 		req.All = true
@@ -38,17 +40,18 @@ func (c *Client) Prune(ctx context.Context, ch chan UsageInfo, opts ...PruneOpti
 		}
 		if ch != nil {
 			ch <- UsageInfo{
-				ID:          d.ID,
-				Mutable:     d.Mutable,
-				InUse:       d.InUse,
-				Size:        d.Size_,
-				Parent:      d.Parent,
-				CreatedAt:   d.CreatedAt,
-				Description: d.Description,
-				UsageCount:  int(d.UsageCount),
-				LastUsedAt:  d.LastUsedAt,
-				RecordType:  UsageRecordType(d.RecordType),
-				Shared:      d.Shared,
+				ID:           d.ID,
+				Mutable:      d.Mutable,
+				InUse:        d.InUse,
+				Size:         d.Size_,
+				Parent:       d.Parent,
+				CreatedAt:    d.CreatedAt,
+				Description:  d.Description,
+				UsageCount:   int(d.UsageCount),
+				LastUsedAt:   d.LastUsedAt,
+				RecordType:   UsageRecordType(d.RecordType),
+				Shared:       d.Shared,
+				CacheMountID: d.CacheMountID,
 			}
 		}
 	}
@@ -63,6 +66,8 @@ type PruneInfo struct {
 	All          bool
 	KeepDuration time.Duration
 	KeepBytes    int64
+
+	CacheNamespace string
 }
 
 type pruneOptionFunc func(*PruneInfo)
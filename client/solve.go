@@ -20,6 +20,7 @@ import (
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/session/grpchijack"
 	"github.com/moby/buildkit/solver/pb"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
 	"github.com/moby/buildkit/util/bklog"
 	"github.com/moby/buildkit/util/entitlements"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
@@ -42,6 +43,21 @@ type SolveOpt struct {
 	AllowedEntitlements   []entitlements.Entitlement
 	SharedSession         *session.Session // TODO: refactor to better session syncing
 	SessionPreInitialized bool             // TODO: refactor to better session syncing
+	SourcePolicy          *spb.Policy
+	CacheNamespace        string
+	// Priority of the build when sharing workers with other builds. Steps of
+	// builds with higher priority are started first.
+	Priority int
+	// Weight of the build when sharing workers with other builds of the same
+	// priority. Builds get free slots in proportion to their weight. The
+	// default weight is 1.
+	Weight int
+	// Timeout stops the build and fails it when the build takes longer. The
+	// timeout starts when the daemon receives the solve.
+	Timeout time.Duration
+	// Ref identifies the solve, e.g. for CancelVertex. A random ID is used if
+	// empty.
+	Ref string
 }
 
 type ExportEntry struct {
@@ -87,7 +103,10 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 		return nil, err
 	}
 
-	ref := identity.NewID()
+	ref := opt.Ref
+	if ref == "" {
+		ref = identity.NewID()
+	}
 	eg, ctx := errgroup.WithContext(ctx)
 
 	statusContext, cancelStatus := context.WithCancel(context.Background())
@@ -209,6 +228,11 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 			FrontendInputs: frontendInputs,
 			Cache:          cacheOpt.options,
 			Entitlements:   opt.AllowedEntitlements,
+			SourcePolicy:   opt.SourcePolicy,
+			CacheNamespace: opt.CacheNamespace,
+			Priority:       int32(opt.Priority),
+			Weight:         int32(opt.Weight),
+			Timeout:        int64(opt.Timeout),
 		})
 		if err != nil {
 			return errors.Wrap(err, "failed to solve")
@@ -288,6 +312,30 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 					Timestamp: v.Timestamp,
 				})
 			}
+			for _, v := range resp.Resources {
+				s.Resources = append(s.Resources, &VertexResources{
+					Vertex:         v.Vertex,
+					Timestamp:      v.Timestamp,
+					CPUNanos:       v.CpuNanos,
+					CPUUserNanos:   v.CpuUserNanos,
+					CPUSystemNanos: v.CpuSystemNanos,
+					MemoryPeak:     v.MemoryPeak,
+					IOReadBytes:    v.IoReadBytes,
+					IOWriteBytes:   v.IoWriteBytes,
+					PidsPeak:       v.PidsPeak,
+				})
+			}
+			for _, v := range resp.Warnings {
+				s.Warnings = append(s.Warnings, &VertexWarning{
+					Vertex:     v.Vertex,
+					Level:      int(v.Level),
+					Short:      v.Short,
+					Detail:     v.Detail,
+					URL:        v.Url,
+					SourceInfo: v.Info,
+					Range:      v.Ranges,
+				})
+			}
 			if statusChan != nil {
 				statusChan <- &s
 			}
@@ -446,7 +494,9 @@ func parseCacheOptions(ctx context.Context, opt SolveOpt) (*cacheOptions, error)
 			contentStores["local:"+csDir] = cs
 
 		}
-		if im.Type == "registry" {
+		// the legacy API only carries the ref, registry importers with any
+		// other attribute (e.g. cache-mounts) need the new API
+		if _, ok := attrs["ref"]; im.Type == "registry" && ok && len(attrs) == 1 {
 			legacyImportRef := attrs["ref"]
 			legacyImportRefs = append(legacyImportRefs, legacyImportRef)
 		} else {
@@ -457,7 +507,7 @@ func parseCacheOptions(ctx context.Context, opt SolveOpt) (*cacheOptions, error)
 		}
 	}
 	if opt.Frontend != "" {
-		// use legacy API for registry importers, because the frontend might not support the new API
+		// use legacy API for registry importers with only a ref, because the frontend might not support the new API
 		if len(legacyImportRefs) > 0 {
 			frontendAttrs["cache-from"] = strings.Join(legacyImportRefs, ",")
 		}
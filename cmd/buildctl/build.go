@@ -6,11 +6,16 @@ import (
 	"io"
 	"os"
 
+	"github.com/containerd/console"
 	"github.com/containerd/continuity"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/cmd/buildctl/build"
 	bccommon "github.com/moby/buildkit/cmd/buildctl/common"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/frontend/subrequests/lint"
+	"github.com/moby/buildkit/frontend/subrequests/outline"
+	"github.com/moby/buildkit/frontend/subrequests/targets"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth/authprovider"
 	"github.com/moby/buildkit/session/sshforward/sshprovider"
@@ -96,7 +101,7 @@ var buildCommand = cli.Command{
 		},
 		cli.StringSliceFlag{
 			Name:  "allow",
-			Usage: "Allow extra privileged entitlement, e.g. network.host, security.insecure",
+			Usage: "Allow extra privileged entitlement, e.g. network.host, security.insecure, device",
 		},
 		cli.StringSliceFlag{
 			Name:  "ssh",
@@ -106,6 +111,38 @@ var buildCommand = cli.Command{
 			Name:  "metadata-file",
 			Usage: "Output build metadata (e.g., image digest) to a file as JSON",
 		},
+		cli.StringFlag{
+			Name:  "source-policy-file",
+			Usage: "Read source policy file from a JSON file",
+		},
+		cli.StringFlag{
+			Name:  "cache-namespace",
+			Usage: "Isolate the build cache in a namespace",
+		},
+		cli.IntFlag{
+			Name:  "priority",
+			Usage: "Priority of the build when sharing workers with other builds",
+		},
+		cli.IntFlag{
+			Name:  "weight",
+			Usage: "Share of the workers the build gets compared to other builds of the same priority",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Stop the build when it runs longer than the timeout, e.g. 30m",
+		},
+		cli.StringFlag{
+			Name:  "print",
+			Usage: "Print the result of a frontend subrequest instead of building [outline, targets, lint]",
+		},
+		cli.BoolFlag{
+			Name:  "debug-on-failure",
+			Usage: "Open an interactive shell in the container of a failed step",
+		},
+		cli.StringSliceFlag{
+			Name:  "debug-shell",
+			Usage: "Command to run in the container of a failed step with --debug-on-failure (default: /bin/sh)",
+		},
 	},
 }
 
@@ -208,6 +245,11 @@ func buildAction(clicontext *cli.Context) error {
 		return err
 	}
 
+	srcPol, err := build.ParseSourcePolicy(clicontext.String("source-policy-file"))
+	if err != nil {
+		return err
+	}
+
 	eg, ctx := errgroup.WithContext(bccommon.CommandContext(clicontext))
 
 	solveOpt := client.SolveOpt{
@@ -219,6 +261,11 @@ func buildAction(clicontext *cli.Context) error {
 		CacheImports:        cacheImports,
 		Session:             attachable,
 		AllowedEntitlements: allowed,
+		SourcePolicy:        srcPol,
+		CacheNamespace:      clicontext.String("cache-namespace"),
+		Priority:            clicontext.Int("priority"),
+		Weight:              clicontext.Int("weight"),
+		Timeout:             clicontext.Duration("timeout"),
 	}
 
 	solveOpt.FrontendAttrs, err = build.ParseOpt(clicontext.StringSlice("opt"), clicontext.StringSlice("frontend-opt"))
@@ -231,6 +278,44 @@ func buildAction(clicontext *cli.Context) error {
 		return errors.Wrap(err, "invalid local")
 	}
 
+	printFunc, err := parsePrintFunc(clicontext.String("print"))
+	if err != nil {
+		return err
+	}
+	if printFunc != nil {
+		if clicontext.String("frontend") == "" {
+			return errors.New("--print requires --frontend")
+		}
+		solveOpt.Exports = nil
+		solveOpt.CacheExports = nil
+	}
+
+	var debugConsole console.Console
+	debugShell := clicontext.StringSlice("debug-shell")
+	if clicontext.Bool("debug-on-failure") {
+		if printFunc != nil {
+			return errors.New("--debug-on-failure cannot be used with --print")
+		}
+		// the tty display would redraw over the shell
+		switch clicontext.String("progress") {
+		case "auto", "":
+			clicontext.Set("progress", "plain")
+		case "tty":
+			return errors.New("--debug-on-failure cannot be used with --progress=tty")
+		}
+		con, closeConsole, err := openDebugConsole()
+		if err != nil {
+			return err
+		}
+		defer closeConsole()
+		debugConsole = con
+		if len(debugShell) == 0 {
+			debugShell = []string{"/bin/sh"}
+		}
+	} else if len(debugShell) > 0 {
+		return errors.New("--debug-shell requires --debug-on-failure")
+	}
+
 	var def *llb.Definition
 	if clicontext.String("frontend") == "" {
 		if fi, _ := os.Stdin.Stat(); (fi.Mode() & os.ModeCharDevice) != 0 {
@@ -282,13 +367,24 @@ func buildAction(clicontext *cli.Context) error {
 		}
 	}
 
+	var printResult []byte
 	eg.Go(func() error {
 		defer func() {
 			for _, w := range writers {
 				close(w.Status())
 			}
 		}()
-		resp, err := c.Solve(ctx, def, solveOpt, progresswriter.ResetTime(mw.WithPrefix("", false)).Status())
+		if printFunc != nil {
+			dt, err := solveSubrequest(ctx, c, solveOpt, printFunc.requestID, progresswriter.ResetTime(mw.WithPrefix("", false)).Status())
+			printResult = dt
+			return err
+		}
+		var resp *client.SolveResponse
+		if debugConsole != nil {
+			resp, err = solveWithDebugShell(ctx, c, def, solveOpt, debugConsole, debugShell, progresswriter.ResetTime(mw.WithPrefix("", false)).Status())
+		} else {
+			resp, err = c.Solve(ctx, def, solveOpt, progresswriter.ResetTime(mw.WithPrefix("", false)).Status())
+		}
 		if err != nil {
 			return err
 		}
@@ -311,7 +407,86 @@ func buildAction(clicontext *cli.Context) error {
 		return pw.Err()
 	})
 
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if printFunc != nil {
+		return printFunc.print(printResult, os.Stdout)
+	}
+	return nil
+}
+
+type subrequestPrinter struct {
+	requestID string
+	print     func([]byte, io.Writer) error
+}
+
+func parsePrintFunc(v string) (*subrequestPrinter, error) {
+	switch v {
+	case "":
+		return nil, nil
+	case "outline":
+		return &subrequestPrinter{requestID: outline.RequestSubrequestsOutline, print: outline.PrintOutline}, nil
+	case "targets":
+		return &subrequestPrinter{requestID: targets.RequestTargets, print: targets.PrintTargets}, nil
+	case "lint":
+		return &subrequestPrinter{requestID: lint.RequestLint, print: printLintViolations}, nil
+	default:
+		return nil, errors.Errorf("invalid print value %q, expected outline, targets or lint", v)
+	}
+}
+
+// printLintViolations prints the lint results and fails if the violations
+// fail the check of the Dockerfile.
+func printLintViolations(dt []byte, w io.Writer) error {
+	if err := lint.PrintLintViolations(dt, w); err != nil {
+		return err
+	}
+	var results lint.LintResults
+	if err := json.Unmarshal(dt, &results); err != nil {
+		return err
+	}
+	if results.Error != "" {
+		return errors.New(results.Error)
+	}
+	return nil
+}
+
+// solveSubrequest runs the subrequest requestID of the frontend of opt and
+// returns its result.json.
+func solveSubrequest(ctx context.Context, c *client.Client, opt client.SolveOpt, requestID string, statusChan chan *client.SolveStatus) ([]byte, error) {
+	frontendOpt := make(map[string]string, len(opt.FrontendAttrs)+2)
+	for k, v := range opt.FrontendAttrs {
+		frontendOpt[k] = v
+	}
+	frontendOpt["requestid"] = requestID
+	frontendOpt["frontend.caps"] = "moby.buildkit.frontend.subrequests"
+
+	// the frontend is called from the build function
+	frontend := opt.Frontend
+	opt.Frontend = ""
+	opt.FrontendAttrs = nil
+
+	var dt []byte
+	_, err := c.Build(ctx, opt, "buildctl", func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			Frontend:    frontend,
+			FrontendOpt: frontendOpt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		dt, ok = res.Metadata["result.json"]
+		if !ok {
+			return nil, errors.Errorf("no result.json metadata in response of %s", requestID)
+		}
+		return gateway.NewResult(), nil
+	}, statusChan)
+	if err != nil {
+		return nil, err
+	}
+	return dt, nil
 }
 
 func writeMetadataFile(filename string, exporterResponse map[string]string) error {
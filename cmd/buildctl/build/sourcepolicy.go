@@ -0,0 +1,36 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/moby/buildkit/sourcepolicy"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
+	"github.com/pkg/errors"
+)
+
+// ParseSourcePolicy parses --source-policy-file
+func ParseSourcePolicy(filename string) (*spb.Policy, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	dt, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read source policy file")
+	}
+	return parseSourcePolicy(dt)
+}
+
+func parseSourcePolicy(dt []byte) (*spb.Policy, error) {
+	var pol spb.Policy
+	dec := json.NewDecoder(bytes.NewReader(dt))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&pol); err != nil {
+		return nil, errors.Wrap(err, "failed to parse source policy")
+	}
+	if err := sourcepolicy.Validate(&pol); err != nil {
+		return nil, err
+	}
+	return &pol, nil
+}
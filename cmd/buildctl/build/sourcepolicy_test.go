@@ -0,0 +1,57 @@
+package build
+
+import (
+	"testing"
+
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSourcePolicy(t *testing.T) {
+	dt := []byte(`{
+	"rules": [
+		{
+			"action": "DENY",
+			"selector": {"identifier": "docker-image://*"}
+		},
+		{
+			"action": "CONVERT",
+			"selector": {
+				"identifier": "docker-image://docker.io/library/alpine:latest",
+				"match_type": "EXACT"
+			},
+			"updates": {
+				"identifier": "docker-image://docker.io/library/alpine:3.14"
+			}
+		},
+		{
+			"action": "ALLOW",
+			"selector": {
+				"identifier": "https://github.com/*",
+				"constraints": [
+					{"key": "http.checksum", "value": "^sha256:", "condition": "MATCHES"}
+				]
+			}
+		}
+	]
+}`)
+	pol, err := parseSourcePolicy(dt)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(pol.Rules))
+	require.Equal(t, spb.PolicyAction_DENY, pol.Rules[0].Action)
+	require.Equal(t, spb.MatchType_WILDCARD, pol.Rules[0].Selector.MatchType)
+	require.Equal(t, spb.PolicyAction_CONVERT, pol.Rules[1].Action)
+	require.Equal(t, spb.MatchType_EXACT, pol.Rules[1].Selector.MatchType)
+	require.Equal(t, "docker-image://docker.io/library/alpine:3.14", pol.Rules[1].Updates.Identifier)
+	require.Equal(t, spb.AttrMatch_MATCHES, pol.Rules[2].Selector.Constraints[0].Condition)
+
+	_, err = parseSourcePolicy([]byte(`{"rules": [{"action": "REJECT", "selector": {"identifier": "*"}}]}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown policy action")
+
+	_, err = parseSourcePolicy([]byte(`{"rules": [{"action": "DENY", "selector": {"identifier": "(", "match_type": "REGEX"}}]}`))
+	require.Error(t, err)
+
+	_, err = parseSourcePolicy([]byte(`{"rulez": []}`))
+	require.Error(t, err)
+}
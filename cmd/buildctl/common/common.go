@@ -2,9 +2,11 @@ package common
 
 import (
 	"context"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/moby/buildkit/client"
@@ -82,6 +84,14 @@ func ResolveClient(c *cli.Context) (*client.Client, error) {
 		opts = append(opts, client.WithCredentials(serverName, caCert, cert, key))
 	}
 
+	if tokenFile := c.GlobalString("tokenfile"); tokenFile != "" {
+		dt, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read token file")
+		}
+		opts = append(opts, client.WithBearerToken(strings.TrimSpace(string(dt))))
+	}
+
 	timeout := time.Duration(c.GlobalInt("timeout"))
 	ctx, cancel := context.WithTimeout(ctx, timeout*time.Second)
 	defer cancel()
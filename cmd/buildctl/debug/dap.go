@@ -0,0 +1,23 @@
+package debug
+
+import (
+	"os"
+
+	bccommon "github.com/moby/buildkit/cmd/buildctl/common"
+	"github.com/moby/buildkit/cmd/buildctl/debug/dap"
+	"github.com/urfave/cli"
+)
+
+var DAPCommand = cli.Command{
+	Name:   "dap",
+	Usage:  "run a Debug Adapter Protocol server on stdio to debug Dockerfile builds",
+	Action: runDAP,
+}
+
+func runDAP(clicontext *cli.Context) error {
+	c, err := bccommon.ResolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+	return dap.NewServer(c).Serve(commandContext(clicontext), os.Stdin, os.Stdout)
+}
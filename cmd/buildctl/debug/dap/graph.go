@@ -0,0 +1,196 @@
+package dap
+
+import (
+	"path/filepath"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// vertex is an op of the build definition with its source locations.
+type vertex struct {
+	dgst      digest.Digest
+	dt        []byte
+	op        *pb.Op
+	meta      pb.OpMetadata
+	locations []location
+}
+
+// location is a range of lines in a source file.
+type location struct {
+	path      string
+	startLine int
+	startCol  int
+	endLine   int
+	endCol    int
+}
+
+func (v *vertex) name() string {
+	if name, ok := v.meta.Description["llb.customname"]; ok {
+		return name
+	}
+	switch op := v.op.Op.(type) {
+	case *pb.Op_Exec:
+		return "exec"
+	case *pb.Op_Source:
+		return op.Source.Identifier
+	case *pb.Op_File:
+		return "file"
+	case *pb.Op_Build:
+		return "build"
+	default:
+		return string(v.dgst)
+	}
+}
+
+// matches returns if any of the locations of v contains line of the source
+// file at path.
+func (v *vertex) matches(path string, line int) bool {
+	for _, loc := range v.locations {
+		if loc.path == path && loc.startLine <= line && line <= loc.endLine {
+			return true
+		}
+	}
+	return false
+}
+
+// graph is a build definition with its vertices in the order the solver
+// needs them.
+type graph struct {
+	def      *pb.Definition
+	vertices map[digest.Digest]*vertex
+	order    []*vertex
+}
+
+// newGraph parses def. Relative source file names are resolved against dir.
+func newGraph(def *pb.Definition, dir string) (*graph, error) {
+	if len(def.Def) == 0 {
+		return nil, errors.New("empty definition")
+	}
+	g := &graph{
+		def:      def,
+		vertices: map[digest.Digest]*vertex{},
+	}
+	var last digest.Digest
+	for _, dt := range def.Def {
+		var op pb.Op
+		if err := (&op).Unmarshal(dt); err != nil {
+			return nil, errors.Wrap(err, "failed to parse llb proto op")
+		}
+		dgst := digest.FromBytes(dt)
+		g.vertices[dgst] = &vertex{
+			dgst: dgst,
+			dt:   dt,
+			op:   &op,
+			meta: def.Metadata[dgst],
+		}
+		last = dgst
+	}
+
+	if def.Source != nil {
+		paths := make([]string, len(def.Source.Infos))
+		for i, info := range def.Source.Infos {
+			p := info.Filename
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(dir, p)
+			}
+			paths[i] = filepath.Clean(p)
+		}
+		for dgst, locs := range def.Source.Locations {
+			v, ok := g.vertices[digest.Digest(dgst)]
+			if !ok {
+				continue
+			}
+			for _, loc := range locs.Locations {
+				if loc.SourceIndex < 0 || int(loc.SourceIndex) >= len(paths) {
+					return nil, errors.Errorf("failed to find source map with index %d", loc.SourceIndex)
+				}
+				for _, r := range loc.Ranges {
+					v.locations = append(v.locations, location{
+						path:      paths[loc.SourceIndex],
+						startLine: int(r.Start.Line),
+						startCol:  int(r.Start.Character),
+						endLine:   int(r.End.Line),
+						endCol:    int(r.End.Character),
+					})
+				}
+			}
+		}
+	}
+
+	// the last op is the one returning the result, visit its inputs in
+	// depth first order
+	seen := map[digest.Digest]struct{}{}
+	var visit func(dgst digest.Digest) error
+	visit = func(dgst digest.Digest) error {
+		if _, ok := seen[dgst]; ok {
+			return nil
+		}
+		seen[dgst] = struct{}{}
+		v, ok := g.vertices[dgst]
+		if !ok {
+			return errors.Errorf("invalid missing input digest %s", dgst)
+		}
+		for _, inp := range v.op.Inputs {
+			if err := visit(inp.Digest); err != nil {
+				return err
+			}
+		}
+		g.order = append(g.order, v)
+		return nil
+	}
+	if err := visit(last); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// hasLocation returns if any vertex has a location containing line of the
+// source file at path.
+func (g *graph) hasLocation(path string, line int) bool {
+	for _, v := range g.order {
+		if v.matches(path, line) {
+			return true
+		}
+	}
+	return false
+}
+
+// inputDefinition returns the definition of the subgraph producing inp.
+func (g *graph) inputDefinition(inp *pb.Input) (*pb.Definition, error) {
+	def := &pb.Definition{
+		Metadata: map[digest.Digest]pb.OpMetadata{},
+	}
+	seen := map[digest.Digest]struct{}{}
+	var visit func(dgst digest.Digest) error
+	visit = func(dgst digest.Digest) error {
+		if _, ok := seen[dgst]; ok {
+			return nil
+		}
+		seen[dgst] = struct{}{}
+		v, ok := g.vertices[dgst]
+		if !ok {
+			return errors.Errorf("invalid missing input digest %s", dgst)
+		}
+		for _, inp := range v.op.Inputs {
+			if err := visit(inp.Digest); err != nil {
+				return err
+			}
+		}
+		def.Def = append(def.Def, v.dt)
+		def.Metadata[dgst] = v.meta
+		return nil
+	}
+	if err := visit(inp.Digest); err != nil {
+		return nil, err
+	}
+
+	// the op without type returns its only input as the result
+	dt, err := (&pb.Op{Inputs: []*pb.Input{inp}}).Marshal()
+	if err != nil {
+		return nil, err
+	}
+	def.Def = append(def.Def, dt)
+	return def, nil
+}
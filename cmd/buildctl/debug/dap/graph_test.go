@@ -0,0 +1,61 @@
+package dap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func lineRange(start, end int) []*pb.Range {
+	return []*pb.Range{{Start: pb.Position{Line: int32(start)}, End: pb.Position{Line: int32(end)}}}
+}
+
+func TestGraph(t *testing.T) {
+	sm := llb.NewSourceMap(nil, "Dockerfile", []byte("FROM busybox\nRUN foo\nRUN bar \\\n  baz\n"))
+
+	base := llb.Image("busybox", sm.Location(lineRange(1, 1)))
+	st := base.Run(llb.Shlex("foo"), sm.Location(lineRange(2, 2))).Root()
+	st = st.Run(llb.Shlex("bar baz"), sm.Location(lineRange(3, 4))).Root()
+
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	g, err := newGraph(def.ToPB(), "/src")
+	require.NoError(t, err)
+
+	// image, two execs and the op returning the result
+	require.Equal(t, 4, len(g.order))
+	require.IsType(t, &pb.Op_Source{}, g.order[0].op.Op)
+	require.IsType(t, &pb.Op_Exec{}, g.order[1].op.Op)
+	require.IsType(t, &pb.Op_Exec{}, g.order[2].op.Op)
+	require.Nil(t, g.order[3].op.Op)
+	require.Equal(t, 0, len(g.order[3].locations))
+
+	require.True(t, g.order[1].matches("/src/Dockerfile", 2))
+	require.False(t, g.order[1].matches("/src/Dockerfile", 3))
+	require.False(t, g.order[1].matches("/other/Dockerfile", 2))
+	require.True(t, g.order[2].matches("/src/Dockerfile", 4))
+
+	require.True(t, g.hasLocation("/src/Dockerfile", 1))
+	require.False(t, g.hasLocation("/src/Dockerfile", 5))
+
+	// the input of the last exec is the result of the first one
+	exec := g.order[2]
+	subdef, err := g.inputDefinition(exec.op.Inputs[0])
+	require.NoError(t, err)
+	require.Equal(t, 3, len(subdef.Def))
+	require.Equal(t, g.order[0].dt, subdef.Def[0])
+	require.Equal(t, g.order[1].dt, subdef.Def[1])
+
+	var ret pb.Op
+	require.NoError(t, (&ret).Unmarshal(subdef.Def[2]))
+	require.Equal(t, []*pb.Input{exec.op.Inputs[0]}, ret.Inputs)
+	require.Contains(t, subdef.Metadata, digest.FromBytes(subdef.Def[1]))
+
+	_, err = newGraph(subdef, "/src")
+	require.NoError(t, err)
+}
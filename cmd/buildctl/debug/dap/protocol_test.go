@@ -0,0 +1,59 @@
+package dap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	ev := &Event{
+		ProtocolMessage: ProtocolMessage{Seq: 1, Type: "event"},
+		Event:           "stopped",
+		Body:            &StoppedEventBody{Reason: "breakpoint", ThreadID: threadID},
+	}
+	require.NoError(t, WriteMessage(&buf, ev))
+	require.NoError(t, WriteMessage(&buf, &Response{
+		ProtocolMessage: ProtocolMessage{Seq: 2, Type: "response"},
+		RequestSeq:      1,
+		Success:         true,
+		Command:         "threads",
+	}))
+
+	r := bufio.NewReader(&buf)
+	dt, err := ReadMessage(r)
+	require.NoError(t, err)
+	var ev2 struct {
+		Event
+		Body StoppedEventBody `json:"body"`
+	}
+	require.NoError(t, json.Unmarshal(dt, &ev2))
+	require.Equal(t, 1, ev2.Seq)
+	require.Equal(t, "stopped", ev2.Event.Event)
+	require.Equal(t, "breakpoint", ev2.Body.Reason)
+
+	dt, err = ReadMessage(r)
+	require.NoError(t, err)
+	var resp Response
+	require.NoError(t, json.Unmarshal(dt, &resp))
+	require.Equal(t, "threads", resp.Command)
+	require.True(t, resp.Success)
+
+	_, err = ReadMessage(r)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReadMessageInvalid(t *testing.T) {
+	_, err := ReadMessage(bufio.NewReader(strings.NewReader("Content-Type: foo\r\n\r\n{}")))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Content-Length")
+
+	_, err = ReadMessage(bufio.NewReader(strings.NewReader("Content-Length: 10\r\n\r\n{}")))
+	require.Error(t, err)
+}
@@ -0,0 +1,855 @@
+package dap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/client"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/bklog"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	threadID = 1
+	frameID  = 1
+)
+
+const (
+	variablesStep = iota + 1
+	variablesEnv
+	variablesMounts
+)
+
+// Server is a Debug Adapter Protocol server that builds a Dockerfile and
+// pauses before the steps with breakpoints.
+type Server struct {
+	c *client.Client
+
+	wmu sync.Mutex
+	w   io.Writer
+	seq int
+
+	mu          sync.Mutex
+	breakpoints map[string][]int
+	launch      *LaunchArguments
+	configured  bool
+	cancel      func()
+	done        chan struct{}
+	stepping    bool
+	graph       *graph
+	paused      *pausedStep
+	resume      chan bool
+	evaluations map[int]func()
+	evaluating  sync.WaitGroup
+}
+
+// errPending is returned by handle for requests that are answered by a
+// goroutine.
+var errPending = errors.New("response pending")
+
+func NewServer(c *client.Client) *Server {
+	return &Server{
+		c:           c,
+		breakpoints: map[string][]int{},
+		evaluations: map[int]func(){},
+	}
+}
+
+// Serve handles the requests read from r and writes the responses and events
+// to w until the client disconnects.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	defer s.evaluating.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer s.stop()
+
+	br := bufio.NewReader(r)
+	for {
+		dt, err := ReadMessage(br)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		var req Request
+		if err := json.Unmarshal(dt, &req); err != nil {
+			return errors.Wrap(err, "failed to parse message")
+		}
+		if req.Type != "request" {
+			continue
+		}
+		body, after, err := s.handle(ctx, &req)
+		if err == errPending {
+			continue
+		}
+		s.respond(&req, body, err)
+		if after != nil {
+			after()
+		}
+		if err == nil && (req.Command == "disconnect" || req.Command == "terminate") {
+			return nil
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req *Request) (interface{}, func(), error) {
+	switch req.Command {
+	case "initialize":
+		return &Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsTerminateRequest:         true,
+			SupportsCancelRequest:            true,
+		}, func() { s.sendEvent("initialized", nil) }, nil
+	case "launch":
+		var args LaunchArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid launch arguments")
+		}
+		return nil, nil, s.setLaunch(ctx, &args)
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid breakpoints")
+		}
+		return s.setBreakpoints(&args), nil, nil
+	case "configurationDone":
+		s.mu.Lock()
+		s.configured = true
+		s.mu.Unlock()
+		return nil, nil, s.startBuild(ctx)
+	case "threads":
+		return &ThreadsResponseBody{Threads: []Thread{{ID: threadID, Name: "build"}}}, nil, nil
+	case "stackTrace":
+		return s.stackTrace()
+	case "scopes":
+		return s.scopes()
+	case "variables":
+		var args VariablesArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid variables arguments")
+		}
+		return s.variables(args.VariablesReference)
+	case "evaluate":
+		var args EvaluateArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid evaluate arguments")
+		}
+		return nil, nil, s.evaluate(req, &args)
+	case "cancel":
+		var args CancelArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid cancel arguments")
+		}
+		s.cancelEvaluation(args.RequestID)
+		return nil, nil, nil
+	case "continue":
+		resume, err := s.continueBuild(false)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ContinueResponseBody{AllThreadsContinued: true}, resume, nil
+	case "next", "stepIn", "stepOut":
+		resume, err := s.continueBuild(true)
+		return nil, resume, err
+	case "pause":
+		s.mu.Lock()
+		s.stepping = true
+		s.mu.Unlock()
+		return nil, nil, nil
+	case "disconnect", "terminate":
+		return nil, s.stop, nil
+	default:
+		return nil, nil, errors.Errorf("unsupported request %q", req.Command)
+	}
+}
+
+func (s *Server) setLaunch(ctx context.Context, args *LaunchArguments) error {
+	if args.Context == "" && args.Dockerfile == "" {
+		return errors.New("launch requires context or dockerfile")
+	}
+	if args.Dockerfile == "" {
+		args.Dockerfile = filepath.Join(args.Context, "Dockerfile")
+	}
+	if args.Context == "" {
+		args.Context = filepath.Dir(args.Dockerfile)
+	}
+	if args.Frontend == "" {
+		args.Frontend = "dockerfile.v0"
+	}
+	var err error
+	if args.Dockerfile, err = filepath.Abs(args.Dockerfile); err != nil {
+		return err
+	}
+	if args.Context, err = filepath.Abs(args.Context); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.launch != nil {
+		s.mu.Unlock()
+		return errors.New("build already launched")
+	}
+	s.launch = args
+	s.mu.Unlock()
+	return s.startBuild(ctx)
+}
+
+func (s *Server) setBreakpoints(args *SetBreakpointsArguments) *SetBreakpointsResponseBody {
+	path := filepath.Clean(args.Source.Path)
+	lines := make([]int, 0, len(args.Breakpoints))
+	body := &SetBreakpointsResponseBody{
+		Breakpoints: make([]Breakpoint, 0, len(args.Breakpoints)),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, bp := range args.Breakpoints {
+		lines = append(lines, bp.Line)
+		b := Breakpoint{Verified: true, Line: bp.Line}
+		// breakpoints can only be checked after the frontend returned the
+		// definition
+		if s.graph != nil && !s.graph.hasLocation(path, bp.Line) {
+			b.Verified = false
+			b.Message = "no build step at this line"
+		}
+		body.Breakpoints = append(body.Breakpoints, b)
+	}
+	s.breakpoints[path] = lines
+	return body
+}
+
+// startBuild starts the build after the client has sent both the launch
+// arguments and the breakpoints.
+func (s *Server) startBuild(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.launch == nil || !s.configured || s.done != nil {
+		return nil
+	}
+	args := s.launch
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	frontendAttrs := map[string]string{}
+	for k, v := range args.Opts {
+		frontendAttrs[k] = v
+	}
+	frontendAttrs["filename"] = filepath.Base(args.Dockerfile)
+	if args.Target != "" {
+		frontendAttrs["target"] = args.Target
+	}
+	for k, v := range args.BuildArgs {
+		frontendAttrs["build-arg:"+k] = v
+	}
+	dockerfileDir := filepath.Dir(args.Dockerfile)
+
+	opt := client.SolveOpt{
+		LocalDirs: map[string]string{
+			"context":    args.Context,
+			"dockerfile": dockerfileDir,
+		},
+		Session: []session.Attachable{authprovider.NewDockerAuthProvider(os.Stderr)},
+	}
+
+	go func() {
+		defer close(s.done)
+		defer cancel()
+
+		statusCh := make(chan *client.SolveStatus)
+		progressDone := make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			s.writeProgress(statusCh)
+		}()
+
+		_, err := s.c.Build(ctx, opt, "buildctl", func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+			return s.build(ctx, c, args.Frontend, frontendAttrs, dockerfileDir)
+		}, statusCh)
+		<-progressDone
+
+		exitCode := 0
+		if err != nil {
+			exitCode = 1
+			s.sendEvent("output", &OutputEventBody{Category: "stderr", Output: fmt.Sprintf("build failed: %v\n", err)})
+		} else {
+			s.sendEvent("output", &OutputEventBody{Category: "console", Output: "build finished\n"})
+		}
+		s.sendEvent("exited", &ExitedEventBody{ExitCode: exitCode})
+		s.sendEvent("terminated", nil)
+	}()
+	return nil
+}
+
+// stop cancels the running evaluations and the build.
+func (s *Server) stop() {
+	s.mu.Lock()
+	evaluations := s.evaluations
+	s.evaluations = map[int]func(){}
+	s.mu.Unlock()
+	for _, cancel := range evaluations {
+		cancel()
+	}
+	s.stopBuild()
+}
+
+func (s *Server) stopBuild() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// build solves the frontend and then runs its definition step by step,
+// pausing before the steps with breakpoints.
+func (s *Server) build(ctx context.Context, c gateway.Client, frontend string, frontendAttrs map[string]string, dir string) (*gateway.Result, error) {
+	res, err := c.Solve(ctx, gateway.SolveRequest{
+		Frontend:    frontend,
+		FrontendOpt: frontendAttrs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return nil, errors.Wrap(err, "debugging multi-platform builds is not supported")
+	}
+	if ref == nil {
+		return res, nil
+	}
+	st, err := ref.ToState()
+	if err != nil {
+		return nil, err
+	}
+	def, err := st.Marshal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g, err := newGraph(def.ToPB(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.graph = g
+	s.mu.Unlock()
+
+	if err := s.run(ctx, c, g); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// run solves the steps of g in order. Each step is evaluated before the
+// build moves on to the next one, so the build stops at the step that fails.
+func (s *Server) run(ctx context.Context, c gateway.Client, g *graph) error {
+	entry := true
+	for _, v := range g.order {
+		// only steps with a source location can be shown to the user
+		if len(v.locations) == 0 {
+			continue
+		}
+		reason := s.stopReason(v, entry)
+		entry = false
+		if reason != "" {
+			if err := s.pause(ctx, newPausedStep(ctx, c, g, v), reason); err != nil {
+				return err
+			}
+		}
+		// the steps without source locations the step depends on are
+		// solved with it
+		def, err := g.inputDefinition(&pb.Input{Digest: v.dgst})
+		if err != nil {
+			return err
+		}
+		if _, err := c.Solve(ctx, gateway.SolveRequest{
+			Definition: def,
+			Evaluate:   true,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) stopReason(v *vertex, entry bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry && s.launch.StopOnEntry {
+		return "entry"
+	}
+	if s.stepping {
+		return "step"
+	}
+	for path, lines := range s.breakpoints {
+		for _, line := range lines {
+			if v.matches(path, line) {
+				return "breakpoint"
+			}
+		}
+	}
+	return ""
+}
+
+// pause blocks until the client continues the build.
+func (s *Server) pause(ctx context.Context, p *pausedStep, reason string) error {
+	defer p.release()
+
+	resume := make(chan bool, 1)
+	s.mu.Lock()
+	s.paused = p
+	s.resume = resume
+	s.mu.Unlock()
+
+	s.sendEvent("stopped", &StoppedEventBody{
+		Reason:            reason,
+		Description:       p.v.name(),
+		ThreadID:          threadID,
+		AllThreadsStopped: true,
+	})
+
+	var step bool
+	select {
+	case step = <-resume:
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	s.paused = nil
+	s.resume = nil
+	s.stepping = step
+	s.mu.Unlock()
+	return ctx.Err()
+}
+
+// continueBuild returns the function that resumes the paused build. It is
+// called after the response is sent, so that the client doesn't receive the
+// next stopped event before the response.
+func (s *Server) continueBuild(step bool) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resume == nil {
+		return nil, errors.New("build is not paused")
+	}
+	resume := s.resume
+	s.resume = nil
+	return func() { resume <- step }, nil
+}
+
+func (s *Server) pausedStep() (*pausedStep, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paused == nil {
+		return nil, errors.New("build is not paused")
+	}
+	return s.paused, nil
+}
+
+func (s *Server) stackTrace() (interface{}, func(), error) {
+	p, err := s.pausedStep()
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := p.v.locations[0]
+	return &StackTraceResponseBody{
+		StackFrames: []StackFrame{{
+			ID:   frameID,
+			Name: p.v.name(),
+			Source: &Source{
+				Name: filepath.Base(loc.path),
+				Path: loc.path,
+			},
+			Line:    loc.startLine,
+			Column:  1,
+			EndLine: loc.endLine,
+		}},
+		TotalFrames: 1,
+	}, nil, nil
+}
+
+func (s *Server) scopes() (interface{}, func(), error) {
+	p, err := s.pausedStep()
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes := []Scope{{Name: "Step", VariablesReference: variablesStep}}
+	if _, ok := p.v.op.Op.(*pb.Op_Exec); ok {
+		scopes = append(scopes,
+			Scope{Name: "Environment", VariablesReference: variablesEnv},
+			Scope{Name: "Mounts", VariablesReference: variablesMounts},
+		)
+	}
+	return &ScopesResponseBody{Scopes: scopes}, nil, nil
+}
+
+func (s *Server) variables(ref int) (interface{}, func(), error) {
+	p, err := s.pausedStep()
+	if err != nil {
+		return nil, nil, err
+	}
+	var vars []Variable
+	add := func(name, value string) {
+		vars = append(vars, Variable{Name: name, Value: value})
+	}
+	exec, _ := p.v.op.Op.(*pb.Op_Exec)
+
+	switch ref {
+	case variablesStep:
+		add("name", p.v.name())
+		add("digest", string(p.v.dgst))
+		if pl := p.v.op.Platform; pl != nil {
+			add("platform", platforms.Format(ocispecs.Platform{OS: pl.OS, Architecture: pl.Architecture, Variant: pl.Variant}))
+		}
+		if exec != nil {
+			if meta := exec.Exec.Meta; meta != nil {
+				add("args", fmt.Sprintf("%q", meta.Args))
+				add("cwd", meta.Cwd)
+				add("user", meta.User)
+			}
+			add("network", strings.ToLower(exec.Exec.Network.String()))
+			add("security", strings.ToLower(exec.Exec.Security.String()))
+		}
+		for i, inp := range p.v.op.Inputs {
+			add(fmt.Sprintf("input %d", i), p.g.vertices[inp.Digest].name())
+		}
+	case variablesEnv:
+		if exec != nil && exec.Exec.Meta != nil {
+			for _, env := range exec.Exec.Meta.Env {
+				parts := strings.SplitN(env, "=", 2)
+				if len(parts) == 2 {
+					add(parts[0], parts[1])
+				} else {
+					add(parts[0], "")
+				}
+			}
+		}
+	case variablesMounts:
+		if exec != nil {
+			for _, m := range exec.Exec.Mounts {
+				add(m.Dest, p.describeMount(m))
+			}
+		}
+	default:
+		return nil, nil, errors.Errorf("invalid variables reference %d", ref)
+	}
+	return &VariablesResponseBody{Variables: vars}, nil, nil
+}
+
+// evaluate runs the expression in the paused step in the background, so that
+// the client can still cancel it, continue or stop the build. The evaluation
+// is canceled when the build continues.
+func (s *Server) evaluate(req *Request, args *EvaluateArguments) error {
+	p, err := s.pausedStep()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(p.ctx)
+
+	s.mu.Lock()
+	s.evaluations[req.Seq] = cancel
+	s.mu.Unlock()
+
+	s.evaluating.Add(1)
+	go func() {
+		defer s.evaluating.Done()
+		defer s.cancelEvaluation(req.Seq)
+
+		out, err := p.exec(ctx, args.Expression)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				err = errors.New("cancelled")
+			}
+			s.respond(req, nil, err)
+			return
+		}
+		s.respond(req, &EvaluateResponseBody{Result: out}, nil)
+	}()
+	return errPending
+}
+
+func (s *Server) cancelEvaluation(seq int) {
+	s.mu.Lock()
+	cancel, ok := s.evaluations[seq]
+	delete(s.evaluations, seq)
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (s *Server) writeProgress(ch chan *client.SolveStatus) {
+	started := map[string]struct{}{}
+	for st := range ch {
+		for _, v := range st.Vertexes {
+			if v.Started == nil {
+				continue
+			}
+			if _, ok := started[v.Digest.String()]; !ok {
+				started[v.Digest.String()] = struct{}{}
+				prefix := ""
+				if v.Cached {
+					prefix = "CACHED "
+				}
+				s.sendEvent("output", &OutputEventBody{Category: "console", Output: prefix + v.Name + "\n"})
+			}
+			if v.Error != "" {
+				s.sendEvent("output", &OutputEventBody{Category: "stderr", Output: fmt.Sprintf("ERROR %s: %s\n", v.Name, v.Error)})
+			}
+		}
+		for _, l := range st.Logs {
+			category := "stdout"
+			if l.Stream == 2 {
+				category = "stderr"
+			}
+			s.sendEvent("output", &OutputEventBody{Category: category, Output: string(l.Data)})
+		}
+	}
+}
+
+func (s *Server) respond(req *Request, body interface{}, err error) {
+	resp := &Response{
+		ProtocolMessage: ProtocolMessage{Type: "response"},
+		RequestSeq:      req.Seq,
+		Success:         err == nil,
+		Command:         req.Command,
+		Body:            body,
+	}
+	if err != nil {
+		resp.Message = err.Error()
+	}
+	s.write(resp, &resp.ProtocolMessage)
+}
+
+func (s *Server) sendEvent(event string, body interface{}) {
+	ev := &Event{
+		ProtocolMessage: ProtocolMessage{Type: "event"},
+		Event:           event,
+		Body:            body,
+	}
+	s.write(ev, &ev.ProtocolMessage)
+}
+
+func (s *Server) write(v interface{}, pm *ProtocolMessage) {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.seq++
+	pm.Seq = s.seq
+	if err := WriteMessage(s.w, v); err != nil {
+		bklog.L.Errorf("failed to write debug adapter message: %v", err)
+	}
+}
+
+// pausedStep is the step the build is paused before. Commands are run in a
+// container with the inputs of the step, which is created on the first
+// command and kept until the build continues.
+type pausedStep struct {
+	c gateway.Client
+	g *graph
+	v *vertex
+
+	// ctx is canceled when the build continues
+	ctx    context.Context
+	cancel func()
+
+	mu   sync.Mutex
+	ctr  gateway.Container
+	refs map[int64]gateway.Reference
+}
+
+func newPausedStep(ctx context.Context, c gateway.Client, g *graph, v *vertex) *pausedStep {
+	ctx, cancel := context.WithCancel(ctx)
+	return &pausedStep{c: c, g: g, v: v, ctx: ctx, cancel: cancel}
+}
+
+func (p *pausedStep) exec(ctx context.Context, cmd string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctr == nil {
+		ctr, err := p.newContainer(ctx)
+		if err != nil {
+			return "", err
+		}
+		p.ctr = ctr
+	}
+
+	req := gateway.StartRequest{
+		Args: []string{"/bin/sh", "-c", cmd},
+	}
+	if exec, ok := p.v.op.Op.(*pb.Op_Exec); ok {
+		if meta := exec.Exec.Meta; meta != nil {
+			req.Env = meta.Env
+			req.User = meta.User
+			req.Cwd = meta.Cwd
+		}
+		req.SecurityMode = exec.Exec.Security
+	}
+	var out lockedBuffer
+	req.Stdout = &out
+	req.Stderr = &out
+
+	proc, err := p.ctr.Start(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- proc.Wait()
+	}()
+	select {
+	case err := <-waitErr:
+		if err != nil {
+			return out.String() + err.Error(), nil
+		}
+		return out.String(), nil
+	case <-ctx.Done():
+		// the process can't be signaled, releasing the container stops it
+		p.releaseContainer()
+		return "", ctx.Err()
+	}
+}
+
+func (p *pausedStep) newContainer(ctx context.Context) (gateway.Container, error) {
+	req := gateway.NewContainerRequest{
+		Platform:    p.v.op.Platform,
+		Constraints: p.v.op.Constraints,
+	}
+	if exec, ok := p.v.op.Op.(*pb.Op_Exec); ok {
+		req.NetMode = exec.Exec.Network
+		for _, m := range exec.Exec.Mounts {
+			mount := gateway.Mount{
+				Selector:  m.Selector,
+				Dest:      m.Dest,
+				Readonly:  m.Readonly,
+				MountType: m.MountType,
+				CacheOpt:  m.CacheOpt,
+				SecretOpt: m.SecretOpt,
+				SSHOpt:    m.SSHOpt,
+			}
+			if m.Input != pb.Empty {
+				ref, err := p.input(ctx, int64(m.Input))
+				if err != nil {
+					return nil, err
+				}
+				mount.Ref = ref
+			}
+			req.Mounts = append(req.Mounts, mount)
+		}
+	} else {
+		// other ops don't run in a container, use their first input as
+		// the root filesystem
+		if len(p.v.op.Inputs) == 0 {
+			return nil, errors.Errorf("%s has no filesystem to run commands in", p.v.name())
+		}
+		ref, err := p.input(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		req.Mounts = []gateway.Mount{{Dest: "/", MountType: pb.MountType_BIND, Ref: ref}}
+	}
+	return p.c.NewContainer(ctx, req)
+}
+
+// input solves the input with index i of the step.
+func (p *pausedStep) input(ctx context.Context, i int64) (gateway.Reference, error) {
+	if ref, ok := p.refs[i]; ok {
+		return ref, nil
+	}
+	if i < 0 || i >= int64(len(p.v.op.Inputs)) {
+		return nil, errors.Errorf("invalid input index %d", i)
+	}
+	def, err := p.g.inputDefinition(p.v.op.Inputs[i])
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.c.Solve(ctx, gateway.SolveRequest{
+		Definition: def,
+		Evaluate:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return nil, err
+	}
+	if p.refs == nil {
+		p.refs = map[int64]gateway.Reference{}
+	}
+	p.refs[i] = ref
+	return ref, nil
+}
+
+func (p *pausedStep) describeMount(m *pb.Mount) string {
+	parts := []string{strings.ToLower(m.MountType.String())}
+	if m.Input != pb.Empty && int(m.Input) < len(p.v.op.Inputs) {
+		parts = append(parts, "from "+p.g.vertices[p.v.op.Inputs[m.Input].Digest].name())
+	}
+	if m.Selector != "" {
+		parts = append(parts, "path "+m.Selector)
+	}
+	if m.CacheOpt != nil {
+		parts = append(parts, "id "+m.CacheOpt.ID)
+	}
+	if m.Readonly {
+		parts = append(parts, "readonly")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (p *pausedStep) release() {
+	p.cancel()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.releaseContainer()
+}
+
+func (p *pausedStep) releaseContainer() {
+	if p.ctr != nil {
+		if err := p.ctr.Release(context.TODO()); err != nil {
+			bklog.L.Warnf("failed to release debug container: %v", err)
+		}
+		p.ctr = nil
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *lockedBuffer) Write(dt []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(dt)
+}
+
+func (b *lockedBuffer) Close() error {
+	return nil
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,238 @@
+package dap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/moby/buildkit/client/llb"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	command string
+	args    interface{}
+}
+
+// serve runs s with reqs and returns the messages it writes.
+func serve(t *testing.T, s *Server, reqs []testRequest) []map[string]interface{} {
+	var in bytes.Buffer
+	for i, req := range reqs {
+		r := Request{
+			ProtocolMessage: ProtocolMessage{Seq: i + 1, Type: "request"},
+			Command:         req.command,
+		}
+		if req.args != nil {
+			dt, err := json.Marshal(req.args)
+			require.NoError(t, err)
+			r.Arguments = dt
+		}
+		require.NoError(t, WriteMessage(&in, r))
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, s.Serve(context.TODO(), &in, &out))
+	return readMessages(t, &out)
+}
+
+func readMessages(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var msgs []map[string]interface{}
+	r := bufio.NewReader(out)
+	for {
+		dt, err := ReadMessage(r)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(dt, &m))
+		msgs = append(msgs, m)
+	}
+	return msgs
+}
+
+func TestServerRequests(t *testing.T) {
+	s := NewServer(nil)
+	msgs := serve(t, s, []testRequest{
+		{"initialize", nil},
+		{"setBreakpoints", &SetBreakpointsArguments{
+			Source:      Source{Path: "/src/Dockerfile"},
+			Breakpoints: []SourceBreakpoint{{Line: 2}, {Line: 5}},
+		}},
+		{"threads", nil},
+		{"evaluate", &EvaluateArguments{Expression: "ls"}},
+		{"continue", nil},
+		{"disconnect", nil},
+	})
+	require.Equal(t, 7, len(msgs))
+
+	for i, m := range msgs {
+		require.Equal(t, float64(i+1), m["seq"])
+	}
+
+	require.Equal(t, "initialize", msgs[0]["command"])
+	require.Equal(t, true, msgs[0]["success"])
+	require.Equal(t, "initialized", msgs[1]["event"])
+
+	require.Equal(t, "setBreakpoints", msgs[2]["command"])
+	bps := msgs[2]["body"].(map[string]interface{})["breakpoints"].([]interface{})
+	require.Equal(t, 2, len(bps))
+	require.Equal(t, true, bps[0].(map[string]interface{})["verified"])
+	require.Equal(t, []int{2, 5}, s.breakpoints["/src/Dockerfile"])
+
+	require.Equal(t, "threads", msgs[3]["command"])
+	require.Equal(t, true, msgs[3]["success"])
+
+	require.Equal(t, "evaluate", msgs[4]["command"])
+	require.Equal(t, false, msgs[4]["success"])
+	require.Equal(t, "build is not paused", msgs[4]["message"])
+
+	require.Equal(t, "continue", msgs[5]["command"])
+	require.Equal(t, false, msgs[5]["success"])
+
+	require.Equal(t, "disconnect", msgs[6]["command"])
+	require.Equal(t, true, msgs[6]["success"])
+}
+
+func TestServerContinueAfterResponse(t *testing.T) {
+	s := NewServer(nil)
+	resume := make(chan bool, 1)
+	s.resume = resume
+
+	// stands in for the build pausing at the next step
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if step := <-resume; step {
+			s.sendEvent("stopped", &StoppedEventBody{Reason: "step", ThreadID: threadID})
+		}
+	}()
+
+	var in, out bytes.Buffer
+	require.NoError(t, WriteMessage(&in, Request{
+		ProtocolMessage: ProtocolMessage{Seq: 1, Type: "request"},
+		Command:         "next",
+	}))
+	require.NoError(t, s.Serve(context.TODO(), &in, &out))
+	<-done
+
+	msgs := readMessages(t, &out)
+	require.Equal(t, 2, len(msgs))
+	require.Equal(t, "next", msgs[0]["command"])
+	require.Equal(t, true, msgs[0]["success"])
+	require.Equal(t, "stopped", msgs[1]["event"])
+}
+
+func TestServerTerminateDuringEvaluate(t *testing.T) {
+	s := NewServer(nil)
+	p := newPausedStep(context.TODO(), nil, nil, &vertex{op: &pb.Op{}})
+	ctr := &testContainer{released: make(chan struct{})}
+	p.ctr = ctr
+	s.paused = p
+
+	msgs := serve(t, s, []testRequest{
+		{"evaluate", &EvaluateArguments{Expression: "sleep 1000"}},
+		{"terminate", nil},
+	})
+	require.Equal(t, 2, len(msgs))
+
+	require.Equal(t, "terminate", msgs[0]["command"])
+	require.Equal(t, true, msgs[0]["success"])
+
+	require.Equal(t, "evaluate", msgs[1]["command"])
+	require.Equal(t, false, msgs[1]["success"])
+	require.Equal(t, "cancelled", msgs[1]["message"])
+
+	// the command is stopped with the container
+	<-ctr.released
+}
+
+// testContainer runs processes that only exit when it is released.
+type testContainer struct {
+	released chan struct{}
+	once     sync.Once
+}
+
+func (c *testContainer) Start(context.Context, gateway.StartRequest) (gateway.ContainerProcess, error) {
+	return &testProcess{c}, nil
+}
+
+func (c *testContainer) Release(context.Context) error {
+	c.once.Do(func() { close(c.released) })
+	return nil
+}
+
+type testProcess struct {
+	c *testContainer
+}
+
+func (p *testProcess) Wait() error {
+	<-p.c.released
+	return errors.New("container released")
+}
+
+func (p *testProcess) Resize(context.Context, gateway.WinSize) error {
+	return nil
+}
+
+func TestServerRunSolvesEachStep(t *testing.T) {
+	sm := llb.NewSourceMap(nil, "Dockerfile", []byte("FROM busybox\nRUN foo\nRUN bar\nRUN baz\n"))
+
+	base := llb.Image("busybox", sm.Location(lineRange(1, 1)))
+	st := base.Run(llb.Shlex("foo"), sm.Location(lineRange(2, 2))).Root()
+	st = st.Run(llb.Shlex("bar"), sm.Location(lineRange(3, 3))).Root()
+	st = st.Run(llb.Shlex("baz"), sm.Location(lineRange(4, 4))).Root()
+
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+	g, err := newGraph(def.ToPB(), "/src")
+	require.NoError(t, err)
+
+	s := NewServer(nil)
+	s.launch = &LaunchArguments{}
+	c := &testClient{}
+	require.NoError(t, s.run(context.TODO(), c, g))
+
+	// every step is evaluated on its own, in order
+	require.Equal(t, 4, len(c.solved))
+	for i, dgst := range c.solved {
+		require.Equal(t, g.order[i].dgst, dgst)
+	}
+
+	// a failing step stops the build before the steps after it
+	c = &testClient{fail: g.order[2].dgst}
+	err = s.run(context.TODO(), c, g)
+	require.Error(t, err)
+	require.Equal(t, 3, len(c.solved))
+}
+
+// testClient records the last step of the definitions it solves.
+type testClient struct {
+	gateway.Client
+	solved []digest.Digest
+	fail   digest.Digest
+}
+
+func (c *testClient) Solve(ctx context.Context, req gateway.SolveRequest) (*gateway.Result, error) {
+	if !req.Evaluate {
+		return nil, errors.New("steps need to be evaluated")
+	}
+	var ret pb.Op
+	if err := (&ret).Unmarshal(req.Definition.Def[len(req.Definition.Def)-1]); err != nil {
+		return nil, err
+	}
+	dgst := ret.Inputs[0].Digest
+	c.solved = append(c.solved, dgst)
+	if dgst == c.fail {
+		return nil, errors.Errorf("failed to solve %s", dgst)
+	}
+	return gateway.NewResult(), nil
+}
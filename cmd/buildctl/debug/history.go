@@ -0,0 +1,113 @@
+package debug
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	bccommon "github.com/moby/buildkit/cmd/buildctl/common"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/tonistiigi/units"
+	"github.com/urfave/cli"
+)
+
+var HistoryCommand = cli.Command{
+	Name:      "history",
+	Usage:     "list finished builds, or show the steps and resource usage of one build",
+	ArgsUsage: "[ref]",
+	Action:    listHistory,
+}
+
+func listHistory(clicontext *cli.Context) error {
+	c, err := bccommon.ResolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	ref := clicontext.Args().First()
+	records, err := c.BuildHistory(commandContext(clicontext), ref)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	if ref == "" {
+		printHistoryTable(tw, records)
+	} else {
+		if len(records) == 0 {
+			return errors.Errorf("no history record for build %s", ref)
+		}
+		printHistoryRecord(tw, records[0])
+	}
+	return tw.Flush()
+}
+
+func printHistoryTable(tw *tabwriter.Writer, records []*client.BuildHistoryRecord) {
+	fmt.Fprintln(tw, "REF\tFRONTEND\tCREATED\tDURATION\tSTATUS")
+	for _, r := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Ref, r.Frontend, formatTime(r.CreatedAt), duration(r.CreatedAt, r.CompletedAt), historyStatus(r))
+	}
+}
+
+func printHistoryRecord(tw *tabwriter.Writer, r *client.BuildHistoryRecord) {
+	fmt.Fprintf(tw, "Ref:\t%s\n", r.Ref)
+	if r.Frontend != "" {
+		fmt.Fprintf(tw, "Frontend:\t%s\n", r.Frontend)
+	}
+	if r.Owner != "" {
+		fmt.Fprintf(tw, "Owner:\t%s\n", r.Owner)
+	}
+	fmt.Fprintf(tw, "Created at:\t%s\n", formatTime(r.CreatedAt))
+	fmt.Fprintf(tw, "Duration:\t%s\n", duration(r.CreatedAt, r.CompletedAt))
+	fmt.Fprintf(tw, "Status:\t%s\n", historyStatus(r))
+	if r.Error != "" {
+		fmt.Fprintf(tw, "Error:\t%s\n", r.Error)
+	}
+	fmt.Fprintln(tw)
+
+	resources := map[digest.Digest]*client.VertexResources{}
+	for _, res := range r.Resources {
+		resources[res.Vertex] = res
+	}
+	fmt.Fprintln(tw, "#\tDURATION\tCPU\tMEMORY PEAK\tIO READ\tIO WRITE\tPIDS PEAK\tNAME")
+	for i, v := range r.Vertexes {
+		d := duration(v.Started, v.Completed)
+		if v.Cached {
+			d = "CACHED"
+		}
+		cpu, mem, ioRead, ioWrite, pids := "-", "-", "-", "-", "-"
+		if res, ok := resources[v.Digest]; ok {
+			cpu = fmt.Sprintf("%.2fs", time.Duration(res.CPUNanos).Seconds())
+			mem = fmt.Sprintf("%.2f", units.Bytes(res.MemoryPeak))
+			ioRead = fmt.Sprintf("%.2f", units.Bytes(res.IOReadBytes))
+			ioWrite = fmt.Sprintf("%.2f", units.Bytes(res.IOWriteBytes))
+			pids = fmt.Sprintf("%d", res.PidsPeak)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, d, cpu, mem, ioRead, ioWrite, pids, strings.Replace(v.Name, "\t", " ", -1))
+	}
+}
+
+func historyStatus(r *client.BuildHistoryRecord) string {
+	if r.Error != "" {
+		return "failed"
+	}
+	return "completed"
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+func duration(start, end *time.Time) string {
+	if start == nil || end == nil {
+		return "-"
+	}
+	return end.Sub(*start).Round(time.Millisecond).String()
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/containerd/console"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/solver/errdefs"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// solveWithDebugShell builds like client.Solve but opens an interactive shell
+// on con in the container of the step that failed if the build fails.
+func solveWithDebugShell(ctx context.Context, c *client.Client, def *llb.Definition, opt client.SolveOpt, con console.Console, shell []string, statusChan chan *client.SolveStatus) (*client.SolveResponse, error) {
+	var cacheImports []gateway.CacheOptionsEntry
+	for _, im := range opt.CacheImports {
+		cacheImports = append(cacheImports, gateway.CacheOptionsEntry{
+			Type:  im.Type,
+			Attrs: im.Attrs,
+		})
+	}
+
+	req := gateway.SolveRequest{
+		Evaluate: true,
+	}
+	if def != nil {
+		req.Definition = def.ToPB()
+		req.CacheImports = cacheImports
+	} else {
+		frontendOpt := make(map[string]string, len(opt.FrontendAttrs)+1)
+		for k, v := range opt.FrontendAttrs {
+			frontendOpt[k] = v
+		}
+		// client.Solve only passes the cache imports to the frontend it calls
+		// itself
+		if len(cacheImports) > 0 {
+			dt, err := json.Marshal(cacheImports)
+			if err != nil {
+				return nil, err
+			}
+			frontendOpt["cache-imports"] = string(dt)
+		}
+		req.Frontend = opt.Frontend
+		req.FrontendOpt = frontendOpt
+	}
+
+	// the frontend is called from the build function
+	opt.Frontend = ""
+	opt.FrontendAttrs = nil
+
+	// the progress output would write into the terminal of the shell
+	var progress *pausedStatus
+	if statusChan != nil {
+		progress = newPausedStatus(statusChan)
+		statusChan = progress.in
+	}
+
+	return c.Build(ctx, opt, "buildctl", func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, req)
+		if err == nil {
+			return res, nil
+		}
+		var se *errdefs.SolveError
+		if !errors.As(err, &se) {
+			return nil, err
+		}
+		exec, ok := se.Solve.Op.GetOp().(*pb.Op_Exec)
+		if !ok {
+			return nil, err
+		}
+		progress.pause()
+		derr := debugShell(ctx, c, se, exec.Exec, con, shell)
+		progress.resume()
+		if derr != nil {
+			logrus.Warnf("failed to run debug shell: %v", derr)
+		}
+		return nil, err
+	}, statusChan)
+}
+
+// debugShell starts shell in a container with the mounts of the failed exec
+// and attaches con to it. The container is released when the shell exits.
+func debugShell(ctx context.Context, c gateway.Client, se *errdefs.SolveError, exec *pb.ExecOp, con console.Console, shell []string) error {
+	mounts, err := debugMounts(se, exec)
+	if err != nil {
+		return err
+	}
+
+	ctr, err := c.NewContainer(ctx, gateway.NewContainerRequest{
+		Mounts:      mounts,
+		NetMode:     exec.Network,
+		Platform:    se.Solve.Op.Platform,
+		Constraints: se.Solve.Op.Constraints,
+	})
+	if err != nil {
+		return err
+	}
+	defer ctr.Release(context.TODO())
+
+	fmt.Fprintf(con, "\nbuild failed, starting %v in the container of the failed step\nexit the shell to finish the build\n", shell)
+
+	if err := con.SetRaw(); err != nil {
+		return errors.Wrap(err, "failed to configure terminal")
+	}
+	defer con.Reset()
+
+	req := gateway.StartRequest{
+		Args:         shell,
+		Tty:          true,
+		Stdin:        io.NopCloser(con),
+		Stdout:       nopWriteCloser{con},
+		Stderr:       nopWriteCloser{con},
+		SecurityMode: exec.Security,
+	}
+	if meta := exec.Meta; meta != nil {
+		req.Env = meta.Env
+		req.User = meta.User
+		req.Cwd = meta.Cwd
+	}
+
+	proc, err := ctr.Start(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go watchConsoleSize(ctx, con, proc)
+
+	return proc.Wait()
+}
+
+// debugMounts returns the mounts of the failed exec with the results of the
+// mounts at the time of the failure.
+func debugMounts(se *errdefs.SolveError, exec *pb.ExecOp) ([]gateway.Mount, error) {
+	if len(se.Solve.MountIDs) != len(exec.Mounts) {
+		return nil, errors.Errorf("failed step has %d mounts but %d results", len(exec.Mounts), len(se.Solve.MountIDs))
+	}
+
+	mounts := make([]gateway.Mount, 0, len(exec.Mounts))
+	for i, m := range exec.Mounts {
+		mounts = append(mounts, gateway.Mount{
+			Selector:  m.Selector,
+			Dest:      m.Dest,
+			ResultID:  se.Solve.MountIDs[i],
+			Readonly:  m.Readonly,
+			MountType: m.MountType,
+			CacheOpt:  m.CacheOpt,
+			SecretOpt: m.SecretOpt,
+			SSHOpt:    m.SSHOpt,
+		})
+	}
+	return mounts, nil
+}
+
+// pausedStatus forwards the build progress to a status channel. The progress
+// is held back while it is paused and sent when it is resumed.
+type pausedStatus struct {
+	in     chan *client.SolveStatus
+	out    chan *client.SolveStatus
+	mu     sync.Mutex
+	paused bool
+	buf    []*client.SolveStatus
+}
+
+func newPausedStatus(out chan *client.SolveStatus) *pausedStatus {
+	p := &pausedStatus{
+		in:  make(chan *client.SolveStatus),
+		out: out,
+	}
+	go p.run()
+	return p
+}
+
+func (p *pausedStatus) run() {
+	for st := range p.in {
+		p.mu.Lock()
+		if p.paused {
+			p.buf = append(p.buf, st)
+		} else {
+			p.out <- st
+		}
+		p.mu.Unlock()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flush()
+	close(p.out)
+}
+
+func (p *pausedStatus) pause() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+}
+
+func (p *pausedStatus) resume() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.paused = false
+	p.flush()
+	p.mu.Unlock()
+}
+
+func (p *pausedStatus) flush() {
+	for _, st := range p.buf {
+		p.out <- st
+	}
+	p.buf = nil
+}
+
+func resizeProcess(ctx context.Context, con console.Console, proc gateway.ContainerProcess) {
+	size, err := con.Size()
+	if err != nil {
+		return
+	}
+	proc.Resize(ctx, gateway.WinSize{
+		Rows: uint32(size.Height),
+		Cols: uint32(size.Width),
+	})
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
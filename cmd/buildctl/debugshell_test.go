@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver/errdefs"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugMounts(t *testing.T) {
+	t.Parallel()
+
+	exec := &pb.ExecOp{
+		Mounts: []*pb.Mount{
+			{Dest: "/", Input: 0, Output: 0},
+			{Dest: "/src", Input: 1, Selector: "/sub", Readonly: true, Output: pb.SkipOutput},
+			{Dest: "/cache", Input: pb.Empty, Output: 1, MountType: pb.MountType_CACHE, CacheOpt: &pb.CacheOpt{ID: "foo"}},
+			{Dest: "/run/secrets/bar", Input: pb.Empty, Output: pb.SkipOutput, MountType: pb.MountType_SECRET, SecretOpt: &pb.SecretOpt{ID: "bar"}},
+		},
+	}
+	se := &errdefs.SolveError{
+		Solve: errdefs.Solve{
+			MountIDs: []string{"root", "src", "cache", ""},
+		},
+	}
+
+	mounts, err := debugMounts(se, exec)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(mounts))
+
+	require.Equal(t, "/", mounts[0].Dest)
+	require.Equal(t, "root", mounts[0].ResultID)
+	require.False(t, mounts[0].Readonly)
+
+	require.Equal(t, "/src", mounts[1].Dest)
+	require.Equal(t, "src", mounts[1].ResultID)
+	require.Equal(t, "/sub", mounts[1].Selector)
+	require.True(t, mounts[1].Readonly)
+
+	require.Equal(t, "cache", mounts[2].ResultID)
+	require.Equal(t, pb.MountType_CACHE, mounts[2].MountType)
+	require.Equal(t, "foo", mounts[2].CacheOpt.ID)
+
+	require.Equal(t, "", mounts[3].ResultID)
+	require.Equal(t, pb.MountType_SECRET, mounts[3].MountType)
+	require.Equal(t, "bar", mounts[3].SecretOpt.ID)
+
+	se.Solve.MountIDs = se.Solve.MountIDs[:3]
+	_, err = debugMounts(se, exec)
+	require.Error(t, err)
+}
+
+func TestPausedStatus(t *testing.T) {
+	t.Parallel()
+
+	out := make(chan *client.SolveStatus, 10)
+	p := newPausedStatus(out)
+
+	st1 := &client.SolveStatus{}
+	p.in <- st1
+	require.Equal(t, st1, <-out)
+
+	p.pause()
+	st2 := &client.SolveStatus{}
+	p.in <- st2
+	st3 := &client.SolveStatus{}
+	p.in <- st3
+	require.Equal(t, 0, len(out))
+
+	p.resume()
+	require.Equal(t, st2, <-out)
+	require.Equal(t, st3, <-out)
+
+	p.pause()
+	st4 := &client.SolveStatus{}
+	p.in <- st4
+	close(p.in)
+
+	// the held back progress is sent before the channel is closed
+	require.Equal(t, st4, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+}
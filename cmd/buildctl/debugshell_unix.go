@@ -0,0 +1,47 @@
+// +build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/containerd/console"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/pkg/errors"
+)
+
+// watchConsoleSize resizes proc whenever the size of con changes until ctx
+// is done.
+func watchConsoleSize(ctx context.Context, con console.Console, proc gateway.ContainerProcess) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	defer signal.Stop(ch)
+
+	resizeProcess(ctx, con, proc)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			resizeProcess(ctx, con, proc)
+		}
+	}
+}
+
+// openDebugConsole opens the controlling terminal of the process. Stdin can't
+// be used as it may carry the LLB definition.
+func openDebugConsole() (console.Console, func() error, error) {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "--debug-on-failure requires a terminal")
+	}
+	con, err := console.ConsoleFromFile(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, errors.Wrap(err, "--debug-on-failure requires a terminal")
+	}
+	return con, f.Close, nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/containerd/console"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/pkg/errors"
+)
+
+// watchConsoleSize sets the initial size of proc. Windows consoles don't
+// notify about size changes.
+func watchConsoleSize(ctx context.Context, con console.Console, proc gateway.ContainerProcess) {
+	resizeProcess(ctx, con, proc)
+}
+
+// openDebugConsole returns the console of the process.
+func openDebugConsole() (console.Console, func() error, error) {
+	con, err := console.ConsoleFromFile(os.Stdin)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "--debug-on-failure requires a terminal")
+	}
+	return con, func() error { return nil }, nil
+}
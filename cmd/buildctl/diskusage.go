@@ -21,6 +21,10 @@ var diskUsageCommand = cli.Command{
 			Name:  "filter, f",
 			Usage: "Filter records",
 		},
+		cli.StringFlag{
+			Name:  "cache-namespace",
+			Usage: "Only list records of a cache namespace",
+		},
 		cli.BoolFlag{
 			Name:  "verbose, v",
 			Usage: "Verbose output",
@@ -34,7 +38,7 @@ func diskUsage(clicontext *cli.Context) error {
 		return err
 	}
 
-	du, err := c.DiskUsage(bccommon.CommandContext(clicontext), client.WithFilter(clicontext.StringSlice("filter")))
+	du, err := c.DiskUsage(bccommon.CommandContext(clicontext), client.WithFilter(clicontext.StringSlice("filter")), client.WithCacheNamespace(clicontext.String("cache-namespace")))
 	if err != nil {
 		return err
 	}
@@ -79,6 +83,9 @@ func printVerbose(tw *tabwriter.Writer, du []*client.UsageInfo) {
 		if di.RecordType != "" {
 			printKV(tw, "Type", di.RecordType)
 		}
+		if di.CacheMountID != "" {
+			printKV(tw, "Cache mount ID", di.CacheMountID)
+		}
 
 		fmt.Fprintf(tw, "\n")
 	}
@@ -80,6 +80,11 @@ func main() {
 			Usage: "directory containing CA certificate, client certificate, and client key",
 			Value: "",
 		},
+		cli.StringFlag{
+			Name:  "tokenfile",
+			Usage: "file containing a bearer token for authentication",
+			Value: "",
+		},
 		cli.IntFlag{
 			Name:  "timeout",
 			Usage: "timeout backend connection after value seconds",
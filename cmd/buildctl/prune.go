@@ -28,6 +28,10 @@ var pruneCommand = cli.Command{
 			Name:  "filter, f",
 			Usage: "Filter records",
 		},
+		cli.StringFlag{
+			Name:  "cache-namespace",
+			Usage: "Only prune records of a cache namespace",
+		},
 		cli.BoolFlag{
 			Name:  "all",
 			Usage: "Include internal/frontend references",
@@ -72,6 +76,7 @@ func prune(clicontext *cli.Context) error {
 	opts := []client.PruneOption{
 		client.WithFilter(clicontext.StringSlice("filter")),
 		client.WithKeepOpt(clicontext.Duration("keep-duration"), int64(clicontext.Float64("keep-storage")*1e6)),
+		client.WithCacheNamespace(clicontext.String("cache-namespace")),
 	}
 
 	if clicontext.Bool("all") {
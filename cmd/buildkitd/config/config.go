@@ -11,7 +11,7 @@ type Config struct {
 	// Root is the path to a directory where buildkit will store persistent data
 	Root string `toml:"root"`
 
-	// Entitlements e.g. security.insecure, network.host
+	// Entitlements e.g. security.insecure, network.host, device
 	Entitlements []string `toml:"insecure-entitlements"`
 	// GRPC configuration settings
 	GRPC GRPCConfig `toml:"grpc"`
@@ -24,6 +24,8 @@ type Config struct {
 	Registries map[string]resolverconfig.RegistryConfig `toml:"registry"`
 
 	DNS *DNSConfig `toml:"dns"`
+
+	CDI CDIConfig `toml:"cdi"`
 }
 
 type GRPCConfig struct {
@@ -32,7 +34,8 @@ type GRPCConfig struct {
 	UID          *int     `toml:"uid"`
 	GID          *int     `toml:"gid"`
 
-	TLS TLSConfig `toml:"tls"`
+	TLS  TLSConfig  `toml:"tls"`
+	Auth AuthConfig `toml:"auth"`
 	// MaxRecvMsgSize int    `toml:"max_recv_message_size"`
 	// MaxSendMsgSize int    `toml:"max_send_message_size"`
 }
@@ -43,6 +46,40 @@ type TLSConfig struct {
 	CA   string `toml:"ca"`
 }
 
+// AuthConfig maps client identities to roles that restrict the operations
+// the clients are allowed to perform. Authorization is disabled if no
+// identities or default role are configured.
+type AuthConfig struct {
+	// DefaultRole is assigned to clients that do not match any identity.
+	// If empty, such clients are rejected.
+	DefaultRole string                `toml:"defaultRole"`
+	Roles       map[string]RoleConfig `toml:"roles"`
+	Identities  []IdentityConfig      `toml:"identity"`
+}
+
+type RoleConfig struct {
+	Admin        bool     `toml:"admin"`
+	Entitlements []string `toml:"entitlements"`
+	Prune        bool     `toml:"prune"`
+	DiskUsage    bool     `toml:"diskUsage"`
+	// CacheNamespaces the clients of the role have to use. All namespaces
+	// are allowed if empty.
+	CacheNamespaces []string `toml:"cacheNamespaces"`
+}
+
+type IdentityConfig struct {
+	Name        string   `toml:"name"`
+	Role        string   `toml:"role"`
+	CommonNames []string `toml:"commonNames"`
+	SANs        []string `toml:"sans"`
+	// TokenDigests are digests of bearer tokens, e.g. sha256:<hex>
+	TokenDigests []string `toml:"tokenDigests"`
+}
+
+func (c AuthConfig) Enabled() bool {
+	return len(c.Identities) > 0 || c.DefaultRole != ""
+}
+
 type GCConfig struct {
 	GC            *bool      `toml:"gc"`
 	GCKeepStorage int64      `toml:"gckeepstorage"`
@@ -64,8 +101,11 @@ type OCIConfig struct {
 	NoProcessSandbox bool              `toml:"noProcessSandbox"`
 	GCConfig
 	NetworkConfig
-	// UserRemapUnsupported is unsupported key for testing. The feature is
-	// incomplete and the intention is to make it default without config.
+	// UsernsRemap runs the build containers in a user namespace mapped to
+	// the subordinate ID ranges of the named user in /etc/subuid and
+	// /etc/subgid.
+	UsernsRemap string `toml:"usernsRemap"`
+	// UserRemapUnsupported is deprecated. Use UsernsRemap instead.
 	UserRemapUnsupported string `toml:"userRemapUnsupported"`
 	// For use in storing the OCI worker binary name that will replace buildkit-runc
 	Binary               string `toml:"binary"`
@@ -93,6 +133,11 @@ type ContainerdConfig struct {
 	GCConfig
 	NetworkConfig
 	Snapshotter string `toml:"snapshotter"`
+	// RemoteSnapshotter enables lazy pulling of eStargz and zstd:chunked
+	// layers. The snapshotter needs to be a remote snapshotter, e.g. the
+	// stargz snapshotter configured as a proxy plugin of containerd.
+	// Snapshotters named "stargz" are always used as remote snapshotters.
+	RemoteSnapshotter bool `toml:"remoteSnapshotter"`
 
 	// ApparmorProfile is the name of the apparmor profile that should be used to constrain build containers.
 	// The profile should already be loaded (by a higher level system) before creating a worker.
@@ -108,6 +153,15 @@ type GCPolicy struct {
 	Filters      []string `toml:"filters"`
 }
 
+// CDIConfig configures the Container Device Interface devices that can be
+// exposed to build containers.
+type CDIConfig struct {
+	Disabled bool `toml:"disabled"`
+	// SpecDirs are the directories CDI specs are loaded from. Defaults to
+	// /etc/cdi and /var/run/cdi.
+	SpecDirs []string `toml:"specDirs"`
+}
+
 type DNSConfig struct {
 	Nameservers   []string `toml:"nameservers"`
 	Options       []string `toml:"options"`
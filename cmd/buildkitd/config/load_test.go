@@ -24,11 +24,26 @@ debugAddress="debug.sock"
 gid=1234
 [grpc.tls]
 cert="mycert.pem"
+[grpc.auth]
+defaultRole="reader"
+[grpc.auth.roles.admin]
+admin=true
+[grpc.auth.roles.reader]
+entitlements=["network.host"]
+diskUsage=true
+cacheNamespaces=["team-a"]
+[[grpc.auth.identity]]
+name="ci"
+role="admin"
+commonNames=["ci.example.com"]
+sans=["spiffe://example.com/ci"]
+tokenDigests=["sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"]
 
 [worker.oci]
 enabled=true
 snapshotter="overlay"
 rootless=true
+usernsRemap="user"
 gc=false
 gckeepstorage=123456789
 [worker.oci.labels]
@@ -62,6 +77,9 @@ cert="cert.pem"
 nameservers=["1.1.1.1","8.8.8.8"]
 options=["edns0"]
 searchDomains=["example.com"]
+
+[cdi]
+specDirs=["/etc/cdi", "/etc/buildkit/cdi"]
 `
 
 	cfg, err := Load(bytes.NewBuffer([]byte(testConfig)))
@@ -78,11 +96,27 @@ searchDomains=["example.com"]
 	require.Equal(t, 1234, *cfg.GRPC.GID)
 	require.Equal(t, "mycert.pem", cfg.GRPC.TLS.Cert)
 
+	require.True(t, cfg.GRPC.Auth.Enabled())
+	require.Equal(t, "reader", cfg.GRPC.Auth.DefaultRole)
+	require.Equal(t, 2, len(cfg.GRPC.Auth.Roles))
+	require.True(t, cfg.GRPC.Auth.Roles["admin"].Admin)
+	require.Equal(t, []string{"network.host"}, cfg.GRPC.Auth.Roles["reader"].Entitlements)
+	require.True(t, cfg.GRPC.Auth.Roles["reader"].DiskUsage)
+	require.False(t, cfg.GRPC.Auth.Roles["reader"].Prune)
+	require.Equal(t, []string{"team-a"}, cfg.GRPC.Auth.Roles["reader"].CacheNamespaces)
+	require.Equal(t, 1, len(cfg.GRPC.Auth.Identities))
+	require.Equal(t, "ci", cfg.GRPC.Auth.Identities[0].Name)
+	require.Equal(t, "admin", cfg.GRPC.Auth.Identities[0].Role)
+	require.Equal(t, []string{"ci.example.com"}, cfg.GRPC.Auth.Identities[0].CommonNames)
+	require.Equal(t, []string{"spiffe://example.com/ci"}, cfg.GRPC.Auth.Identities[0].SANs)
+	require.Equal(t, 1, len(cfg.GRPC.Auth.Identities[0].TokenDigests))
+
 	require.NotNil(t, cfg.Workers.OCI.Enabled)
 	require.Equal(t, int64(123456789), cfg.Workers.OCI.GCKeepStorage)
 	require.Equal(t, true, *cfg.Workers.OCI.Enabled)
 	require.Equal(t, "overlay", cfg.Workers.OCI.Snapshotter)
 	require.Equal(t, true, cfg.Workers.OCI.Rootless)
+	require.Equal(t, "user", cfg.Workers.OCI.UsernsRemap)
 	require.Equal(t, false, *cfg.Workers.OCI.GC)
 
 	require.Equal(t, "bar", cfg.Workers.OCI.Labels["foo"])
@@ -118,4 +152,7 @@ searchDomains=["example.com"]
 	require.Equal(t, cfg.DNS.Nameservers, []string{"1.1.1.1", "8.8.8.8"})
 	require.Equal(t, cfg.DNS.SearchDomains, []string{"example.com"})
 	require.Equal(t, cfg.DNS.Options, []string{"edns0"})
+
+	require.False(t, cfg.CDI.Disabled)
+	require.Equal(t, []string{"/etc/cdi", "/etc/buildkit/cdi"}, cfg.CDI.SpecDirs)
 }
@@ -34,18 +34,22 @@ import (
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/cmd/buildkitd/config"
 	"github.com/moby/buildkit/control"
+	"github.com/moby/buildkit/control/authz"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/frontend"
 	dockerfile "github.com/moby/buildkit/frontend/dockerfile/builder"
 	"github.com/moby/buildkit/frontend/gateway"
 	"github.com/moby/buildkit/frontend/gateway/forwarder"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/bboltcachestorage"
 	"github.com/moby/buildkit/util/apicaps"
 	"github.com/moby/buildkit/util/appcontext"
 	"github.com/moby/buildkit/util/appdefaults"
 	"github.com/moby/buildkit/util/archutil"
 	"github.com/moby/buildkit/util/bklog"
+	"github.com/moby/buildkit/util/cdidevices"
+	"github.com/moby/buildkit/util/entitlements"
 	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/moby/buildkit/util/profiler"
 	"github.com/moby/buildkit/util/resolver"
@@ -56,10 +60,12 @@ import (
 	"github.com/moby/buildkit/util/tracing/transform"
 	"github.com/moby/buildkit/version"
 	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	bolt "go.etcd.io/bbolt"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -88,6 +94,7 @@ type workerInitializerOpt struct {
 	config         *config.Config
 	sessionManager *session.Manager
 	traceSocket    string
+	cdiManager     *cdidevices.Manager
 }
 
 type workerInitializer struct {
@@ -192,7 +199,7 @@ func main() {
 		},
 		cli.StringSliceFlag{
 			Name:  "allow-insecure-entitlement",
-			Usage: "allows insecure entitlements e.g. network.host, security.insecure",
+			Usage: "allows insecure entitlements e.g. network.host, security.insecure, device",
 		},
 	)
 	app.Flags = append(app.Flags, appFlags...)
@@ -234,8 +241,20 @@ func main() {
 
 		streamTracer := otelgrpc.StreamServerInterceptor(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPropagators(propagators))
 
-		unary := grpc_middleware.ChainUnaryServer(unaryInterceptor(ctx, tp), grpcerrors.UnaryServerInterceptor)
-		stream := grpc_middleware.ChainStreamServer(streamTracer, grpcerrors.StreamServerInterceptor)
+		unaryInterceptors := []grpc.UnaryServerInterceptor{unaryInterceptor(ctx, tp), grpcerrors.UnaryServerInterceptor}
+		streamInterceptors := []grpc.StreamServerInterceptor{streamTracer, grpcerrors.StreamServerInterceptor}
+
+		authorizer, err := newAuthorizer(cfg.GRPC.Auth)
+		if err != nil {
+			return err
+		}
+		if authorizer != nil {
+			unaryInterceptors = append(unaryInterceptors, authorizer.UnaryServerInterceptor)
+			streamInterceptors = append(streamInterceptors, authorizer.StreamServerInterceptor)
+		}
+
+		unary := grpc_middleware.ChainUnaryServer(unaryInterceptors...)
+		stream := grpc_middleware.ChainStreamServer(streamInterceptors...)
 
 		opts := []grpc.ServerOption{grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream)}
 		server := grpc.NewServer(opts...)
@@ -281,6 +300,8 @@ func main() {
 					cfg.Entitlements = append(cfg.Entitlements, e)
 				case "network.host":
 					cfg.Entitlements = append(cfg.Entitlements, e)
+				case "device":
+					cfg.Entitlements = append(cfg.Entitlements, e)
 				default:
 					return fmt.Errorf("invalid entitlement : %v", e)
 				}
@@ -578,6 +599,72 @@ func unaryInterceptor(globalCtx context.Context, tp trace.TracerProvider) grpc.U
 	}
 }
 
+func newAuthorizer(cfg config.AuthConfig) (*authz.Authorizer, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	roles := map[string]*authz.Role{}
+	for name, rc := range cfg.Roles {
+		ents := make([]entitlements.Entitlement, 0, len(rc.Entitlements))
+		for _, e := range rc.Entitlements {
+			ents = append(ents, entitlements.Entitlement(e))
+		}
+		set, err := entitlements.WhiteList(ents, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid entitlements for role %s", name)
+		}
+		for _, ns := range rc.CacheNamespaces {
+			if ns == "" {
+				continue
+			}
+			if err := solver.ValidateCacheNamespace(ns); err != nil {
+				return nil, errors.Wrapf(err, "invalid cache namespaces for role %s", name)
+			}
+		}
+		roles[name] = &authz.Role{
+			Name:            name,
+			Admin:           rc.Admin,
+			Entitlements:    set,
+			Prune:           rc.Prune,
+			DiskUsage:       rc.DiskUsage,
+			CacheNamespaces: rc.CacheNamespaces,
+		}
+	}
+
+	var opt authz.Opt
+	if cfg.DefaultRole != "" {
+		role, ok := roles[cfg.DefaultRole]
+		if !ok {
+			return nil, errors.Errorf("default role %s is not defined", cfg.DefaultRole)
+		}
+		opt.DefaultRole = role
+	}
+
+	for _, ic := range cfg.Identities {
+		role, ok := roles[ic.Role]
+		if !ok {
+			return nil, errors.Errorf("role %s of identity %s is not defined", ic.Role, ic.Name)
+		}
+		s := authz.Subject{
+			Name:        ic.Name,
+			Role:        role,
+			CommonNames: ic.CommonNames,
+			SANs:        ic.SANs,
+		}
+		for _, d := range ic.TokenDigests {
+			dgst, err := digest.Parse(d)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid token digest for identity %s", ic.Name)
+			}
+			s.TokenDigests = append(s.TokenDigests, dgst)
+		}
+		opt.Subjects = append(opt.Subjects, s)
+	}
+
+	return authz.New(opt)
+}
+
 func serverCredentials(cfg config.TLSConfig) (*tls.Config, error) {
 	certFile := cfg.Cert
 	keyFile := cfg.Key
@@ -634,10 +721,19 @@ func newController(c *cli.Context, cfg *config.Config) (*control.Controller, err
 		}
 	}
 
+	var cdiManager *cdidevices.Manager
+	if !cfg.CDI.Disabled {
+		cdiManager, err = cdidevices.NewManager(cfg.CDI.SpecDirs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	wc, err := newWorkerController(c, workerInitializerOpt{
 		config:         cfg,
 		sessionManager: sessionManager,
 		traceSocket:    traceSocket,
+		cdiManager:     cdiManager,
 	})
 	if err != nil {
 		return nil, err
@@ -651,6 +747,11 @@ func newController(c *cli.Context, cfg *config.Config) (*control.Controller, err
 		return nil, err
 	}
 
+	historyDB, err := bolt.Open(filepath.Join(cfg.Root, "history.db"), 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	resolverFn := resolverFunc(cfg)
 
 	w, err := wc.GetDefault()
@@ -679,6 +780,7 @@ func newController(c *cli.Context, cfg *config.Config) (*control.Controller, err
 		CacheKeyStorage:           cacheStorage,
 		Entitlements:              cfg.Entitlements,
 		TraceCollector:            tc,
+		HistoryDB:                 historyDB,
 	})
 }
 
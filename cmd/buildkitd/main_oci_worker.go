@@ -31,6 +31,7 @@ import (
 	"github.com/moby/buildkit/cmd/buildkitd/config"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/fairqueue"
 	"github.com/moby/buildkit/util/network/cniprovider"
 	"github.com/moby/buildkit/util/network/netproviders"
 	"github.com/moby/buildkit/util/resolver"
@@ -41,7 +42,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
-	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 )
@@ -121,6 +121,11 @@ func init() {
 			Usage: u,
 		})
 	}
+	flags = append(flags, cli.StringFlag{
+		Name:  "oci-worker-userns-remap",
+		Usage: "run build containers in a user namespace mapped to the subuid/subgid ranges of a user",
+		Value: defaultConf.Workers.OCI.UsernsRemap,
+	})
 	flags = append(flags, cli.BoolFlag{
 		Name:  "oci-worker-no-process-sandbox",
 		Usage: "use the host PID namespace and procfs (WARNING: allows build containers to kill (and potentially ptrace) an arbitrary process in the host namespace)",
@@ -194,6 +199,9 @@ func applyOCIFlags(c *cli.Context, cfg *config.Config) error {
 		}
 		cfg.Workers.OCI.Rootless = c.GlobalBool("oci-worker-rootless")
 	}
+	if c.GlobalIsSet("oci-worker-userns-remap") {
+		cfg.Workers.OCI.UsernsRemap = c.GlobalString("oci-worker-userns-remap")
+	}
 	if c.GlobalIsSet("oci-worker-no-process-sandbox") {
 		cfg.Workers.OCI.NoProcessSandbox = c.GlobalBool("oci-worker-no-process-sandbox")
 	}
@@ -243,12 +251,27 @@ func ociWorkerInitializer(c *cli.Context, common workerInitializerOpt) ([]worker
 		return nil, nil
 	}
 
-	// TODO: this should never change the existing state dir
-	idmapping, err := parseIdentityMapping(cfg.UserRemapUnsupported)
+	usernsRemap := cfg.UsernsRemap
+	if usernsRemap == "" && cfg.UserRemapUnsupported != "" {
+		logrus.Warn("userRemapUnsupported is deprecated, use usernsRemap instead")
+		usernsRemap = cfg.UserRemapUnsupported
+	}
+	idmapping, err := parseIdentityMapping(usernsRemap)
 	if err != nil {
 		return nil, err
 	}
 
+	root := common.config.Root
+	if idmapping != nil {
+		if cfg.Rootless {
+			return nil, errors.New("userns remap is not supported in rootless mode")
+		}
+		root, err = remappedRoot(root, idmapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	hosts := resolverFunc(common.config)
 	snFactory, err := snapshotterFactory(common.config.Root, cfg, common.sessionManager, hosts)
 	if err != nil {
@@ -282,12 +305,12 @@ func ociWorkerInitializer(c *cli.Context, common workerInitializerOpt) ([]worker
 		},
 	}
 
-	var parallelismSem *semaphore.Weighted
+	var parallelismSem *fairqueue.Queue
 	if cfg.MaxParallelism > 0 {
-		parallelismSem = semaphore.NewWeighted(int64(cfg.MaxParallelism))
+		parallelismSem = fairqueue.New(cfg.MaxParallelism)
 	}
 
-	opt, err := runc.NewWorkerOpt(common.config.Root, snFactory, cfg.Rootless, processMode, cfg.Labels, idmapping, nc, dns, cfg.Binary, cfg.ApparmorProfile, parallelismSem, common.traceSocket)
+	opt, err := runc.NewWorkerOpt(root, snFactory, cfg.Rootless, processMode, cfg.Labels, idmapping, nc, dns, cfg.Binary, cfg.ApparmorProfile, parallelismSem, common.traceSocket, common.cdiManager)
 	if err != nil {
 		return nil, err
 	}
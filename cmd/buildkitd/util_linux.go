@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/docker/docker/pkg/idtools"
@@ -14,7 +17,7 @@ func parseIdentityMapping(str string) (*idtools.IdentityMapping, error) {
 	}
 
 	idparts := strings.SplitN(str, ":", 3)
-	if len(idparts) > 2 {
+	if len(idparts) > 2 || idparts[0] == "" {
 		return nil, errors.Errorf("invalid userns remap specification in %q", str)
 	}
 
@@ -27,5 +30,22 @@ func parseIdentityMapping(str string) (*idtools.IdentityMapping, error) {
 		return nil, errors.Wrap(err, "failed to create ID mappings")
 	}
 	return mappings, nil
+}
 
+// remappedRoot returns the state directory for a worker with remapped IDs.
+// Files in the state directory are owned by the remapped IDs, so it is kept
+// separate from the state of a worker without remapping. The parent
+// directories need to be traversable by the remapped root user.
+func remappedRoot(root string, idmap *idtools.IdentityMapping) (string, error) {
+	rootPair := idmap.RootPair()
+	remapped := filepath.Join(root, fmt.Sprintf("%d.%d", rootPair.UID, rootPair.GID))
+	if err := os.MkdirAll(remapped, 0700); err != nil {
+		return "", errors.WithStack(err)
+	}
+	for _, dir := range []string{root, remapped} {
+		if err := os.Chmod(dir, 0711); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	return remapped, nil
 }
@@ -13,3 +13,7 @@ func parseIdentityMapping(str string) (*idtools.IdentityMapping, error) {
 	}
 	return nil, errors.New("user namespaces are only supported on linux")
 }
+
+func remappedRoot(root string, idmap *idtools.IdentityMapping) (string, error) {
+	return "", errors.New("user namespaces are only supported on linux")
+}
@@ -0,0 +1,293 @@
+// Package authz implements per-client authentication and authorization for
+// the buildkitd gRPC API.
+package authz
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/util/entitlements"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	methodSolve     = "/moby.buildkit.v1.Control/Solve"
+	methodPrune     = "/moby.buildkit.v1.Control/Prune"
+	methodDiskUsage = "/moby.buildkit.v1.Control/DiskUsage"
+
+	healthPrefix = "/grpc.health.v1.Health/"
+)
+
+// Role defines the operations a client is allowed to perform.
+type Role struct {
+	Name string
+	// Admin allows all operations, including access to builds of other
+	// identities.
+	Admin bool
+	// Entitlements that builds of the role may request. The daemon
+	// configuration still needs to allow them.
+	Entitlements entitlements.Set
+	// Prune allows removing build cache.
+	Prune bool
+	// DiskUsage allows listing build cache records.
+	DiskUsage bool
+	// CacheNamespaces restricts the cache namespaces the role may use. If
+	// empty, all namespaces are allowed.
+	CacheNamespaces []string
+}
+
+// Identity is an authenticated client.
+type Identity struct {
+	Name string
+	Role *Role
+}
+
+// Owns returns true if the identity may access a build started by owner.
+func (i *Identity) Owns(owner *Identity) bool {
+	if i == nil || i.Role.Admin {
+		return true
+	}
+	return owner != nil && owner.Name == i.Name
+}
+
+// AllowsCacheNamespace returns true if the identity may use cache namespace
+// ns. The default namespace is not allowed for roles restricted to specific
+// namespaces.
+func (i *Identity) AllowsCacheNamespace(ns string) bool {
+	if i == nil || i.Role.Admin || len(i.Role.CacheNamespaces) == 0 {
+		return true
+	}
+	for _, n := range i.Role.CacheNamespaces {
+		if n == ns {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Identity) String() string {
+	if i.Name == "" {
+		return "anonymous client"
+	}
+	return "identity " + i.Name
+}
+
+// Subject maps client credentials to an identity.
+type Subject struct {
+	Name string
+	Role *Role
+	// CommonNames are matched against the subject common name of the
+	// client certificate.
+	CommonNames []string
+	// SANs are matched against the DNS, email, IP and URI subject
+	// alternative names of the client certificate.
+	SANs []string
+	// TokenDigests are matched against the digest of the bearer token
+	// sent in the authorization metadata.
+	TokenDigests []digest.Digest
+}
+
+type Opt struct {
+	Subjects []Subject
+	// DefaultRole is assigned to clients that do not match any subject. If
+	// nil, such clients are rejected.
+	DefaultRole *Role
+}
+
+type Authorizer struct {
+	subjects    []Subject
+	defaultRole *Role
+}
+
+func New(opt Opt) (*Authorizer, error) {
+	for _, s := range opt.Subjects {
+		if s.Name == "" {
+			return nil, errors.New("identity name must be set")
+		}
+		if s.Role == nil {
+			return nil, errors.Errorf("no role set for identity %s", s.Name)
+		}
+		if len(s.CommonNames) == 0 && len(s.SANs) == 0 && len(s.TokenDigests) == 0 {
+			return nil, errors.Errorf("no credentials set for identity %s", s.Name)
+		}
+		for _, dgst := range s.TokenDigests {
+			if err := dgst.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "invalid token digest for identity %s", s.Name)
+			}
+			if !dgst.Algorithm().Available() {
+				return nil, errors.Errorf("unsupported token digest algorithm %s for identity %s", dgst.Algorithm(), s.Name)
+			}
+		}
+	}
+	return &Authorizer{subjects: opt.Subjects, defaultRole: opt.DefaultRole}, nil
+}
+
+// Authenticate returns the identity of the client that made the request.
+func (a *Authorizer) Authenticate(ctx context.Context) (*Identity, error) {
+	if token, ok := bearerToken(ctx); ok {
+		for _, s := range a.subjects {
+			for _, d := range s.TokenDigests {
+				if d.Algorithm().Available() && d == d.Algorithm().FromString(token) {
+					return &Identity{Name: s.Name, Role: s.Role}, nil
+				}
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+
+	if cert := peerCertificate(ctx); cert != nil {
+		for _, s := range a.subjects {
+			if matchCertificate(cert, s) {
+				return &Identity{Name: s.Name, Role: s.Role}, nil
+			}
+		}
+	}
+
+	if a.defaultRole == nil {
+		return nil, status.Error(codes.Unauthenticated, "client is not authorized to access buildkitd")
+	}
+	return &Identity{Name: "", Role: a.defaultRole}, nil
+}
+
+// Authorize checks if identity is allowed to call method with req. Checks
+// that depend on state of the daemon, like the owner of a build, are done by
+// the service handlers.
+func Authorize(id *Identity, method string, req interface{}) error {
+	if id.Role.Admin {
+		return nil
+	}
+	switch method {
+	case methodPrune:
+		if !id.Role.Prune {
+			return status.Errorf(codes.PermissionDenied, "%s is not allowed to prune build cache", id)
+		}
+	case methodDiskUsage:
+		if !id.Role.DiskUsage {
+			return status.Errorf(codes.PermissionDenied, "%s is not allowed to list build cache", id)
+		}
+	case methodSolve:
+		if req, ok := req.(*controlapi.SolveRequest); ok {
+			for _, e := range req.Entitlements {
+				if !id.Role.Entitlements.Allowed(e) {
+					return status.Errorf(codes.PermissionDenied, "%s is not allowed to grant entitlement %s", id, e)
+				}
+			}
+			// builds can't take workers from builds of other identities
+			if req.Priority > 0 {
+				return status.Errorf(codes.PermissionDenied, "%s is not allowed to raise build priority", id)
+			}
+			if req.Weight > 1 {
+				return status.Errorf(codes.PermissionDenied, "%s is not allowed to raise build weight", id)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Authorizer) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, healthPrefix) {
+		return handler(ctx, req)
+	}
+	id, err := a.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(id, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(WithIdentity(ctx, id), req)
+}
+
+func (a *Authorizer) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if strings.HasPrefix(info.FullMethod, healthPrefix) {
+		return handler(srv, ss)
+	}
+	id, err := a.Authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	if err := Authorize(id, info.FullMethod, nil); err != nil {
+		return err
+	}
+	wrapped := grpc_middleware.WrapServerStream(ss)
+	wrapped.WrappedContext = WithIdentity(ss.Context(), id)
+	return handler(srv, wrapped)
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a context carrying the authenticated identity.
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the authenticated identity of the request. It returns
+// nil if authorization is not enabled.
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for _, v := range md.Get("authorization") {
+		if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+			return strings.TrimSpace(v[7:]), true
+		}
+	}
+	return "", false
+}
+
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	// only trust certificates that were verified against the configured CA
+	if len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return tlsInfo.State.VerifiedChains[0][0]
+}
+
+func matchCertificate(cert *x509.Certificate, s Subject) bool {
+	for _, cn := range s.CommonNames {
+		if cn == cert.Subject.CommonName {
+			return true
+		}
+	}
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	for _, want := range s.SANs {
+		for _, san := range sans {
+			if want == san {
+				return true
+			}
+		}
+	}
+	return false
+}
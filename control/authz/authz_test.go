@@ -0,0 +1,189 @@
+package authz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/util/entitlements"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	adminRole   = &Role{Name: "admin", Admin: true}
+	builderRole = &Role{Name: "builder", Entitlements: entitlements.Set{entitlements.EntitlementNetworkHost: {}}, DiskUsage: true}
+	readerRole  = &Role{Name: "reader"}
+)
+
+func newTestAuthorizer(t *testing.T, defaultRole *Role) *Authorizer {
+	a, err := New(Opt{
+		Subjects: []Subject{
+			{Name: "ci", Role: builderRole, CommonNames: []string{"ci.example.com"}},
+			{Name: "ops", Role: adminRole, SANs: []string{"spiffe://example.com/ops"}},
+			{Name: "bot", Role: builderRole, TokenDigests: []digest.Digest{digest.FromString("secret")}},
+		},
+		DefaultRole: defaultRole,
+	})
+	require.NoError(t, err)
+	return a
+}
+
+func withCert(ctx context.Context, cert *x509.Certificate) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	a := newTestAuthorizer(t, nil)
+	ctx := context.TODO()
+
+	id, err := a.Authenticate(withCert(ctx, &x509.Certificate{Subject: pkix.Name{CommonName: "ci.example.com"}}))
+	require.NoError(t, err)
+	require.Equal(t, "ci", id.Name)
+	require.Equal(t, builderRole, id.Role)
+
+	u, err := url.Parse("spiffe://example.com/ops")
+	require.NoError(t, err)
+	id, err = a.Authenticate(withCert(ctx, &x509.Certificate{Subject: pkix.Name{CommonName: "other"}, URIs: []*url.URL{u}}))
+	require.NoError(t, err)
+	require.Equal(t, "ops", id.Name)
+
+	id, err = a.Authenticate(metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer secret")))
+	require.NoError(t, err)
+	require.Equal(t, "bot", id.Name)
+
+	_, err = a.Authenticate(metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer wrong")))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = a.Authenticate(withCert(ctx, &x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}}))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// certificates that were not verified are ignored
+	_, err = a.Authenticate(peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "ci.example.com"}}},
+		}},
+	}))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	a = newTestAuthorizer(t, readerRole)
+	id, err = a.Authenticate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "", id.Name)
+	require.Equal(t, readerRole, id.Role)
+}
+
+func TestAuthorize(t *testing.T) {
+	t.Parallel()
+
+	admin := &Identity{Name: "ops", Role: adminRole}
+	builder := &Identity{Name: "ci", Role: builderRole}
+	reader := &Identity{Role: readerRole}
+
+	require.NoError(t, Authorize(admin, methodPrune, nil))
+	require.NoError(t, Authorize(admin, methodSolve, &controlapi.SolveRequest{Entitlements: []entitlements.Entitlement{entitlements.EntitlementSecurityInsecure}}))
+
+	require.Equal(t, codes.PermissionDenied, status.Code(Authorize(builder, methodPrune, nil)))
+	require.NoError(t, Authorize(builder, methodDiskUsage, nil))
+	require.NoError(t, Authorize(builder, methodSolve, &controlapi.SolveRequest{Entitlements: []entitlements.Entitlement{entitlements.EntitlementNetworkHost}}))
+	err := Authorize(builder, methodSolve, &controlapi.SolveRequest{Entitlements: []entitlements.Entitlement{entitlements.EntitlementSecurityInsecure}})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Contains(t, err.Error(), "security.insecure")
+
+	require.NoError(t, Authorize(admin, methodSolve, &controlapi.SolveRequest{Priority: 10, Weight: 4}))
+	require.NoError(t, Authorize(builder, methodSolve, &controlapi.SolveRequest{Priority: -1, Weight: 1}))
+	require.Equal(t, codes.PermissionDenied, status.Code(Authorize(builder, methodSolve, &controlapi.SolveRequest{Priority: 1})))
+	require.Equal(t, codes.PermissionDenied, status.Code(Authorize(builder, methodSolve, &controlapi.SolveRequest{Weight: 2})))
+
+	require.Equal(t, codes.PermissionDenied, status.Code(Authorize(reader, methodDiskUsage, nil)))
+	require.NoError(t, Authorize(reader, methodSolve, &controlapi.SolveRequest{}))
+	require.NoError(t, Authorize(reader, "/moby.buildkit.v1.Control/ListWorkers", nil))
+}
+
+func TestOwns(t *testing.T) {
+	t.Parallel()
+
+	admin := &Identity{Name: "ops", Role: adminRole}
+	builder := &Identity{Name: "ci", Role: builderRole}
+	other := &Identity{Name: "bot", Role: builderRole}
+
+	require.True(t, admin.Owns(builder))
+	require.True(t, builder.Owns(&Identity{Name: "ci", Role: builderRole}))
+	require.False(t, builder.Owns(other))
+	require.False(t, builder.Owns(nil))
+
+	var disabled *Identity
+	require.True(t, disabled.Owns(builder))
+}
+
+func TestAllowsCacheNamespace(t *testing.T) {
+	t.Parallel()
+
+	admin := &Identity{Name: "ops", Role: &Role{Admin: true, CacheNamespaces: []string{"ops"}}}
+	team := &Identity{Name: "ci", Role: &Role{CacheNamespaces: []string{"team-a", "team-b"}}}
+	unrestricted := &Identity{Name: "bot", Role: builderRole}
+
+	require.True(t, admin.AllowsCacheNamespace(""))
+	require.True(t, admin.AllowsCacheNamespace("team-a"))
+	require.True(t, team.AllowsCacheNamespace("team-b"))
+	require.False(t, team.AllowsCacheNamespace("team-c"))
+	require.False(t, team.AllowsCacheNamespace(""))
+	require.True(t, unrestricted.AllowsCacheNamespace("team-c"))
+	require.True(t, unrestricted.AllowsCacheNamespace(""))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	a := newTestAuthorizer(t, nil)
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("authorization", "Bearer secret"))
+
+	var called *Identity
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = FromContext(ctx)
+		return nil, nil
+	}
+
+	_, err := a.UnaryServerInterceptor(ctx, &controlapi.DiskUsageRequest{}, &grpc.UnaryServerInfo{FullMethod: methodDiskUsage}, handler)
+	require.NoError(t, err)
+	require.NotNil(t, called)
+	require.Equal(t, "bot", called.Name)
+
+	called = nil
+	_, err = a.UnaryServerInterceptor(context.TODO(), &controlapi.DiskUsageRequest{}, &grpc.UnaryServerInfo{FullMethod: methodDiskUsage}, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.Nil(t, called)
+
+	_, err = a.UnaryServerInterceptor(context.TODO(), nil, &grpc.UnaryServerInfo{FullMethod: healthPrefix + "Check"}, handler)
+	require.NoError(t, err)
+}
+
+func TestNewValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(Opt{Subjects: []Subject{{Name: "ci", Role: builderRole}}})
+	require.Error(t, err)
+
+	_, err = New(Opt{Subjects: []Subject{{Name: "ci", CommonNames: []string{"ci"}}}})
+	require.Error(t, err)
+
+	_, err = New(Opt{Subjects: []Subject{{Name: "ci", Role: builderRole, TokenDigests: []digest.Digest{"sha256:invalid"}}}})
+	require.Error(t, err)
+}
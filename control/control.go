@@ -12,19 +12,24 @@ import (
 	apitypes "github.com/moby/buildkit/api/types"
 	"github.com/moby/buildkit/cache/remotecache"
 	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/control/authz"
 	controlgateway "github.com/moby/buildkit/control/gateway"
 	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/util/epoch"
 	"github.com/moby/buildkit/frontend"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/grpchijack"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/llbsolver"
+	"github.com/moby/buildkit/solver/llbsolver/mounts"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/moby/buildkit/util/imageutil"
 	"github.com/moby/buildkit/util/throttle"
 	"github.com/moby/buildkit/util/tracing/transform"
 	"github.com/moby/buildkit/worker"
 	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	tracev1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"golang.org/x/sync/errgroup"
@@ -42,6 +47,9 @@ type Opt struct {
 	ResolveCacheImporterFuncs map[string]remotecache.ResolveCacheImporterFunc
 	Entitlements              []string
 	TraceCollector            sdktrace.SpanExporter
+	// HistoryDB stores the records of finished builds. History is disabled
+	// if nil.
+	HistoryDB *bolt.DB
 }
 
 type Controller struct { // TODO: ControlService
@@ -53,25 +61,41 @@ type Controller struct { // TODO: ControlService
 	gatewayForwarder *controlgateway.GatewayForwarder
 	throttledGC      func()
 	gcmu             sync.Mutex
+	ownersMu         sync.Mutex
+	ownersCond       *sync.Cond
+	owners           map[string]*authz.Identity
+	sessionOwners    map[string]*sessionOwner
+	nsCacheMu        sync.Mutex
+	nsCache          map[string]solver.CacheManager
+	history          *historyStore
 	*tracev1.UnimplementedTraceServiceServer
 }
 
 func NewController(opt Opt) (*Controller, error) {
-	cache := solver.NewCacheManager(context.TODO(), "local", opt.CacheKeyStorage, worker.NewCacheResultStorage(opt.WorkerController))
+	cache := solver.NewCacheManager(context.TODO(), "local", solver.NewNamespacedCacheKeyStorage(opt.CacheKeyStorage, ""), worker.NewCacheResultStorage(opt.WorkerController))
 
-	gatewayForwarder := controlgateway.NewGatewayForwarder()
+	c := &Controller{
+		opt:           opt,
+		cache:         cache,
+		owners:        map[string]*authz.Identity{},
+		sessionOwners: map[string]*sessionOwner{},
+		nsCache:       map[string]solver.CacheManager{},
+	}
+	gatewayForwarder := controlgateway.NewGatewayForwarder(c.authorizeBuild)
+	c.gatewayForwarder = gatewayForwarder
 
-	solver, err := llbsolver.New(opt.WorkerController, opt.Frontends, cache, opt.ResolveCacheImporterFuncs, gatewayForwarder, opt.SessionManager, opt.Entitlements)
+	solver, err := llbsolver.New(opt.WorkerController, opt.Frontends, cache, c.namespacedCache, opt.ResolveCacheImporterFuncs, gatewayForwarder, opt.SessionManager, opt.Entitlements)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create solver")
 	}
-
-	c := &Controller{
-		opt:              opt,
-		solver:           solver,
-		cache:            cache,
-		gatewayForwarder: gatewayForwarder,
+	c.solver = solver
+	if opt.HistoryDB != nil {
+		c.history, err = newHistoryStore(opt.HistoryDB)
+		if err != nil {
+			return nil, err
+		}
 	}
+	c.ownersCond = sync.NewCond(&c.ownersMu)
 	c.throttledGC = throttle.After(time.Minute, c.gc)
 
 	defer func() {
@@ -89,6 +113,9 @@ func (c *Controller) Register(server *grpc.Server) error {
 }
 
 func (c *Controller) DiskUsage(ctx context.Context, r *controlapi.DiskUsageRequest) (*controlapi.DiskUsageResponse, error) {
+	if err := c.authorizeCacheNamespace(ctx, r.CacheNamespace); err != nil {
+		return nil, err
+	}
 	resp := &controlapi.DiskUsageResponse{}
 	workers, err := c.opt.WorkerController.List()
 	if err != nil {
@@ -102,20 +129,35 @@ func (c *Controller) DiskUsage(ctx context.Context, r *controlapi.DiskUsageReque
 			return nil, err
 		}
 
+		if r.CacheNamespace != "" {
+			ids, err := c.namespaceRecords(ctx, w, r.CacheNamespace)
+			if err != nil {
+				return nil, err
+			}
+			filtered := du[:0]
+			for _, r := range du {
+				if _, ok := ids[r.ID]; ok {
+					filtered = append(filtered, r)
+				}
+			}
+			du = filtered
+		}
+
 		for _, r := range du {
 			resp.Record = append(resp.Record, &controlapi.UsageRecord{
 				// TODO: add worker info
-				ID:          r.ID,
-				Mutable:     r.Mutable,
-				InUse:       r.InUse,
-				Size_:       r.Size,
-				Parent:      r.Parent,
-				UsageCount:  int64(r.UsageCount),
-				Description: r.Description,
-				CreatedAt:   r.CreatedAt,
-				LastUsedAt:  r.LastUsedAt,
-				RecordType:  string(r.RecordType),
-				Shared:      r.Shared,
+				ID:           r.ID,
+				Mutable:      r.Mutable,
+				InUse:        r.InUse,
+				Size_:        r.Size,
+				Parent:       r.Parent,
+				UsageCount:   int64(r.UsageCount),
+				Description:  r.Description,
+				CreatedAt:    r.CreatedAt,
+				LastUsedAt:   r.LastUsedAt,
+				RecordType:   string(r.RecordType),
+				Shared:       r.Shared,
+				CacheMountID: r.CacheMountID,
 			})
 		}
 	}
@@ -123,6 +165,10 @@ func (c *Controller) DiskUsage(ctx context.Context, r *controlapi.DiskUsageReque
 }
 
 func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Control_PruneServer) error {
+	if err := c.authorizeCacheNamespace(stream.Context(), req.CacheNamespace); err != nil {
+		return err
+	}
+
 	if atomic.LoadInt64(&c.buildCount) == 0 {
 		imageutil.CancelCacheLeases()
 	}
@@ -138,11 +184,13 @@ func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Contr
 	didPrune := false
 	defer func() {
 		if didPrune {
-			if c, ok := c.cache.(interface {
-				ReleaseUnreferenced() error
-			}); ok {
-				if err := c.ReleaseUnreferenced(); err != nil {
-					bklog.G(ctx).Errorf("failed to release cache metadata: %+v", err)
+			for _, cm := range c.cacheManagers() {
+				if c, ok := cm.(interface {
+					ReleaseUnreferenced() error
+				}); ok {
+					if err := c.ReleaseUnreferenced(); err != nil {
+						bklog.G(ctx).Errorf("failed to release cache metadata: %+v", err)
+					}
 				}
 			}
 		}
@@ -151,8 +199,19 @@ func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Contr
 	for _, w := range workers {
 		func(w worker.Worker) {
 			eg.Go(func() error {
+				filter := req.Filter
+				if req.CacheNamespace != "" {
+					ids, err := c.namespaceRecords(ctx, w, req.CacheNamespace)
+					if err != nil {
+						return err
+					}
+					if len(ids) == 0 {
+						return nil
+					}
+					filter = scopeFilters(filter, ids)
+				}
 				return w.Prune(ctx, ch, client.PruneInfo{
-					Filter:       req.Filter,
+					Filter:       filter,
 					All:          req.All,
 					KeepDuration: time.Duration(req.KeepDuration),
 					KeepBytes:    req.KeepBytes,
@@ -173,17 +232,18 @@ func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Contr
 			didPrune = true
 			if err := stream.Send(&controlapi.UsageRecord{
 				// TODO: add worker info
-				ID:          r.ID,
-				Mutable:     r.Mutable,
-				InUse:       r.InUse,
-				Size_:       r.Size,
-				Parent:      r.Parent,
-				UsageCount:  int64(r.UsageCount),
-				Description: r.Description,
-				CreatedAt:   r.CreatedAt,
-				LastUsedAt:  r.LastUsedAt,
-				RecordType:  string(r.RecordType),
-				Shared:      r.Shared,
+				ID:           r.ID,
+				Mutable:      r.Mutable,
+				InUse:        r.InUse,
+				Size_:        r.Size,
+				Parent:       r.Parent,
+				UsageCount:   int64(r.UsageCount),
+				Description:  r.Description,
+				CreatedAt:    r.CreatedAt,
+				LastUsedAt:   r.LastUsedAt,
+				RecordType:   string(r.RecordType),
+				Shared:       r.Shared,
+				CacheMountID: r.CacheMountID,
 			}); err != nil {
 				return err
 			}
@@ -240,6 +300,25 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 
 	// This method registers job ID in solver.Solve. Make sure there are no blocking calls before that might delay this.
 
+	if err := c.authorizeCacheNamespace(ctx, req.CacheNamespace); err != nil {
+		return nil, err
+	}
+
+	if id := authz.FromContext(ctx); id != nil {
+		release, err := c.claimOwner(req.Ref, id)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		if req.Session != "" {
+			release, err := c.claimSession(req.Session, id)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+		}
+	}
+
 	if err := translateLegacySolveRequest(req); err != nil {
 		return nil, err
 	}
@@ -260,6 +339,14 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		if err != nil {
 			return nil, err
 		}
+		if v, ok := epoch.ParseBuildArgs(req.FrontendAttrs); ok {
+			if _, ok := req.ExporterAttrs[epoch.KeySourceDateEpoch]; !ok {
+				if req.ExporterAttrs == nil {
+					req.ExporterAttrs = make(map[string]string)
+				}
+				req.ExporterAttrs[epoch.KeySourceDateEpoch] = v
+			}
+		}
 		expi, err = exp.Resolve(ctx, req.ExporterAttrs)
 		if err != nil {
 			return nil, err
@@ -267,9 +354,10 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 	}
 
 	var (
-		cacheExporter   remotecache.Exporter
-		cacheExportMode solver.CacheExportMode
-		cacheImports    []frontend.CacheOptionsEntry
+		cacheExporter     remotecache.Exporter
+		cacheExportMode   solver.CacheExportMode
+		cacheExportMounts bool
+		cacheImports      []frontend.CacheOptionsEntry
 	)
 	if len(req.Cache.Exports) > 1 {
 		// TODO(AkihiroSuda): this should be fairly easy
@@ -291,6 +379,13 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		} else {
 			cacheExportMode = exportMode
 		}
+		cacheExportMounts, err = llbsolver.ParseCacheMountsAttr(e.Attrs)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := cacheExporter.(remotecache.CacheMountExporter); cacheExportMounts && !ok {
+			return nil, errors.Errorf("cache exporter %q does not support exporting cache mounts", e.Type)
+		}
 	}
 	for _, im := range req.Cache.Imports {
 		cacheImports = append(cacheImports, frontend.CacheOptionsEntry{
@@ -299,6 +394,12 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		})
 	}
 
+	var owner string
+	if id := authz.FromContext(ctx); id != nil {
+		owner = id.Name
+	}
+	hr := c.recordHistory(req, owner)
+
 	resp, err := c.solver.Solve(ctx, req.Ref, req.Session, frontend.SolveRequest{
 		Frontend:       req.Frontend,
 		Definition:     req.Definition,
@@ -306,10 +407,12 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		FrontendInputs: req.FrontendInputs,
 		CacheImports:   cacheImports,
 	}, llbsolver.ExporterRequest{
-		Exporter:        expi,
-		CacheExporter:   cacheExporter,
-		CacheExportMode: cacheExportMode,
-	}, req.Entitlements)
+		Exporter:          expi,
+		CacheExporter:     cacheExporter,
+		CacheExportMode:   cacheExportMode,
+		CacheExportMounts: cacheExportMounts,
+	}, req.Entitlements, req.SourcePolicy, req.CacheNamespace, int(req.Priority), int(req.Weight), time.Duration(req.Timeout))
+	hr.finish(err)
 	if err != nil {
 		return nil, err
 	}
@@ -319,6 +422,10 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 }
 
 func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Control_StatusServer) error {
+	if err := c.authorizeBuild(stream.Context(), req.Ref); err != nil {
+		return err
+	}
+
 	ch := make(chan *client.SolveStatus, 8)
 
 	eg, ctx := errgroup.WithContext(stream.Context())
@@ -359,6 +466,30 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 						Completed: v.Completed,
 					})
 				}
+				for _, v := range ss.Resources {
+					sr.Resources = append(sr.Resources, &controlapi.VertexResources{
+						Vertex:         v.Vertex,
+						Timestamp:      v.Timestamp,
+						CpuNanos:       v.CPUNanos,
+						CpuUserNanos:   v.CPUUserNanos,
+						CpuSystemNanos: v.CPUSystemNanos,
+						MemoryPeak:     v.MemoryPeak,
+						IoReadBytes:    v.IOReadBytes,
+						IoWriteBytes:   v.IOWriteBytes,
+						PidsPeak:       v.PidsPeak,
+					})
+				}
+				for _, v := range ss.Warnings {
+					sr.Warnings = append(sr.Warnings, &controlapi.VertexWarning{
+						Vertex: v.Vertex,
+						Level:  int64(v.Level),
+						Short:  v.Short,
+						Detail: v.Detail,
+						Url:    v.URL,
+						Info:   v.SourceInfo,
+						Ranges: v.Range,
+					})
+				}
 				for i, v := range ss.Logs {
 					sr.Logs = append(sr.Logs, &controlapi.VertexLog{
 						Vertex:    v.Vertex,
@@ -371,6 +502,8 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 					if logSize > 1024*1024 {
 						ss.Vertexes = nil
 						ss.Statuses = nil
+						ss.Resources = nil
+						ss.Warnings = nil
 						ss.Logs = ss.Logs[i+1:]
 						retry = true
 						break
@@ -389,12 +522,201 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 	return eg.Wait()
 }
 
+// namespacedCache returns the cache manager for the cache keys of a cache
+// namespace.
+func (c *Controller) namespacedCache(ns string) (solver.CacheManager, error) {
+	if err := solver.ValidateCacheNamespace(ns); err != nil {
+		return nil, err
+	}
+	c.nsCacheMu.Lock()
+	defer c.nsCacheMu.Unlock()
+	if cm, ok := c.nsCache[ns]; ok {
+		return cm, nil
+	}
+	cm := solver.NewCacheManager(context.TODO(), "local/"+ns, solver.NewNamespacedCacheKeyStorage(c.opt.CacheKeyStorage, ns), worker.NewCacheResultStorage(c.opt.WorkerController))
+	c.nsCache[ns] = cm
+	return cm, nil
+}
+
+func (c *Controller) cacheManagers() []solver.CacheManager {
+	c.nsCacheMu.Lock()
+	defer c.nsCacheMu.Unlock()
+	cms := []solver.CacheManager{c.cache}
+	for _, cm := range c.nsCache {
+		cms = append(cms, cm)
+	}
+	return cms
+}
+
+// namespaceRecords returns the IDs of the records of worker w that belong to
+// cache namespace ns. These are the results of the cache keys of the
+// namespace and the cache mounts of its builds.
+func (c *Controller) namespaceRecords(ctx context.Context, w worker.Worker, ns string) (map[string]struct{}, error) {
+	if err := solver.ValidateCacheNamespace(ns); err != nil {
+		return nil, err
+	}
+	results := map[string]struct{}{}
+	st := solver.NewNamespacedCacheKeyStorage(c.opt.CacheKeyStorage, ns)
+	if err := st.Walk(func(id string) error {
+		return st.WalkResults(id, func(res solver.CacheResult) error {
+			results[res.ID] = struct{}{}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	du, err := w.DiskUsage(ctx, client.DiskUsageInfo{})
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]struct{}{}
+	for _, r := range du {
+		if _, ok := results[w.ID()+"::"+r.ID]; ok {
+			ids[r.ID] = struct{}{}
+		} else if r.RecordType == client.UsageRecordTypeCacheMount && mounts.CacheMountNamespace(r.CacheMountID) == ns {
+			ids[r.ID] = struct{}{}
+		}
+	}
+	return ids, nil
+}
+
+// scopeFilters restricts prune filters to the records with ids. Filters are
+// combined with OR and the fields of a single filter with AND.
+func scopeFilters(filters []string, ids map[string]struct{}) []string {
+	if len(filters) == 0 {
+		filters = []string{""}
+	}
+	out := make([]string, 0, len(filters)*len(ids))
+	for _, f := range filters {
+		for id := range ids {
+			if f == "" {
+				out = append(out, "recordid=="+id)
+			} else {
+				out = append(out, f+",recordid=="+id)
+			}
+		}
+	}
+	return out
+}
+
+func (c *Controller) authorizeCacheNamespace(ctx context.Context, ns string) error {
+	if err := solver.ValidateCacheNamespace(ns); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if id := authz.FromContext(ctx); id != nil && !id.AllowsCacheNamespace(ns) {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to use cache namespace %q", id, ns)
+	}
+	return nil
+}
+
+// claimOwner registers id as the owner of the build with ref. It fails if a
+// build with the same ref is running. The returned function removes the
+// registration.
+func (c *Controller) claimOwner(ref string, id *authz.Identity) (func(), error) {
+	c.ownersMu.Lock()
+	defer c.ownersMu.Unlock()
+	if _, ok := c.owners[ref]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "job ID %s exists", ref)
+	}
+	c.owners[ref] = id
+	c.ownersCond.Broadcast()
+	return func() {
+		c.ownersMu.Lock()
+		delete(c.owners, ref)
+		c.ownersMu.Unlock()
+	}, nil
+}
+
+type sessionOwner struct {
+	id    *authz.Identity
+	count int
+}
+
+// claimSession registers id as a user of the session with sid, either by
+// attaching it or by starting a build that uses it. A session can't be used
+// by clients of different identities. The returned function removes the
+// registration.
+func (c *Controller) claimSession(sid string, id *authz.Identity) (func(), error) {
+	c.ownersMu.Lock()
+	defer c.ownersMu.Unlock()
+	so, ok := c.sessionOwners[sid]
+	if !ok {
+		so = &sessionOwner{id: id}
+		c.sessionOwners[sid] = so
+	} else if !id.Owns(so.id) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed to access session %s", id, sid)
+	}
+	so.count++
+	return func() {
+		c.ownersMu.Lock()
+		defer c.ownersMu.Unlock()
+		if so.count--; so.count == 0 {
+			delete(c.sessionOwners, sid)
+		}
+	}, nil
+}
+
+// authorizeBuild returns an error if the client of ctx is not allowed to
+// access the build with ref.
+func (c *Controller) authorizeBuild(ctx context.Context, ref string) error {
+	id := authz.FromContext(ctx)
+	if id == nil || id.Role.Admin {
+		return nil
+	}
+	owner, ok := c.waitOwner(ctx, ref)
+	if !ok {
+		return status.Errorf(codes.NotFound, "no such job %s", ref)
+	}
+	if !id.Owns(owner) {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to access job %s", id, ref)
+	}
+	return nil
+}
+
+// waitOwner returns the identity that started the build with ref. Status
+// requests are usually sent before the build is started so this waits for
+// the same time as the solver waits for the job to be created.
+func (c *Controller) waitOwner(ctx context.Context, ref string) (*authz.Identity, bool) {
+	ctx, cancel := context.WithTimeout(ctx, 6*time.Second)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		c.ownersMu.Lock()
+		c.ownersCond.Broadcast()
+		c.ownersMu.Unlock()
+	}()
+
+	c.ownersMu.Lock()
+	defer c.ownersMu.Unlock()
+	for {
+		if id, ok := c.owners[ref]; ok {
+			return id, true
+		}
+		if ctx.Err() != nil {
+			return nil, false
+		}
+		c.ownersCond.Wait()
+	}
+}
+
 func (c *Controller) Session(stream controlapi.Control_SessionServer) error {
 	bklog.G(stream.Context()).Debugf("session started")
 
 	conn, closeCh, opts := grpchijack.Hijack(stream)
 	defer conn.Close()
 
+	if id := authz.FromContext(stream.Context()); id != nil {
+		if sid := session.IDFromHeaders(opts); sid != "" {
+			release, err := c.claimSession(sid, id)
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+	}
+
 	ctx, cancel := context.WithCancel(stream.Context())
 	go func() {
 		<-closeCh
@@ -423,6 +745,35 @@ func (c *Controller) ListWorkers(ctx context.Context, r *controlapi.ListWorkersR
 	return resp, nil
 }
 
+func (c *Controller) CancelVertex(ctx context.Context, req *controlapi.CancelVertexRequest) (*controlapi.CancelVertexResponse, error) {
+	if err := c.authorizeBuild(ctx, req.Ref); err != nil {
+		return nil, err
+	}
+	if err := c.solver.CancelVertex(req.Ref, req.Digest); err != nil {
+		return nil, grpcerrors.WrapCode(err, codes.NotFound)
+	}
+	return &controlapi.CancelVertexResponse{}, nil
+}
+
+func (c *Controller) ListBuildHistory(ctx context.Context, req *controlapi.BuildHistoryRequest) (*controlapi.BuildHistoryResponse, error) {
+	if c.history == nil {
+		return nil, status.Errorf(codes.Unimplemented, "build history is not enabled")
+	}
+	recs, err := c.history.list(req.Ref)
+	if err != nil {
+		return nil, err
+	}
+	resp := &controlapi.BuildHistoryResponse{}
+	id := authz.FromContext(ctx)
+	for _, rec := range recs {
+		if !id.Owns(&authz.Identity{Name: rec.Owner}) {
+			continue
+		}
+		resp.Records = append(resp.Records, rec)
+	}
+	return resp, nil
+}
+
 func (c *Controller) gc() {
 	c.gcmu.Lock()
 	defer c.gcmu.Unlock()
@@ -0,0 +1,78 @@
+package control
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/moby/buildkit/control/authz"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newOwnersController() *Controller {
+	c := &Controller{
+		owners:        map[string]*authz.Identity{},
+		sessionOwners: map[string]*sessionOwner{},
+	}
+	c.ownersCond = sync.NewCond(&c.ownersMu)
+	return c
+}
+
+func TestClaimOwner(t *testing.T) {
+	builder := &authz.Role{Name: "builder"}
+	alice := &authz.Identity{Name: "alice", Role: builder}
+	bob := &authz.Identity{Name: "bob", Role: builder}
+	admin := &authz.Identity{Name: "ops", Role: &authz.Role{Name: "admin", Admin: true}}
+
+	c := newOwnersController()
+
+	release, err := c.claimOwner("ref1", alice)
+	require.NoError(t, err)
+
+	// a duplicate ref does not take over or remove the running build
+	_, err = c.claimOwner("ref1", bob)
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	owner, ok := c.waitOwner(context.TODO(), "ref1")
+	require.True(t, ok)
+	require.Equal(t, alice, owner)
+
+	require.NoError(t, c.authorizeBuild(authz.WithIdentity(context.TODO(), alice), "ref1"))
+	require.NoError(t, c.authorizeBuild(authz.WithIdentity(context.TODO(), admin), "ref1"))
+	err = c.authorizeBuild(authz.WithIdentity(context.TODO(), bob), "ref1")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	release()
+	release, err = c.claimOwner("ref1", bob)
+	require.NoError(t, err)
+	release()
+}
+
+func TestClaimSession(t *testing.T) {
+	builder := &authz.Role{Name: "builder"}
+	alice := &authz.Identity{Name: "alice", Role: builder}
+	bob := &authz.Identity{Name: "bob", Role: builder}
+	admin := &authz.Identity{Name: "ops", Role: &authz.Role{Name: "admin", Admin: true}}
+
+	c := newOwnersController()
+
+	release1, err := c.claimSession("sid", alice)
+	require.NoError(t, err)
+	release2, err := c.claimSession("sid", alice)
+	require.NoError(t, err)
+	release3, err := c.claimSession("sid", admin)
+	require.NoError(t, err)
+
+	_, err = c.claimSession("sid", bob)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	release1()
+	release2()
+	release3()
+
+	release, err := c.claimSession("sid", bob)
+	require.NoError(t, err)
+	release()
+	require.Empty(t, c.sessionOwners)
+}
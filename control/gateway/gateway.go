@@ -12,15 +12,23 @@ import (
 	"google.golang.org/grpc"
 )
 
+// AuthorizeFunc returns an error if the client of ctx is not allowed to
+// access the build with id.
+type AuthorizeFunc func(ctx context.Context, id string) error
+
 type GatewayForwarder struct {
 	mu         sync.RWMutex
 	updateCond *sync.Cond
 	builds     map[string]gateway.LLBBridgeForwarder
+	authorize  AuthorizeFunc
 }
 
-func NewGatewayForwarder() *GatewayForwarder {
+// NewGatewayForwarder returns a forwarder of the gateway API to the builds
+// registered with it. If authorize is set, it is called for every request.
+func NewGatewayForwarder(authorize AuthorizeFunc) *GatewayForwarder {
 	gwf := &GatewayForwarder{
-		builds: map[string]gateway.LLBBridgeForwarder{},
+		builds:    map[string]gateway.LLBBridgeForwarder{},
+		authorize: authorize,
 	}
 	gwf.updateCond = sync.NewCond(gwf.mu.RLocker())
 	return gwf
@@ -58,6 +66,19 @@ func (gwf *GatewayForwarder) lookupForwarder(ctx context.Context) (gateway.LLBBr
 		return nil, errors.New("no buildid found in context")
 	}
 
+	fwd, err := gwf.waitForwarder(ctx, bid)
+	if err != nil {
+		return nil, err
+	}
+	if gwf.authorize != nil {
+		if err := gwf.authorize(ctx, bid); err != nil {
+			return nil, err
+		}
+	}
+	return fwd, nil
+}
+
+func (gwf *GatewayForwarder) waitForwarder(ctx context.Context, bid string) (gateway.LLBBridgeForwarder, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
@@ -153,6 +174,14 @@ func (gwf *GatewayForwarder) StatFile(ctx context.Context, req *gwapi.StatFileRe
 	return fwd.StatFile(ctx, req)
 }
 
+func (gwf *GatewayForwarder) Warn(ctx context.Context, req *gwapi.WarnRequest) (*gwapi.WarnResponse, error) {
+	fwd, err := gwf.lookupForwarder(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "forwarding Warn")
+	}
+	return fwd.Warn(ctx, req)
+}
+
 func (gwf *GatewayForwarder) NewContainer(ctx context.Context, req *gwapi.NewContainerRequest) (*gwapi.NewContainerResponse, error) {
 	fwd, err := gwf.lookupForwarder(ctx)
 	if err != nil {
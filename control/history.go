@@ -0,0 +1,214 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/bklog"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	historyBucket = "_history"
+	// maxHistoryRecords is the number of build records kept. The oldest
+	// records are removed first.
+	maxHistoryRecords = 100
+)
+
+// historyStore keeps the records of finished builds in a bolt database.
+type historyStore struct {
+	mu  sync.Mutex
+	db  *bolt.DB
+	max int
+}
+
+func newHistoryStore(db *bolt.DB) (*historyStore, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(historyBucket))
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to create history bucket")
+	}
+	return &historyStore{db: db, max: maxHistoryRecords}, nil
+}
+
+func (h *historyStore) add(rec *controlapi.BuildHistoryRecord) error {
+	dt, err := rec.Marshal()
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(historyBucket))
+		if err := b.Put([]byte(rec.Ref), dt); err != nil {
+			return err
+		}
+		recs, err := readHistory(b, "")
+		if err != nil {
+			return err
+		}
+		for len(recs) > h.max {
+			if err := b.Delete([]byte(recs[0].Ref)); err != nil {
+				return err
+			}
+			recs = recs[1:]
+		}
+		return nil
+	})
+}
+
+// list returns the records sorted from the oldest to the newest. If ref is
+// set, only the record of that build is returned.
+func (h *historyStore) list(ref string) (recs []*controlapi.BuildHistoryRecord, err error) {
+	err = h.db.View(func(tx *bolt.Tx) error {
+		recs, err = readHistory(tx.Bucket([]byte(historyBucket)), ref)
+		return err
+	})
+	return recs, err
+}
+
+func readHistory(b *bolt.Bucket, ref string) ([]*controlapi.BuildHistoryRecord, error) {
+	var recs []*controlapi.BuildHistoryRecord
+	read := func(k, v []byte) error {
+		var rec controlapi.BuildHistoryRecord
+		if err := rec.Unmarshal(v); err != nil {
+			return errors.Wrapf(err, "failed to parse history record %s", k)
+		}
+		recs = append(recs, &rec)
+		return nil
+	}
+	if ref != "" {
+		if v := b.Get([]byte(ref)); v != nil {
+			if err := read([]byte(ref), v); err != nil {
+				return nil, err
+			}
+		}
+		return recs, nil
+	}
+	if err := b.ForEach(read); err != nil {
+		return nil, err
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return timeOrZero(recs[i].CreatedAt).Before(timeOrZero(recs[j].CreatedAt))
+	})
+	return recs, nil
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// historyRecorder collects the progress of a build for its history record.
+type historyRecorder struct {
+	rec      *controlapi.BuildHistoryRecord
+	vertexes map[digest.Digest]*controlapi.Vertex
+	order    []digest.Digest
+	finished chan error
+}
+
+// recordHistory starts collecting the progress of the build req. The record
+// is saved after finish has been called and the progress of the build has
+// ended.
+func (c *Controller) recordHistory(req *controlapi.SolveRequest, owner string) *historyRecorder {
+	if c.history == nil {
+		return nil
+	}
+	now := time.Now()
+	hr := &historyRecorder{
+		rec: &controlapi.BuildHistoryRecord{
+			Ref:           req.Ref,
+			Frontend:      req.Frontend,
+			FrontendAttrs: historyFrontendAttrs(req.FrontendAttrs),
+			Owner:         owner,
+			CreatedAt:     &now,
+		},
+		vertexes: map[digest.Digest]*controlapi.Vertex{},
+		finished: make(chan error, 1),
+	}
+
+	ch := make(chan *client.SolveStatus)
+	go func() {
+		// the progress ends when the job is discarded after the build
+		c.solver.Status(context.TODO(), req.Ref, ch)
+	}()
+	go func() {
+		for ss := range ch {
+			hr.update(ss)
+		}
+		err := <-hr.finished
+		completed := time.Now()
+		hr.rec.CompletedAt = &completed
+		if err != nil {
+			hr.rec.Error = err.Error()
+		}
+		for _, dgst := range hr.order {
+			hr.rec.Vertexes = append(hr.rec.Vertexes, hr.vertexes[dgst])
+		}
+		if err := c.history.add(hr.rec); err != nil {
+			bklog.L.Warnf("failed to save history record of %s: %v", req.Ref, err)
+		}
+	}()
+	return hr
+}
+
+// historyFrontendAttrs returns the frontend attributes saved in a history
+// record. Build arguments often carry credentials and are left out.
+func historyFrontendAttrs(attrs map[string]string) map[string]string {
+	out := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if strings.HasPrefix(k, "build-arg:") {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func (hr *historyRecorder) update(ss *client.SolveStatus) {
+	for _, v := range ss.Vertexes {
+		if _, ok := hr.vertexes[v.Digest]; !ok {
+			hr.order = append(hr.order, v.Digest)
+		}
+		hr.vertexes[v.Digest] = &controlapi.Vertex{
+			Digest:    v.Digest,
+			Inputs:    v.Inputs,
+			Name:      v.Name,
+			Started:   v.Started,
+			Completed: v.Completed,
+			Error:     v.Error,
+			Cached:    v.Cached,
+		}
+	}
+	for _, v := range ss.Resources {
+		hr.rec.Resources = append(hr.rec.Resources, &controlapi.VertexResources{
+			Vertex:         v.Vertex,
+			Timestamp:      v.Timestamp,
+			CpuNanos:       v.CPUNanos,
+			CpuUserNanos:   v.CPUUserNanos,
+			CpuSystemNanos: v.CPUSystemNanos,
+			MemoryPeak:     v.MemoryPeak,
+			IoReadBytes:    v.IOReadBytes,
+			IoWriteBytes:   v.IOWriteBytes,
+			PidsPeak:       v.PidsPeak,
+		})
+	}
+}
+
+// finish sets the result of the build. It does not block.
+func (hr *historyRecorder) finish(err error) {
+	if hr == nil {
+		return
+	}
+	hr.finished <- err
+}
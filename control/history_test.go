@@ -0,0 +1,58 @@
+package control
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestHistoryStore(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "history.db"), 0600, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	h, err := newHistoryStore(db)
+	require.NoError(t, err)
+	h.max = 2
+
+	now := time.Now()
+	for i, ref := range []string{"b", "a", "c"} {
+		created := now.Add(time.Duration(i) * time.Second)
+		require.NoError(t, h.add(&controlapi.BuildHistoryRecord{
+			Ref:       ref,
+			CreatedAt: &created,
+		}))
+	}
+
+	// the oldest record is removed
+	recs, err := h.list("")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(recs))
+	require.Equal(t, "a", recs[0].Ref)
+	require.Equal(t, "c", recs[1].Ref)
+
+	recs, err = h.list("c")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(recs))
+	require.Equal(t, "c", recs[0].Ref)
+
+	recs, err = h.list("b")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(recs))
+}
+
+func TestHistoryFrontendAttrs(t *testing.T) {
+	attrs := historyFrontendAttrs(map[string]string{
+		"filename":        "Dockerfile",
+		"build-arg:TOKEN": "secret",
+		"target":          "release",
+	})
+	require.Equal(t, map[string]string{
+		"filename": "Dockerfile",
+		"target":   "release",
+	}, attrs)
+}
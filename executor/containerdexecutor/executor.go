@@ -21,10 +21,13 @@ import (
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/moby/buildkit/executor"
 	"github.com/moby/buildkit/executor/oci"
+	"github.com/moby/buildkit/executor/resources"
+	resourcestypes "github.com/moby/buildkit/executor/resources/types"
 	gatewayapi "github.com/moby/buildkit/frontend/gateway/pb"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/cdidevices"
 	"github.com/moby/buildkit/util/network"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
@@ -40,10 +43,12 @@ type containerdExecutor struct {
 	mu               sync.Mutex
 	apparmorProfile  string
 	traceSocket      string
+	resmon           *resources.Monitor
+	cdiManager       *cdidevices.Manager
 }
 
 // New creates a new executor backed by connection to containerd API
-func New(client *containerd.Client, root, cgroup string, networkProviders map[pb.NetMode]network.Provider, dnsConfig *oci.DNSConfig, apparmorProfile string, traceSocket string) executor.Executor {
+func New(client *containerd.Client, root, cgroup string, networkProviders map[pb.NetMode]network.Provider, dnsConfig *oci.DNSConfig, apparmorProfile string, traceSocket string, resmon *resources.Monitor, cdiManager *cdidevices.Manager) executor.Executor {
 	// clean up old hosts/resolv.conf file. ignore errors
 	os.RemoveAll(filepath.Join(root, "hosts"))
 	os.RemoveAll(filepath.Join(root, "resolv.conf"))
@@ -57,10 +62,12 @@ func New(client *containerd.Client, root, cgroup string, networkProviders map[pb
 		running:          make(map[string]chan error),
 		apparmorProfile:  apparmorProfile,
 		traceSocket:      traceSocket,
+		resmon:           resmon,
+		cdiManager:       cdiManager,
 	}
 }
 
-func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) (err error) {
+func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) (usage *resourcestypes.Usage, err error) {
 	if id == "" {
 		id = identity.NewID()
 	}
@@ -87,12 +94,12 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 
 	resolvConf, err := oci.GetResolvConf(ctx, w.root, nil, w.dnsConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	hostsFile, clean, err := oci.GetHostsFile(ctx, w.root, meta.ExtraHosts, nil, meta.Hostname)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if clean != nil {
 		defer clean()
@@ -100,12 +107,12 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 
 	mountable, err := root.Src.Mount(ctx, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	rootMounts, release, err := mountable.Mount()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if release != nil {
 		defer release()
@@ -114,7 +121,7 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 	lm := snapshot.LocalMounterWithMounts(rootMounts)
 	rootfsPath, err := lm.Mount()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer lm.Unmount()
 	defer executor.MountStubsCleaner(rootfsPath, mounts)()
@@ -124,7 +131,7 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 	if err != nil {
 		uid, gid, sgids, err = oci.GetUser(rootfsPath, meta.User)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		identity := idtools.Identity{
@@ -134,22 +141,22 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 
 		newp, err := fs.RootPath(rootfsPath, meta.Cwd)
 		if err != nil {
-			return errors.Wrapf(err, "working dir %s points to invalid target", newp)
+			return nil, errors.Wrapf(err, "working dir %s points to invalid target", newp)
 		}
 		if _, err := os.Stat(newp); err != nil {
 			if err := idtools.MkdirAllAndChown(newp, 0755, identity); err != nil {
-				return errors.Wrapf(err, "failed to create working directory %s", newp)
+				return nil, errors.Wrapf(err, "failed to create working directory %s", newp)
 			}
 		}
 	}
 
 	provider, ok := w.networkProviders[meta.NetMode]
 	if !ok {
-		return errors.Errorf("unknown network mode %s", meta.NetMode)
+		return nil, errors.Errorf("unknown network mode %s", meta.NetMode)
 	}
 	namespace, err := provider.New()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer namespace.Close()
 
@@ -173,18 +180,30 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 		opts = append(opts, containerdoci.WithCgroup(cgroupsPath))
 	}
 	processMode := oci.ProcessSandbox // FIXME(AkihiroSuda)
-	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, processMode, nil, w.apparmorProfile, w.traceSocket, opts...)
+	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, processMode, nil, w.apparmorProfile, w.traceSocket, w.cdiManager, opts...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer cleanup()
 	spec.Process.Terminal = meta.Tty
 
+	var rec *resources.Recorder
+	if spec.Linux != nil {
+		rec, err = w.resmon.Record(spec.Linux.CgroupsPath)
+		if err != nil {
+			bklog.G(ctx).Debugf("failed to record resource usage of %s: %v", id, err)
+		}
+		if rec != nil {
+			spec.Linux.CgroupsPath = rec.CgroupsPath()
+		}
+	}
+	defer rec.Close()
+
 	container, err := w.client.NewContainer(ctx, id,
 		containerd.WithSpec(spec),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer func() {
@@ -205,13 +224,19 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 		Options: []string{"rbind"},
 	}}))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer func() {
 		if _, err1 := task.Delete(context.TODO()); err == nil && err1 != nil {
 			err = errors.Wrapf(err1, "failed to delete task %s", id)
 		}
+		// the task has to be deleted before the stats of its cgroup are final
+		var err1 error
+		usage, err1 = rec.Close()
+		if err1 != nil {
+			bklog.G(ctx).Debugf("failed to record resource usage of %s: %v", id, err1)
+		}
 	}()
 
 	err = w.runProcess(ctx, task, process.Resize, func() {
@@ -221,7 +246,7 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 			}
 		})
 	})
-	return err
+	return nil, err
 }
 
 func (w *containerdExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) (err error) {
@@ -381,7 +406,8 @@ func (w *containerdExecutor) runProcess(ctx context.Context, p containerd.Proces
 			var killCtx context.Context
 			killCtx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 			killCtxDone = killCtx.Done()
-			p.Kill(killCtx, syscall.SIGKILL)
+			// kill all processes of the container, not only the init process
+			p.Kill(killCtx, syscall.SIGKILL, containerd.WithKillAll)
 			io.Cancel()
 		case status := <-statusCh:
 			if cancel != nil {
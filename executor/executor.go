@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 
+	resourcestypes "github.com/moby/buildkit/executor/resources/types"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver/pb"
 )
@@ -20,6 +21,7 @@ type Meta struct {
 	ExtraHosts     []HostIP
 	NetMode        pb.NetMode
 	SecurityMode   pb.SecurityMode
+	CDIDevices     []*pb.CDIDevice
 }
 
 type Mountable interface {
@@ -49,7 +51,8 @@ type Executor interface {
 	// Run will start a container for the given process with rootfs, mounts.
 	// `id` is an optional name for the container so it can be referenced later via Exec.
 	// `started` is an optional channel that will be closed when the container setup completes and has started running.
-	Run(ctx context.Context, id string, rootfs Mount, mounts []Mount, process ProcessInfo, started chan<- struct{}) error
+	// The returned usage is nil if the resource usage of the container could not be recorded.
+	Run(ctx context.Context, id string, rootfs Mount, mounts []Mount, process ProcessInfo, started chan<- struct{}) (*resourcestypes.Usage, error)
 	// Exec will start a process in container matching `id`. An error will be returned
 	// if the container failed to start (via Run) or has exited before Exec is called.
 	Exec(ctx context.Context, id string, process ProcessInfo) error
@@ -14,6 +14,7 @@ import (
 	"github.com/mitchellh/hashstructure"
 	"github.com/moby/buildkit/executor"
 	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/cdidevices"
 	"github.com/moby/buildkit/util/network"
 	traceexec "github.com/moby/buildkit/util/tracing/exec"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -37,7 +38,7 @@ const (
 
 // GenerateSpec generates spec using containerd functionality.
 // opts are ignored for s.Process, s.Hostname, and s.Mounts .
-func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mount, id, resolvConf, hostsFile string, namespace network.Namespace, processMode ProcessMode, idmap *idtools.IdentityMapping, apparmorProfile string, tracingSocket string, opts ...oci.SpecOpts) (*specs.Spec, func(), error) {
+func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mount, id, resolvConf, hostsFile string, namespace network.Namespace, processMode ProcessMode, idmap *idtools.IdentityMapping, apparmorProfile string, tracingSocket string, cdiManager *cdidevices.Manager, opts ...oci.SpecOpts) (*specs.Spec, func(), error) {
 	c := &containers.Container{
 		ID: id,
 	}
@@ -72,6 +73,12 @@ func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mou
 		return nil, nil, err
 	}
 
+	if cdiOpts, err := generateCDIOpts(cdiManager, meta.CDIDevices); err == nil {
+		opts = append(opts, cdiOpts...)
+	} else {
+		return nil, nil, err
+	}
+
 	hostname := defaultHostname
 	if meta.Hostname != "" {
 		hostname = meta.Hostname
@@ -103,7 +110,10 @@ func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mou
 
 	s.Process.Rlimits = nil // reset open files limit
 
-	sm := &submounts{}
+	// With a user namespace, mounts like overlay can't be created by the
+	// runtime inside the container, so they are mounted on the host first and
+	// bind-mounted into the container.
+	sm := &submounts{bindOnly: idmap != nil}
 
 	var releasers []func() error
 	releaseAll := func() {
@@ -164,11 +174,12 @@ type mountRef struct {
 }
 
 type submounts struct {
-	m map[uint64]mountRef
+	m        map[uint64]mountRef
+	bindOnly bool
 }
 
 func (s *submounts) subMount(m mount.Mount, subPath string) (mount.Mount, error) {
-	if path.Join("/", subPath) == "/" {
+	if path.Join("/", subPath) == "/" && (m.Type == "bind" || !s.bindOnly) {
 		return m, nil
 	}
 	if s.m == nil {
@@ -4,6 +4,7 @@ package oci
 
 import (
 	"context"
+	"strings"
 
 	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/oci"
@@ -11,9 +12,11 @@ import (
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/profiles/seccomp"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/cdidevices"
 	"github.com/moby/buildkit/util/entitlements/security"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/selinux/go-selinux/label"
+	"github.com/pkg/errors"
 )
 
 func generateMountOpts(resolvConf, hostsFile string) ([]oci.SpecOpts, error) {
@@ -87,3 +90,26 @@ func withDefaultProfile() oci.SpecOpts {
 		return err
 	}
 }
+
+func generateCDIOpts(manager *cdidevices.Manager, devices []*pb.CDIDevice) ([]oci.SpecOpts, error) {
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	if manager == nil {
+		var required []string
+		for _, d := range devices {
+			if !d.Optional {
+				required = append(required, d.Name)
+			}
+		}
+		if len(required) > 0 {
+			return nil, errors.Errorf("CDI devices are not enabled, requested: %s", strings.Join(required, ", "))
+		}
+		return nil, nil
+	}
+	return []oci.SpecOpts{
+		func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+			return manager.InjectDevices(s, devices...)
+		},
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/containerd/containerd/oci"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/cdidevices"
 	"github.com/pkg/errors"
 )
 
@@ -35,3 +36,10 @@ func generateIDmapOpts(idmap *idtools.IdentityMapping) ([]oci.SpecOpts, error) {
 	}
 	return nil, errors.New("no support for IdentityMapping on Windows")
 }
+
+func generateCDIOpts(_ *cdidevices.Manager, devices []*pb.CDIDevice) ([]oci.SpecOpts, error) {
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	return nil, errors.New("no support for CDI devices on Windows")
+}
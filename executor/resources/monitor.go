@@ -0,0 +1,262 @@
+package resources
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	resourcestypes "github.com/moby/buildkit/executor/resources/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMountpoint = "/sys/fs/cgroup"
+	sampleInterval    = 200 * time.Millisecond
+
+	// containerCgroup is the name of the child cgroup the container is
+	// placed in. The recorded cgroup itself never contains processes, so it
+	// survives the container and keeps the accumulated stats of the child.
+	containerCgroup = "container"
+)
+
+// Monitor records the resource usage of containers from cgroup v2 stats.
+// A nil Monitor is valid and does not record anything.
+type Monitor struct {
+	mountpoint string
+	ownCgroup  string
+}
+
+// NewMonitor returns a new Monitor or nil if cgroup v2 is not available on
+// the host.
+func NewMonitor() *Monitor {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(defaultMountpoint, "cgroup.controllers")); err != nil {
+		return nil
+	}
+	own, err := ownCgroup()
+	if err != nil {
+		return nil
+	}
+	return &Monitor{
+		mountpoint: defaultMountpoint,
+		ownCgroup:  own,
+	}
+}
+
+// Record starts recording the resource usage of the cgroup at cgroupsPath,
+// as defined by the OCI runtime spec. A relative path is resolved like runc
+// does, relative to the parent of the daemon's own cgroup. Systemd style
+// paths are not supported and return a nil Recorder.
+//
+// The container must be placed in the cgroup returned by
+// Recorder.CgroupsPath instead of cgroupsPath.
+func (m *Monitor) Record(cgroupsPath string) (*Recorder, error) {
+	if m == nil || cgroupsPath == "" || strings.Contains(cgroupsPath, ":") {
+		return nil, nil
+	}
+	dir := filepath.Join(m.mountpoint, filepath.Clean("/"+cgroupsPath))
+	if !filepath.IsAbs(cgroupsPath) {
+		dir = filepath.Join(m.mountpoint, filepath.Dir(m.ownCgroup), filepath.Clean("/"+cgroupsPath))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create cgroup %s", dir)
+	}
+	r := &Recorder{
+		dir:         dir,
+		cgroupsPath: filepath.Join(cgroupsPath, containerCgroup),
+		done:        make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// Recorder samples the resource usage of a single container.
+type Recorder struct {
+	dir         string
+	cgroupsPath string
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	mu         sync.Mutex
+	memoryPeak int64
+	pidsPeak   int64
+}
+
+// CgroupsPath returns the cgroups path the container needs to be placed in.
+func (r *Recorder) CgroupsPath() string {
+	return r.cgroupsPath
+}
+
+func (r *Recorder) run() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	for {
+		r.sample()
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample tracks the peak values for kernels that don't provide the
+// memory.peak and pids.peak files.
+func (r *Recorder) sample() {
+	mem, _ := readInt(filepath.Join(r.dir, "memory.current"))
+	pids, _ := readInt(filepath.Join(r.dir, "pids.current"))
+	r.mu.Lock()
+	if mem > r.memoryPeak {
+		r.memoryPeak = mem
+	}
+	if pids > r.pidsPeak {
+		r.pidsPeak = pids
+	}
+	r.mu.Unlock()
+}
+
+// Close stops the sampling, removes the cgroup and returns the resource usage
+// of the container. Close must be called after the container has exited.
+// The returned usage is nil if no stats could be read.
+func (r *Recorder) Close() (*resourcestypes.Usage, error) {
+	if r == nil {
+		return nil, nil
+	}
+	var usage *resourcestypes.Usage
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+		r.wg.Wait()
+		usage, err = r.usage()
+		// the runtime normally removes the container cgroup itself
+		os.Remove(filepath.Join(r.dir, containerCgroup))
+		if rerr := os.Remove(r.dir); rerr != nil && !os.IsNotExist(rerr) && err == nil {
+			err = errors.Wrapf(rerr, "failed to remove cgroup %s", r.dir)
+		}
+	})
+	return usage, err
+}
+
+func (r *Recorder) usage() (*resourcestypes.Usage, error) {
+	cpu, err := readKeyValues(filepath.Join(r.dir, "cpu.stat"))
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	u := &resourcestypes.Usage{
+		CPUNanos:       cpu["usage_usec"] * int64(time.Microsecond),
+		CPUUserNanos:   cpu["user_usec"] * int64(time.Microsecond),
+		CPUSystemNanos: cpu["system_usec"] * int64(time.Microsecond),
+	}
+
+	r.sample()
+	r.mu.Lock()
+	u.MemoryPeak = r.memoryPeak
+	u.PidsPeak = r.pidsPeak
+	r.mu.Unlock()
+	if v, err := readInt(filepath.Join(r.dir, "memory.peak")); err == nil && v > u.MemoryPeak {
+		u.MemoryPeak = v
+	}
+	if v, err := readInt(filepath.Join(r.dir, "pids.peak")); err == nil && v > u.PidsPeak {
+		u.PidsPeak = v
+	}
+
+	if dt, err := ioutil.ReadFile(filepath.Join(r.dir, "io.stat")); err == nil {
+		u.IOReadBytes, u.IOWriteBytes = parseIOStat(dt)
+	}
+
+	if u.CPUNanos == 0 && u.MemoryPeak == 0 && u.PidsPeak == 0 {
+		// the container was not placed under the recorded cgroup
+		return nil, nil
+	}
+	return u, nil
+}
+
+// parseIOStat sums the read and written bytes of all devices in io.stat.
+// Lines are in the format "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 ...".
+func parseIOStat(dt []byte) (read int64, write int64) {
+	s := bufio.NewScanner(bytes.NewReader(dt))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch kv[0] {
+			case "rbytes":
+				read += v
+			case "wbytes":
+				write += v
+			}
+		}
+	}
+	return read, write
+}
+
+func readKeyValues(fn string) (map[string]int64, error) {
+	dt, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	m := map[string]int64{}
+	s := bufio.NewScanner(bytes.NewReader(dt))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		m[fields[0]] = v
+	}
+	return m, nil
+}
+
+func readInt(fn string) (int64, error) {
+	dt, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(dt))
+	if s == "max" {
+		return 0, errors.Errorf("unlimited value in %s", fn)
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// ownCgroup returns the cgroup v2 path of the current process.
+func ownCgroup() (string, error) {
+	dt, err := ioutil.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	s := bufio.NewScanner(bytes.NewReader(dt))
+	for s.Scan() {
+		if p := strings.TrimPrefix(s.Text(), "0::"); p != s.Text() {
+			return p, nil
+		}
+	}
+	return "", errors.New("cgroup v2 path not found")
+}
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIOStat(t *testing.T) {
+	t.Parallel()
+
+	read, write := parseIOStat([]byte(`8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0
+8:16 rbytes=100 wbytes=0 rios=3 wios=0 dbytes=0 dios=0
+`))
+	require.Equal(t, int64(1124), read)
+	require.Equal(t, int64(2048), write)
+
+	read, write = parseIOStat(nil)
+	require.Equal(t, int64(0), read)
+	require.Equal(t, int64(0), write)
+}
+
+func TestRecorderUsage(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "buildkit-resources")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"cpu.stat":       "usage_usec 1500\nuser_usec 1000\nsystem_usec 500\nnr_periods 0\n",
+		"memory.current": "4096\n",
+		"memory.peak":    "8192\n",
+		"pids.current":   "0\n",
+		"pids.peak":      "3\n",
+		"io.stat":        "8:0 rbytes=10 wbytes=20 rios=1 wios=1 dbytes=0 dios=0\n",
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	r := &Recorder{dir: dir}
+	u, err := r.usage()
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	require.Equal(t, int64(1500000), u.CPUNanos)
+	require.Equal(t, int64(1000000), u.CPUUserNanos)
+	require.Equal(t, int64(500000), u.CPUSystemNanos)
+	require.Equal(t, int64(8192), u.MemoryPeak)
+	require.Equal(t, int64(3), u.PidsPeak)
+	require.Equal(t, int64(10), u.IOReadBytes)
+	require.Equal(t, int64(20), u.IOWriteBytes)
+
+	empty, err := ioutil.TempDir("", "buildkit-resources")
+	require.NoError(t, err)
+	defer os.RemoveAll(empty)
+
+	u, err = (&Recorder{dir: empty}).usage()
+	require.NoError(t, err)
+	require.Nil(t, u)
+}
+
+func TestNilMonitor(t *testing.T) {
+	t.Parallel()
+
+	var m *Monitor
+	r, err := m.Record("/buildkit/foo")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	u, err := r.Close()
+	require.NoError(t, err)
+	require.Nil(t, u)
+}
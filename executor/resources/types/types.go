@@ -0,0 +1,19 @@
+package types
+
+// Usage is the resource usage of a container, read from its cgroup.
+type Usage struct {
+	// CPUNanos is the total CPU time (user and system) consumed.
+	CPUNanos int64
+	// CPUUserNanos is the CPU time consumed in user mode.
+	CPUUserNanos int64
+	// CPUSystemNanos is the CPU time consumed in kernel mode.
+	CPUSystemNanos int64
+	// MemoryPeak is the maximum memory usage in bytes.
+	MemoryPeak int64
+	// IOReadBytes is the number of bytes read from block devices.
+	IOReadBytes int64
+	// IOWriteBytes is the number of bytes written to block devices.
+	IOWriteBytes int64
+	// PidsPeak is the maximum number of processes running at the same time.
+	PidsPeak int64
+}
@@ -21,9 +21,12 @@ import (
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/moby/buildkit/executor"
 	"github.com/moby/buildkit/executor/oci"
+	"github.com/moby/buildkit/executor/resources"
+	resourcestypes "github.com/moby/buildkit/executor/resources/types"
 	gatewayapi "github.com/moby/buildkit/frontend/gateway/pb"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/cdidevices"
 	"github.com/moby/buildkit/util/network"
 	rootlessspecconv "github.com/moby/buildkit/util/rootless/specconv"
 	"github.com/moby/buildkit/util/stack"
@@ -48,6 +51,10 @@ type Opt struct {
 	OOMScoreAdj     *int
 	ApparmorProfile string
 	TracingSocket   string
+	// ResourceMonitor records the resource usage of containers. Optional.
+	ResourceMonitor *resources.Monitor
+	// CDIManager injects CDI devices into containers. Optional.
+	CDIManager *cdidevices.Manager
 }
 
 var defaultCommandCandidates = []string{"buildkit-runc", "runc"}
@@ -67,6 +74,8 @@ type runcExecutor struct {
 	mu               sync.Mutex
 	apparmorProfile  string
 	tracingSocket    string
+	resmon           *resources.Monitor
+	cdiManager       *cdidevices.Manager
 }
 
 func New(opt Opt, networkProviders map[pb.NetMode]network.Provider) (executor.Executor, error) {
@@ -131,11 +140,13 @@ func New(opt Opt, networkProviders map[pb.NetMode]network.Provider) (executor.Ex
 		running:          make(map[string]chan error),
 		apparmorProfile:  opt.ApparmorProfile,
 		tracingSocket:    opt.TracingSocket,
+		resmon:           opt.ResourceMonitor,
+		cdiManager:       opt.CDIManager,
 	}
 	return w, nil
 }
 
-func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) (err error) {
+func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) (_ *resourcestypes.Usage, err error) {
 	meta := process.Meta
 
 	startedOnce := sync.Once{}
@@ -158,11 +169,11 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	provider, ok := w.networkProviders[meta.NetMode]
 	if !ok {
-		return errors.Errorf("unknown network mode %s", meta.NetMode)
+		return nil, errors.Errorf("unknown network mode %s", meta.NetMode)
 	}
 	namespace, err := provider.New()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer namespace.Close()
 
@@ -172,12 +183,12 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	resolvConf, err := oci.GetResolvConf(ctx, w.root, w.idmap, w.dns)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	hostsFile, clean, err := oci.GetHostsFile(ctx, w.root, meta.ExtraHosts, w.idmap, meta.Hostname)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if clean != nil {
 		defer clean()
@@ -185,12 +196,12 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	mountable, err := root.Src.Mount(ctx, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	rootMount, release, err := mountable.Mount()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if release != nil {
 		defer release()
@@ -202,7 +213,7 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 	bundle := filepath.Join(w.root, id)
 
 	if err := os.Mkdir(bundle, 0711); err != nil {
-		return err
+		return nil, err
 	}
 	defer os.RemoveAll(bundle)
 
@@ -213,10 +224,10 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	rootFSPath := filepath.Join(bundle, "rootfs")
 	if err := idtools.MkdirAllAndChown(rootFSPath, 0700, identity); err != nil {
-		return err
+		return nil, err
 	}
 	if err := mount.All(rootMount, rootFSPath); err != nil {
-		return err
+		return nil, err
 	}
 	defer mount.Unmount(rootFSPath, 0)
 
@@ -224,12 +235,12 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	uid, gid, sgids, err := oci.GetUser(rootFSPath, meta.User)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	f, err := os.Create(filepath.Join(bundle, "config.json"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f.Close()
 
@@ -246,7 +257,7 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 	if w.idmap != nil {
 		identity, err = w.idmap.ToHost(identity)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -260,9 +271,9 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 		}
 		opts = append(opts, containerdoci.WithCgroup(cgroupsPath))
 	}
-	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, w.processMode, w.idmap, w.apparmorProfile, w.tracingSocket, opts...)
+	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, w.processMode, w.idmap, w.apparmorProfile, w.tracingSocket, w.cdiManager, opts...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer cleanup()
 
@@ -273,11 +284,11 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	newp, err := fs.RootPath(rootFSPath, meta.Cwd)
 	if err != nil {
-		return errors.Wrapf(err, "working dir %s points to invalid target", newp)
+		return nil, errors.Wrapf(err, "working dir %s points to invalid target", newp)
 	}
 	if _, err := os.Stat(newp); err != nil {
 		if err := idtools.MkdirAllAndChown(newp, 0755, identity); err != nil {
-			return errors.Wrapf(err, "failed to create working directory %s", newp)
+			return nil, errors.Wrapf(err, "failed to create working directory %s", newp)
 		}
 	}
 
@@ -285,12 +296,24 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 	spec.Process.OOMScoreAdj = w.oomScoreAdj
 	if w.rootless {
 		if err := rootlessspecconv.ToRootless(spec); err != nil {
-			return err
+			return nil, err
+		}
+	}
+
+	var rec *resources.Recorder
+	if spec.Linux != nil {
+		rec, err = w.resmon.Record(spec.Linux.CgroupsPath)
+		if err != nil {
+			bklog.G(ctx).Debugf("failed to record resource usage of %s: %v", id, err)
+		}
+		if rec != nil {
+			spec.Linux.CgroupsPath = rec.CgroupsPath()
 		}
 	}
 
 	if err := json.NewEncoder(f).Encode(spec); err != nil {
-		return err
+		rec.Close()
+		return nil, err
 	}
 
 	// runCtx/killCtx is used for extra check in case the kill command blocks
@@ -303,7 +326,15 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 			select {
 			case <-ctx.Done():
 				killCtx, timeout := context.WithTimeout(context.Background(), 7*time.Second)
-				if err := w.runc.Kill(killCtx, id, int(syscall.SIGKILL), nil); err != nil {
+				// kill all processes of the container, not only the init
+				// process, e.g. for processes that are not in a pid namespace.
+				// Killing all processes requires cgroups so fall back to the
+				// init process if it fails.
+				err := w.runc.Kill(killCtx, id, int(syscall.SIGKILL), &runc.KillOpts{All: true})
+				if err != nil {
+					err = w.runc.Kill(killCtx, id, int(syscall.SIGKILL), nil)
+				}
+				if err != nil {
 					bklog.G(ctx).Errorf("failed to kill runc %s: %+v", id, err)
 					select {
 					case <-killCtx.Done():
@@ -335,7 +366,11 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	err = w.run(runCtx, id, bundle, process)
 	close(ended)
-	return exitError(ctx, err)
+	usage, rerr := rec.Close()
+	if rerr != nil {
+		bklog.G(ctx).Debugf("failed to record resource usage of %s: %v", id, rerr)
+	}
+	return usage, exitError(ctx, err)
 }
 
 func exitError(ctx context.Context, err error) error {
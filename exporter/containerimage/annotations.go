@@ -0,0 +1,178 @@
+package containerimage
+
+import (
+	"context"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/util/bklog"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Annotations are the OCI annotations added to the objects of an exported
+// image.
+type Annotations struct {
+	Index              map[string]string
+	IndexDescriptor    map[string]string
+	Manifest           map[string]string
+	ManifestDescriptor map[string]string
+}
+
+// AnnotationsGroup maps platforms to the annotations of their manifests. The
+// empty key holds the annotations that apply to all platforms.
+type AnnotationsGroup map[string]*Annotations
+
+// ParseAnnotations returns the annotations set in exporter attributes or
+// metadata and the remaining values.
+func ParseAnnotations(data map[string][]byte) (AnnotationsGroup, map[string][]byte, error) {
+	ag := make(AnnotationsGroup)
+	rest := make(map[string][]byte)
+	for k, v := range data {
+		ak, ok, err := exptypes.ParseAnnotationKey(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			rest[k] = v
+			continue
+		}
+		ag.add(ak, string(v))
+	}
+	return ag, rest, nil
+}
+
+// ParseFrontendAnnotations returns the annotations set by the frontend in the
+// metadata of its result. Docker media types can't carry annotations on
+// indexes and manifests, so these are dropped with a warning unless ociTypes
+// is set.
+func ParseFrontendAnnotations(ctx context.Context, metadata map[string][]byte, ociTypes bool) (AnnotationsGroup, error) {
+	ag, _, err := ParseAnnotations(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if ociTypes {
+		return ag, nil
+	}
+	var dropped bool
+	for _, a := range ag {
+		if len(a.Index)+len(a.Manifest)+len(a.ManifestDescriptor) > 0 {
+			dropped = true
+		}
+		a.Index, a.Manifest, a.ManifestDescriptor = nil, nil, nil
+	}
+	if dropped {
+		const msg = "ignoring annotations set by the frontend, they require oci-mediatypes to be enabled"
+		bklog.G(ctx).Warn(msg)
+		oneOffProgress(ctx, "WARNING: "+msg)(nil)
+	}
+	return ag, nil
+}
+
+func (ag AnnotationsGroup) add(ak exptypes.AnnotationKey, v string) {
+	var pk string
+	if ak.Platform != nil {
+		pk = platforms.Format(*ak.Platform)
+	}
+	a, ok := ag[pk]
+	if !ok {
+		a = &Annotations{}
+		ag[pk] = a
+	}
+	var m *map[string]string
+	switch ak.Type {
+	case exptypes.AnnotationIndex:
+		m = &a.Index
+	case exptypes.AnnotationIndexDescriptor:
+		m = &a.IndexDescriptor
+	case exptypes.AnnotationManifest:
+		m = &a.Manifest
+	case exptypes.AnnotationManifestDescriptor:
+		m = &a.ManifestDescriptor
+	}
+	if *m == nil {
+		*m = make(map[string]string)
+	}
+	(*m)[ak.Key] = v
+}
+
+// Merge returns a group with the annotations of ag and other. Values of other
+// take precedence.
+func (ag AnnotationsGroup) Merge(other AnnotationsGroup) AnnotationsGroup {
+	res := make(AnnotationsGroup)
+	for _, g := range []AnnotationsGroup{ag, other} {
+		for pk, a := range g {
+			res.merge(pk, a)
+		}
+	}
+	return res
+}
+
+// Platform returns the annotations that apply to the manifest of platform p.
+// Platform specific values take precedence. A nil p only returns the
+// annotations that apply to all platforms.
+func (ag AnnotationsGroup) Platform(p *ocispecs.Platform) *Annotations {
+	res := make(AnnotationsGroup)
+	res.merge("", ag[""])
+	if p != nil {
+		res.merge("", ag[platforms.Format(platforms.Normalize(*p))])
+	}
+	if a, ok := res[""]; ok {
+		return a
+	}
+	return &Annotations{}
+}
+
+// Empty returns true if there are no annotations in the group.
+func (ag AnnotationsGroup) Empty() bool {
+	for _, a := range ag {
+		if len(a.Index)+len(a.IndexDescriptor)+len(a.Manifest)+len(a.ManifestDescriptor) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ag AnnotationsGroup) merge(pk string, a *Annotations) {
+	if a == nil {
+		return
+	}
+	for k, v := range a.Index {
+		ag.add(exptypes.AnnotationKey{Type: exptypes.AnnotationIndex, Key: k}, v)
+	}
+	for k, v := range a.IndexDescriptor {
+		ag.add(exptypes.AnnotationKey{Type: exptypes.AnnotationIndexDescriptor, Key: k}, v)
+	}
+	p := parsePlatformKey(pk)
+	for k, v := range a.Manifest {
+		ag.add(exptypes.AnnotationKey{Type: exptypes.AnnotationManifest, Platform: p, Key: k}, v)
+	}
+	for k, v := range a.ManifestDescriptor {
+		ag.add(exptypes.AnnotationKey{Type: exptypes.AnnotationManifestDescriptor, Platform: p, Key: k}, v)
+	}
+}
+
+func parsePlatformKey(pk string) *ocispecs.Platform {
+	if pk == "" {
+		return nil
+	}
+	p := platforms.MustParse(pk)
+	return &p
+}
+
+// ParseAnnotationAttrs returns the annotations set in exporter attributes and
+// the remaining attributes.
+func ParseAnnotationAttrs(opt map[string]string) (AnnotationsGroup, map[string]string, error) {
+	data := make(map[string][]byte, len(opt))
+	for k, v := range opt {
+		data[k] = []byte(v)
+	}
+	ag, rest, err := ParseAnnotations(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	restOpt := make(map[string]string, len(rest))
+	for k, v := range rest {
+		restOpt[k] = string(v)
+	}
+	return ag, restOpt, nil
+}
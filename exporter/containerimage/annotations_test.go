@@ -0,0 +1,84 @@
+package containerimage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	t.Parallel()
+
+	arm64 := platforms.MustParse("linux/arm64")
+	amd64 := platforms.MustParse("linux/amd64")
+
+	ag, rest, err := ParseAnnotationAttrs(map[string]string{
+		"name":                                   "foo",
+		"annotation.title":                       "all",
+		"annotation-manifest[linux/arm64].title": "arm64",
+		"annotation-manifest[linux/arm64/v8].other": "arm64-other",
+		"annotation-index.index":                    "idx",
+		"annotation-index-descriptor.index":         "idxdesc",
+		"annotation-manifest-descriptor.desc":       "mfstdesc",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "foo"}, rest)
+
+	a := ag.Platform(&arm64)
+	require.Equal(t, map[string]string{"title": "arm64", "other": "arm64-other"}, a.Manifest)
+	require.Equal(t, map[string]string{"desc": "mfstdesc"}, a.ManifestDescriptor)
+	require.Equal(t, map[string]string{"index": "idx"}, a.Index)
+
+	a = ag.Platform(&amd64)
+	require.Equal(t, map[string]string{"title": "all"}, a.Manifest)
+
+	a = ag.Platform(nil)
+	require.Equal(t, map[string]string{"title": "all"}, a.Manifest)
+	require.Equal(t, map[string]string{"index": "idxdesc"}, a.IndexDescriptor)
+
+	frontend, _, err := ParseAnnotations(map[string][]byte{
+		exptypes.AnnotationManifestKey(nil, "title"):    []byte("frontend"),
+		exptypes.AnnotationManifestKey(&amd64, "title"): []byte("frontend-amd64"),
+		exptypes.AnnotationIndexKey("source"):           []byte("git"),
+	})
+	require.NoError(t, err)
+	merged := frontend.Merge(ag)
+	require.Equal(t, map[string]string{"title": "all"}, merged.Platform(nil).Manifest)
+	require.Equal(t, map[string]string{"title": "frontend-amd64"}, merged.Platform(&amd64).Manifest)
+	require.Equal(t, map[string]string{"index": "idx", "source": "git"}, merged.Platform(nil).Index)
+	require.False(t, merged.Empty())
+	require.True(t, AnnotationsGroup{}.Empty())
+
+	_, _, err = ParseAnnotationAttrs(map[string]string{"annotation-index[linux/amd64].foo": "bar"})
+	require.Error(t, err)
+	_, _, err = ParseAnnotationAttrs(map[string]string{"annotation-config.foo": "bar"})
+	require.Error(t, err)
+	_, _, err = ParseAnnotationAttrs(map[string]string{"annotation-manifest[invalid/os/arch/x].foo": "bar"})
+	require.Error(t, err)
+}
+
+func TestParseFrontendAnnotations(t *testing.T) {
+	t.Parallel()
+
+	amd64 := platforms.MustParse("linux/amd64")
+	metadata := map[string][]byte{
+		exptypes.AnnotationManifestKey(&amd64, "title"): []byte("frontend-amd64"),
+		exptypes.AnnotationIndexKey("source"):           []byte("git"),
+		"annotation-index-descriptor.index":             []byte("idxdesc"),
+		"containerimage.config":                         []byte("{}"),
+	}
+
+	ag, err := ParseFrontendAnnotations(context.TODO(), metadata, true)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"title": "frontend-amd64"}, ag.Platform(&amd64).Manifest)
+	require.Equal(t, map[string]string{"source": "git"}, ag.Platform(nil).Index)
+
+	ag, err = ParseFrontendAnnotations(context.TODO(), metadata, false)
+	require.NoError(t, err)
+	require.Empty(t, ag.Platform(&amd64).Manifest)
+	require.Empty(t, ag.Platform(nil).Index)
+	require.Equal(t, map[string]string{"index": "idxdesc"}, ag.Platform(nil).IndexDescriptor)
+}
@@ -2,6 +2,7 @@ package containerimage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,13 +18,13 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/exporter"
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/exporter/util/epoch"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/util/buildinfo"
 	"github.com/moby/buildkit/util/compression"
-	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/leaseutil"
-	"github.com/moby/buildkit/util/push"
+	"github.com/moby/buildkit/util/resolver/retryhandler"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/identity"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
@@ -41,8 +42,16 @@ const (
 	keyNameCanonical    = "name-canonical"
 	keyLayerCompression = "compression"
 	keyForceCompression = "force-compression"
+	keyCompressionLevel = "compression-level"
 	keyBuildInfo        = "buildinfo"
+	keySignSecret       = "sign.secret"
+	keyPushRetries      = "push-retries"
+	keyPushRetryBackoff = "push-retry-backoff"
+	keyPushRollback     = "push-rollback"
 	ociTypes            = "oci-mediatypes"
+
+	// keySignAnnotationPrefix sets optional values in the signature payload.
+	keySignAnnotationPrefix = "sign.annotation."
 )
 
 type Opt struct {
@@ -69,11 +78,22 @@ func New(opt Opt) (exporter.Exporter, error) {
 func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
 	i := &imageExporterInstance{
 		imageExporter:    e,
-		layerCompression: compression.Default,
+		layerCompression: compression.New(compression.Default),
 		buildInfoMode:    buildinfo.ExportDefault,
+		retryOpts:        retryhandler.DefaultOpts,
+	}
+
+	tm, opt, err := epoch.ParseExporterAttrs(opt)
+	if err != nil {
+		return nil, err
+	}
+	i.epoch = tm
+
+	i.annotations, opt, err = ParseAnnotationAttrs(opt)
+	if err != nil {
+		return nil, err
 	}
 
-	var esgz bool
 	for k, v := range opt {
 		switch k {
 		case keyImageName:
@@ -141,29 +161,27 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 			}
 			i.nameCanonical = b
 		case keyLayerCompression:
-			switch v {
-			case "gzip":
-				i.layerCompression = compression.Gzip
-			case "estargz":
-				i.layerCompression = compression.EStargz
-				esgz = true
-			case "zstd":
-				i.layerCompression = compression.Zstd
-			case "uncompressed":
-				i.layerCompression = compression.Uncompressed
-			default:
-				return nil, errors.Errorf("unsupported layer compression type: %v", v)
+			t, err := compression.Parse(v)
+			if err != nil {
+				return nil, err
 			}
+			i.layerCompression.Type = t
 		case keyForceCompression:
 			if v == "" {
-				i.forceCompression = true
+				i.layerCompression.Force = true
 				continue
 			}
 			b, err := strconv.ParseBool(v)
 			if err != nil {
 				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
 			}
-			i.forceCompression = b
+			i.layerCompression.Force = b
+		case keyCompressionLevel:
+			level, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-int value specified for %s", k)
+			}
+			i.layerCompression.Level = &level
 		case keyBuildInfo:
 			if v == "" {
 				continue
@@ -173,16 +191,61 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 				return nil, err
 			}
 			i.buildInfoMode = bimode
+		case keySignSecret:
+			i.signSecret = v
+		case keyPushRollback:
+			if v == "" {
+				i.pushRollback = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.pushRollback = b
+		case keyPushRetries:
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, errors.Errorf("invalid value %q for %s, expected a non-negative integer", v, k)
+			}
+			i.retryOpts.MaxRetries = n
+		case keyPushRetryBackoff:
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return nil, errors.Errorf("invalid value %q for %s, expected a positive duration", v, k)
+			}
+			i.retryOpts.InitialBackoff = d
 		default:
+			if strings.HasPrefix(k, keySignAnnotationPrefix) {
+				if i.signAnnotations == nil {
+					i.signAnnotations = make(map[string]string)
+				}
+				i.signAnnotations[strings.TrimPrefix(k, keySignAnnotationPrefix)] = v
+				continue
+			}
 			if i.meta == nil {
 				i.meta = make(map[string][]byte)
 			}
 			i.meta[k] = []byte(v)
 		}
 	}
-	if esgz && !i.ociTypes {
-		logrus.Warn("forcibly turning on oci-mediatype mode for estargz")
-		i.ociTypes = true
+	switch i.layerCompression.Type {
+	case compression.EStargz, compression.ZstdChunked:
+		if !i.ociTypes {
+			logrus.Warnf("forcibly turning on oci-mediatype mode for %s", i.layerCompression.Type)
+			i.ociTypes = true
+		}
+	}
+	if i.layerCompression.Level != nil {
+		if err := i.layerCompression.Type.CheckLevel(*i.layerCompression.Level); err != nil {
+			return nil, err
+		}
+	}
+	if i.signSecret == "" && i.signAnnotations != nil {
+		return nil, errors.Errorf("%s requires %s", keySignAnnotationPrefix+"*", keySignSecret)
+	}
+	if i.signSecret != "" && !i.push {
+		return nil, errors.Errorf("%s requires push to be enabled", keySignSecret)
 	}
 	return i, nil
 }
@@ -197,9 +260,14 @@ type imageExporterInstance struct {
 	ociTypes         bool
 	nameCanonical    bool
 	danglingPrefix   string
-	layerCompression compression.Type
-	forceCompression bool
+	layerCompression compression.Config
 	buildInfoMode    buildinfo.ExportMode
+	epoch            *time.Time
+	annotations      AnnotationsGroup
+	signSecret       string
+	signAnnotations  map[string]string
+	retryOpts        retryhandler.Opts
+	pushRollback     bool
 	meta             map[string][]byte
 }
 
@@ -215,13 +283,25 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 		src.Metadata[k] = v
 	}
 
+	annotations, err := ParseFrontendAnnotations(ctx, src.Metadata, e.ociTypes)
+	if err != nil {
+		return nil, err
+	}
+	annotations = annotations.Merge(e.annotations)
+
 	ctx, done, err := leaseutil.WithLease(ctx, e.opt.LeaseManager, leaseutil.MakeTemporary)
 	if err != nil {
 		return nil, err
 	}
 	defer done(context.TODO())
 
-	desc, err := e.opt.ImageWriter.Commit(ctx, src, e.ociTypes, e.layerCompression, e.buildInfoMode, e.forceCompression, sessionID)
+	desc, err := e.opt.ImageWriter.Commit(ctx, src, sessionID, ImageCommitOpts{
+		OCITypes:      e.ociTypes,
+		Compression:   e.layerCompression,
+		BuildInfoMode: e.buildInfoMode,
+		Epoch:         e.epoch,
+		Annotations:   annotations,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -284,36 +364,18 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 					}
 				}
 			}
-			if e.push {
-				annotations := map[digest.Digest]map[string]string{}
-				mprovider := contentutil.NewMultiProvider(e.opt.ImageWriter.ContentStore())
-				if src.Ref != nil {
-					remote, err := src.Ref.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
-					if err != nil {
-						return nil, err
-					}
-					for _, desc := range remote.Descriptors {
-						mprovider.Add(desc.Digest, remote.Provider)
-						addAnnotations(annotations, desc)
-					}
-				}
-				if len(src.Refs) > 0 {
-					for _, r := range src.Refs {
-						remote, err := r.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
-						if err != nil {
-							return nil, err
-						}
-						for _, desc := range remote.Descriptors {
-							mprovider.Add(desc.Digest, remote.Provider)
-							addAnnotations(annotations, desc)
-						}
-					}
-				}
-
-				if err := push.Push(ctx, e.opt.SessionManager, sessionID, mprovider, e.opt.ImageWriter.ContentStore(), desc.Digest, targetName, e.insecure, e.opt.RegistryHosts, e.pushByDigest, annotations); err != nil {
-					return nil, err
-				}
+		}
+		if e.push {
+			results, err := e.pushImage(ctx, src, sessionID, *desc, targetNames)
+			if err != nil {
+				logPushResults(ctx, results)
+				return nil, err
+			}
+			dt, err := json.Marshal(results)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to marshal push results")
 			}
+			resp[exptypes.ExporterImagePushKey] = string(dt)
 		}
 		resp["image.name"] = e.targetName
 	}
@@ -352,10 +414,16 @@ func (e *imageExporterInstance) unpackImage(ctx context.Context, img images.Imag
 		}
 	}
 
-	remote, err := topLayerRef.GetRemote(ctx, true, e.layerCompression, e.forceCompression, s)
+	remote, err := topLayerRef.GetRemote(ctx, true, e.layerCompression, s)
 	if err != nil {
 		return err
 	}
+	if e.epoch != nil {
+		remote, err = cache.RewriteLayerTimestamps(ctx, contentStore, remote, *e.epoch)
+		if err != nil {
+			return err
+		}
+	}
 
 	// ensure the content for each layer exists locally in case any are lazy
 	if unlazier, ok := remote.Provider.(cache.Unlazier); ok {
@@ -0,0 +1,80 @@
+package exptypes
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/containerd/containerd/platforms"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Annotation types select the object of the exported image an annotation is
+// added to.
+const (
+	AnnotationIndex              = "index"
+	AnnotationIndexDescriptor    = "index-descriptor"
+	AnnotationManifest           = "manifest"
+	AnnotationManifestDescriptor = "manifest-descriptor"
+)
+
+// AnnotationKey is the parsed form of an annotation exporter attribute or
+// metadata key, e.g. "annotation-manifest[linux/arm64].org.opencontainers.image.title".
+type AnnotationKey struct {
+	Type     string
+	Platform *ocispecs.Platform
+	Key      string
+}
+
+func (k AnnotationKey) String() string {
+	prefix := "annotation"
+	if k.Type != "" {
+		prefix += "-" + k.Type
+	}
+	if k.Platform != nil {
+		prefix += fmt.Sprintf("[%s]", platforms.Format(*k.Platform))
+	}
+	return prefix + "." + k.Key
+}
+
+// AnnotationIndexKey returns the metadata key for an annotation of the index.
+func AnnotationIndexKey(key string) string {
+	return AnnotationKey{Type: AnnotationIndex, Key: key}.String()
+}
+
+// AnnotationManifestKey returns the metadata key for an annotation of the
+// manifest of platform p, or of all manifests if p is nil.
+func AnnotationManifestKey(p *ocispecs.Platform, key string) string {
+	return AnnotationKey{Type: AnnotationManifest, Platform: p, Key: key}.String()
+}
+
+var annotationKeyRe = regexp.MustCompile(`^annotation(?:-([a-z-]+))?(?:\[([^\]]*)\])?\.(.+)$`)
+
+// ParseAnnotationKey parses an annotation attribute or metadata key. It
+// returns false if k is not an annotation key.
+func ParseAnnotationKey(k string) (AnnotationKey, bool, error) {
+	m := annotationKeyRe.FindStringSubmatch(k)
+	if m == nil {
+		return AnnotationKey{}, false, nil
+	}
+	ak := AnnotationKey{Type: m[1], Key: m[3]}
+	switch ak.Type {
+	case "":
+		ak.Type = AnnotationManifest
+	case AnnotationIndex, AnnotationIndexDescriptor, AnnotationManifest, AnnotationManifestDescriptor:
+	default:
+		return AnnotationKey{}, true, errors.Errorf("unknown annotation type %q in %s", ak.Type, k)
+	}
+	if m[2] != "" {
+		if ak.Type == AnnotationIndex || ak.Type == AnnotationIndexDescriptor {
+			return AnnotationKey{}, true, errors.Errorf("platform can't be set for %s annotation %s", ak.Type, k)
+		}
+		p, err := platforms.Parse(m[2])
+		if err != nil {
+			return AnnotationKey{}, true, errors.Wrapf(err, "invalid platform in annotation %s", k)
+		}
+		p = platforms.Normalize(p)
+		ak.Platform = &p
+	}
+	return ak, true, nil
+}
@@ -14,6 +14,7 @@ const (
 	ExporterInlineCache          = "containerimage.inlinecache"
 	ExporterBuildInfo            = "containerimage.buildinfo"
 	ExporterPlatformsKey         = "refs.platforms"
+	ExporterImagePushKey         = "containerimage.push"
 )
 
 const EmptyGZLayer = digest.Digest("sha256:4f4fb700ef54461cfa02571ae0db9a0dc1e0cdb5577484a6d75e68dc38e8acc1")
@@ -27,6 +28,35 @@ type Platform struct {
 	Platform ocispecs.Platform
 }
 
+// PushResult is the status of pushing the image to one of the names of the
+// image exporter. A JSON encoded list of results is returned in the solver
+// ExporterResponse as ExporterImagePushKey.
+type PushResult struct {
+	// Name is the name the image was pushed to.
+	Name string `json:"name"`
+	// Digest is the digest of the pushed image.
+	Digest digest.Digest `json:"digest"`
+	// Previous is the image the tag of Name pointed to before the push. It is
+	// only resolved when push rollback is enabled.
+	Previous *ocispecs.Descriptor `json:"previous,omitempty"`
+	// Status is the result of the push.
+	Status PushStatus `json:"status"`
+}
+
+// PushStatus is the result of pushing the image to a single name.
+type PushStatus string
+
+const (
+	PushStatusPushed PushStatus = "pushed"
+	PushStatusFailed PushStatus = "failed"
+	// PushStatusSkipped is set for names that were not pushed because the
+	// push to another name failed.
+	PushStatusSkipped PushStatus = "skipped"
+	// PushStatusRolledBack is set for names whose tag was pointed back to the
+	// previous image after the push to another name failed.
+	PushStatusRolledBack PushStatus = "rolled-back"
+)
+
 // BuildInfo defines build dependencies that will be added to image config as
 // moby.buildkit.buildinfo.v1 key and returned in solver ExporterResponse as
 // ExporterBuildInfo key.
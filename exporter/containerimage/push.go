@@ -0,0 +1,190 @@
+package containerimage
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/progress/logs"
+	"github.com/moby/buildkit/util/push"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// pushImage pushes the image desc to all targetNames. The image is uploaded to
+// every repository before any tag is updated so that a failing destination
+// does not leave some of the tags pointing to the new image. If the push
+// fails after it was started, the results are returned with the error.
+func (e *imageExporterInstance) pushImage(ctx context.Context, src exporter.Source, sessionID string, desc ocispecs.Descriptor, targetNames []string) ([]exptypes.PushResult, error) {
+	var signingKey crypto.Signer
+	if e.signSecret != "" {
+		var err error
+		signingKey, err = e.signingKey(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	annotations := map[digest.Digest]map[string]string{}
+	mprovider := contentutil.NewMultiProvider(e.opt.ImageWriter.ContentStore())
+	if src.Ref != nil {
+		remote, err := src.Ref.GetRemote(ctx, false, e.layerCompression, session.NewGroup(sessionID))
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range remote.Descriptors {
+			mprovider.Add(desc.Digest, remote.Provider)
+			addAnnotations(annotations, desc)
+		}
+	}
+	if len(src.Refs) > 0 {
+		for _, r := range src.Refs {
+			remote, err := r.GetRemote(ctx, false, e.layerCompression, session.NewGroup(sessionID))
+			if err != nil {
+				return nil, err
+			}
+			for _, desc := range remote.Descriptors {
+				mprovider.Add(desc.Digest, remote.Provider)
+				addAnnotations(annotations, desc)
+			}
+		}
+	}
+
+	results := make([]exptypes.PushResult, len(targetNames))
+	for i, targetName := range targetNames {
+		results[i] = exptypes.PushResult{
+			Name:   targetName,
+			Digest: desc.Digest,
+			Status: exptypes.PushStatusSkipped,
+		}
+	}
+
+	opts := e.pushOpts(annotations)
+	if e.pushByDigest {
+		opts.ByDigest = true
+		for i, targetName := range targetNames {
+			if err := push.Push(ctx, e.opt.SessionManager, sessionID, mprovider, e.opt.ImageWriter.ContentStore(), desc.Digest, targetName, opts); err != nil {
+				results[i].Status = exptypes.PushStatusFailed
+				return results, err
+			}
+			if signingKey != nil {
+				if err := e.pushSignature(ctx, sessionID, signingKey, desc, targetName); err != nil {
+					results[i].Status = exptypes.PushStatusFailed
+					return results, err
+				}
+			}
+			results[i].Status = exptypes.PushStatusPushed
+		}
+		return results, nil
+	}
+
+	uploaded := map[string]struct{}{}
+	for i, targetName := range targetNames {
+		parsed, err := reference.ParseNormalizedNamed(targetName)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := uploaded[parsed.Name()]; ok {
+			continue
+		}
+		if err := push.Upload(ctx, e.opt.SessionManager, sessionID, mprovider, e.opt.ImageWriter.ContentStore(), desc.Digest, targetName, opts); err != nil {
+			results[i].Status = exptypes.PushStatusFailed
+			return results, errors.Wrapf(err, "failed to push %s, no tags were updated", targetName)
+		}
+		if signingKey != nil {
+			if err := e.pushSignature(ctx, sessionID, signingKey, desc, targetName); err != nil {
+				results[i].Status = exptypes.PushStatusFailed
+				return results, err
+			}
+		}
+		uploaded[parsed.Name()] = struct{}{}
+	}
+
+	if e.pushRollback {
+		for i, targetName := range targetNames {
+			prev, err := push.ResolveTag(ctx, e.opt.SessionManager, sessionID, targetName, opts)
+			if err != nil {
+				return results, errors.Wrapf(err, "failed to resolve current image of %s, no tags were updated", targetName)
+			}
+			results[i].Previous = prev
+		}
+	}
+
+	for i, targetName := range targetNames {
+		if err := push.Tag(ctx, e.opt.SessionManager, sessionID, mprovider, desc, targetName, opts); err != nil {
+			results[i].Status = exptypes.PushStatusFailed
+			err = errors.Wrapf(err, "failed to update tag %s", targetName)
+			if e.pushRollback {
+				return results, e.rollbackTags(ctx, sessionID, results[:i], err)
+			}
+			if i > 0 {
+				return results, errors.Wrapf(err, "tags of %s were already updated", joinNames(results[:i]))
+			}
+			return results, err
+		}
+		results[i].Status = exptypes.PushStatusPushed
+	}
+	return results, nil
+}
+
+// pushOpts returns the options to push to the registry with, annotations set
+// the distribution source of the pushed blobs.
+func (e *imageExporterInstance) pushOpts(annotations map[digest.Digest]map[string]string) push.Opts {
+	return push.Opts{
+		Insecure:    e.insecure,
+		Hosts:       e.opt.RegistryHosts,
+		Annotations: annotations,
+		Retry:       e.retryOpts,
+	}
+}
+
+// rollbackTags points the tags of the updated results back to the image they
+// pointed to before and sets their status. Tags that did not exist before can
+// not be removed and are left pointing to the new image.
+func (e *imageExporterInstance) rollbackTags(ctx context.Context, sessionID string, updated []exptypes.PushResult, err error) error {
+	var rolledBack, kept []exptypes.PushResult
+	for i := len(updated) - 1; i >= 0; i-- {
+		r := updated[i]
+		if r.Previous == nil {
+			kept = append(kept, r)
+			continue
+		}
+		if err := push.Tag(ctx, e.opt.SessionManager, sessionID, nil, *r.Previous, r.Name, e.pushOpts(nil)); err != nil {
+			kept = append(kept, r)
+			continue
+		}
+		updated[i].Status = exptypes.PushStatusRolledBack
+		rolledBack = append(rolledBack, r)
+	}
+	if len(rolledBack) > 0 {
+		err = errors.Wrapf(err, "rolled back tags of %s", joinNames(rolledBack))
+	}
+	if len(kept) > 0 {
+		err = errors.Wrapf(err, "tags of %s could not be rolled back", joinNames(kept))
+	}
+	return err
+}
+
+// logPushResults writes the results of a failed push to the progress of the
+// exporter as the exporter response is not returned for failed builds.
+func logPushResults(ctx context.Context, results []exptypes.PushResult) {
+	log := logs.LoggerFromContext(ctx)
+	for _, r := range results {
+		log([]byte(fmt.Sprintf("%s: %s\n", r.Name, r.Status)))
+	}
+}
+
+func joinNames(results []exptypes.PushResult) string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,103 @@
+package containerimage
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/moby/buildkit/util/cosign"
+	"github.com/moby/buildkit/util/push"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// signingKey loads the private key for signing from the secret provided by
+// the client session.
+func (e *imageExporterInstance) signingKey(ctx context.Context, sessionID string) (crypto.Signer, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	caller, err := e.opt.SessionManager.Get(timeoutCtx, sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dt, err := secrets.GetSecret(ctx, caller, e.signSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get signing key")
+	}
+	return cosign.LoadPrivateKey(dt)
+}
+
+// pushSignature signs the manifest desc pushed to targetName and pushes the
+// signature to the same repository with the tag cosign looks up. Like cosign,
+// the signature is appended to the signatures already pushed for the image.
+func (e *imageExporterInstance) pushSignature(ctx context.Context, sessionID string, key crypto.Signer, desc ocispecs.Descriptor, targetName string) error {
+	parsed, err := reference.ParseNormalizedNamed(targetName)
+	if err != nil {
+		return err
+	}
+	sigRef := reference.TrimNamed(parsed).String() + ":" + cosign.SignatureTag(desc.Digest)
+	prev, err := e.existingSignature(ctx, sessionID, sigRef)
+	if err != nil {
+		return err
+	}
+
+	signDone := oneOffProgress(ctx, fmt.Sprintf("signing %s for %s", desc.Digest, reference.TrimNamed(parsed)))
+	payload, err := cosign.Payload(parsed, desc.Digest, e.signAnnotations)
+	if err != nil {
+		return signDone(err)
+	}
+	sig, err := cosign.Sign(key, payload)
+	if err != nil {
+		return signDone(errors.Wrap(err, "failed to sign image"))
+	}
+	s, err := cosign.NewSignature(prev, payload, sig)
+	if err != nil {
+		return signDone(err)
+	}
+
+	cs := e.opt.ImageWriter.ContentStore()
+	labels := map[string]string{}
+	for i, b := range append([]cosign.Blob{s.Config}, s.Layers...) {
+		if err := content.WriteBlob(ctx, cs, b.Descriptor.Digest.String(), bytes.NewReader(b.Data), b.Descriptor); err != nil {
+			return signDone(errors.Wrap(err, "error writing signature blob"))
+		}
+		labels[fmt.Sprintf("containerd.io/gc.ref.content.%d", i)] = b.Descriptor.Digest.String()
+	}
+	if err := content.WriteBlob(ctx, cs, s.Manifest.Descriptor.Digest.String(), bytes.NewReader(s.Manifest.Data), s.Manifest.Descriptor, content.WithLabels(labels)); err != nil {
+		return signDone(errors.Wrap(err, "error writing signature manifest"))
+	}
+	signDone(nil)
+
+	return push.Push(ctx, e.opt.SessionManager, sessionID, cs, cs, s.Manifest.Descriptor.Digest, sigRef, e.pushOpts(nil))
+}
+
+// existingSignature returns the signature manifest the registry has at
+// sigRef, or nil if the image was not signed before.
+func (e *imageExporterInstance) existingSignature(ctx context.Context, sessionID string, sigRef string) (*ocispecs.Manifest, error) {
+	desc, dt, err := push.FetchTag(ctx, e.opt.SessionManager, sessionID, sigRef, e.pushOpts(nil))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve existing signatures %s", sigRef)
+	}
+	if desc == nil {
+		return nil, nil
+	}
+	switch desc.MediaType {
+	case ocispecs.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+	default:
+		return nil, errors.Errorf("unexpected media type %s of existing signatures %s", desc.MediaType, sigRef)
+	}
+	var mfst ocispecs.Manifest
+	if err := json.Unmarshal(dt, &mfst); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse existing signatures %s", sigRef)
+	}
+	return &mfst, nil
+}
@@ -48,25 +48,46 @@ type ImageWriter struct {
 	opt WriterOpt
 }
 
-func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool, compressionType compression.Type, buildInfoMode buildinfo.ExportMode, forceCompression bool, sessionID string) (*ocispecs.Descriptor, error) {
+type ImageCommitOpts struct {
+	OCITypes      bool
+	Compression   compression.Config
+	BuildInfoMode buildinfo.ExportMode
+	// Epoch clamps the timestamps of the image config, history and layer
+	// files if set.
+	Epoch       *time.Time
+	Annotations AnnotationsGroup
+}
+
+func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, sessionID string, opts ImageCommitOpts) (*ocispecs.Descriptor, error) {
 	platformsBytes, ok := inp.Metadata[exptypes.ExporterPlatformsKey]
 
 	if len(inp.Refs) > 0 && !ok {
 		return nil, errors.Errorf("unable to export multiple refs, missing platforms mapping")
 	}
 
+	if !opts.OCITypes {
+		for _, a := range opts.Annotations {
+			if len(a.Index)+len(a.Manifest)+len(a.ManifestDescriptor) > 0 {
+				return nil, errors.Errorf("annotations require oci-mediatypes to be enabled")
+			}
+		}
+	}
+
 	if len(inp.Refs) == 0 {
-		remotes, err := ic.exportLayers(ctx, compressionType, forceCompression, session.NewGroup(sessionID), inp.Ref)
+		remotes, err := ic.exportLayers(ctx, opts, session.NewGroup(sessionID), inp.Ref)
 		if err != nil {
 			return nil, err
 		}
 
 		var buildInfo []byte
-		if buildInfoMode&buildinfo.ExportImageConfig > 0 {
+		if opts.BuildInfoMode&buildinfo.ExportImageConfig > 0 {
 			buildInfo = inp.Metadata[exptypes.ExporterBuildInfo]
 		}
 
-		mfstDesc, configDesc, err := ic.commitDistributionManifest(ctx, inp.Ref, inp.Metadata[exptypes.ExporterImageConfigKey], &remotes[0], oci, inp.Metadata[exptypes.ExporterInlineCache], buildInfo)
+		config := inp.Metadata[exptypes.ExporterImageConfigKey]
+		annotations := opts.Annotations.Platform(platformFromConfig(config))
+
+		mfstDesc, configDesc, err := ic.commitDistributionManifest(ctx, inp.Ref, config, &remotes[0], opts, annotations, inp.Metadata[exptypes.ExporterInlineCache], buildInfo)
 		if err != nil {
 			return nil, err
 		}
@@ -94,7 +115,7 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 		refs = append(refs, r)
 	}
 
-	remotes, err := ic.exportLayers(ctx, compressionType, forceCompression, session.NewGroup(sessionID), refs...)
+	remotes, err := ic.exportLayers(ctx, opts, session.NewGroup(sessionID), refs...)
 	if err != nil {
 		return nil, err
 	}
@@ -111,10 +132,11 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 			Versioned: specs.Versioned{
 				SchemaVersion: 2,
 			},
+			Annotations: opts.Annotations.Platform(nil).Index,
 		},
 	}
 
-	if !oci {
+	if !opts.OCITypes {
 		idx.MediaType = images.MediaTypeDockerSchema2ManifestList
 	}
 
@@ -129,11 +151,13 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 		inlineCache := inp.Metadata[fmt.Sprintf("%s/%s", exptypes.ExporterInlineCache, p.ID)]
 
 		var buildInfo []byte
-		if buildInfoMode&buildinfo.ExportImageConfig > 0 {
+		if opts.BuildInfoMode&buildinfo.ExportImageConfig > 0 {
 			buildInfo = inp.Metadata[fmt.Sprintf("%s/%s", exptypes.ExporterBuildInfo, p.ID)]
 		}
 
-		desc, _, err := ic.commitDistributionManifest(ctx, r, config, &remotes[remotesMap[p.ID]], oci, inlineCache, buildInfo)
+		annotations := opts.Annotations.Platform(&p.Platform)
+
+		desc, _, err := ic.commitDistributionManifest(ctx, r, config, &remotes[remotesMap[p.ID]], opts, annotations, inlineCache, buildInfo)
 		if err != nil {
 			return nil, err
 		}
@@ -165,10 +189,10 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 	return &idxDesc, nil
 }
 
-func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compression.Type, forceCompression bool, s session.Group, refs ...cache.ImmutableRef) ([]solver.Remote, error) {
+func (ic *ImageWriter) exportLayers(ctx context.Context, opts ImageCommitOpts, s session.Group, refs ...cache.ImmutableRef) ([]solver.Remote, error) {
 	span, ctx := tracing.StartSpan(ctx, "export layers", trace.WithAttributes(
-		attribute.String("exportLayers.compressionType", compressionType.String()),
-		attribute.Bool("exportLayers.forceCompression", forceCompression),
+		attribute.String("exportLayers.compressionType", opts.Compression.Type.String()),
+		attribute.Bool("exportLayers.forceCompression", opts.Compression.Force),
 	))
 
 	eg, ctx := errgroup.WithContext(ctx)
@@ -182,10 +206,16 @@ func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compres
 				return
 			}
 			eg.Go(func() error {
-				remote, err := ref.GetRemote(ctx, true, compressionType, forceCompression, s)
+				remote, err := ref.GetRemote(ctx, true, opts.Compression, s)
 				if err != nil {
 					return err
 				}
+				if opts.Epoch != nil {
+					remote, err = cache.RewriteLayerTimestamps(ctx, ic.opt.ContentStore, remote, *opts.Epoch)
+					if err != nil {
+						return err
+					}
+				}
 				out[i] = *remote
 				return nil
 			})
@@ -197,7 +227,7 @@ func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compres
 	return out, err
 }
 
-func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache.ImmutableRef, config []byte, remote *solver.Remote, oci bool, inlineCache []byte, buildInfo []byte) (*ocispecs.Descriptor, *ocispecs.Descriptor, error) {
+func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache.ImmutableRef, config []byte, remote *solver.Remote, opts ImageCommitOpts, annotations *Annotations, inlineCache []byte, buildInfo []byte) (*ocispecs.Descriptor, *ocispecs.Descriptor, error) {
 	if len(config) == 0 {
 		var err error
 		config, err = emptyImageConfig()
@@ -217,9 +247,9 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 		return nil, nil, err
 	}
 
-	remote, history = normalizeLayersAndHistory(ctx, remote, history, ref, oci)
+	remote, history = normalizeLayersAndHistory(ctx, remote, history, ref, opts.OCITypes, opts.Epoch)
 
-	config, err = patchImageConfig(config, remote.Descriptors, history, inlineCache, buildInfo)
+	config, err = patchImageConfig(config, remote.Descriptors, history, inlineCache, buildInfo, opts.Epoch)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -231,7 +261,7 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 	)
 
 	// Use docker media types for older Docker versions and registries
-	if !oci {
+	if !opts.OCITypes {
 		manifestType = images.MediaTypeDockerSchema2Manifest
 		configType = images.MediaTypeDockerSchema2Config
 	}
@@ -253,6 +283,7 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 				Size:      int64(len(config)),
 				MediaType: configType,
 			},
+			Annotations: annotations.Manifest,
 		},
 	}
 
@@ -262,7 +293,7 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 
 	for i, desc := range remote.Descriptors {
 		// oci supports annotations but don't export internal annotations
-		if oci {
+		if opts.OCITypes {
 			delete(desc.Annotations, "containerd.io/uncompressed")
 			delete(desc.Annotations, "buildkit/createdat")
 			for k := range desc.Annotations {
@@ -306,10 +337,19 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 	}
 	configDone(nil)
 
+	var descAnnotations map[string]string
+	if len(annotations.ManifestDescriptor) > 0 {
+		descAnnotations = make(map[string]string, len(annotations.ManifestDescriptor))
+		for k, v := range annotations.ManifestDescriptor {
+			descAnnotations[k] = v
+		}
+	}
+
 	return &ocispecs.Descriptor{
-		Digest:    mfstDigest,
-		Size:      int64(len(mfstJSON)),
-		MediaType: manifestType,
+		Digest:      mfstDigest,
+		Size:        int64(len(mfstJSON)),
+		MediaType:   manifestType,
+		Annotations: descAnnotations,
 	}, &configDesc, nil
 }
 
@@ -349,6 +389,25 @@ func emptyImageConfig() ([]byte, error) {
 	return dt, errors.Wrap(err, "failed to create empty image config")
 }
 
+// platformFromConfig returns the platform of an image config or nil if it
+// can't be detected.
+func platformFromConfig(dt []byte) *ocispecs.Platform {
+	var img struct {
+		ocispecs.Image
+
+		Variant string `json:"variant,omitempty"`
+	}
+	if err := json.Unmarshal(dt, &img); err != nil || img.OS == "" || img.Architecture == "" {
+		return nil
+	}
+	p := platforms.Normalize(ocispecs.Platform{
+		OS:           img.OS,
+		Architecture: img.Architecture,
+		Variant:      img.Variant,
+	})
+	return &p
+}
+
 func parseHistoryFromConfig(dt []byte) ([]ocispecs.History, error) {
 	var config struct {
 		History []ocispecs.History
@@ -359,7 +418,7 @@ func parseHistoryFromConfig(dt []byte) ([]ocispecs.History, error) {
 	return config.History, nil
 }
 
-func patchImageConfig(dt []byte, descs []ocispecs.Descriptor, history []ocispecs.History, cache []byte, buildInfo []byte) ([]byte, error) {
+func patchImageConfig(dt []byte, descs []ocispecs.Descriptor, history []ocispecs.History, cache []byte, buildInfo []byte, epoch *time.Time) ([]byte, error) {
 	m := map[string]json.RawMessage{}
 	if err := json.Unmarshal(dt, &m); err != nil {
 		return nil, errors.Wrap(err, "failed to parse image config for patch")
@@ -394,6 +453,18 @@ func patchImageConfig(dt []byte, descs []ocispecs.Descriptor, history []ocispecs
 			return nil, errors.Wrap(err, "failed to marshal creation time")
 		}
 		m["created"] = dt
+	} else if epoch != nil {
+		var tm *time.Time
+		if err := json.Unmarshal(m["created"], &tm); err != nil {
+			return nil, errors.Wrap(err, "failed to parse creation time")
+		}
+		if tm == nil || tm.After(*epoch) {
+			dt, err = json.Marshal(epoch)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to marshal creation time")
+			}
+			m["created"] = dt
+		}
 	}
 
 	if cache != nil {
@@ -418,7 +489,7 @@ func patchImageConfig(dt []byte, descs []ocispecs.Descriptor, history []ocispecs
 	return dt, errors.Wrap(err, "failed to marshal config after patch")
 }
 
-func normalizeLayersAndHistory(ctx context.Context, remote *solver.Remote, history []ocispecs.History, ref cache.ImmutableRef, oci bool) (*solver.Remote, []ocispecs.History) {
+func normalizeLayersAndHistory(ctx context.Context, remote *solver.Remote, history []ocispecs.History, ref cache.ImmutableRef, oci bool, epoch *time.Time) (*solver.Remote, []ocispecs.History) {
 	refMeta := getRefMetadata(ref, len(remote.Descriptors))
 
 	var historyLayers int
@@ -501,6 +572,9 @@ func normalizeLayersAndHistory(ctx context.Context, remote *solver.Remote, histo
 			noCreatedTime = true
 			h.Created = created
 		}
+		if epoch != nil && (h.Created == nil || h.Created.After(*epoch)) {
+			h.Created = epoch
+		}
 		history[i] = h
 	}
 
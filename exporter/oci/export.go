@@ -13,6 +13,7 @@ import (
 	"github.com/moby/buildkit/exporter"
 	"github.com/moby/buildkit/exporter/containerimage"
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/exporter/util/epoch"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/util/buildinfo"
@@ -36,6 +37,7 @@ const (
 	VariantDocker       = "docker"
 	ociTypes            = "oci-mediatypes"
 	keyForceCompression = "force-compression"
+	keyCompressionLevel = "compression-level"
 	keyBuildInfo        = "buildinfo"
 )
 
@@ -59,38 +61,46 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 	var ot *bool
 	i := &imageExporterInstance{
 		imageExporter:    e,
-		layerCompression: compression.Default,
+		layerCompression: compression.New(compression.Default),
 		buildInfoMode:    buildinfo.ExportDefault,
 	}
-	var esgz bool
+	tm, opt, err := epoch.ParseExporterAttrs(opt)
+	if err != nil {
+		return nil, err
+	}
+	i.epoch = tm
+
+	i.annotations, opt, err = containerimage.ParseAnnotationAttrs(opt)
+	if err != nil {
+		return nil, err
+	}
+
 	for k, v := range opt {
 		switch k {
 		case keyImageName:
 			i.name = v
 		case keyLayerCompression:
-			switch v {
-			case "gzip":
-				i.layerCompression = compression.Gzip
-			case "estargz":
-				i.layerCompression = compression.EStargz
-				esgz = true
-			case "zstd":
-				i.layerCompression = compression.Zstd
-			case "uncompressed":
-				i.layerCompression = compression.Uncompressed
-			default:
-				return nil, errors.Errorf("unsupported layer compression type: %v", v)
+			t, err := compression.Parse(v)
+			if err != nil {
+				return nil, err
 			}
+			i.layerCompression.Type = t
 		case keyForceCompression:
 			if v == "" {
-				i.forceCompression = true
+				i.layerCompression.Force = true
 				continue
 			}
 			b, err := strconv.ParseBool(v)
 			if err != nil {
 				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
 			}
-			i.forceCompression = b
+			i.layerCompression.Force = b
+		case keyCompressionLevel:
+			level, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-int value specified for %s", k)
+			}
+			i.layerCompression.Level = &level
 		case ociTypes:
 			ot = new(bool)
 			if v == "" {
@@ -123,9 +133,17 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 	} else {
 		i.ociTypes = *ot
 	}
-	if esgz && !i.ociTypes {
-		logrus.Warn("forcibly turning on oci-mediatype mode for estargz")
-		i.ociTypes = true
+	switch i.layerCompression.Type {
+	case compression.EStargz, compression.ZstdChunked:
+		if !i.ociTypes {
+			logrus.Warnf("forcibly turning on oci-mediatype mode for %s", i.layerCompression.Type)
+			i.ociTypes = true
+		}
+	}
+	if i.layerCompression.Level != nil {
+		if err := i.layerCompression.Type.CheckLevel(*i.layerCompression.Level); err != nil {
+			return nil, err
+		}
 	}
 	return i, nil
 }
@@ -135,9 +153,10 @@ type imageExporterInstance struct {
 	meta             map[string][]byte
 	name             string
 	ociTypes         bool
-	layerCompression compression.Type
-	forceCompression bool
+	layerCompression compression.Config
 	buildInfoMode    buildinfo.ExportMode
+	epoch            *time.Time
+	annotations      containerimage.AnnotationsGroup
 }
 
 func (e *imageExporterInstance) Name() string {
@@ -156,13 +175,25 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 		src.Metadata[k] = v
 	}
 
+	annotations, err := containerimage.ParseFrontendAnnotations(ctx, src.Metadata, e.ociTypes)
+	if err != nil {
+		return nil, err
+	}
+	annotations = annotations.Merge(e.annotations)
+
 	ctx, done, err := leaseutil.WithLease(ctx, e.opt.LeaseManager, leaseutil.MakeTemporary)
 	if err != nil {
 		return nil, err
 	}
 	defer done(context.TODO())
 
-	desc, err := e.opt.ImageWriter.Commit(ctx, src, e.ociTypes, e.layerCompression, e.buildInfoMode, e.forceCompression, sessionID)
+	desc, err := e.opt.ImageWriter.Commit(ctx, src, sessionID, containerimage.ImageCommitOpts{
+		OCITypes:      e.ociTypes,
+		Compression:   e.layerCompression,
+		BuildInfoMode: e.buildInfoMode,
+		Epoch:         e.epoch,
+		Annotations:   annotations,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +213,10 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 	if desc.Annotations == nil {
 		desc.Annotations = map[string]string{}
 	}
-	desc.Annotations[ocispecs.AnnotationCreated] = time.Now().UTC().Format(time.RFC3339)
+	desc.Annotations[ocispecs.AnnotationCreated] = epoch.OrNow(e.epoch).UTC().Format(time.RFC3339)
+	for k, v := range annotations.Platform(nil).IndexDescriptor {
+		desc.Annotations[k] = v
+	}
 
 	resp := make(map[string]string)
 	resp[exptypes.ExporterImageDigestKey] = desc.Digest.String()
@@ -228,7 +262,7 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 
 	mprovider := contentutil.NewMultiProvider(e.opt.ImageWriter.ContentStore())
 	if src.Ref != nil {
-		remote, err := src.Ref.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
+		remote, err := src.Ref.GetRemote(ctx, false, e.layerCompression, session.NewGroup(sessionID))
 		if err != nil {
 			return nil, err
 		}
@@ -245,7 +279,7 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 	}
 	if len(src.Refs) > 0 {
 		for _, r := range src.Refs {
-			remote, err := r.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
+			remote, err := r.GetRemote(ctx, false, e.layerCompression, session.NewGroup(sessionID))
 			if err != nil {
 				return nil, err
 			}
@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/util/epoch"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/snapshot"
@@ -33,12 +34,17 @@ func New(opt Opt) (exporter.Exporter, error) {
 }
 
 func (e *localExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
-	li := &localExporterInstance{localExporter: e}
+	tm, _, err := epoch.ParseExporterAttrs(opt)
+	if err != nil {
+		return nil, err
+	}
+	li := &localExporterInstance{localExporter: e, epoch: tm}
 	return li, nil
 }
 
 type localExporterInstance struct {
 	*localExporter
+	epoch *time.Time
 }
 
 func (e *localExporterInstance) Name() string {
@@ -84,17 +90,22 @@ func (e *localExporterInstance) Export(ctx context.Context, inp exporter.Source,
 
 		walkOpt := &fsutil.WalkOpt{}
 
-		if idmap != nil {
+		if idmap != nil || e.epoch != nil {
 			walkOpt.Map = func(p string, st *fstypes.Stat) bool {
-				uid, gid, err := idmap.ToContainer(idtools.Identity{
-					UID: int(st.Uid),
-					GID: int(st.Gid),
-				})
-				if err != nil {
-					return false
+				if idmap != nil {
+					uid, gid, err := idmap.ToContainer(idtools.Identity{
+						UID: int(st.Uid),
+						GID: int(st.Gid),
+					})
+					if err != nil {
+						return false
+					}
+					st.Uid = uint32(uid)
+					st.Gid = uint32(gid)
+				}
+				if e.epoch != nil && st.ModTime > e.epoch.UnixNano() {
+					st.ModTime = e.epoch.UnixNano()
 				}
-				st.Uid = uint32(uid)
-				st.Gid = uint32(gid)
 				return true
 			}
 		}
@@ -0,0 +1,67 @@
+// Package epoch parses the SOURCE_DATE_EPOCH value that exporters use to
+// produce reproducible outputs.
+package epoch
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// KeySourceDateEpoch is the exporter attribute that sets the epoch.
+	KeySourceDateEpoch = "source-date-epoch"
+
+	frontendSourceDateEpochArg = "build-arg:SOURCE_DATE_EPOCH"
+)
+
+// ParseBuildArgs returns the SOURCE_DATE_EPOCH build argument from frontend
+// attributes.
+func ParseBuildArgs(opt map[string]string) (string, bool) {
+	v, ok := opt[frontendSourceDateEpochArg]
+	return v, ok
+}
+
+// ParseExporterAttrs returns the epoch set in the exporter attributes and the
+// remaining attributes. It returns a nil time if no epoch is set.
+func ParseExporterAttrs(opts map[string]string) (*time.Time, map[string]string, error) {
+	rest := make(map[string]string, len(opts))
+
+	var tm *time.Time
+	for k, v := range opts {
+		switch k {
+		case KeySourceDateEpoch:
+			var err error
+			tm, err = Parse(v)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid %s", k)
+			}
+		default:
+			rest[k] = v
+		}
+	}
+	return tm, rest, nil
+}
+
+// Parse parses an epoch in seconds since the Unix epoch. An empty value
+// returns nil.
+func Parse(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	sde, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid SOURCE_DATE_EPOCH %q", v)
+	}
+	tm := time.Unix(sde, 0).UTC()
+	return &tm, nil
+}
+
+// OrNow returns epoch if it is set and the current time otherwise.
+func OrNow(epoch *time.Time) time.Time {
+	if epoch != nil {
+		return *epoch
+	}
+	return time.Now()
+}
@@ -43,6 +43,7 @@ const (
 	keyFilename          = "filename"
 	keyCacheFrom         = "cache-from"    // for registry only. deprecated in favor of keyCacheImports
 	keyCacheImports      = "cache-imports" // JSON representation of []CacheOptionsEntry
+	keyCheck             = "check"
 	keyContextSubDir     = "contextsubdir"
 	keyForceNetwork      = "force-network-mode"
 	keyGlobalAddHosts    = "add-hosts"
@@ -59,6 +60,7 @@ const (
 	// a new build-arg: frontend/dockerfile/docs/syntax.md
 	keyCacheNSArg           = "build-arg:BUILDKIT_CACHE_MOUNT_NS"
 	keyContextKeepGitDirArg = "build-arg:BUILDKIT_CONTEXT_KEEP_GIT_DIR"
+	keyDockerfileCheckArg   = "build-arg:BUILDKIT_DOCKERFILE_CHECK"
 	keyHostnameArg          = "build-arg:BUILDKIT_SANDBOX_HOSTNAME"
 	keyMultiPlatformArg     = "build-arg:BUILDKIT_MULTI_PLATFORM"
 	keySyntaxArg            = "build-arg:BUILDKIT_SYNTAX"
@@ -372,10 +374,48 @@ func Build(ctx context.Context, c client.Client) (*client.Result, error) {
 		return nil, capsError
 	}
 
-	if res, ok, err := checkSubRequest(ctx, opts); ok {
+	if res, ok, err := checkSubRequest(ctx, opts, dtDockerfile); ok {
+		if err != nil {
+			var el *parser.ErrorLocation
+			if errors.As(err, &el) {
+				err = wrapSource(err, sourceMap, el.Location)
+			}
+		}
 		return res, err
 	}
 
+	lintConfig, err := dockerfile2llb.ParseLintConfig(dtDockerfile, opts[keyDockerfileCheckArg])
+	if err != nil {
+		return nil, err
+	}
+	_, checkOnly := opts[keyCheck]
+	lintOpt := dockerfile2llb.ConvertOpt{
+		BuildArgs:  filter(opts, buildArgPrefix),
+		LintConfig: lintConfig,
+	}
+	if checkOnly {
+		lintConfig.ReturnAsError = true
+	} else if (&gwcaps).Supports(gwpb.CapGatewayWarnings) == nil {
+		defVtx, err := def.Head()
+		if err != nil {
+			return nil, err
+		}
+		lintOpt.Warn = func(short, url string, detail [][]byte, location []parser.Range) error {
+			return c.Warn(ctx, defVtx, short, warnOpts(sourceMap, location, detail, url))
+		}
+	}
+	results, err := dockerfile2llb.DockerfileLint(ctx, dtDockerfile, lintOpt)
+	if checkOnly && results != nil {
+		return lintResult(results, err)
+	}
+	if err != nil {
+		var el *parser.ErrorLocation
+		if errors.As(err, &el) {
+			err = wrapSource(err, sourceMap, el.Location)
+		}
+		return nil, err
+	}
+
 	exportMap := len(targetPlatforms) > 1
 
 	if v := opts[keyMultiPlatformArg]; v != "" {
@@ -715,6 +755,20 @@ func scopeToSubDir(c *llb.State, fileop bool, dir string) *llb.State {
 	return &bc
 }
 
+func warnOpts(sm *llb.SourceMap, ranges []parser.Range, detail [][]byte, url string) client.WarnOpts {
+	opts := client.WarnOpts{Level: 1, Detail: detail, URL: url}
+	if sm == nil {
+		return opts
+	}
+	opts.SourceInfo = &pb.SourceInfo{
+		Data:       sm.Data,
+		Filename:   sm.Filename,
+		Definition: sm.Definition.ToPB(),
+	}
+	opts.Range = toPBRanges(ranges)
+	return opts
+}
+
 func wrapSource(err error, sm *llb.SourceMap, ranges []parser.Range) error {
 	if sm == nil {
 		return err
@@ -725,10 +779,15 @@ func wrapSource(err error, sm *llb.SourceMap, ranges []parser.Range) error {
 			Filename:   sm.Filename,
 			Definition: sm.Definition.ToPB(),
 		},
-		Ranges: make([]*pb.Range, 0, len(ranges)),
+		Ranges: toPBRanges(ranges),
 	}
+	return errdefs.WithSource(err, s)
+}
+
+func toPBRanges(ranges []parser.Range) []*pb.Range {
+	out := make([]*pb.Range, 0, len(ranges))
 	for _, r := range ranges {
-		s.Ranges = append(s.Ranges, &pb.Range{
+		out = append(out, &pb.Range{
 			Start: pb.Position{
 				Line:      int32(r.Start.Line),
 				Character: int32(r.Start.Character),
@@ -739,5 +798,5 @@ func wrapSource(err error, sm *llb.SourceMap, ranges []parser.Range) error {
 			},
 		})
 	}
-	return errdefs.WithSource(err, s)
+	return out
 }
@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/moby/buildkit/frontend/dockerfile/dockerfile2llb"
 	"github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/frontend/subrequests"
+	"github.com/moby/buildkit/frontend/subrequests/lint"
+	"github.com/moby/buildkit/frontend/subrequests/outline"
+	"github.com/moby/buildkit/frontend/subrequests/targets"
 	"github.com/moby/buildkit/solver/errdefs"
 )
 
-func checkSubRequest(ctx context.Context, opts map[string]string) (*client.Result, bool, error) {
+func checkSubRequest(ctx context.Context, opts map[string]string, dtDockerfile []byte) (*client.Result, bool, error) {
 	req, ok := opts["requestid"]
 	if !ok {
 		return nil, false, nil
@@ -18,13 +22,57 @@ func checkSubRequest(ctx context.Context, opts map[string]string) (*client.Resul
 	case subrequests.RequestSubrequestsDescribe:
 		res, err := describe()
 		return res, true, err
+	case outline.RequestSubrequestsOutline:
+		o, err := dockerfile2llb.Dockerfile2Outline(ctx, dtDockerfile, dockerfile2llb.ConvertOpt{
+			Target:    opts[keyTarget],
+			BuildArgs: filter(opts, buildArgPrefix),
+		})
+		if err != nil {
+			return nil, true, err
+		}
+		res, err := o.ToResult()
+		return res, true, err
+	case lint.RequestLint:
+		lintConfig, err := dockerfile2llb.ParseLintConfig(dtDockerfile, opts[keyDockerfileCheckArg])
+		if err != nil {
+			return nil, true, err
+		}
+		results, err := dockerfile2llb.DockerfileLint(ctx, dtDockerfile, dockerfile2llb.ConvertOpt{
+			BuildArgs:  filter(opts, buildArgPrefix),
+			LintConfig: lintConfig,
+		})
+		res, err := lintResult(results, err)
+		return res, true, err
+	case targets.RequestTargets:
+		l, err := dockerfile2llb.ListTargets(ctx, dtDockerfile)
+		if err != nil {
+			return nil, true, err
+		}
+		res, err := l.ToResult()
+		return res, true, err
 	default:
 		return nil, true, errdefs.NewUnsupportedSubrequestError(req)
 	}
 }
 
+// lintResult returns the results of DockerfileLint as the result of the
+// frontend. Violations that fail the check are returned in the results so the
+// client gets their locations.
+func lintResult(results *lint.LintResults, err error) (*client.Result, error) {
+	if results == nil {
+		return nil, err
+	}
+	if err != nil {
+		results.Error = err.Error()
+	}
+	return results.ToResult()
+}
+
 func describe() (*client.Result, error) {
 	all := []subrequests.Request{
+		outline.SubrequestsOutlineDefinition,
+		targets.SubrequestsTargetsDefinition,
+		lint.SubrequestLintDefinition,
 		subrequests.SubrequestsDescribeDefinition,
 	}
 	dt, err := json.MarshalIndent(all, "  ", "")
@@ -0,0 +1,29 @@
+package dockerfile2llb
+
+import (
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+func dispatchRunDevices(c *instructions.RunCommand, dopt dispatchOpt) ([]llb.RunOption, error) {
+	devices := instructions.GetDevices(c)
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	if dopt.llbCaps != nil {
+		if err := dopt.llbCaps.Supports(pb.CapExecMetaCDI); err != nil {
+			return nil, errors.Wrap(err, "RUN --device is not supported by the builder")
+		}
+	}
+	var out []llb.RunOption
+	for _, d := range devices {
+		opts := []llb.CDIDeviceOption{llb.CDIDeviceName(d.Name)}
+		if !d.Required {
+			opts = append(opts, llb.CDIDeviceOptional)
+		}
+		out = append(out, llb.AddCDIDevice(opts...))
+	}
+	return out, nil
+}
@@ -27,7 +27,7 @@ func detectRunMount(cmd *command, allDispatchStates *dispatchStates) bool {
 				// mount.Type because it might be a variable)
 				from = emptyImageName
 			} else {
-				from = mount.From
+				from, _ = splitStageOutput(mount.From)
 			}
 			stn, ok := allDispatchStates.findStateByName(from)
 			if !ok {
@@ -80,8 +80,16 @@ func setCacheUIDGID(m *instructions.Mount, st llb.State, fileop bool) llb.State
 	return llb.Image("busybox").Run(llb.Shlex(fmt.Sprintf("sh -c 'mkdir -p /mnt/cache;%s'", b.String())), llb.WithCustomName("[internal] settings cache mount permissions")).AddMount("/mnt", st)
 }
 
-func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*dispatchState, opt dispatchOpt) ([]llb.RunOption, error) {
+// mountOutput is a read-write bind mount whose writes are kept as a named
+// output of the stage.
+type mountOutput struct {
+	name   string
+	target string
+}
+
+func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*dispatchState, opt dispatchOpt) ([]llb.RunOption, []mountOutput, error) {
 	var out []llb.RunOption
+	var outputs []mountOutput
 	mounts := instructions.GetMounts(c)
 
 	for i, mount := range mounts {
@@ -90,7 +98,12 @@ func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*
 		}
 		st := opt.buildContext
 		if mount.From != "" {
-			st = sources[i].state
+			_, output := splitStageOutput(mount.From)
+			var err error
+			st, err = sources[i].stateForOutput(output)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 		var mountOpts []llb.MountOption
 		if mount.Type == instructions.MountTypeTmpfs {
@@ -100,7 +113,7 @@ func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*
 		if mount.Type == instructions.MountTypeSecret {
 			secret, err := dispatchSecret(mount)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			out = append(out, secret)
 			continue
@@ -108,14 +121,14 @@ func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*
 		if mount.Type == instructions.MountTypeSSH {
 			ssh, err := dispatchSSH(mount)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			out = append(out, ssh)
 			continue
 		}
 		if mount.ReadOnly {
 			mountOpts = append(mountOpts, llb.Readonly)
-		} else if mount.Type == instructions.MountTypeBind && opt.llbCaps.Supports(pb.CapExecMountBindReadWriteNoOuput) == nil {
+		} else if mount.Type == instructions.MountTypeBind && mount.Output == "" && opt.llbCaps.Supports(pb.CapExecMountBindReadWriteNoOuput) == nil {
 			mountOpts = append(mountOpts, llb.ForceNoOutput)
 		}
 		if mount.Type == instructions.MountTypeCache {
@@ -130,17 +143,32 @@ func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*
 				mount.CacheID = path.Clean(mount.Target)
 			}
 			mountOpts = append(mountOpts, llb.AsPersistentCacheDir(opt.cacheIDNamespace+"/"+mount.CacheID, sharing))
+			if mount.CacheMaxSize != 0 || mount.CacheMaxAge != 0 {
+				if opt.llbCaps != nil {
+					if err := opt.llbCaps.Supports(pb.CapExecMountCacheLimits); err != nil {
+						return nil, nil, errors.Wrap(err, "cache mount limits are not supported by the builder")
+					}
+				}
+				limits := llb.CacheMountLimits{
+					MaxSize: mount.CacheMaxSize,
+					MaxAge:  mount.CacheMaxAge,
+				}
+				if mount.CachePrune == instructions.MountCachePruneReset {
+					limits.PrunePolicy = llb.CacheMountPruneReset
+				}
+				mountOpts = append(mountOpts, llb.WithCacheMountLimits(limits))
+			}
 		}
 		target := mount.Target
 		if !filepath.IsAbs(filepath.Clean(mount.Target)) {
 			dir, err := d.state.GetDir(context.TODO())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			target = filepath.Join("/", dir, mount.Target)
 		}
 		if target == "/" {
-			return nil, errors.Errorf("invalid mount target %q", target)
+			return nil, nil, errors.Errorf("invalid mount target %q", target)
 		}
 		if src := path.Join("/", mount.Source); src != "/" {
 			mountOpts = append(mountOpts, llb.SourcePath(src))
@@ -153,9 +181,18 @@ func dispatchRunMounts(d *dispatchState, c *instructions.RunCommand, sources []*
 
 		out = append(out, llb.AddMount(target, st, mountOpts...))
 
+		if mount.Output != "" {
+			for _, o := range outputs {
+				if o.name == mount.Output {
+					return nil, nil, errors.Errorf("duplicate mount output %q", mount.Output)
+				}
+			}
+			outputs = append(outputs, mountOutput{name: mount.Output, target: target})
+		}
+
 		if mount.From == "" {
 			d.ctxPaths[path.Join("/", filepath.ToSlash(mount.Source))] = struct{}{}
 		}
 	}
-	return out, nil
+	return out, outputs, nil
 }
@@ -0,0 +1,21 @@
+package dockerfile2llb
+
+import (
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+func dispatchRunTimeout(c *instructions.RunCommand, dopt dispatchOpt) (llb.RunOption, error) {
+	timeout := instructions.GetTimeout(c)
+	if timeout == 0 {
+		return nil, nil
+	}
+	if dopt.llbCaps != nil {
+		if err := dopt.llbCaps.Supports(pb.CapMetaTimeout); err != nil {
+			return nil, errors.Wrap(err, "RUN --timeout is not supported by the builder")
+		}
+	}
+	return llb.WithTimeout(timeout), nil
+}
@@ -9,16 +9,9 @@ import (
 )
 
 func dispatchSecret(m *instructions.Mount) (llb.RunOption, error) {
-	id := m.CacheID
-	if m.Source != "" {
-		id = m.Source
-	}
-
+	id := secretID(m)
 	if id == "" {
-		if m.Target == "" {
-			return nil, errors.Errorf("one of source, target required")
-		}
-		id = path.Base(m.Target)
+		return nil, errors.Errorf("one of source, target required")
 	}
 
 	target := m.Target
@@ -50,3 +43,16 @@ func dispatchSecret(m *instructions.Mount) (llb.RunOption, error) {
 
 	return llb.AddSecret(target, opts...), nil
 }
+
+// secretID returns the ID of the secret of a secret mount, empty if the mount
+// has neither an ID, source nor target.
+func secretID(m *instructions.Mount) string {
+	id := m.CacheID
+	if m.Source != "" {
+		id = m.Source
+	}
+	if id == "" && m.Target != "" {
+		id = path.Base(m.Target)
+	}
+	return id
+}
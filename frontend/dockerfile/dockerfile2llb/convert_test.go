@@ -8,7 +8,9 @@ import (
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
 	"github.com/moby/buildkit/frontend/dockerfile/shell"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/appcontext"
+	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -125,6 +127,90 @@ func TestParseKeyValue(t *testing.T) {
 	assert.Equal(t, "", v)
 }
 
+func TestSplitParentsPivot(t *testing.T) {
+	cases := []struct {
+		src      string
+		parent   string
+		patterns []string
+	}{
+		{src: "a/*/package.json", parent: "/", patterns: []string{"a/*/package.json"}},
+		{src: "/a/b", parent: "/", patterns: []string{"a/b"}},
+		{src: "a/./b/*.go", parent: "/a", patterns: []string{"b/*.go"}},
+		{src: "./a/b", parent: "/", patterns: []string{"a/b"}},
+		{src: "a/b/./", parent: "/a/b", patterns: nil},
+	}
+	for _, c := range cases {
+		parent, patterns := splitParentsPivot(c.src)
+		assert.Equal(t, c.parent, parent, c.src)
+		assert.Equal(t, c.patterns, patterns, c.src)
+	}
+}
+
+func TestMountOutputs(t *testing.T) {
+	t.Parallel()
+	df := `FROM scratch AS build
+RUN --mount=type=bind,target=/out,output=artifacts make
+FROM scratch
+COPY --from=build#artifacts /bin/app /
+`
+	caps := pb.Caps.CapSet(pb.Caps.All())
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		LLBCaps: &caps,
+	})
+	require.NoError(t, err)
+
+	def, err := st.Marshal(appcontext.Context())
+	require.NoError(t, err)
+
+	ops := map[digest.Digest]*pb.Op{}
+	var copyOp *pb.Op
+	for _, dt := range def.Def {
+		var op pb.Op
+		require.NoError(t, op.Unmarshal(dt))
+		ops[digest.FromBytes(dt)] = &op
+		if f := op.GetFile(); f != nil && f.Actions[0].GetCopy() != nil {
+			copyOp = &op
+		}
+	}
+	require.NotNil(t, copyOp)
+
+	// the copy reads from the output of the read-write mount of the exec
+	var execInput *pb.Input
+	for _, inp := range copyOp.Inputs {
+		if ops[inp.Digest].GetExec() != nil {
+			execInput = inp
+		}
+	}
+	require.NotNil(t, execInput)
+	exec := ops[execInput.Digest].GetExec()
+	var mnt *pb.Mount
+	for _, m := range exec.Mounts {
+		if m.Dest == "/out" {
+			mnt = m
+		}
+	}
+	require.NotNil(t, mnt)
+	require.False(t, mnt.Readonly)
+	require.Equal(t, mnt.Output, execInput.Index)
+
+	df = `FROM scratch AS build
+RUN --mount=type=bind,target=/out,output=artifacts make
+FROM scratch
+COPY --from=build#missing /bin/app /
+`
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `stage build has no output named "missing"`)
+
+	df = `FROM scratch AS build
+RUN --mount=type=bind,target=/out,output=artifacts make
+FROM scratch
+RUN --mount=from=build#artifacts,target=/in ls /in
+`
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{})
+	require.NoError(t, err)
+}
+
 func TestToEnvList(t *testing.T) {
 	// args has no duplicated key with env
 	v := "val2"
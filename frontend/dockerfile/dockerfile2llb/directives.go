@@ -9,7 +9,10 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
-const keySyntax = "syntax"
+const (
+	keySyntax = "syntax"
+	keyCheck  = "check"
+)
 
 var reDirective = regexp.MustCompile(`^#\s*([a-zA-Z][a-zA-Z0-9]*)\s*=\s*(.+?)\s*$`)
 
@@ -0,0 +1,217 @@
+package dockerfile2llb
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/linter"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/moby/buildkit/frontend/dockerfile/shell"
+	"github.com/moby/buildkit/frontend/subrequests/lint"
+	"github.com/pkg/errors"
+)
+
+var (
+	secretsRegexp      = regexp.MustCompile(`(?i)(?:_|^)(?:apikey|auth|credential|credentials|key|password|pword|passwd|secret|token)(?:_|$)`)
+	secretsAllowRegexp = regexp.MustCompile(`(?i)(?:_|^)public(?:_|$)`)
+)
+
+// ParseLintConfig returns the lint config set with the check directive of
+// the Dockerfile. A non-empty checkStr, e.g. from the
+// BUILDKIT_DOCKERFILE_CHECK build argument, overrides the directive.
+func ParseLintConfig(dt []byte, checkStr string) (*linter.Config, error) {
+	if checkStr == "" {
+		if d, ok := ParseDirectives(bytes.NewReader(dt))[keyCheck]; ok {
+			checkStr = d.Value
+		}
+	}
+	cfg, err := linter.ParseLintOptions(checkStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse check options")
+	}
+	return cfg, nil
+}
+
+// DockerfileLint checks the Dockerfile against the lint rules that are not
+// skipped by opt.LintConfig. If the config asks for violations to be
+// returned as an error, the results are returned together with the error.
+// The warnings of the parser are only reported to opt.Warn.
+func DockerfileLint(ctx context.Context, dt []byte, opt ConvertOpt) (*lint.LintResults, error) {
+	if len(dt) == 0 {
+		return nil, errors.Errorf("the Dockerfile cannot be empty")
+	}
+
+	dockerfile, err := parser.Parse(bytes.NewReader(dt))
+	if err != nil {
+		return nil, err
+	}
+
+	stages, metaArgs, err := instructions.Parse(dockerfile.AST)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Warn != nil {
+		for _, w := range dockerfile.DetailedWarnings {
+			if err := opt.Warn(w.Short, w.URL, w.Detail, w.Location); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results := &lint.LintResults{
+		Sources: [][]byte{dt},
+	}
+	cfg := linter.Config{}
+	if opt.LintConfig != nil {
+		cfg = *opt.LintConfig
+	}
+	warn := cfg.Warn
+	var warnErr error
+	cfg.Warn = func(rule linter.Rule, msg string, location []parser.Range) {
+		results.AddWarning(rule.Name, rule.Description, rule.URL, msg, 0, toPBRanges(location))
+		if warn != nil {
+			warn(rule, msg, location)
+		}
+		if opt.Warn != nil && warnErr == nil {
+			warnErr = opt.Warn(rule.Name+": "+msg, rule.URL, [][]byte{[]byte(rule.Description)}, location)
+		}
+	}
+	l := linter.New(&cfg)
+
+	lintStageNames(l, dockerfile.AST)
+
+	shlex := shell.NewLex(dockerfile.EscapeToken)
+	env := metaArgsToMap(getPlatformArgs(buildPlatformOpt(&opt)))
+	for _, cmd := range metaArgs {
+		for _, metaArg := range cmd.Args {
+			lintSecretKey(l, "ARG", metaArg.Key, cmd.Location())
+			var v string
+			if metaArg.Value != nil {
+				v, _ = shlex.ProcessWordWithMap(*metaArg.Value, env)
+			}
+			if bv, ok := opt.BuildArgs[metaArg.Key]; ok {
+				v = bv
+			}
+			env[metaArg.Key] = v
+		}
+	}
+
+	for _, st := range stages {
+		res, err := shlex.ProcessWordWithMatches(st.BaseName, env)
+		if err != nil {
+			return nil, parser.WithLocation(err, st.Location)
+		}
+		for _, k := range sortedKeys(res.Unmatched) {
+			l.Run(linter.RuleUndefinedArgInFrom, st.Location, k)
+		}
+
+		if st.Platform != "" {
+			res, err := shlex.ProcessWordWithMatches(st.Platform, env)
+			if err != nil {
+				return nil, parser.WithLocation(err, st.Location)
+			}
+			switch {
+			case st.Platform == "$TARGETPLATFORM" || st.Platform == "${TARGETPLATFORM}":
+				l.Run(linter.RuleRedundantTargetPlatform, st.Location, st.Platform)
+			case len(res.Matched) == 0 && len(res.Unmatched) == 0:
+				l.Run(linter.RuleFromPlatformFlagConstDisallowed, st.Location, st.Platform)
+			}
+			for _, k := range sortedKeys(res.Unmatched) {
+				l.Run(linter.RuleUndefinedArgInFrom, st.Location, k)
+			}
+		}
+
+		for _, cmd := range st.Commands {
+			switch c := cmd.(type) {
+			case *instructions.MaintainerCommand:
+				l.Run(linter.RuleMaintainerDeprecated, c.Location())
+			case *instructions.CmdCommand:
+				if c.PrependShell {
+					l.Run(linter.RuleJSONArgsRecommended, c.Location(), "CMD")
+				}
+			case *instructions.EntrypointCommand:
+				if c.PrependShell {
+					l.Run(linter.RuleJSONArgsRecommended, c.Location(), "ENTRYPOINT")
+				}
+			case *instructions.ArgCommand:
+				for _, kv := range c.Args {
+					lintSecretKey(l, "ARG", kv.Key, c.Location())
+				}
+			case *instructions.EnvCommand:
+				for _, kv := range c.Env {
+					lintSecretKey(l, "ENV", kv.Key, c.Location())
+				}
+			}
+		}
+	}
+
+	if warnErr != nil {
+		return nil, warnErr
+	}
+	return results, l.Error()
+}
+
+// lintStageNames checks the FROM instructions of the AST as the stage names
+// of the parsed instructions are already lowercased.
+func lintStageNames(l *linter.Linter, ast *parser.Node) {
+	seen := map[string]struct{}{}
+	for _, n := range ast.Children {
+		if !strings.EqualFold(n.Value, "from") {
+			continue
+		}
+		var args []string
+		for next := n.Next; next != nil; next = next.Next {
+			args = append(args, next.Value)
+		}
+		if len(args) != 3 || !strings.EqualFold(args[1], "as") {
+			continue
+		}
+		name := args[2]
+		if name != strings.ToLower(name) {
+			l.Run(linter.RuleStageNameCasing, n.Location(), name)
+		}
+
+		fields := strings.Fields(n.Original)
+		if len(fields) == 0 {
+			continue
+		}
+		from := fields[0]
+		if isSelfConsistentCasing(from) && isSelfConsistentCasing(args[1]) && isUpper(from) != isUpper(args[1]) {
+			l.Run(linter.RuleFromAsCasing, n.Location(), from, args[1])
+		}
+
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			l.Run(linter.RuleDuplicateStageName, n.Location(), key)
+		}
+		seen[key] = struct{}{}
+	}
+}
+
+func lintSecretKey(l *linter.Linter, instruction, key string, location []parser.Range) {
+	if secretsRegexp.MatchString(key) && !secretsAllowRegexp.MatchString(key) {
+		l.Run(linter.RuleSecretsUsedInArgOrEnv, location, instruction, key)
+	}
+}
+
+func isSelfConsistentCasing(s string) bool {
+	return s == strings.ToUpper(s) || s == strings.ToLower(s)
+}
+
+func isUpper(s string) bool {
+	return s == strings.ToUpper(s)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package dockerfile2llb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/linter"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerfileLint(t *testing.T) {
+	df := `ARG base=busybox
+ARG API_KEY
+FROM ${base} AS Build
+MAINTAINER foo@example.com
+ENV PUBLIC_KEY=abc DB_PASSWORD=secret
+CMD echo hello
+
+from ${undefined} as build
+FROM --platform=linux/amd64 alpine AS cross
+FROM --platform=$TARGETPLATFORM alpine AS target
+ENTRYPOINT ["/bin/sh"]
+`
+	results, err := DockerfileLint(context.TODO(), []byte(df), ConvertOpt{})
+	require.NoError(t, err)
+
+	var rules []string
+	for _, w := range results.Warnings {
+		rules = append(rules, w.RuleName)
+	}
+	require.Equal(t, []string{
+		"StageNameCasing",
+		"DuplicateStageName",
+		"SecretsUsedInArgOrEnv",
+		"MaintainerDeprecated",
+		"SecretsUsedInArgOrEnv",
+		"JSONArgsRecommended",
+		"UndefinedArgInFrom",
+		"FromPlatformFlagConstDisallowed",
+		"RedundantTargetPlatform",
+	}, rules)
+
+	w := results.Warnings[0]
+	require.Equal(t, "Stage name 'Build' should be lowercase", w.Detail)
+	require.Equal(t, "https://docs.docker.com/go/dockerfile/rule/stage-name-casing/", w.URL)
+	require.Equal(t, int32(3), w.Location.Ranges[0].Start.Line)
+
+	require.Equal(t, `Do not use ARG or ENV instructions for sensitive data (ENV "DB_PASSWORD")`, results.Warnings[4].Detail)
+	require.Equal(t, "FROM argument 'undefined' is not declared", results.Warnings[6].Detail)
+	require.Equal(t, int32(8), results.Warnings[6].Location.Ranges[0].Start.Line)
+
+	require.Equal(t, 1, len(results.Sources))
+}
+
+func TestDockerfileLintCheckDirective(t *testing.T) {
+	df := `# check=skip=StageNameCasing,JSONArgsRecommended;error=true
+FROM busybox AS Build
+CMD echo hello
+MAINTAINER foo@example.com
+`
+	cfg, err := ParseLintConfig([]byte(df), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"StageNameCasing", "JSONArgsRecommended"}, cfg.SkipRules)
+	require.True(t, cfg.ReturnAsError)
+
+	results, err := DockerfileLint(context.TODO(), []byte(df), ConvertOpt{LintConfig: cfg})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "MaintainerDeprecated")
+	require.Equal(t, 1, len(results.Warnings))
+	require.Equal(t, "MaintainerDeprecated", results.Warnings[0].RuleName)
+
+	// the build argument overrides the directive
+	cfg, err = ParseLintConfig([]byte(df), "skip=all")
+	require.NoError(t, err)
+	results, err = DockerfileLint(context.TODO(), []byte(df), ConvertOpt{LintConfig: cfg})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results.Warnings))
+
+	_, err = ParseLintConfig([]byte(df), "foo=bar")
+	require.Error(t, err)
+
+	var warned []string
+	_, err = DockerfileLint(context.TODO(), []byte(df), ConvertOpt{LintConfig: &linter.Config{
+		Warn: func(rule linter.Rule, msg string, _ []parser.Range) {
+			warned = append(warned, rule.Name)
+		},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"StageNameCasing", "JSONArgsRecommended", "MaintainerDeprecated"}, warned)
+}
+
+func TestDockerfileLintWarn(t *testing.T) {
+	df := `FROM busybox AS Build
+RUN echo \
+
+  hello
+`
+	var warnings []string
+	var urls []string
+	_, err := DockerfileLint(context.TODO(), []byte(df), ConvertOpt{
+		Warn: func(short, url string, detail [][]byte, location []parser.Range) error {
+			warnings = append(warnings, short)
+			urls = append(urls, url)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(warnings))
+	require.Contains(t, warnings[0], "Empty continuation line found in")
+	require.Equal(t, "StageNameCasing: Stage name 'Build' should be lowercase", warnings[1])
+	require.Equal(t, linter.RuleStageNameCasing.URL, urls[1])
+
+	// errors sending the warnings fail the lint
+	_, err = DockerfileLint(context.TODO(), []byte(df), ConvertOpt{
+		Warn: func(short, url string, detail [][]byte, location []parser.Range) error {
+			return errors.New("failed to send warning")
+		},
+	})
+	require.EqualError(t, err, "failed to send warning")
+}
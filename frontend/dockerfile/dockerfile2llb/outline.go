@@ -0,0 +1,272 @@
+package dockerfile2llb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/moby/buildkit/frontend/dockerfile/shell"
+	"github.com/moby/buildkit/frontend/subrequests/outline"
+	"github.com/moby/buildkit/frontend/subrequests/targets"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// Dockerfile2Outline returns the build arguments, secrets and SSH IDs used by
+// the target stage of the Dockerfile and the stages it depends on. Unlike
+// Dockerfile2LLB it doesn't resolve any images.
+func Dockerfile2Outline(ctx context.Context, dt []byte, opt ConvertOpt) (*outline.Outline, error) {
+	if len(dt) == 0 {
+		return nil, errors.Errorf("the Dockerfile cannot be empty")
+	}
+
+	dockerfile, err := parser.Parse(bytes.NewReader(dt))
+	if err != nil {
+		return nil, err
+	}
+
+	stages, metaArgs, err := instructions.Parse(dockerfile.AST)
+	if err != nil {
+		return nil, err
+	}
+
+	shlex := shell.NewLex(dockerfile.EscapeToken)
+
+	env := metaArgsToMap(getPlatformArgs(buildPlatformOpt(&opt)))
+	globalArgs := map[string]outline.Arg{}
+	for _, cmd := range metaArgs {
+		for _, metaArg := range cmd.Args {
+			var v string
+			if metaArg.Value != nil {
+				v, _ = shlex.ProcessWordWithMap(*metaArg.Value, env)
+			}
+			if bv, ok := opt.BuildArgs[metaArg.Key]; ok {
+				v = bv
+			}
+			env[metaArg.Key] = v
+			globalArgs[metaArg.Key] = outline.Arg{
+				Name:        metaArg.Key,
+				Description: metaArg.Comment,
+				Value:       v,
+				Location:    toSourceLocation(cmd.Location()),
+			}
+		}
+	}
+
+	allDispatchStates := newDispatchStates()
+	baseMatches := map[*dispatchState]map[string]struct{}{}
+	for i, st := range stages {
+		res, err := shlex.ProcessWordWithMatches(st.BaseName, env)
+		if err != nil {
+			return nil, parser.WithLocation(err, st.Location)
+		}
+		st.BaseName = res.Result
+		matches := res.Matched
+
+		ds := &dispatchState{
+			stage:     st,
+			deps:      make(map[*dispatchState]struct{}),
+			stageName: st.Name,
+		}
+		if st.Name == "" {
+			ds.stageName = fmt.Sprintf("stage-%d", i)
+		}
+		if st.Platform != "" {
+			res, err := shlex.ProcessWordWithMatches(st.Platform, env)
+			if err != nil {
+				return nil, parser.WithLocation(err, st.Location)
+			}
+			for k := range res.Matched {
+				matches[k] = struct{}{}
+			}
+		}
+		baseMatches[ds] = matches
+		allDispatchStates.addState(ds)
+	}
+
+	var target *dispatchState
+	if opt.Target == "" {
+		target = allDispatchStates.lastTarget()
+	} else {
+		var ok bool
+		target, ok = allDispatchStates.findStateByName(opt.Target)
+		if !ok {
+			return nil, errors.Errorf("target stage %s could not be found", opt.Target)
+		}
+	}
+
+	for _, d := range allDispatchStates.states {
+		for _, cmd := range d.stage.Commands {
+			newCmd, err := toCommand(cmd, allDispatchStates)
+			if err != nil {
+				return nil, err
+			}
+			for _, src := range newCmd.sources {
+				if src != nil {
+					d.deps[src] = struct{}{}
+				}
+			}
+		}
+	}
+
+	if has, state := hasCircularDependency(allDispatchStates.states); has {
+		return nil, errors.Errorf("circular dependency detected on stage: %s", state.stageName)
+	}
+
+	o := &outline.Outline{
+		Name:        target.stage.Name,
+		Description: target.stage.Comment,
+		Sources:     [][]byte{dt},
+	}
+
+	args := map[string]int{}
+	addArg := func(a outline.Arg) {
+		if i, ok := args[a.Name]; ok {
+			if o.Args[i].Description == "" {
+				o.Args[i].Description = a.Description
+			}
+			return
+		}
+		args[a.Name] = len(o.Args)
+		o.Args = append(o.Args, a)
+	}
+	secrets := map[string]int{}
+	ssh := map[string]int{}
+
+	for _, d := range allDispatchStates.states {
+		if !isReachable(target, d) {
+			continue
+		}
+		for _, metaArg := range metaArgs {
+			for _, kv := range metaArg.Args {
+				if _, ok := baseMatches[d][kv.Key]; ok {
+					addArg(globalArgs[kv.Key])
+				}
+			}
+		}
+
+		stageEnv := map[string]string{}
+		for _, cmd := range d.stage.Commands {
+			switch c := cmd.(type) {
+			case *instructions.ArgCommand:
+				for _, kv := range c.Args {
+					if a, ok := globalArgs[kv.Key]; ok && kv.Value == nil {
+						// global argument redeclared in the stage
+						if kv.Comment != "" {
+							a.Description = kv.Comment
+						}
+						stageEnv[kv.Key] = a.Value
+						addArg(a)
+						continue
+					}
+					var v string
+					if kv.Value != nil {
+						v, _ = shlex.ProcessWordWithMap(*kv.Value, stageEnv)
+					}
+					if bv, ok := opt.BuildArgs[kv.Key]; ok {
+						v = bv
+					}
+					stageEnv[kv.Key] = v
+					addArg(outline.Arg{
+						Name:        kv.Key,
+						Description: kv.Comment,
+						Value:       v,
+						Location:    toSourceLocation(c.Location()),
+					})
+				}
+			case *instructions.RunCommand:
+				err := c.Expand(func(word string) (string, error) {
+					return shlex.ProcessWordWithMap(word, stageEnv)
+				})
+				if err != nil {
+					return nil, parser.WithLocation(err, c.Location())
+				}
+				for _, m := range instructions.GetMounts(c) {
+					switch m.Type {
+					case instructions.MountTypeSecret:
+						id := secretID(m)
+						if id == "" {
+							continue
+						}
+						if i, ok := secrets[id]; ok {
+							o.Secrets[i].Required = o.Secrets[i].Required || m.Required
+							continue
+						}
+						secrets[id] = len(o.Secrets)
+						o.Secrets = append(o.Secrets, outline.Secret{
+							Name:     id,
+							Required: m.Required,
+							Location: toSourceLocation(c.Location()),
+						})
+					case instructions.MountTypeSSH:
+						id := m.CacheID
+						if id == "" {
+							id = "default"
+						}
+						if i, ok := ssh[id]; ok {
+							o.SSH[i].Required = o.SSH[i].Required || m.Required
+							continue
+						}
+						ssh[id] = len(o.SSH)
+						o.SSH = append(o.SSH, outline.SSH{
+							Name:     id,
+							Required: m.Required,
+							Location: toSourceLocation(c.Location()),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// ListTargets returns the stages of the Dockerfile that can be used as build
+// targets.
+func ListTargets(ctx context.Context, dt []byte) (*targets.List, error) {
+	if len(dt) == 0 {
+		return nil, errors.Errorf("the Dockerfile cannot be empty")
+	}
+
+	dockerfile, err := parser.Parse(bytes.NewReader(dt))
+	if err != nil {
+		return nil, err
+	}
+
+	stages, _, err := instructions.Parse(dockerfile.AST)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &targets.List{
+		Sources: [][]byte{dt},
+	}
+	for i, s := range stages {
+		last := i == len(stages)-1
+		if s.Name == "" && !last {
+			continue
+		}
+		l.Targets = append(l.Targets, targets.Target{
+			Name:        s.Name,
+			Default:     last,
+			Description: s.Comment,
+			Base:        s.BaseName,
+			Platform:    s.Platform,
+			Location:    toSourceLocation(s.Location),
+		})
+	}
+	return l, nil
+}
+
+func toSourceLocation(r []parser.Range) *pb.Location {
+	if len(r) == 0 {
+		return nil
+	}
+	return &pb.Location{
+		SourceIndex: 0,
+		Ranges:      toPBRanges(r),
+	}
+}
@@ -0,0 +1,95 @@
+package dockerfile2llb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerfile2Outline(t *testing.T) {
+	df := `ARG inherited=box
+ARG unused=foo
+# base sets the base image
+ARG base=busy${inherited}
+
+FROM ${base} AS first
+# first is an argument of the first stage
+ARG first
+RUN --mount=type=secret,target=/etc/passwd,required=true --mount=type=ssh true
+
+FROM alpine AS second
+ARG second
+RUN --mount=type=secret,id=unused true
+
+# target is the stage to build
+FROM first AS target
+ARG foo
+ARG target=${foo}default
+RUN --mount=type=secret,id=secret${foo} --mount=type=ssh,id=key,required true
+`
+	o, err := Dockerfile2Outline(context.TODO(), []byte(df), ConvertOpt{
+		Target:    "target",
+		BuildArgs: map[string]string{"foo": "bar"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "target", o.Name)
+	require.Equal(t, "is the stage to build", o.Description)
+
+	require.Equal(t, 4, len(o.Args))
+	require.Equal(t, "base", o.Args[0].Name)
+	require.Equal(t, "busybox", o.Args[0].Value)
+	require.Equal(t, "sets the base image", o.Args[0].Description)
+	require.Equal(t, int32(4), o.Args[0].Location.Ranges[0].Start.Line)
+	require.Equal(t, "first", o.Args[1].Name)
+	require.Equal(t, "is an argument of the first stage", o.Args[1].Description)
+	require.Equal(t, "foo", o.Args[2].Name)
+	require.Equal(t, "bar", o.Args[2].Value)
+	require.Equal(t, "target", o.Args[3].Name)
+	require.Equal(t, "bardefault", o.Args[3].Value)
+
+	require.Equal(t, 2, len(o.Secrets))
+	require.Equal(t, "passwd", o.Secrets[0].Name)
+	require.True(t, o.Secrets[0].Required)
+	require.Equal(t, "secretbar", o.Secrets[1].Name)
+	require.False(t, o.Secrets[1].Required)
+
+	require.Equal(t, 2, len(o.SSH))
+	require.Equal(t, "default", o.SSH[0].Name)
+	require.False(t, o.SSH[0].Required)
+	require.Equal(t, "key", o.SSH[1].Name)
+	require.True(t, o.SSH[1].Required)
+
+	_, err = Dockerfile2Outline(context.TODO(), []byte(df), ConvertOpt{Target: "notexist"})
+	require.Error(t, err)
+}
+
+func TestListTargets(t *testing.T) {
+	df := `
+# build defines stage for compiling the binary
+FROM alpine AS build
+RUN true
+
+FROM busybox
+RUN true
+
+FROM --platform=$BUILDPLATFORM build AS binary
+
+FROM scratch
+`
+	l, err := ListTargets(context.TODO(), []byte(df))
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(l.Targets))
+	require.Equal(t, "build", l.Targets[0].Name)
+	require.Equal(t, "alpine", l.Targets[0].Base)
+	require.Equal(t, "defines stage for compiling the binary", l.Targets[0].Description)
+	require.Equal(t, int32(3), l.Targets[0].Location.Ranges[0].Start.Line)
+	require.False(t, l.Targets[0].Default)
+	require.Equal(t, "binary", l.Targets[1].Name)
+	require.Equal(t, "$BUILDPLATFORM", l.Targets[1].Platform)
+	require.Equal(t, "", l.Targets[2].Name)
+	require.Equal(t, "scratch", l.Targets[2].Base)
+	require.True(t, l.Targets[2].Default)
+}
@@ -0,0 +1,240 @@
+package dockerfile
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/containerd/continuity/fs/fstest"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/frontend/dockerfile/builder"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/frontend/subrequests/lint"
+	"github.com/moby/buildkit/util/testutil/integration"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+var lintTests = []integration.Test{
+	testLintSubrequest,
+	testLintCheckMode,
+	testLintCheckError,
+	testLintWarnings,
+}
+
+func init() {
+	allTests = append(allTests, lintTests...)
+}
+
+func testLintSubrequest(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+	if _, ok := f.(*clientFrontend); !ok {
+		t.Skip("only test with client frontend")
+	}
+
+	dockerfile := []byte(`# check=skip=JSONArgsRecommended
+FROM busybox AS Base
+MAINTAINER foo@example.com
+CMD echo hello
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	called := false
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			FrontendOpt: map[string]string{
+				"frontend.caps": "moby.buildkit.frontend.subrequests",
+				"requestid":     "frontend.lints.v0",
+			},
+			Frontend: "dockerfile.v0",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var results lint.LintResults
+		require.NoError(t, json.Unmarshal(res.Metadata["result.json"], &results))
+
+		require.Equal(t, 2, len(results.Warnings))
+		require.Equal(t, "StageNameCasing", results.Warnings[0].RuleName)
+		require.Equal(t, int32(2), results.Warnings[0].Location.Ranges[0].Start.Line)
+		require.Equal(t, "MaintainerDeprecated", results.Warnings[1].RuleName)
+		require.Equal(t, int32(3), results.Warnings[1].Location.Ranges[0].Start.Line)
+
+		require.Equal(t, 1, len(results.Sources))
+		require.Equal(t, dockerfile, results.Sources[0])
+		require.Empty(t, results.Error)
+
+		require.Contains(t, string(res.Metadata["result.txt"]), "https://docs.docker.com/go/dockerfile/rule/maintainer-deprecated/")
+
+		called = true
+		return nil, nil
+	}
+
+	_, err = c.Build(sb.Context(), client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+
+	require.True(t, called)
+}
+
+func testLintCheckMode(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+	if _, ok := f.(*clientFrontend); !ok {
+		t.Skip("only test with client frontend")
+	}
+
+	dockerfile := []byte(`FROM scratch AS Base
+COPY Dockerfile /foo
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	called := false
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			FrontendOpt: map[string]string{
+				"check": "",
+			},
+			Frontend: "dockerfile.v0",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// violations fail the check but are returned with their locations
+		var results lint.LintResults
+		require.NoError(t, json.Unmarshal(res.Metadata["result.json"], &results))
+		require.Equal(t, 1, len(results.Warnings))
+		require.Equal(t, "StageNameCasing", results.Warnings[0].RuleName)
+		require.Equal(t, int32(1), results.Warnings[0].Location.Ranges[0].Start.Line)
+		require.Contains(t, results.Error, "StageNameCasing")
+		require.Contains(t, string(res.Metadata["result.txt"]), "StageNameCasing")
+
+		called = true
+		return nil, nil
+	}
+
+	_, err = c.Build(sb.Context(), client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+
+	require.True(t, called)
+}
+
+func testLintCheckError(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`# check=error=true
+FROM scratch AS Base
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "StageNameCasing")
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		FrontendAttrs: map[string]string{
+			"build-arg:BUILDKIT_DOCKERFILE_CHECK": "skip=StageNameCasing;error=true",
+		},
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.NoError(t, err)
+}
+
+func testLintWarnings(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`
+FROM scratch AS Base
+COPY Dockerfile \
+
+  /foo
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	status := make(chan *client.SolveStatus)
+	var warnings []*client.VertexWarning
+	eg, ctx := errgroup.WithContext(sb.Context())
+	eg.Go(func() error {
+		for st := range status {
+			warnings = append(warnings, st.Warnings...)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		_, err := f.Solve(ctx, c, client.SolveOpt{
+			LocalDirs: map[string]string{
+				builder.DefaultLocalNameDockerfile: dir,
+				builder.DefaultLocalNameContext:    dir,
+			},
+		}, status)
+		return err
+	})
+	require.NoError(t, eg.Wait())
+
+	require.Equal(t, 2, len(warnings))
+
+	w := warnings[0]
+	require.Contains(t, string(w.Short), "Empty continuation line found in")
+	require.Equal(t, "Dockerfile", w.SourceInfo.Filename)
+	require.Equal(t, dockerfile, w.SourceInfo.Data)
+	require.Equal(t, int32(3), w.Range[0].Start.Line)
+
+	w = warnings[1]
+	require.Equal(t, "StageNameCasing: Stage name 'Base' should be lowercase", string(w.Short))
+	require.Equal(t, "https://docs.docker.com/go/dockerfile/rule/stage-name-casing/", w.URL)
+	require.Equal(t, int32(2), w.Range[0].Start.Line)
+}
@@ -25,6 +25,7 @@ var mountTests = []integration.Test{
 	testMountMetaArg,
 	testMountFromError,
 	testMountInvalid,
+	testMountOutput,
 }
 
 func init() {
@@ -452,3 +453,64 @@ RUN --mount=from=$ttt,type=cache,target=/tmp ls
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "'from' doesn't support variable expansion, define alias stage instead")
 }
+
+func testMountOutput(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`
+FROM busybox AS build
+RUN --mount=type=bind,target=/src,output=src echo bar > /src/bar
+
+FROM busybox AS combine
+RUN --mount=from=build#src,target=/in cat /in/foo /in/bar > /combined
+
+FROM scratch
+COPY --from=build#src /foo /bar /
+COPY --from=combine /combined /
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+		fstest.CreateFile("foo", []byte("foo\n"), 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		Exports: []client.ExportEntry{
+			{
+				Type:      client.ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "foo\n", string(dt))
+
+	dt, err = ioutil.ReadFile(filepath.Join(destDir, "bar"))
+	require.NoError(t, err)
+	require.Equal(t, "bar\n", string(dt))
+
+	dt, err = ioutil.ReadFile(filepath.Join(destDir, "combined"))
+	require.NoError(t, err)
+	require.Equal(t, "foo\nbar\n", string(dt))
+
+	// writes to the mount don't change the build context
+	_, err = os.Stat(filepath.Join(dir, "bar"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,289 @@
+package dockerfile
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/containerd/continuity/fs/fstest"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/frontend/dockerfile/builder"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/frontend/subrequests/outline"
+	"github.com/moby/buildkit/frontend/subrequests/targets"
+	"github.com/moby/buildkit/util/testutil/integration"
+	"github.com/stretchr/testify/require"
+)
+
+var outlineTests = []integration.Test{
+	testOutlineArgs,
+	testOutlineSecrets,
+	testListTargets,
+}
+
+func init() {
+	allTests = append(allTests, outlineTests...)
+}
+
+func testOutlineArgs(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+	if _, ok := f.(*clientFrontend); !ok {
+		t.Skip("only test with client frontend")
+	}
+
+	dockerfile := []byte(`ARG inherited=box
+ARG inherited2=bar
+ARG unused=foo
+# base sets the base image
+ARG base=busy${inherited}
+
+FROM ${base} AS first
+# first is an argument of the first stage
+ARG first
+RUN true
+
+# target is the stage to build
+FROM first AS target
+# target sets the target
+ARG target=default
+RUN true
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	called := false
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			FrontendOpt: map[string]string{
+				"frontend.caps":  "moby.buildkit.frontend.subrequests",
+				"requestid":      "frontend.outline.v0",
+				"target":         "target",
+				"build-arg:base": "alpine",
+			},
+			Frontend: "dockerfile.v0",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var o outline.Outline
+		require.NoError(t, json.Unmarshal(res.Metadata["result.json"], &o))
+
+		require.Equal(t, "target", o.Name)
+		require.Equal(t, "is the stage to build", o.Description)
+
+		require.Equal(t, 3, len(o.Args))
+
+		arg := o.Args[0]
+		require.Equal(t, "base", arg.Name)
+		require.Equal(t, "alpine", arg.Value)
+		require.Equal(t, "sets the base image", arg.Description)
+		require.Equal(t, int32(0), arg.Location.SourceIndex)
+		require.Equal(t, int32(5), arg.Location.Ranges[0].Start.Line)
+
+		arg = o.Args[1]
+		require.Equal(t, "first", arg.Name)
+		require.Equal(t, "", arg.Value)
+		require.Equal(t, "is an argument of the first stage", arg.Description)
+
+		arg = o.Args[2]
+		require.Equal(t, "target", arg.Name)
+		require.Equal(t, "default", arg.Value)
+		require.Equal(t, "sets the target", arg.Description)
+
+		require.Equal(t, 1, len(o.Sources))
+		require.Equal(t, dockerfile, o.Sources[0])
+
+		require.Contains(t, string(res.Metadata["result.txt"]), "BUILD ARG")
+
+		called = true
+		return nil, nil
+	}
+
+	_, err = c.Build(sb.Context(), client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+
+	require.True(t, called)
+}
+
+func testOutlineSecrets(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+	if _, ok := f.(*clientFrontend); !ok {
+		t.Skip("only test with client frontend")
+	}
+
+	dockerfile := []byte(`
+FROM busybox AS first
+RUN --mount=type=secret,target=/etc/passwd,required=true --mount=type=ssh true
+
+FROM alpine AS second
+RUN --mount=type=secret,id=unused --mount=type=ssh,id=ssh2 true
+
+FROM scratch AS third
+ARG foo
+RUN --mount=type=secret,id=second${foo} true
+
+FROM third AS target
+COPY --from=first /foo /
+RUN --mount=type=ssh,id=ssh3,required true
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	called := false
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			FrontendOpt: map[string]string{
+				"frontend.caps": "moby.buildkit.frontend.subrequests",
+				"requestid":     "frontend.outline.v0",
+				"target":        "target",
+			},
+			Frontend: "dockerfile.v0",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var o outline.Outline
+		require.NoError(t, json.Unmarshal(res.Metadata["result.json"], &o))
+
+		require.Equal(t, 2, len(o.Secrets))
+		require.Equal(t, "passwd", o.Secrets[0].Name)
+		require.True(t, o.Secrets[0].Required)
+		require.Equal(t, int32(3), o.Secrets[0].Location.Ranges[0].Start.Line)
+		require.Equal(t, "second", o.Secrets[1].Name)
+		require.False(t, o.Secrets[1].Required)
+
+		require.Equal(t, 2, len(o.SSH))
+		require.Equal(t, "default", o.SSH[0].Name)
+		require.False(t, o.SSH[0].Required)
+		require.Equal(t, "ssh3", o.SSH[1].Name)
+		require.True(t, o.SSH[1].Required)
+
+		called = true
+		return nil, nil
+	}
+
+	_, err = c.Build(sb.Context(), client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+
+	require.True(t, called)
+}
+
+func testListTargets(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+	if _, ok := f.(*clientFrontend); !ok {
+		t.Skip("only test with client frontend")
+	}
+
+	dockerfile := []byte(`
+# build defines stage for compiling the binary
+FROM alpine AS build
+RUN true
+
+FROM busybox AS unused
+RUN true
+
+FROM --platform=$BUILDPLATFORM busybox AS cross
+
+# binary returns the compiled binary
+FROM build AS binary
+
+FROM scratch
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	called := false
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			FrontendOpt: map[string]string{
+				"frontend.caps": "moby.buildkit.frontend.subrequests",
+				"requestid":     "frontend.targets.v0",
+			},
+			Frontend: "dockerfile.v0",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var l targets.List
+		require.NoError(t, json.Unmarshal(res.Metadata["result.json"], &l))
+
+		require.Equal(t, 1, len(l.Sources))
+		require.Equal(t, dockerfile, l.Sources[0])
+
+		require.Equal(t, 5, len(l.Targets))
+
+		target := l.Targets[0]
+		require.Equal(t, "build", target.Name)
+		require.Equal(t, "alpine", target.Base)
+		require.Equal(t, "defines stage for compiling the binary", target.Description)
+		require.False(t, target.Default)
+		require.Equal(t, int32(3), target.Location.Ranges[0].Start.Line)
+
+		target = l.Targets[1]
+		require.Equal(t, "unused", target.Name)
+		require.Equal(t, "busybox", target.Base)
+
+		target = l.Targets[2]
+		require.Equal(t, "cross", target.Name)
+		require.Equal(t, "$BUILDPLATFORM", target.Platform)
+
+		target = l.Targets[3]
+		require.Equal(t, "binary", target.Name)
+		require.Equal(t, "returns the compiled binary", target.Description)
+
+		target = l.Targets[4]
+		require.Equal(t, "", target.Name)
+		require.Equal(t, "scratch", target.Base)
+		require.True(t, target.Default)
+
+		require.Contains(t, string(res.Metadata["result.txt"]), "(default)")
+
+		called = true
+		return nil, nil
+	}
+
+	_, err = c.Build(sb.Context(), client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+
+	require.True(t, called)
+}
@@ -115,6 +115,10 @@ var allTests = []integration.Test{
 	testWildcardRenameCache,
 	testDockerfileInvalidInstruction,
 	testBuildInfo,
+	testSourceDateEpoch,
+	testRunTimeout,
+	testCopyParents,
+	testCopyExclude,
 }
 
 var fileOpTests = []integration.Test{
@@ -4785,6 +4789,207 @@ COPY --from=build /out /
 	require.Equal(t, "hpvalue2::::foocontents2::::bazcontent", string(dt))
 }
 
+func testSourceDateEpoch(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`
+FROM busybox AS build
+RUN echo -n $SOURCE_DATE_EPOCH > /out
+FROM scratch
+COPY --from=build /out /
+`)
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		FrontendAttrs: map[string]string{
+			"build-arg:SOURCE_DATE_EPOCH": fmt.Sprintf("%d", tm.Unix()),
+		},
+		Exports: []client.ExportEntry{
+			{
+				Type:      client.ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "out"))
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d", tm.Unix()), string(dt))
+
+	fi, err := os.Stat(filepath.Join(destDir, "out"))
+	require.NoError(t, err)
+	require.Equal(t, tm, fi.ModTime().UTC())
+}
+
+func testRunTimeout(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`
+FROM busybox
+RUN --timeout=2s sleep 60
+`)
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	start := time.Now()
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.Error(t, err)
+	var terr *errdefs.VertexTimeoutError
+	require.True(t, errors.As(err, &terr))
+	require.Equal(t, int64(2*time.Second), terr.Timeout)
+	require.Less(t, int64(time.Since(start)), int64(30*time.Second))
+}
+
+func testCopyParents(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`
+FROM scratch
+COPY --parents a/*/package.json /app/
+COPY --parents x/./y/*.txt /pivot/
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+		fstest.CreateDir("a", 0700),
+		fstest.CreateDir("a/foo", 0700),
+		fstest.CreateDir("a/bar", 0700),
+		fstest.CreateFile("a/foo/package.json", []byte("foo"), 0600),
+		fstest.CreateFile("a/foo/index.js", []byte("index"), 0600),
+		fstest.CreateFile("a/bar/package.json", []byte("bar"), 0600),
+		fstest.CreateDir("x", 0700),
+		fstest.CreateDir("x/y", 0700),
+		fstest.CreateFile("x/y/z.txt", []byte("z"), 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		Exports: []client.ExportEntry{
+			{
+				Type:      client.ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "app/a/foo/package.json"))
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(dt))
+
+	dt, err = ioutil.ReadFile(filepath.Join(destDir, "app/a/bar/package.json"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(dt))
+
+	_, err = os.Stat(filepath.Join(destDir, "app/a/foo/index.js"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	dt, err = ioutil.ReadFile(filepath.Join(destDir, "pivot/y/z.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "z", string(dt))
+}
+
+func testCopyExclude(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+
+	dockerfile := []byte(`
+FROM scratch
+COPY --exclude=**/*.test --exclude=docs . /src/
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+		fstest.CreateFile("main.go", []byte("main"), 0600),
+		fstest.CreateFile("main.test", []byte("test"), 0600),
+		fstest.CreateDir("sub", 0700),
+		fstest.CreateFile("sub/sub.go", []byte("sub"), 0600),
+		fstest.CreateFile("sub/sub.test", []byte("test"), 0600),
+		fstest.CreateDir("docs", 0700),
+		fstest.CreateFile("docs/README.md", []byte("docs"), 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		Exports: []client.ExportEntry{
+			{
+				Type:      client.ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "src/main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "main", string(dt))
+
+	dt, err = ioutil.ReadFile(filepath.Join(destDir, "src/sub/sub.go"))
+	require.NoError(t, err)
+	require.Equal(t, "sub", string(dt))
+
+	for _, p := range []string{"src/main.test", "src/sub/sub.test", "src/docs"} {
+		_, err = os.Stat(filepath.Join(destDir, p))
+		require.True(t, errors.Is(err, os.ErrNotExist), p)
+	}
+}
+
 func testTarContext(t *testing.T, sb integration.Sandbox) {
 	f := getFrontend(t, sb)
 	isFileOp := getFileOp(t, sb)
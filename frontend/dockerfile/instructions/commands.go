@@ -214,8 +214,9 @@ func (s *SourcesAndDest) Expand(expander SingleWordExpander) error {
 type AddCommand struct {
 	withNameAndCode
 	SourcesAndDest
-	Chown string
-	Chmod string
+	Chown           string
+	Chmod           string
+	ExcludePatterns []string
 }
 
 // Expand variables
@@ -226,6 +227,10 @@ func (c *AddCommand) Expand(expander SingleWordExpander) error {
 	}
 	c.Chown = expandedChown
 
+	if err := expandSliceInPlace(c.ExcludePatterns, expander); err != nil {
+		return err
+	}
+
 	return c.SourcesAndDest.Expand(expander)
 }
 
@@ -236,9 +241,11 @@ func (c *AddCommand) Expand(expander SingleWordExpander) error {
 type CopyCommand struct {
 	withNameAndCode
 	SourcesAndDest
-	From  string
-	Chown string
-	Chmod string
+	From            string
+	Chown           string
+	Chmod           string
+	Parents         bool // parents preserves directory structure
+	ExcludePatterns []string
 }
 
 // Expand variables
@@ -249,6 +256,10 @@ func (c *CopyCommand) Expand(expander SingleWordExpander) error {
 	}
 	c.Chown = expandedChown
 
+	if err := expandSliceInPlace(c.ExcludePatterns, expander); err != nil {
+		return err
+	}
+
 	return c.SourcesAndDest.Expand(expander)
 }
 
@@ -0,0 +1,95 @@
+package instructions
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var devicesKey = "dockerfile/run/devices"
+
+func init() {
+	parseRunPreHooks = append(parseRunPreHooks, runDevicePreHook)
+	parseRunPostHooks = append(parseRunPostHooks, runDevicePostHook)
+}
+
+// Device is a CDI device requested with RUN --device.
+type Device struct {
+	Name     string
+	Required bool
+}
+
+func runDevicePreHook(cmd *RunCommand, req parseRequest) error {
+	st := &deviceState{}
+	st.flag = req.flags.AddStrings("device")
+	cmd.setExternalValue(devicesKey, st)
+	return nil
+}
+
+func runDevicePostHook(cmd *RunCommand, req parseRequest) error {
+	st := cmd.getExternalValue(devicesKey).(*deviceState)
+	if st == nil {
+		return errors.Errorf("no device state")
+	}
+
+	var devices []*Device
+	for _, value := range st.flag.StringValues {
+		d, err := ParseDevice(value)
+		if err != nil {
+			return err
+		}
+		devices = append(devices, d)
+	}
+	st.devices = devices
+
+	return nil
+}
+
+// ParseDevice parses the value of a RUN --device flag in the form
+// name[,required[=<bool>]]. The name can be a fully qualified CDI device name
+// (vendor.com/class=name) or a short device name. Devices are required unless
+// required=false is set.
+func ParseDevice(value string) (*Device, error) {
+	fields, err := csv.NewReader(strings.NewReader(value)).Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse csv device")
+	}
+
+	d := &Device{Name: fields[0], Required: true}
+	if d.Name == "" {
+		return nil, errors.Errorf("device name is required")
+	}
+
+	for _, field := range fields[1:] {
+		parts := strings.SplitN(field, "=", 2)
+		key := strings.ToLower(parts[0])
+		switch key {
+		case "required":
+			if len(parts) == 1 {
+				d.Required = true
+				continue
+			}
+			v, err := strconv.ParseBool(parts[1])
+			if err != nil {
+				return nil, errors.Errorf("invalid value for %s: %s", key, parts[1])
+			}
+			d.Required = v
+		default:
+			return nil, errors.Errorf("unexpected key '%s' in '%s'", key, field)
+		}
+	}
+
+	return d, nil
+}
+
+// GetDevices returns the CDI devices requested by the command.
+func GetDevices(cmd *RunCommand) []*Device {
+	return cmd.getExternalValue(devicesKey).(*deviceState).devices
+}
+
+type deviceState struct {
+	flag    *Flag
+	devices []*Device
+}
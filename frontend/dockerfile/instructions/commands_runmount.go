@@ -5,7 +5,9 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/docker/go-units"
 	"github.com/moby/buildkit/util/suggest"
 	"github.com/pkg/errors"
 )
@@ -28,12 +30,22 @@ const MountSharingShared = "shared"
 const MountSharingPrivate = "private"
 const MountSharingLocked = "locked"
 
+var mountOutputNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
 var allowedSharingTypes = map[string]struct{}{
 	MountSharingShared:  {},
 	MountSharingPrivate: {},
 	MountSharingLocked:  {},
 }
 
+const MountCachePruneTrim = "trim"
+const MountCachePruneReset = "reset"
+
+var allowedCachePrunePolicies = map[string]struct{}{
+	MountCachePruneTrim:  {},
+	MountCachePruneReset: {},
+}
+
 type mountsKeyT string
 
 var mountsKey = mountsKeyT("dockerfile/run/mounts")
@@ -129,6 +141,14 @@ type Mount struct {
 	Mode         *uint64
 	UID          *uint64
 	GID          *uint64
+	// Output is the name under which the writes to a read-write bind mount
+	// are kept, so they can be used with COPY --from=<stage>#<output>.
+	Output string
+	// CacheMaxSize and CacheMaxAge limit the contents of a cache mount.
+	// CachePrune selects how the size limit is enforced.
+	CacheMaxSize int64
+	CacheMaxAge  time.Duration
+	CachePrune   string
 }
 
 func parseMount(value string, expander SingleWordExpander) (*Mount, error) {
@@ -252,9 +272,31 @@ func parseMount(value string, expander SingleWordExpander) (*Mount, error) {
 				return nil, errors.Errorf("invalid value %s for gid", value)
 			}
 			m.GID = &gid
+		case "output":
+			if !mountOutputNameRe.MatchString(value) {
+				return nil, errors.Errorf("invalid output name %q", value)
+			}
+			m.Output = value
+		case "maxsize":
+			size, err := units.RAMInBytes(value)
+			if err != nil || size <= 0 {
+				return nil, errors.Errorf("invalid value %s for maxsize", value)
+			}
+			m.CacheMaxSize = size
+		case "maxage":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return nil, errors.Errorf("invalid value %s for maxage", value)
+			}
+			m.CacheMaxAge = d
+		case "prune":
+			if _, ok := allowedCachePrunePolicies[strings.ToLower(value)]; !ok {
+				return nil, errors.Errorf("unsupported prune value %q", value)
+			}
+			m.CachePrune = strings.ToLower(value)
 		default:
 			allKeys := []string{
-				"type", "from", "source", "target", "readonly", "id", "sharing", "required", "mode", "uid", "gid", "src", "dst", "ro", "rw", "readwrite",
+				"type", "from", "source", "target", "readonly", "id", "sharing", "required", "mode", "uid", "gid", "src", "dst", "ro", "rw", "readwrite", "output", "maxsize", "maxage", "prune",
 			}
 			return nil, suggest.WrapError(errors.Errorf("unexpected key '%s' in '%s'", key, field), key, allKeys, true)
 		}
@@ -274,6 +316,17 @@ func parseMount(value string, expander SingleWordExpander) (*Mount, error) {
 		return nil, errors.Errorf("gid not allowed for %q type mounts", m.Type)
 	}
 
+	if m.Output != "" {
+		if m.Type != MountTypeBind {
+			return nil, errors.Errorf("output not allowed for %q type mounts", m.Type)
+		}
+		if !roAuto && m.ReadOnly {
+			return nil, errors.Errorf("output requires a read-write mount")
+		}
+		m.ReadOnly = false
+		roAuto = false
+	}
+
 	if roAuto {
 		if m.Type == MountTypeCache || m.Type == MountTypeTmpfs {
 			m.ReadOnly = false
@@ -286,6 +339,14 @@ func parseMount(value string, expander SingleWordExpander) (*Mount, error) {
 		return nil, errors.Errorf("invalid cache sharing set for %v mount", m.Type)
 	}
 
+	if (m.CacheMaxSize != 0 || m.CacheMaxAge != 0 || m.CachePrune != "") && m.Type != MountTypeCache {
+		return nil, errors.Errorf("invalid cache limits set for %v mount", m.Type)
+	}
+
+	if m.CachePrune != "" && m.CacheMaxSize == 0 {
+		return nil, errors.Errorf("prune requires maxsize to be set")
+	}
+
 	if m.Type == MountTypeSecret {
 		if m.From != "" {
 			return nil, errors.Errorf("secret mount should not have a from")
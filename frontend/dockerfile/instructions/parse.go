@@ -283,6 +283,7 @@ func parseAdd(req parseRequest) (*AddCommand, error) {
 	}
 	flChown := req.flags.AddString("chown", "")
 	flChmod := req.flags.AddString("chmod", "")
+	flExcludes := req.flags.AddStrings("exclude")
 	if err := req.flags.Parse(); err != nil {
 		return nil, err
 	}
@@ -297,6 +298,7 @@ func parseAdd(req parseRequest) (*AddCommand, error) {
 		SourcesAndDest:  *sourcesAndDest,
 		Chown:           flChown.Value,
 		Chmod:           flChmod.Value,
+		ExcludePatterns: flExcludes.StringValues,
 	}, nil
 }
 
@@ -307,6 +309,8 @@ func parseCopy(req parseRequest) (*CopyCommand, error) {
 	flChown := req.flags.AddString("chown", "")
 	flFrom := req.flags.AddString("from", "")
 	flChmod := req.flags.AddString("chmod", "")
+	flParents := req.flags.AddBool("parents", false)
+	flExcludes := req.flags.AddStrings("exclude")
 	if err := req.flags.Parse(); err != nil {
 		return nil, err
 	}
@@ -322,6 +326,8 @@ func parseCopy(req parseRequest) (*CopyCommand, error) {
 		From:            flFrom.Value,
 		Chown:           flChown.Value,
 		Chmod:           flChmod.Value,
+		Parents:         flParents.IsTrue(),
+		ExcludePatterns: flExcludes.StringValues,
 	}, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/moby/buildkit/frontend/dockerfile/command"
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
@@ -239,3 +240,156 @@ func TestRunCmdFlagsUsed(t *testing.T) {
 	require.IsType(t, c, &RunCommand{})
 	require.Equal(t, []string{"mount"}, c.(*RunCommand).FlagsUsed)
 }
+
+func TestRunTimeout(t *testing.T) {
+	cases := []struct {
+		dockerfile    string
+		timeout       time.Duration
+		expectedError string
+	}{
+		{dockerfile: "RUN echo hello", timeout: 0},
+		{dockerfile: "RUN --timeout=1m30s echo hello", timeout: 90 * time.Second},
+		{dockerfile: "RUN --timeout=10 echo hello", expectedError: "invalid timeout"},
+		{dockerfile: "RUN --timeout=-1s echo hello", expectedError: "must be positive"},
+	}
+	for _, c := range cases {
+		ast, err := parser.Parse(strings.NewReader(c.dockerfile))
+		require.NoError(t, err)
+
+		cmd, err := ParseInstruction(ast.AST.Children[0])
+		if c.expectedError != "" {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, c.timeout, GetTimeout(cmd.(*RunCommand)))
+	}
+}
+
+func TestCopyParentsAndExclude(t *testing.T) {
+	dockerfile := `COPY --parents --exclude=**/*.test --exclude=docs a/*/package.json /app/
+ADD --exclude=*.md . /src/`
+	ast, err := parser.Parse(strings.NewReader(dockerfile))
+	require.NoError(t, err)
+
+	cmd, err := ParseInstruction(ast.AST.Children[0])
+	require.NoError(t, err)
+	copyCmd := cmd.(*CopyCommand)
+	require.True(t, copyCmd.Parents)
+	require.Equal(t, []string{"**/*.test", "docs"}, copyCmd.ExcludePatterns)
+	require.Equal(t, []string{"a/*/package.json"}, copyCmd.SourcePaths)
+
+	cmd, err = ParseInstruction(ast.AST.Children[1])
+	require.NoError(t, err)
+	addCmd := cmd.(*AddCommand)
+	require.Equal(t, []string{"*.md"}, addCmd.ExcludePatterns)
+
+	ast, err = parser.Parse(strings.NewReader("ADD --parents a/b /app/"))
+	require.NoError(t, err)
+	_, err = ParseInstruction(ast.AST.Children[0])
+	require.Error(t, err)
+}
+
+func TestRunDevice(t *testing.T) {
+	cases := []struct {
+		dockerfile    string
+		devices       []*Device
+		expectedError string
+	}{
+		{dockerfile: "RUN echo hello"},
+		{
+			dockerfile: "RUN --device=vendor1.com/device=foo --device=bar,required echo hello",
+			devices: []*Device{
+				{Name: "vendor1.com/device=foo", Required: true},
+				{Name: "bar", Required: true},
+			},
+		},
+		{
+			dockerfile: "RUN --device=foo,required=false echo hello",
+			devices:    []*Device{{Name: "foo"}},
+		},
+		{dockerfile: "RUN --device=,required echo hello", expectedError: "device name is required"},
+		{dockerfile: "RUN --device=foo,required=maybe echo hello", expectedError: "invalid value for required"},
+		{dockerfile: "RUN --device=foo,mode=rw echo hello", expectedError: "unexpected key 'mode'"},
+	}
+	for _, c := range cases {
+		ast, err := parser.Parse(strings.NewReader(c.dockerfile))
+		require.NoError(t, err)
+
+		cmd, err := ParseInstruction(ast.AST.Children[0])
+		if c.expectedError != "" {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, c.devices, GetDevices(cmd.(*RunCommand)))
+	}
+}
+
+func TestRunMountOutput(t *testing.T) {
+	expander := func(word string) (string, error) {
+		return word, nil
+	}
+	cases := []struct {
+		value         string
+		output        string
+		readOnly      bool
+		expectedError string
+	}{
+		{value: "type=bind,target=/out", readOnly: true},
+		{value: "type=bind,target=/out,output=artifacts", output: "artifacts"},
+		{value: "target=/out,rw,output=artifacts", output: "artifacts"},
+		{value: "type=bind,target=/out,ro,output=artifacts", expectedError: "requires a read-write mount"},
+		{value: "type=cache,target=/out,output=artifacts", expectedError: "not allowed for \"cache\""},
+		{value: "type=bind,target=/out,output=-bad", expectedError: "invalid output name"},
+	}
+	for _, c := range cases {
+		m, err := parseMount(c.value, expander)
+		if c.expectedError != "" {
+			require.Error(t, err, c.value)
+			require.Contains(t, err.Error(), c.expectedError)
+			continue
+		}
+		require.NoError(t, err, c.value)
+		require.Equal(t, c.output, m.Output)
+		require.Equal(t, c.readOnly, m.ReadOnly)
+	}
+}
+
+func TestRunMountCacheLimits(t *testing.T) {
+	expander := func(word string) (string, error) {
+		return word, nil
+	}
+	cases := []struct {
+		value         string
+		maxSize       int64
+		maxAge        time.Duration
+		prune         string
+		expectedError string
+	}{
+		{value: "type=cache,target=/cache"},
+		{value: "type=cache,target=/cache,maxsize=512m", maxSize: 512 << 20},
+		{value: "type=cache,target=/cache,maxsize=1GB,prune=reset", maxSize: 1 << 30, prune: "reset"},
+		{value: "type=cache,target=/cache,maxage=72h", maxAge: 72 * time.Hour},
+		{value: "type=cache,target=/cache,maxsize=100k,maxage=1h,prune=TRIM", maxSize: 100 << 10, maxAge: time.Hour, prune: "trim"},
+		{value: "type=cache,target=/cache,maxsize=foo", expectedError: "invalid value foo for maxsize"},
+		{value: "type=cache,target=/cache,maxage=3", expectedError: "invalid value 3 for maxage"},
+		{value: "type=cache,target=/cache,maxsize=1g,prune=all", expectedError: "unsupported prune value"},
+		{value: "type=cache,target=/cache,prune=reset", expectedError: "prune requires maxsize"},
+		{value: "type=bind,target=/cache,maxsize=1g", expectedError: "invalid cache limits set for bind mount"},
+	}
+	for _, c := range cases {
+		m, err := parseMount(c.value, expander)
+		if c.expectedError != "" {
+			require.Error(t, err, c.value)
+			require.Contains(t, err.Error(), c.expectedError)
+			continue
+		}
+		require.NoError(t, err, c.value)
+		require.Equal(t, c.maxSize, m.CacheMaxSize)
+		require.Equal(t, c.maxAge, m.CacheMaxAge)
+		require.Equal(t, c.prune, m.CachePrune)
+	}
+}
@@ -0,0 +1,138 @@
+package linter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/pkg/errors"
+)
+
+// Rule is a check of the Dockerfile linter.
+type Rule struct {
+	// Name is the ID of the rule used for skipping it
+	Name        string
+	Description string
+	URL         string
+	// Format is the fmt format of the message of a violation
+	Format string
+}
+
+// Config controls which rules are run and how violations are reported.
+type Config struct {
+	// Warn is called for every violation of a rule that is not skipped
+	Warn          func(rule Rule, msg string, location []parser.Range)
+	SkipRules     []string
+	SkipAll       bool
+	ReturnAsError bool
+}
+
+// Linter runs rules and records their violations.
+type Linter struct {
+	skip          map[string]struct{}
+	skipAll       bool
+	returnAsError bool
+	warn          func(rule Rule, msg string, location []parser.Range)
+	violations    []string
+}
+
+func New(config *Config) *Linter {
+	l := &Linter{
+		skip:          map[string]struct{}{},
+		skipAll:       config.SkipAll,
+		returnAsError: config.ReturnAsError,
+		warn:          config.Warn,
+	}
+	for _, r := range config.SkipRules {
+		l.skip[strings.TrimSpace(r)] = struct{}{}
+	}
+	return l
+}
+
+// Run reports a violation of rule at location unless the rule is skipped.
+// args are used for the Format of the rule.
+func (l *Linter) Run(rule Rule, location []parser.Range, args ...interface{}) {
+	if l == nil || l.skipAll {
+		return
+	}
+	if _, ok := l.skip[rule.Name]; ok {
+		return
+	}
+	msg := fmt.Sprintf(rule.Format, args...)
+	l.violations = append(l.violations, rule.Name)
+	if l.warn != nil {
+		l.warn(rule, msg, location)
+	}
+}
+
+// Error returns an error if violations were reported and the config asked
+// for violations to fail the build.
+func (l *Linter) Error() error {
+	if l == nil || !l.returnAsError || len(l.violations) == 0 {
+		return nil
+	}
+	var names []string
+	seen := map[string]struct{}{}
+	for _, v := range l.violations {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		names = append(names, v)
+	}
+	return errors.Errorf("lint violation found for rules: %s", strings.Join(names, ", "))
+}
+
+// ParseLintOptions parses the value of the check directive, e.g.
+// "skip=StageNameCasing,JSONArgsRecommended;error=true".
+func ParseLintOptions(checkStr string) (*Config, error) {
+	checkStr = strings.TrimSpace(checkStr)
+	if checkStr == "" {
+		return &Config{}, nil
+	}
+
+	parts := strings.Split(checkStr, ";")
+	var skipSet []string
+	var errorOnWarn, skipAll bool
+	for _, p := range parts {
+		k, v, ok := parseKeyValue(p)
+		if !ok {
+			return nil, errors.Errorf("invalid check option %q", p)
+		}
+		k = strings.TrimSpace(k)
+		switch k {
+		case "skip":
+			v = strings.TrimSpace(v)
+			if v == "all" {
+				skipAll = true
+			} else {
+				skipSet = strings.Split(v, ",")
+				for i, rule := range skipSet {
+					skipSet[i] = strings.TrimSpace(rule)
+				}
+			}
+		case "error":
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse check option %q", p)
+			}
+			errorOnWarn = b
+		default:
+			return nil, errors.Errorf("invalid check option %q", k)
+		}
+	}
+	return &Config{
+		SkipRules:     skipSet,
+		SkipAll:       skipAll,
+		ReturnAsError: errorOnWarn,
+	}, nil
+}
+
+func parseKeyValue(s string) (string, string, bool) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
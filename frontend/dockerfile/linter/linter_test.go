@@ -0,0 +1,59 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLintOptions(t *testing.T) {
+	cfg, err := ParseLintOptions("")
+	require.NoError(t, err)
+	require.False(t, cfg.SkipAll)
+	require.False(t, cfg.ReturnAsError)
+
+	cfg, err = ParseLintOptions("skip=StageNameCasing, FromAsCasing ;error=true")
+	require.NoError(t, err)
+	require.Equal(t, []string{"StageNameCasing", "FromAsCasing"}, cfg.SkipRules)
+	require.True(t, cfg.ReturnAsError)
+
+	cfg, err = ParseLintOptions("skip=all")
+	require.NoError(t, err)
+	require.True(t, cfg.SkipAll)
+
+	// every option is parsed, not only the first two
+	cfg, err = ParseLintOptions("error=false;skip=StageNameCasing;error=true")
+	require.NoError(t, err)
+	require.Equal(t, []string{"StageNameCasing"}, cfg.SkipRules)
+	require.True(t, cfg.ReturnAsError)
+
+	_, err = ParseLintOptions("skip=StageNameCasing;error=true;unknown=1")
+	require.Error(t, err)
+
+	_, err = ParseLintOptions("error=maybe")
+	require.Error(t, err)
+
+	_, err = ParseLintOptions("skip")
+	require.Error(t, err)
+}
+
+func TestLinter(t *testing.T) {
+	var msgs []string
+	l := New(&Config{
+		Warn: func(rule Rule, msg string, location []parser.Range) {
+			msgs = append(msgs, msg)
+		},
+		SkipRules:     []string{RuleMaintainerDeprecated.Name},
+		ReturnAsError: true,
+	})
+	require.NoError(t, l.Error())
+
+	l.Run(RuleMaintainerDeprecated, nil)
+	require.NoError(t, l.Error())
+
+	l.Run(RuleStageNameCasing, nil, "Foo")
+	l.Run(RuleStageNameCasing, nil, "Bar")
+	require.Equal(t, []string{"Stage name 'Foo' should be lowercase", "Stage name 'Bar' should be lowercase"}, msgs)
+	require.EqualError(t, l.Error(), "lint violation found for rules: StageNameCasing")
+}
@@ -0,0 +1,58 @@
+package linter
+
+var (
+	RuleStageNameCasing = Rule{
+		Name:        "StageNameCasing",
+		Description: "Stage names should be lowercase",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/stage-name-casing/",
+		Format:      "Stage name '%s' should be lowercase",
+	}
+	RuleFromAsCasing = Rule{
+		Name:        "FromAsCasing",
+		Description: "The 'as' keyword should match the case of the 'from' keyword",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/from-as-casing/",
+		Format:      "'%s' and '%s' keywords' casing do not match",
+	}
+	RuleDuplicateStageName = Rule{
+		Name:        "DuplicateStageName",
+		Description: "Stage names should be unique",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/duplicate-stage-name/",
+		Format:      "Duplicate stage name %q, stage names should be unique",
+	}
+	RuleUndefinedArgInFrom = Rule{
+		Name:        "UndefinedArgInFrom",
+		Description: "FROM command must use declared ARGs",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/undefined-arg-in-from/",
+		Format:      "FROM argument '%s' is not declared",
+	}
+	RuleFromPlatformFlagConstDisallowed = Rule{
+		Name:        "FromPlatformFlagConstDisallowed",
+		Description: "FROM --platform flag should not use a constant value",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/from-platform-flag-const-disallowed/",
+		Format:      "FROM --platform flag should not use constant value %q",
+	}
+	RuleRedundantTargetPlatform = Rule{
+		Name:        "RedundantTargetPlatform",
+		Description: "Setting platform to predefined $TARGETPLATFORM in FROM is redundant as this is the default behavior",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/redundant-target-platform/",
+		Format:      "Setting platform to predefined %s in FROM is redundant as this is the default behavior",
+	}
+	RuleJSONArgsRecommended = Rule{
+		Name:        "JSONArgsRecommended",
+		Description: "JSON arguments recommended for ENTRYPOINT/CMD to prevent unintended behavior related to OS signals",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/json-args-recommended/",
+		Format:      "JSON arguments recommended for %s to prevent unintended behavior related to OS signals",
+	}
+	RuleMaintainerDeprecated = Rule{
+		Name:        "MaintainerDeprecated",
+		Description: "The MAINTAINER instruction is deprecated, use a label instead to define an image author",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/maintainer-deprecated/",
+		Format:      "Maintainer instruction is deprecated in favor of using label",
+	}
+	RuleSecretsUsedInArgOrEnv = Rule{
+		Name:        "SecretsUsedInArgOrEnv",
+		Description: "Sensitive data should not be used in the ARG or ENV commands",
+		URL:         "https://docs.docker.com/go/dockerfile/rule/secrets-used-in-arg-or-env/",
+		Format:      "Do not use ARG or ENV instructions for sensitive data (%s %q)",
+	}
+)
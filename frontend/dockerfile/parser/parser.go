@@ -270,6 +270,17 @@ type Result struct {
 	AST         *Node
 	EscapeToken rune
 	Warnings    []string
+	// DetailedWarnings are the warnings of the parser with their location in
+	// the Dockerfile.
+	DetailedWarnings []Warning
+}
+
+// Warning is a non-fatal issue found while parsing a Dockerfile
+type Warning struct {
+	Short    string
+	Detail   [][]byte
+	URL      string
+	Location []Range
 }
 
 // PrintWarnings to the writer
@@ -289,6 +300,7 @@ func Parse(rwc io.Reader) (*Result, error) {
 	scanner := bufio.NewScanner(rwc)
 	scanner.Split(scanLines)
 	warnings := []string{}
+	var detailedWarnings []Warning
 	var comments []string
 
 	var err error
@@ -342,6 +354,12 @@ func Parse(rwc io.Reader) (*Result, error) {
 
 		if hasEmptyContinuationLine {
 			warnings = append(warnings, "[WARNING]: Empty continuation line found in:\n    "+line)
+			detailedWarnings = append(detailedWarnings, Warning{
+				Short:    "Empty continuation line found in: " + line,
+				Detail:   [][]byte{[]byte("Empty continuation lines will become errors in a future release")},
+				URL:      "https://github.com/moby/moby/pull/33719",
+				Location: toRanges(startLine, currentLine),
+			})
 		}
 
 		child, err := newNodeFromLine(line, d, comments)
@@ -393,9 +411,10 @@ func Parse(rwc io.Reader) (*Result, error) {
 	}
 
 	return &Result{
-		AST:         root,
-		Warnings:    warnings,
-		EscapeToken: d.escapeToken,
+		AST:              root,
+		Warnings:         warnings,
+		DetailedWarnings: detailedWarnings,
+		EscapeToken:      d.escapeToken,
 	}, withLocation(handleScannerError(scanner.Err()), currentLine, 0)
 }
 
@@ -167,6 +167,14 @@ RUN indented \
 	require.Contains(t, warnings[0], "RUN something     following     more")
 	require.Contains(t, warnings[1], "RUN another     thing")
 	require.Contains(t, warnings[2], "will become errors in a future release")
+
+	detailed := result.DetailedWarnings
+	require.Equal(t, 2, len(detailed))
+	require.Contains(t, detailed[0].Short, "Empty continuation line found in")
+	require.Contains(t, detailed[0].Short, "RUN something     following     more")
+	require.Contains(t, string(detailed[0].Detail[0]), "will become errors in a future release")
+	require.Contains(t, detailed[1].Short, "RUN another     thing")
+	require.Equal(t, 3, len(detailed[1].Location))
 }
 
 func TestParseReturnsScannerErrors(t *testing.T) {
@@ -60,16 +60,43 @@ func (s *Lex) ProcessWordsWithMap(word string, env map[string]string) ([]string,
 	return words, err
 }
 
+// ProcessWordResult is the result of ProcessWordWithMatches.
+type ProcessWordResult struct {
+	Result string
+	// Matched are the names of the variables of env referenced by the word
+	Matched map[string]struct{}
+	// Unmatched are the names of the referenced variables missing from env
+	Unmatched map[string]struct{}
+}
+
+// ProcessWordWithMatches works like ProcessWordWithMap and also returns the
+// names of the variables referenced by 'word'.
+func (s *Lex) ProcessWordWithMatches(word string, env map[string]string) (ProcessWordResult, error) {
+	sw := s.init(word, env)
+	word, _, err := sw.process(word)
+	return ProcessWordResult{
+		Result:    word,
+		Matched:   sw.matches,
+		Unmatched: sw.nonmatches,
+	}, err
+}
+
 func (s *Lex) process(word string, env map[string]string) (string, []string, error) {
+	return s.init(word, env).process(word)
+}
+
+func (s *Lex) init(word string, env map[string]string) *shellWord {
 	sw := &shellWord{
 		envs:         env,
 		escapeToken:  s.escapeToken,
 		skipUnsetEnv: s.SkipUnsetEnv,
 		rawQuotes:    s.RawQuotes,
 		rawEscapes:   s.RawEscapes,
+		matches:      make(map[string]struct{}),
+		nonmatches:   make(map[string]struct{}),
 	}
 	sw.scanner.Init(strings.NewReader(word))
-	return sw.process(word)
+	return sw
 }
 
 type shellWord struct {
@@ -79,6 +106,8 @@ type shellWord struct {
 	rawQuotes    bool
 	rawEscapes   bool
 	skipUnsetEnv bool
+	matches      map[string]struct{}
+	nonmatches   map[string]struct{}
 }
 
 func (sw *shellWord) process(source string) (string, []string, error) {
@@ -450,9 +479,15 @@ func isSpecialParam(char rune) bool {
 func (sw *shellWord) getEnv(name string) (string, bool) {
 	for key, value := range sw.envs {
 		if EqualEnvKeys(name, key) {
+			if sw.matches != nil {
+				sw.matches[key] = struct{}{}
+			}
 			return value, true
 		}
 	}
+	if sw.nonmatches != nil {
+		sw.nonmatches[name] = struct{}{}
+	}
 	return "", false
 }
 
@@ -225,3 +225,18 @@ func TestGetEnv(t *testing.T) {
 		t.Fatal("8 - 'car' should map to 'bike'")
 	}
 }
+
+func TestProcessWordMatches(t *testing.T) {
+	shlex := NewLex('\\')
+	env := map[string]string{
+		"FOO": "foo",
+		"BAR": "bar",
+		"BAZ": "baz",
+	}
+
+	res, err := shlex.ProcessWordWithMatches("$FOO-${BAR:-x}-$UNSET", env)
+	require.NoError(t, err)
+	require.Equal(t, "foo-bar-", res.Result)
+	require.Equal(t, map[string]struct{}{"FOO": {}, "BAR": {}}, res.Matched)
+	require.Equal(t, map[string]struct{}{"UNSET": {}}, res.Unmatched)
+}
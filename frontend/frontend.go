@@ -17,8 +17,13 @@ type Frontend interface {
 type FrontendLLBBridge interface {
 	Solve(ctx context.Context, req SolveRequest, sid string) (*Result, error)
 	ResolveImageConfig(ctx context.Context, ref string, opt llb.ResolveImageConfigOpt) (digest.Digest, []byte, error)
+	Warn(ctx context.Context, dgst digest.Digest, msg string, opts WarnOpts) error
+	// CacheNamespace returns the cache namespace of the build.
+	CacheNamespace() (string, error)
 }
 
 type SolveRequest = gw.SolveRequest
 
 type CacheOptionsEntry = gw.CacheOptionsEntry
+
+type WarnOpts = gw.WarnOpts
@@ -18,6 +18,18 @@ type Client interface {
 	BuildOpts() BuildOpts
 	Inputs(ctx context.Context) (map[string]llb.State, error)
 	NewContainer(ctx context.Context, req NewContainerRequest) (Container, error)
+	Warn(ctx context.Context, dgst digest.Digest, msg string, opts WarnOpts) error
+}
+
+// WarnOpts describes a non-fatal warning reported by a frontend. The warning
+// is delivered to the client with the progress of the build.
+type WarnOpts struct {
+	// Level is the severity of the warning, 1 if unset
+	Level      int
+	SourceInfo *pb.SourceInfo
+	Range      []*pb.Range
+	Detail     [][]byte
+	URL        string
 }
 
 // NewContainerRequest encapsulates the requirements for a client to define a
@@ -32,6 +32,9 @@ type NewContainerRequest struct {
 	Mounts      []Mount
 	Platform    *opspb.Platform
 	Constraints *opspb.WorkerConstraints
+	// CacheNamespace scopes the cache mounts of the container to a cache
+	// namespace.
+	CacheNamespace string
 }
 
 // Mount used for the gateway.Container is nearly identical to the client.Mount
@@ -43,6 +46,12 @@ type Mount struct {
 }
 
 func NewContainer(ctx context.Context, w worker.Worker, sm *session.Manager, g session.Group, req NewContainerRequest) (client.Container, error) {
+	for _, m := range req.Mounts {
+		if err := mounts.NamespaceCacheMount(m.Mount, req.CacheNamespace); err != nil {
+			return nil, err
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	eg, ctx := errgroup.WithContext(ctx)
 	platform := opspb.Platform{
@@ -337,7 +346,7 @@ func (gwCtr *gatewayContainer) Start(ctx context.Context, req client.StartReques
 		startedCh := make(chan struct{})
 		gwProc.errGroup.Go(func() error {
 			bklog.G(gwCtr.ctx).Debugf("Starting new container for %s with args: %q", gwCtr.id, procInfo.Meta.Args)
-			err := gwCtr.executor.Run(ctx, gwCtr.id, gwCtr.rootFS, gwCtr.mounts, procInfo, startedCh)
+			_, err := gwCtr.executor.Run(ctx, gwCtr.id, gwCtr.rootFS, gwCtr.mounts, procInfo, startedCh)
 			return stack.Enable(err)
 		})
 		select {
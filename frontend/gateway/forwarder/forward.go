@@ -155,20 +155,7 @@ func (c *bridgeClient) wrapSolveError(solveErr error) error {
 func (c *bridgeClient) registerResultIDs(results ...solver.Result) (ids []string, err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
-	ids = make([]string, len(results))
-	for i, res := range results {
-		if res == nil {
-			continue
-		}
-		workerRef, ok := res.Sys().(*worker.WorkerRef)
-		if !ok {
-			return ids, errors.Errorf("unexpected type for result, got %T", res.Sys())
-		}
-		ids[i] = workerRef.ID()
-		c.workerRefByID[workerRef.ID()] = workerRef
-	}
-	return ids, nil
+	return gateway.RegisterResultIDs(c.workerRefByID, results...)
 }
 
 func (c *bridgeClient) toFrontendResult(r *client.Result) (*frontend.Result, error) {
@@ -277,6 +264,11 @@ func (c *bridgeClient) NewContainer(ctx context.Context, req client.NewContainer
 		return nil, err
 	}
 
+	ctrReq.CacheNamespace, err = c.CacheNamespace()
+	if err != nil {
+		return nil, err
+	}
+
 	w, err := c.workers.GetDefault()
 	if err != nil {
 		return nil, err
@@ -252,7 +252,7 @@ func (gf *gatewayFrontend) Solve(ctx context.Context, llbBridge frontend.Fronten
 		return nil, err
 	}
 
-	err = w.Executor().Run(ctx, "", mountWithSession(rootFS, session.NewGroup(sid)), nil, executor.ProcessInfo{Meta: meta, Stdin: lbf.Stdin, Stdout: lbf.Stdout, Stderr: os.Stderr}, nil)
+	_, err = w.Executor().Run(ctx, "", mountWithSession(rootFS, session.NewGroup(sid)), nil, executor.ProcessInfo{Meta: meta, Stdin: lbf.Stdin, Stdout: lbf.Stdout, Stderr: os.Stderr}, nil)
 
 	if err != nil {
 		if errdefs.IsCanceled(err) && lbf.isErrServerClosed {
@@ -534,7 +534,14 @@ func (lbf *llbBridgeForwarder) wrapSolveError(solveErr error) error {
 func (lbf *llbBridgeForwarder) registerResultIDs(results ...solver.Result) (ids []string, err error) {
 	lbf.mu.Lock()
 	defer lbf.mu.Unlock()
+	return RegisterResultIDs(lbf.workerRefByID, results...)
+}
 
+// RegisterResultIDs adds the worker refs of results to refs and returns their
+// IDs. Nil results get an empty ID. The refs are cloned because the results
+// are owned by the solver and may be released before the client uses them,
+// e.g. when they are forwarded from a frontend. The caller releases the refs.
+func RegisterResultIDs(refs map[string]*worker.WorkerRef, results ...solver.Result) (ids []string, err error) {
 	ids = make([]string, len(results))
 	for i, res := range results {
 		if res == nil {
@@ -545,7 +552,13 @@ func (lbf *llbBridgeForwarder) registerResultIDs(results ...solver.Result) (ids
 			return ids, errors.Errorf("unexpected type for result, got %T", res.Sys())
 		}
 		ids[i] = workerRef.ID()
-		lbf.workerRefByID[workerRef.ID()] = workerRef
+		if _, ok := refs[ids[i]]; ok {
+			continue
+		}
+		if workerRef.ImmutableRef != nil {
+			workerRef = &worker.WorkerRef{ImmutableRef: workerRef.ImmutableRef.Clone(), Worker: workerRef.Worker}
+		}
+		refs[ids[i]] = workerRef
 	}
 	return ids, nil
 }
@@ -758,6 +771,20 @@ func (lbf *llbBridgeForwarder) StatFile(ctx context.Context, req *pb.StatFileReq
 	return &pb.StatFileResponse{Stat: st}, nil
 }
 
+func (lbf *llbBridgeForwarder) Warn(ctx context.Context, in *pb.WarnRequest) (*pb.WarnResponse, error) {
+	err := lbf.llbBridge.Warn(ctx, in.Digest, string(in.Short), frontend.WarnOpts{
+		Level:      int(in.Level),
+		SourceInfo: in.Info,
+		Range:      in.Ranges,
+		Detail:     in.Detail,
+		URL:        in.Url,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.WarnResponse{}, nil
+}
+
 func (lbf *llbBridgeForwarder) Ping(context.Context, *pb.PingRequest) (*pb.PongResponse, error) {
 
 	workers := lbf.workers.WorkerInfos()
@@ -892,6 +919,11 @@ func (lbf *llbBridgeForwarder) NewContainer(ctx context.Context, in *pb.NewConta
 		return nil, stack.Enable(err)
 	}
 
+	ctrReq.CacheNamespace, err = lbf.llbBridge.CacheNamespace()
+	if err != nil {
+		return nil, stack.Enable(err)
+	}
+
 	ctr, err := NewContainer(context.Background(), w, lbf.sm, group, ctrReq)
 	if err != nil {
 		return nil, stack.Enable(err)
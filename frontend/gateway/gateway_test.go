@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/worker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterResultIDs(t *testing.T) {
+	t.Parallel()
+
+	ref := &testRef{id: "foo", count: 1}
+	res := worker.NewWorkerRefResult(ref, &testWorker{id: "worker"})
+
+	lbf := newBridgeForwarder(context.TODO(), nil, nil, nil, "", nil)
+	ids, err := lbf.registerResultIDs(res, nil, res)
+	require.NoError(t, err)
+	require.Equal(t, []string{"worker::foo", "", "worker::foo"}, ids)
+
+	// the result is registered once with its own reference
+	require.Equal(t, 1, len(lbf.workerRefByID))
+	require.Equal(t, 2, ref.refCount())
+
+	// the ref stays valid after the solver releases the result
+	ref.Release(context.TODO())
+	require.Equal(t, 1, ref.refCount())
+
+	lbf.Discard()
+	require.Equal(t, 0, ref.refCount())
+	require.Equal(t, 0, len(lbf.workerRefByID))
+}
+
+type testRef struct {
+	cache.ImmutableRef
+	id    string
+	mu    sync.Mutex
+	count int
+}
+
+func (r *testRef) ID() string {
+	return r.id
+}
+
+func (r *testRef) Clone() cache.ImmutableRef {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	return r
+}
+
+func (r *testRef) Release(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count--
+	return nil
+}
+
+func (r *testRef) refCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+type testWorker struct {
+	worker.Worker
+	id string
+}
+
+func (w *testWorker) ID() string {
+	return w.id
+}
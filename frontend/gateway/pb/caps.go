@@ -51,6 +51,9 @@ const (
 	// results. This is generally used by the client to return and handle solve
 	// errors.
 	CapGatewayEvaluateSolve apicaps.CapID = "gateway.solve.evaluate"
+
+	// CapGatewayWarnings is the capability to log warnings from frontend
+	CapGatewayWarnings apicaps.CapID = "gateway.warnings"
 )
 
 func init() {
@@ -180,4 +183,11 @@ func init() {
 		Enabled: true,
 		Status:  apicaps.CapStatusExperimental,
 	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapGatewayWarnings,
+		Name:    "logging warnings",
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
 }
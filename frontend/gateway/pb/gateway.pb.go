@@ -2021,6 +2021,133 @@ func (m *ResizeMessage) GetCols() uint32 {
 	return 0
 }
 
+type WarnRequest struct {
+	Digest               github_com_opencontainers_go_digest.Digest `protobuf:"bytes,1,opt,name=digest,proto3,customtype=github.com/opencontainers/go-digest.Digest" json:"digest"`
+	Level                int64                                      `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Short                []byte                                     `protobuf:"bytes,3,opt,name=short,proto3" json:"short,omitempty"`
+	Detail               [][]byte                                   `protobuf:"bytes,4,rep,name=detail,proto3" json:"detail,omitempty"`
+	Url                  string                                     `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	Info                 *pb.SourceInfo                             `protobuf:"bytes,6,opt,name=info,proto3" json:"info,omitempty"`
+	Ranges               []*pb.Range                                `protobuf:"bytes,7,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                   `json:"-"`
+	XXX_unrecognized     []byte                                     `json:"-"`
+	XXX_sizecache        int32                                      `json:"-"`
+}
+
+func (m *WarnRequest) Reset()         { *m = WarnRequest{} }
+func (m *WarnRequest) String() string { return proto.CompactTextString(m) }
+func (*WarnRequest) ProtoMessage()    {}
+func (*WarnRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{33}
+}
+func (m *WarnRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WarnRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WarnRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WarnRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WarnRequest.Merge(m, src)
+}
+func (m *WarnRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *WarnRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WarnRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WarnRequest proto.InternalMessageInfo
+
+func (m *WarnRequest) GetLevel() int64 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *WarnRequest) GetShort() []byte {
+	if m != nil {
+		return m.Short
+	}
+	return nil
+}
+
+func (m *WarnRequest) GetDetail() [][]byte {
+	if m != nil {
+		return m.Detail
+	}
+	return nil
+}
+
+func (m *WarnRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *WarnRequest) GetInfo() *pb.SourceInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *WarnRequest) GetRanges() []*pb.Range {
+	if m != nil {
+		return m.Ranges
+	}
+	return nil
+}
+
+type WarnResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WarnResponse) Reset()         { *m = WarnResponse{} }
+func (m *WarnResponse) String() string { return proto.CompactTextString(m) }
+func (*WarnResponse) ProtoMessage()    {}
+func (*WarnResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{34}
+}
+func (m *WarnResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WarnResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WarnResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WarnResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WarnResponse.Merge(m, src)
+}
+func (m *WarnResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *WarnResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WarnResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WarnResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Result)(nil), "moby.buildkit.v1.frontend.Result")
 	proto.RegisterMapType((map[string][]byte)(nil), "moby.buildkit.v1.frontend.Result.MetadataEntry")
@@ -2062,133 +2189,142 @@ func init() {
 	proto.RegisterType((*DoneMessage)(nil), "moby.buildkit.v1.frontend.DoneMessage")
 	proto.RegisterType((*FdMessage)(nil), "moby.buildkit.v1.frontend.FdMessage")
 	proto.RegisterType((*ResizeMessage)(nil), "moby.buildkit.v1.frontend.ResizeMessage")
+	proto.RegisterType((*WarnRequest)(nil), "moby.buildkit.v1.frontend.WarnRequest")
+	proto.RegisterType((*WarnResponse)(nil), "moby.buildkit.v1.frontend.WarnResponse")
 }
 
 func init() { proto.RegisterFile("gateway.proto", fileDescriptor_f1a937782ebbded5) }
 
 var fileDescriptor_f1a937782ebbded5 = []byte{
-	// 1932 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4f, 0x6f, 0x1b, 0xc7,
-	0x15, 0xd7, 0x8a, 0x94, 0x48, 0x3e, 0xfe, 0x31, 0x33, 0x4e, 0x53, 0x7a, 0x11, 0x38, 0xcc, 0x22,
-	0x55, 0x69, 0x47, 0x59, 0xa6, 0x74, 0x02, 0xb9, 0x72, 0x90, 0xd4, 0x94, 0x28, 0x48, 0x8d, 0x24,
-	0xb3, 0xe3, 0x14, 0x06, 0x82, 0x14, 0xe8, 0x8a, 0x3b, 0xa4, 0x17, 0xa6, 0x76, 0xb7, 0xb3, 0x43,
-	0xcb, 0x4c, 0x2e, 0xed, 0xad, 0xc7, 0x02, 0x05, 0x7a, 0x2d, 0xd0, 0x4f, 0xd0, 0x4b, 0xaf, 0x3d,
-	0xe7, 0xd8, 0x73, 0x0f, 0x46, 0xe1, 0xcf, 0xd0, 0x7b, 0x8b, 0x37, 0x3b, 0x43, 0x2e, 0x29, 0x6a,
-	0x49, 0x22, 0x27, 0xce, 0xbc, 0x7d, 0xbf, 0x37, 0xef, 0xdf, 0xbc, 0xf7, 0x86, 0x50, 0x1e, 0x38,
-	0x82, 0x5d, 0x39, 0x63, 0x3b, 0xe4, 0x81, 0x08, 0xc8, 0x9d, 0xcb, 0xe0, 0x62, 0x6c, 0x5f, 0x8c,
-	0xbc, 0xa1, 0xfb, 0xc2, 0x13, 0xf6, 0xcb, 0x9f, 0xd9, 0x7d, 0x1e, 0xf8, 0x82, 0xf9, 0xae, 0xf9,
-	0xd1, 0xc0, 0x13, 0xcf, 0x47, 0x17, 0x76, 0x2f, 0xb8, 0x6c, 0x0e, 0x82, 0x41, 0xd0, 0x94, 0x88,
-	0x8b, 0x51, 0x5f, 0xee, 0xe4, 0x46, 0xae, 0x62, 0x49, 0x66, 0x6b, 0x9e, 0x7d, 0x10, 0x04, 0x83,
-	0x21, 0x73, 0x42, 0x2f, 0x52, 0xcb, 0x26, 0x0f, 0x7b, 0xcd, 0x48, 0x38, 0x62, 0x14, 0x29, 0xcc,
-	0x6e, 0x02, 0x83, 0x8a, 0x34, 0xb5, 0x22, 0xcd, 0x28, 0x18, 0xbe, 0x64, 0xbc, 0x19, 0x5e, 0x34,
-	0x83, 0x50, 0x73, 0x37, 0x6f, 0xe4, 0x76, 0x42, 0xaf, 0x29, 0xc6, 0x21, 0x8b, 0x9a, 0x57, 0x01,
-	0x7f, 0xc1, 0xb8, 0x02, 0x3c, 0xb8, 0x11, 0x30, 0x12, 0xde, 0x10, 0x51, 0x3d, 0x27, 0x8c, 0xf0,
-	0x10, 0xfc, 0x55, 0xa0, 0xa4, 0xd9, 0x22, 0xf0, 0xbd, 0x48, 0x78, 0xde, 0xc0, 0x6b, 0xf6, 0x23,
-	0x89, 0x89, 0x4f, 0x41, 0x23, 0x62, 0x76, 0xeb, 0x8f, 0x19, 0xd8, 0xa6, 0x2c, 0x1a, 0x0d, 0x05,
-	0xd9, 0x81, 0x32, 0x67, 0xfd, 0x43, 0x16, 0x72, 0xd6, 0x73, 0x04, 0x73, 0x6b, 0x46, 0xdd, 0x68,
-	0x14, 0x8e, 0x37, 0xe8, 0x2c, 0x99, 0xfc, 0x1a, 0x2a, 0x9c, 0xf5, 0xa3, 0x04, 0xe3, 0x66, 0xdd,
-	0x68, 0x14, 0x5b, 0x1f, 0xda, 0x37, 0x06, 0xc3, 0xa6, 0xac, 0x7f, 0xe6, 0x84, 0x53, 0xc8, 0xf1,
-	0x06, 0x9d, 0x13, 0x42, 0x5a, 0x90, 0xe1, 0xac, 0x5f, 0xcb, 0x48, 0x59, 0x77, 0xd3, 0x65, 0x1d,
-	0x6f, 0x50, 0x64, 0x26, 0x7b, 0x90, 0x45, 0x29, 0xb5, 0xac, 0x04, 0xbd, 0xbf, 0x54, 0x81, 0xe3,
-	0x0d, 0x2a, 0x01, 0xe4, 0x4b, 0xc8, 0x5f, 0x32, 0xe1, 0xb8, 0x8e, 0x70, 0x6a, 0x50, 0xcf, 0x34,
-	0x8a, 0xad, 0x66, 0x2a, 0x18, 0x1d, 0x64, 0x9f, 0x29, 0x44, 0xc7, 0x17, 0x7c, 0x4c, 0x27, 0x02,
-	0xcc, 0x47, 0x50, 0x9e, 0xf9, 0x44, 0xaa, 0x90, 0x79, 0xc1, 0xc6, 0xb1, 0xff, 0x28, 0x2e, 0xc9,
-	0xdb, 0xb0, 0xf5, 0xd2, 0x19, 0x8e, 0x98, 0x74, 0x55, 0x89, 0xc6, 0x9b, 0xfd, 0xcd, 0x87, 0x46,
-	0x3b, 0x0f, 0xdb, 0x5c, 0x8a, 0xb7, 0xfe, 0x62, 0x40, 0x75, 0xde, 0x4f, 0xe4, 0x44, 0x59, 0x68,
-	0x48, 0x25, 0x3f, 0x5d, 0xc3, 0xc5, 0x48, 0x88, 0x62, 0x55, 0xa5, 0x08, 0x73, 0x0f, 0x0a, 0x13,
-	0xd2, 0x32, 0x15, 0x0b, 0x09, 0x15, 0xad, 0x3d, 0xc8, 0x50, 0xd6, 0x27, 0x15, 0xd8, 0xf4, 0x54,
-	0x52, 0xd0, 0x4d, 0xcf, 0x25, 0x75, 0xc8, 0xb8, 0xac, 0xaf, 0x82, 0x5f, 0xb1, 0xc3, 0x0b, 0xfb,
-	0x90, 0xf5, 0x3d, 0xdf, 0x13, 0x5e, 0xe0, 0x53, 0xfc, 0x64, 0xfd, 0xcd, 0xc0, 0xe4, 0x42, 0xb5,
-	0xc8, 0x17, 0x33, 0x76, 0x2c, 0x4f, 0x95, 0x6b, 0xda, 0x3f, 0x4b, 0xd7, 0xfe, 0x93, 0xa4, 0xf6,
-	0x4b, 0xf3, 0x27, 0x69, 0x9d, 0x80, 0x32, 0x65, 0x62, 0xc4, 0x7d, 0xca, 0x7e, 0x37, 0x62, 0x91,
-	0x20, 0x3f, 0xd7, 0x11, 0x91, 0xf2, 0x97, 0xa5, 0x15, 0x32, 0x52, 0x05, 0x20, 0x0d, 0xd8, 0x62,
-	0x9c, 0x07, 0x5c, 0x69, 0x41, 0xec, 0xb8, 0x72, 0xd8, 0x3c, 0xec, 0xd9, 0x4f, 0x65, 0xe5, 0xa0,
-	0x31, 0x83, 0x55, 0x85, 0x8a, 0x3e, 0x35, 0x0a, 0x03, 0x3f, 0x62, 0xd6, 0x2d, 0x28, 0x9f, 0xf8,
-	0xe1, 0x48, 0x44, 0x4a, 0x0f, 0xeb, 0x9f, 0x06, 0x54, 0x34, 0x25, 0xe6, 0x21, 0xdf, 0x40, 0x71,
-	0xea, 0x63, 0xed, 0xcc, 0xfd, 0x14, 0xfd, 0x66, 0xf1, 0x89, 0x00, 0x29, 0xdf, 0x26, 0xc5, 0x99,
-	0xe7, 0x50, 0x9d, 0x67, 0x58, 0xe0, 0xe9, 0x0f, 0x66, 0x3d, 0x3d, 0x1f, 0xf8, 0x84, 0x67, 0xff,
-	0x6c, 0xc0, 0x1d, 0xca, 0x64, 0x29, 0x3c, 0xb9, 0x74, 0x06, 0xec, 0x20, 0xf0, 0xfb, 0xde, 0x40,
-	0xbb, 0xb9, 0x2a, 0xb3, 0x4a, 0x4b, 0xc6, 0x04, 0x6b, 0x40, 0xbe, 0x3b, 0x74, 0x44, 0x3f, 0xe0,
-	0x97, 0x4a, 0x78, 0x09, 0x85, 0x6b, 0x1a, 0x9d, 0x7c, 0x25, 0x75, 0x28, 0x2a, 0xc1, 0x67, 0x81,
-	0xcb, 0x64, 0xcd, 0x28, 0xd0, 0x24, 0x89, 0xd4, 0x20, 0x77, 0x1a, 0x0c, 0xce, 0x9d, 0x4b, 0x26,
-	0x8b, 0x43, 0x81, 0xea, 0xad, 0xf5, 0x7b, 0x03, 0xcc, 0x45, 0x5a, 0x29, 0x17, 0xff, 0x12, 0xb6,
-	0x0f, 0xbd, 0x01, 0x8b, 0xe2, 0xe8, 0x17, 0xda, 0xad, 0xef, 0x5f, 0xbf, 0xb7, 0xf1, 0xef, 0xd7,
-	0xef, 0xdd, 0x4f, 0xd4, 0xd5, 0x20, 0x64, 0x7e, 0x2f, 0xf0, 0x85, 0xe3, 0xf9, 0x8c, 0x63, 0x7b,
-	0xf8, 0xc8, 0x95, 0x10, 0x3b, 0x46, 0x52, 0x25, 0x81, 0xbc, 0x03, 0xdb, 0xb1, 0x74, 0x75, 0xed,
-	0xd5, 0xce, 0xfa, 0xef, 0x16, 0x94, 0x9e, 0xa2, 0x02, 0xda, 0x17, 0x36, 0xc0, 0xd4, 0x85, 0x2a,
-	0xed, 0xe6, 0x1d, 0x9b, 0xe0, 0x20, 0x26, 0xe4, 0x8f, 0x54, 0x88, 0xd5, 0x75, 0x9d, 0xec, 0xc9,
-	0xd7, 0x50, 0xd4, 0xeb, 0x27, 0xa1, 0xa8, 0x65, 0x64, 0x8e, 0x3c, 0x4c, 0xc9, 0x91, 0xa4, 0x26,
-	0x76, 0x02, 0xaa, 0x32, 0x24, 0x41, 0x21, 0x9f, 0xc1, 0x9d, 0x93, 0xcb, 0x30, 0xe0, 0xe2, 0xc0,
-	0xe9, 0x3d, 0x67, 0x74, 0xb6, 0x0b, 0x64, 0xeb, 0x99, 0x46, 0x81, 0xde, 0xcc, 0x40, 0x76, 0xe1,
-	0x2d, 0x67, 0x38, 0x0c, 0xae, 0xd4, 0xa5, 0x91, 0xe9, 0x5f, 0xdb, 0xaa, 0x1b, 0x8d, 0x3c, 0xbd,
-	0xfe, 0x81, 0x7c, 0x0c, 0xb7, 0x13, 0xc4, 0xc7, 0x9c, 0x3b, 0x63, 0xcc, 0x97, 0x6d, 0xc9, 0xbf,
-	0xe8, 0x13, 0x56, 0xb0, 0x23, 0xcf, 0x77, 0x86, 0x35, 0x90, 0x3c, 0xf1, 0x86, 0x58, 0x50, 0xea,
-	0xbc, 0x42, 0x95, 0x18, 0x7f, 0x2c, 0x04, 0xaf, 0x15, 0x65, 0x28, 0x66, 0x68, 0xa4, 0x0b, 0x25,
-	0xa9, 0x70, 0xac, 0x7b, 0x54, 0x2b, 0x49, 0xa7, 0xed, 0xa6, 0x38, 0x4d, 0xb2, 0x3f, 0x09, 0x13,
-	0x57, 0x69, 0x46, 0x02, 0xe9, 0x41, 0x45, 0x3b, 0x2e, 0xbe, 0x83, 0xb5, 0xb2, 0x94, 0xf9, 0x68,
-	0xdd, 0x40, 0xc4, 0xe8, 0xf8, 0x88, 0x39, 0x91, 0x98, 0x06, 0x1d, 0xbc, 0x6e, 0x8e, 0x60, 0xb5,
-	0x8a, 0xb4, 0x79, 0xb2, 0x37, 0x3f, 0x87, 0xea, 0x7c, 0x2c, 0xd7, 0x29, 0xfa, 0xe6, 0xaf, 0xe0,
-	0xf6, 0x02, 0x15, 0x7e, 0x50, 0x3d, 0xf8, 0xbb, 0x01, 0x6f, 0x5d, 0xf3, 0x1b, 0x21, 0x90, 0xfd,
-	0x6a, 0x1c, 0x32, 0x25, 0x52, 0xae, 0xc9, 0x19, 0x6c, 0x61, 0x5c, 0xa2, 0xda, 0xa6, 0x74, 0xda,
-	0xde, 0x3a, 0x81, 0xb0, 0x25, 0x32, 0x76, 0x58, 0x2c, 0xc5, 0x7c, 0x08, 0x30, 0x25, 0xae, 0xd5,
-	0xfa, 0xbe, 0x81, 0xb2, 0x8a, 0x8a, 0x2a, 0x0f, 0xd5, 0x78, 0x4a, 0x51, 0x60, 0x9c, 0x41, 0xa6,
-	0xed, 0x22, 0xb3, 0x66, 0xbb, 0xb0, 0xbe, 0x83, 0x5b, 0x94, 0x39, 0xee, 0x91, 0x37, 0x64, 0x37,
-	0x57, 0x45, 0xbc, 0xeb, 0xde, 0x90, 0x75, 0x1d, 0xf1, 0x7c, 0x72, 0xd7, 0xd5, 0x9e, 0xec, 0xc3,
-	0x16, 0x75, 0xfc, 0x01, 0x53, 0x47, 0x7f, 0x90, 0x72, 0xb4, 0x3c, 0x04, 0x79, 0x69, 0x0c, 0xb1,
-	0x1e, 0x41, 0x61, 0x42, 0xc3, 0x4a, 0xf5, 0xa4, 0xdf, 0x8f, 0x58, 0x5c, 0xf5, 0x32, 0x54, 0xed,
-	0x90, 0x7e, 0xca, 0xfc, 0x81, 0x3a, 0x3a, 0x43, 0xd5, 0xce, 0xda, 0xc1, 0x51, 0x45, 0x6b, 0xae,
-	0x5c, 0x43, 0x20, 0x7b, 0x88, 0xf3, 0x94, 0x21, 0x2f, 0x98, 0x5c, 0x5b, 0x2e, 0xb6, 0x39, 0xc7,
-	0x3d, 0xf4, 0xf8, 0xcd, 0x06, 0xd6, 0x20, 0x77, 0xe8, 0xf1, 0x84, 0x7d, 0x7a, 0x4b, 0x76, 0xb0,
-	0x01, 0xf6, 0x86, 0x23, 0x17, 0xad, 0x15, 0x8c, 0xfb, 0xaa, 0xd2, 0xcf, 0x51, 0xad, 0x2f, 0x62,
-	0x3f, 0xca, 0x53, 0x94, 0x32, 0xbb, 0x90, 0x63, 0xbe, 0xe0, 0x1e, 0xd3, 0x5d, 0x92, 0xd8, 0xf1,
-	0x08, 0x6c, 0xcb, 0x11, 0x58, 0x76, 0x63, 0xaa, 0x59, 0xac, 0x3d, 0xb8, 0x85, 0x84, 0xf4, 0x40,
-	0x10, 0xc8, 0x26, 0x94, 0x94, 0x6b, 0x6b, 0x1f, 0xaa, 0x53, 0xa0, 0x3a, 0x7a, 0x07, 0xb2, 0x38,
-	0x60, 0xab, 0x32, 0xbe, 0xe8, 0x5c, 0xf9, 0xdd, 0x2a, 0x43, 0xb1, 0xeb, 0xf9, 0xba, 0x1f, 0x5a,
-	0x6f, 0x0c, 0x28, 0x75, 0x03, 0x7f, 0xda, 0x89, 0xba, 0x70, 0x4b, 0xdf, 0xc0, 0xc7, 0xdd, 0x93,
-	0x03, 0x27, 0xd4, 0xa6, 0xd4, 0xaf, 0x87, 0x59, 0xbd, 0x05, 0xec, 0x98, 0xb1, 0x9d, 0xc5, 0xa6,
-	0x45, 0xe7, 0xe1, 0xe4, 0x17, 0x90, 0x3b, 0x3d, 0x6d, 0x4b, 0x49, 0x9b, 0x6b, 0x49, 0xd2, 0x30,
-	0xf2, 0x39, 0xe4, 0x9e, 0xc9, 0x27, 0x4a, 0xa4, 0x1a, 0xcb, 0x82, 0x94, 0x8b, 0x0d, 0x8d, 0xd9,
-	0x28, 0xeb, 0x05, 0xdc, 0xa5, 0x1a, 0x64, 0xfd, 0x69, 0x13, 0x6e, 0x9f, 0xb3, 0xab, 0x03, 0xdd,
-	0x3c, 0xb5, 0xb7, 0xeb, 0x50, 0x9c, 0xd0, 0x4e, 0x0e, 0x95, 0xd7, 0x93, 0x24, 0xf2, 0x3e, 0x6c,
-	0x9f, 0x05, 0x23, 0x5f, 0x68, 0xd5, 0x0b, 0x58, 0x67, 0x24, 0x85, 0xaa, 0x0f, 0xe4, 0x27, 0x90,
-	0x3b, 0x67, 0x02, 0x9f, 0x50, 0x32, 0x4f, 0x2a, 0xad, 0x22, 0xf2, 0x9c, 0x33, 0x81, 0x13, 0x01,
-	0xd5, 0xdf, 0x70, 0xcc, 0x08, 0xf5, 0x98, 0x91, 0x5d, 0x34, 0x66, 0xe8, 0xaf, 0x64, 0x0f, 0x8a,
-	0xbd, 0xc0, 0x8f, 0x04, 0x77, 0x3c, 0x3c, 0x78, 0x4b, 0x32, 0xff, 0x08, 0x99, 0x63, 0x7b, 0x0e,
-	0xa6, 0x1f, 0x69, 0x92, 0x93, 0xdc, 0x07, 0x60, 0xaf, 0x04, 0x77, 0x8e, 0x83, 0x48, 0x44, 0xb5,
-	0x6d, 0xa9, 0x30, 0x20, 0x0e, 0x09, 0x27, 0x5d, 0x9a, 0xf8, 0x6a, 0xbd, 0x03, 0x6f, 0xcf, 0x7a,
-	0x44, 0xcd, 0x83, 0x8f, 0xe0, 0xc7, 0x94, 0x0d, 0x99, 0x13, 0xb1, 0xf5, 0xbd, 0x65, 0x99, 0x50,
-	0xbb, 0x0e, 0x56, 0x82, 0xff, 0x91, 0x81, 0x62, 0xe7, 0x15, 0xeb, 0x9d, 0xb1, 0x28, 0x72, 0x06,
-	0x8c, 0xbc, 0x0b, 0x85, 0x2e, 0x0f, 0x7a, 0x2c, 0x8a, 0x26, 0xb2, 0xa6, 0x04, 0xf2, 0x19, 0x64,
-	0x4f, 0x7c, 0x4f, 0xa8, 0xea, 0xbe, 0x93, 0x3a, 0x6b, 0x7a, 0x42, 0xc9, 0xc4, 0x77, 0x16, 0x6e,
-	0xc9, 0x3e, 0x64, 0xf1, 0x6e, 0xac, 0x52, 0x9f, 0xdc, 0x04, 0x16, 0x31, 0xa4, 0x2d, 0x5f, 0xa6,
-	0xde, 0xb7, 0x4c, 0x45, 0xa9, 0x91, 0x5e, 0x58, 0xbd, 0x6f, 0xd9, 0x54, 0x82, 0x42, 0x92, 0x0e,
-	0xe4, 0x9e, 0x0a, 0x87, 0xe3, 0x78, 0x12, 0x47, 0xef, 0x5e, 0x5a, 0xff, 0x8d, 0x39, 0xa7, 0x52,
-	0x34, 0x16, 0x9d, 0xd0, 0x79, 0xe5, 0x09, 0x39, 0x7c, 0xa4, 0x3b, 0x01, 0xd9, 0x12, 0x86, 0xe0,
-	0x16, 0xd1, 0x87, 0x81, 0xcf, 0x6a, 0xb9, 0xa5, 0x68, 0x64, 0x4b, 0xa0, 0x71, 0xdb, 0xce, 0xc1,
-	0x96, 0x6c, 0xc0, 0xd6, 0x5f, 0x0d, 0x28, 0x26, 0x7c, 0xbc, 0xc2, 0x9d, 0x79, 0x17, 0xb2, 0xf8,
-	0x30, 0x55, 0xb1, 0xcb, 0xcb, 0x1b, 0xc3, 0x84, 0x43, 0x25, 0x15, 0x2b, 0xdc, 0x91, 0x1b, 0xdf,
-	0xe3, 0x32, 0xc5, 0x25, 0x52, 0xbe, 0x12, 0x63, 0xe9, 0xee, 0x3c, 0xc5, 0x25, 0xd9, 0x85, 0xfc,
-	0x53, 0xd6, 0x1b, 0x71, 0x4f, 0x8c, 0xa5, 0x03, 0x2b, 0xad, 0x2a, 0x4a, 0xd1, 0x34, 0x79, 0xb1,
-	0x26, 0x1c, 0xd6, 0x97, 0x98, 0x58, 0x53, 0x05, 0x09, 0x64, 0x0f, 0x70, 0x3c, 0x47, 0xcd, 0xca,
-	0x54, 0xae, 0xf1, 0x85, 0xd4, 0x59, 0xf6, 0x42, 0xea, 0xe8, 0x17, 0xd2, 0x6c, 0x40, 0xb0, 0x60,
-	0x26, 0x1c, 0x64, 0x3d, 0x86, 0xc2, 0x24, 0x69, 0xf0, 0x71, 0x7a, 0xe4, 0xaa, 0x93, 0x36, 0x8f,
-	0x5c, 0x34, 0xa5, 0xf3, 0xe4, 0x48, 0x9e, 0x92, 0xa7, 0xb8, 0x9c, 0xb4, 0xa7, 0x4c, 0xa2, 0x3d,
-	0xed, 0xe1, 0xdb, 0x2f, 0x91, 0x39, 0xc8, 0x44, 0x83, 0xab, 0x48, 0xab, 0x8c, 0xeb, 0xd8, 0x8c,
-	0x61, 0x24, 0x65, 0x49, 0x33, 0x86, 0x51, 0xeb, 0x7f, 0x79, 0x28, 0x9c, 0x9e, 0xb6, 0xdb, 0xdc,
-	0x73, 0x07, 0x8c, 0xfc, 0xc1, 0x00, 0x72, 0xfd, 0x49, 0x41, 0x3e, 0x49, 0x4f, 0xd8, 0xc5, 0xef,
-	0x22, 0xf3, 0xd3, 0x35, 0x51, 0xaa, 0x5b, 0x7c, 0x0d, 0x5b, 0x72, 0x52, 0x21, 0x3f, 0x5d, 0x71,
-	0xc2, 0x34, 0x1b, 0xcb, 0x19, 0x95, 0xec, 0x1e, 0xe4, 0x75, 0xb7, 0x27, 0xf7, 0x53, 0xd5, 0x9b,
-	0x19, 0x66, 0xcc, 0x0f, 0x57, 0xe2, 0x55, 0x87, 0xfc, 0x16, 0x72, 0xaa, 0x89, 0x93, 0x7b, 0x4b,
-	0x70, 0xd3, 0x71, 0xc2, 0xbc, 0xbf, 0x0a, 0xeb, 0xd4, 0x0c, 0xdd, 0xac, 0x53, 0xcd, 0x98, 0x1b,
-	0x05, 0x52, 0xcd, 0xb8, 0xd6, 0xfd, 0x9f, 0x41, 0x16, 0xbb, 0x3a, 0x49, 0xbb, 0xe6, 0x89, 0xb6,
-	0x6f, 0xa6, 0x85, 0x6b, 0x66, 0x1c, 0xf8, 0x0d, 0x96, 0x43, 0xf9, 0x32, 0x4a, 0x2f, 0x84, 0x89,
-	0xbf, 0x32, 0xcc, 0x7b, 0x2b, 0x70, 0x4e, 0xc5, 0xab, 0x57, 0x45, 0x63, 0x85, 0xff, 0x13, 0x96,
-	0x8b, 0x9f, 0xfb, 0xe7, 0x22, 0x80, 0x52, 0xb2, 0xcb, 0x11, 0x3b, 0x05, 0xba, 0x60, 0x40, 0x30,
-	0x9b, 0x2b, 0xf3, 0xab, 0x03, 0xbf, 0xc3, 0x09, 0x75, 0xb6, 0x03, 0x92, 0x56, 0xaa, 0x3b, 0x16,
-	0xf6, 0x5a, 0xf3, 0xc1, 0x5a, 0x18, 0x75, 0xb8, 0x13, 0x77, 0x58, 0xd5, 0x45, 0x49, 0x7a, 0xc3,
-	0x98, 0x74, 0x62, 0x73, 0x45, 0xbe, 0x86, 0xf1, 0xb1, 0xd1, 0x2e, 0x7d, 0xff, 0xe6, 0xae, 0xf1,
-	0xaf, 0x37, 0x77, 0x8d, 0xff, 0xbc, 0xb9, 0x6b, 0x5c, 0x6c, 0xcb, 0x7f, 0x73, 0x1f, 0xfc, 0x3f,
-	0x00, 0x00, 0xff, 0xff, 0xf5, 0x6a, 0xee, 0x8e, 0x1f, 0x17, 0x00, 0x00,
+	// 2042 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0xf7, 0x8a, 0x14, 0x3f, 0x1e, 0x3f, 0xc4, 0x8c, 0xd3, 0x74, 0xbd, 0x08, 0x1c, 0x65, 0x91,
+	0x2a, 0xb4, 0xad, 0x2c, 0x53, 0x39, 0x81, 0x5c, 0x39, 0x48, 0x6a, 0x7d, 0x41, 0x4a, 0x24, 0x59,
+	0x1d, 0xa5, 0x30, 0x10, 0xa4, 0x40, 0x57, 0xdc, 0x21, 0xbd, 0xf0, 0x6a, 0x77, 0x3b, 0x3b, 0xb4,
+	0xac, 0xe4, 0xd2, 0xde, 0x7a, 0x2c, 0x50, 0xa0, 0xd7, 0x02, 0xfd, 0x0b, 0x7a, 0xe9, 0xb5, 0xe7,
+	0x1c, 0x7b, 0x2c, 0x7a, 0x08, 0x0a, 0xff, 0x0d, 0x45, 0xcf, 0xc1, 0x9b, 0x99, 0x25, 0x97, 0x14,
+	0xb5, 0x24, 0x91, 0x13, 0xe7, 0xbd, 0x7d, 0xbf, 0x37, 0xef, 0x63, 0xe6, 0xbd, 0x37, 0x84, 0x46,
+	0xdf, 0x15, 0xec, 0xd2, 0xbd, 0x72, 0x62, 0x1e, 0x89, 0x88, 0xdc, 0xb9, 0x88, 0xce, 0xaf, 0x9c,
+	0xf3, 0x81, 0x1f, 0x78, 0x2f, 0x7c, 0xe1, 0xbc, 0xfc, 0xb9, 0xd3, 0xe3, 0x51, 0x28, 0x58, 0xe8,
+	0x59, 0x1f, 0xf4, 0x7d, 0xf1, 0x7c, 0x70, 0xee, 0x74, 0xa3, 0x8b, 0x4e, 0x3f, 0xea, 0x47, 0x1d,
+	0x89, 0x38, 0x1f, 0xf4, 0x24, 0x25, 0x09, 0xb9, 0x52, 0x9a, 0xac, 0x8d, 0x49, 0xf1, 0x7e, 0x14,
+	0xf5, 0x03, 0xe6, 0xc6, 0x7e, 0xa2, 0x97, 0x1d, 0x1e, 0x77, 0x3b, 0x89, 0x70, 0xc5, 0x20, 0xd1,
+	0x98, 0xf5, 0x0c, 0x06, 0x0d, 0xe9, 0xa4, 0x86, 0x74, 0x92, 0x28, 0x78, 0xc9, 0x78, 0x27, 0x3e,
+	0xef, 0x44, 0x71, 0x2a, 0xdd, 0xb9, 0x51, 0xda, 0x8d, 0xfd, 0x8e, 0xb8, 0x8a, 0x59, 0xd2, 0xb9,
+	0x8c, 0xf8, 0x0b, 0xc6, 0x35, 0xe0, 0xe1, 0x8d, 0x80, 0x81, 0xf0, 0x03, 0x44, 0x75, 0xdd, 0x38,
+	0xc1, 0x4d, 0xf0, 0x57, 0x83, 0xb2, 0x6e, 0x8b, 0x28, 0xf4, 0x13, 0xe1, 0xfb, 0x7d, 0xbf, 0xd3,
+	0x4b, 0x24, 0x46, 0xed, 0x82, 0x4e, 0x28, 0x71, 0xfb, 0x8f, 0x05, 0x28, 0x51, 0x96, 0x0c, 0x02,
+	0x41, 0xd6, 0xa0, 0xc1, 0x59, 0x6f, 0x97, 0xc5, 0x9c, 0x75, 0x5d, 0xc1, 0x3c, 0xd3, 0x58, 0x35,
+	0xda, 0xd5, 0x83, 0x5b, 0x74, 0x9c, 0x4d, 0x7e, 0x0d, 0x4d, 0xce, 0x7a, 0x49, 0x46, 0x70, 0x69,
+	0xd5, 0x68, 0xd7, 0x36, 0x1e, 0x38, 0x37, 0x26, 0xc3, 0xa1, 0xac, 0x77, 0xec, 0xc6, 0x23, 0xc8,
+	0xc1, 0x2d, 0x3a, 0xa1, 0x84, 0x6c, 0x40, 0x81, 0xb3, 0x9e, 0x59, 0x90, 0xba, 0xee, 0xe6, 0xeb,
+	0x3a, 0xb8, 0x45, 0x51, 0x98, 0x6c, 0x42, 0x11, 0xb5, 0x98, 0x45, 0x09, 0x7a, 0x77, 0xa6, 0x01,
+	0x07, 0xb7, 0xa8, 0x04, 0x90, 0x2f, 0xa0, 0x72, 0xc1, 0x84, 0xeb, 0xb9, 0xc2, 0x35, 0x61, 0xb5,
+	0xd0, 0xae, 0x6d, 0x74, 0x72, 0xc1, 0x18, 0x20, 0xe7, 0x58, 0x23, 0xf6, 0x42, 0xc1, 0xaf, 0xe8,
+	0x50, 0x81, 0xf5, 0x18, 0x1a, 0x63, 0x9f, 0x48, 0x0b, 0x0a, 0x2f, 0xd8, 0x95, 0x8a, 0x1f, 0xc5,
+	0x25, 0x79, 0x13, 0x96, 0x5f, 0xba, 0xc1, 0x80, 0xc9, 0x50, 0xd5, 0xa9, 0x22, 0xb6, 0x96, 0x1e,
+	0x19, 0xdb, 0x15, 0x28, 0x71, 0xa9, 0xde, 0xfe, 0x8b, 0x01, 0xad, 0xc9, 0x38, 0x91, 0x43, 0xed,
+	0xa1, 0x21, 0x8d, 0xfc, 0x78, 0x81, 0x10, 0x23, 0x23, 0x51, 0xa6, 0x4a, 0x15, 0xd6, 0x26, 0x54,
+	0x87, 0xac, 0x59, 0x26, 0x56, 0x33, 0x26, 0xda, 0x9b, 0x50, 0xa0, 0xac, 0x47, 0x9a, 0xb0, 0xe4,
+	0xeb, 0x43, 0x41, 0x97, 0x7c, 0x8f, 0xac, 0x42, 0xc1, 0x63, 0x3d, 0x9d, 0xfc, 0xa6, 0x13, 0x9f,
+	0x3b, 0xbb, 0xac, 0xe7, 0x87, 0xbe, 0xf0, 0xa3, 0x90, 0xe2, 0x27, 0xfb, 0x6f, 0x06, 0x94, 0x94,
+	0x59, 0xe4, 0xb3, 0x31, 0x3f, 0x66, 0x1f, 0x95, 0x6b, 0xd6, 0x3f, 0xcb, 0xb7, 0xfe, 0xa3, 0xac,
+	0xf5, 0x33, 0xcf, 0x4f, 0xd6, 0x3b, 0x01, 0x0d, 0xca, 0xc4, 0x80, 0x87, 0x94, 0xfd, 0x6e, 0xc0,
+	0x12, 0x41, 0x7e, 0x91, 0x66, 0xc4, 0x34, 0xe6, 0x38, 0x56, 0x28, 0x48, 0x35, 0x80, 0xb4, 0x61,
+	0x99, 0x71, 0x1e, 0x71, 0x6d, 0x05, 0x71, 0x54, 0xe5, 0x70, 0x78, 0xdc, 0x75, 0xce, 0x64, 0xe5,
+	0xa0, 0x4a, 0xc0, 0x6e, 0x41, 0x33, 0xdd, 0x35, 0x89, 0xa3, 0x30, 0x61, 0xf6, 0x0a, 0x34, 0x0e,
+	0xc3, 0x78, 0x20, 0x12, 0x6d, 0x87, 0xfd, 0x4f, 0x03, 0x9a, 0x29, 0x47, 0xc9, 0x90, 0xaf, 0xa1,
+	0x36, 0x8a, 0x71, 0x1a, 0xcc, 0xad, 0x1c, 0xfb, 0xc6, 0xf1, 0x99, 0x04, 0xe9, 0xd8, 0x66, 0xd5,
+	0x59, 0x27, 0xd0, 0x9a, 0x14, 0x98, 0x12, 0xe9, 0xf7, 0xc6, 0x23, 0x3d, 0x99, 0xf8, 0x4c, 0x64,
+	0xff, 0x6c, 0xc0, 0x1d, 0xca, 0x64, 0x29, 0x3c, 0xbc, 0x70, 0xfb, 0x6c, 0x27, 0x0a, 0x7b, 0x7e,
+	0x3f, 0x0d, 0x73, 0x4b, 0x9e, 0xaa, 0x54, 0x33, 0x1e, 0xb0, 0x36, 0x54, 0x4e, 0x03, 0x57, 0xf4,
+	0x22, 0x7e, 0xa1, 0x95, 0xd7, 0x51, 0x79, 0xca, 0xa3, 0xc3, 0xaf, 0x64, 0x15, 0x6a, 0x5a, 0xf1,
+	0x71, 0xe4, 0x31, 0x59, 0x33, 0xaa, 0x34, 0xcb, 0x22, 0x26, 0x94, 0x8f, 0xa2, 0xfe, 0x89, 0x7b,
+	0xc1, 0x64, 0x71, 0xa8, 0xd2, 0x94, 0xb4, 0x7f, 0x6f, 0x80, 0x35, 0xcd, 0x2a, 0x1d, 0xe2, 0xcf,
+	0xa1, 0xb4, 0xeb, 0xf7, 0x59, 0xa2, 0xb2, 0x5f, 0xdd, 0xde, 0xf8, 0xee, 0xfb, 0x77, 0x6e, 0xfd,
+	0xe7, 0xfb, 0x77, 0xee, 0x67, 0xea, 0x6a, 0x14, 0xb3, 0xb0, 0x1b, 0x85, 0xc2, 0xf5, 0x43, 0xc6,
+	0xb1, 0x3d, 0x7c, 0xe0, 0x49, 0x88, 0xa3, 0x90, 0x54, 0x6b, 0x20, 0x6f, 0x41, 0x49, 0x69, 0xd7,
+	0xd7, 0x5e, 0x53, 0xf6, 0xff, 0x96, 0xa1, 0x7e, 0x86, 0x06, 0xa4, 0xb1, 0x70, 0x00, 0x46, 0x21,
+	0x34, 0x8d, 0xa9, 0x81, 0xcd, 0x48, 0x10, 0x0b, 0x2a, 0xfb, 0x3a, 0xc5, 0xfa, 0xba, 0x0e, 0x69,
+	0xf2, 0x15, 0xd4, 0xd2, 0xf5, 0xd3, 0x58, 0x98, 0x05, 0x79, 0x46, 0x1e, 0xe5, 0x9c, 0x91, 0xac,
+	0x25, 0x4e, 0x06, 0xaa, 0x4f, 0x48, 0x86, 0x43, 0x3e, 0x81, 0x3b, 0x87, 0x17, 0x71, 0xc4, 0xc5,
+	0x8e, 0xdb, 0x7d, 0xce, 0xe8, 0x78, 0x17, 0x28, 0xae, 0x16, 0xda, 0x55, 0x7a, 0xb3, 0x00, 0x59,
+	0x87, 0x37, 0xdc, 0x20, 0x88, 0x2e, 0xf5, 0xa5, 0x91, 0xc7, 0xdf, 0x5c, 0x5e, 0x35, 0xda, 0x15,
+	0x7a, 0xfd, 0x03, 0xf9, 0x10, 0x6e, 0x67, 0x98, 0x4f, 0x38, 0x77, 0xaf, 0xf0, 0xbc, 0x94, 0xa4,
+	0xfc, 0xb4, 0x4f, 0x58, 0xc1, 0xf6, 0xfd, 0xd0, 0x0d, 0x4c, 0x90, 0x32, 0x8a, 0x20, 0x36, 0xd4,
+	0xf7, 0x5e, 0xa1, 0x49, 0x8c, 0x3f, 0x11, 0x82, 0x9b, 0x35, 0x99, 0x8a, 0x31, 0x1e, 0x39, 0x85,
+	0xba, 0x34, 0x58, 0xd9, 0x9e, 0x98, 0x75, 0x19, 0xb4, 0xf5, 0x9c, 0xa0, 0x49, 0xf1, 0xa7, 0x71,
+	0xe6, 0x2a, 0x8d, 0x69, 0x20, 0x5d, 0x68, 0xa6, 0x81, 0x53, 0x77, 0xd0, 0x6c, 0x48, 0x9d, 0x8f,
+	0x17, 0x4d, 0x84, 0x42, 0xab, 0x2d, 0x26, 0x54, 0xe2, 0x31, 0xd8, 0xc3, 0xeb, 0xe6, 0x0a, 0x66,
+	0x36, 0xa5, 0xcf, 0x43, 0xda, 0xfa, 0x14, 0x5a, 0x93, 0xb9, 0x5c, 0xa4, 0xe8, 0x5b, 0xbf, 0x82,
+	0xdb, 0x53, 0x4c, 0xf8, 0x51, 0xf5, 0xe0, 0xef, 0x06, 0xbc, 0x71, 0x2d, 0x6e, 0x84, 0x40, 0xf1,
+	0xcb, 0xab, 0x98, 0x69, 0x95, 0x72, 0x4d, 0x8e, 0x61, 0x19, 0xf3, 0x92, 0x98, 0x4b, 0x32, 0x68,
+	0x9b, 0x8b, 0x24, 0xc2, 0x91, 0x48, 0xb9, 0xa4, 0x4a, 0x8b, 0xf5, 0x08, 0x60, 0xc4, 0x5c, 0xa8,
+	0xf5, 0x7d, 0x0d, 0x0d, 0x9d, 0x15, 0x5d, 0x1e, 0x5a, 0x6a, 0x4a, 0xd1, 0x60, 0x9c, 0x41, 0x46,
+	0xed, 0xa2, 0xb0, 0x60, 0xbb, 0xb0, 0xbf, 0x85, 0x15, 0xca, 0x5c, 0x6f, 0xdf, 0x0f, 0xd8, 0xcd,
+	0x55, 0x11, 0xef, 0xba, 0x1f, 0xb0, 0x53, 0x57, 0x3c, 0x1f, 0xde, 0x75, 0x4d, 0x93, 0x2d, 0x58,
+	0xa6, 0x6e, 0xd8, 0x67, 0x7a, 0xeb, 0xf7, 0x72, 0xb6, 0x96, 0x9b, 0xa0, 0x2c, 0x55, 0x10, 0xfb,
+	0x31, 0x54, 0x87, 0x3c, 0xac, 0x54, 0x4f, 0x7b, 0xbd, 0x84, 0xa9, 0xaa, 0x57, 0xa0, 0x9a, 0x42,
+	0xfe, 0x11, 0x0b, 0xfb, 0x7a, 0xeb, 0x02, 0xd5, 0x94, 0xbd, 0x06, 0xad, 0x91, 0xe5, 0x3a, 0x34,
+	0x04, 0x8a, 0xbb, 0x38, 0x4f, 0x19, 0xf2, 0x82, 0xc9, 0xb5, 0xed, 0x61, 0x9b, 0x73, 0xbd, 0x5d,
+	0x9f, 0xdf, 0xec, 0xa0, 0x09, 0xe5, 0x5d, 0x9f, 0x67, 0xfc, 0x4b, 0x49, 0xb2, 0x86, 0x0d, 0xb0,
+	0x1b, 0x0c, 0x3c, 0xf4, 0x56, 0x30, 0x1e, 0xea, 0x4a, 0x3f, 0xc1, 0xb5, 0x3f, 0x83, 0x95, 0xe1,
+	0x2e, 0xda, 0x98, 0x75, 0x28, 0xb3, 0x50, 0x70, 0x9f, 0xa5, 0x5d, 0x92, 0x38, 0x6a, 0x04, 0x76,
+	0xe4, 0x08, 0x2c, 0xbb, 0x31, 0x4d, 0x45, 0xec, 0x4d, 0x58, 0x41, 0x46, 0x7e, 0x22, 0x08, 0x14,
+	0x33, 0x46, 0xca, 0xb5, 0xbd, 0x05, 0xad, 0x11, 0x50, 0x6f, 0xbd, 0x06, 0x45, 0x1c, 0xb0, 0x75,
+	0x19, 0x9f, 0xb6, 0xaf, 0xfc, 0x6e, 0x37, 0xa0, 0x76, 0xea, 0x87, 0x69, 0x3f, 0xb4, 0x5f, 0x1b,
+	0x50, 0x3f, 0x8d, 0xc2, 0x51, 0x27, 0x3a, 0x85, 0x95, 0xf4, 0x06, 0x3e, 0x39, 0x3d, 0xdc, 0x71,
+	0xe3, 0xd4, 0x95, 0xd5, 0xeb, 0x69, 0xd6, 0x6f, 0x01, 0x47, 0x09, 0x6e, 0x17, 0xb1, 0x69, 0xd1,
+	0x49, 0x38, 0xf9, 0x25, 0x94, 0x8f, 0x8e, 0xb6, 0xa5, 0xa6, 0xa5, 0x85, 0x34, 0xa5, 0x30, 0xf2,
+	0x29, 0x94, 0x9f, 0xc9, 0x27, 0x4a, 0xa2, 0x1b, 0xcb, 0x94, 0x23, 0xa7, 0x1c, 0x55, 0x62, 0x94,
+	0x75, 0x23, 0xee, 0xd1, 0x14, 0x64, 0xff, 0x69, 0x09, 0x6e, 0x9f, 0xb0, 0xcb, 0x9d, 0xb4, 0x79,
+	0xa6, 0xd1, 0x5e, 0x85, 0xda, 0x90, 0x77, 0xb8, 0xab, 0xa3, 0x9e, 0x65, 0x91, 0x77, 0xa1, 0x74,
+	0x1c, 0x0d, 0x42, 0x91, 0x9a, 0x5e, 0xc5, 0x3a, 0x23, 0x39, 0x54, 0x7f, 0x20, 0x3f, 0x83, 0xf2,
+	0x09, 0x13, 0xf8, 0x84, 0x92, 0xe7, 0xa4, 0xb9, 0x51, 0x43, 0x99, 0x13, 0x26, 0x70, 0x22, 0xa0,
+	0xe9, 0x37, 0x1c, 0x33, 0xe2, 0x74, 0xcc, 0x28, 0x4e, 0x1b, 0x33, 0xd2, 0xaf, 0x64, 0x13, 0x6a,
+	0xdd, 0x28, 0x4c, 0x04, 0x77, 0x7d, 0xdc, 0x78, 0x59, 0x0a, 0xff, 0x04, 0x85, 0x95, 0x3f, 0x3b,
+	0xa3, 0x8f, 0x34, 0x2b, 0x49, 0xee, 0x03, 0xb0, 0x57, 0x82, 0xbb, 0x07, 0x51, 0x22, 0x12, 0xb3,
+	0x24, 0x0d, 0x06, 0xc4, 0x21, 0xe3, 0xf0, 0x94, 0x66, 0xbe, 0xda, 0x6f, 0xc1, 0x9b, 0xe3, 0x11,
+	0xd1, 0xf3, 0xe0, 0x63, 0xf8, 0x29, 0x65, 0x01, 0x73, 0x13, 0xb6, 0x78, 0xb4, 0x6c, 0x0b, 0xcc,
+	0xeb, 0x60, 0xad, 0xf8, 0x1f, 0x05, 0xa8, 0xed, 0xbd, 0x62, 0xdd, 0x63, 0x96, 0x24, 0x6e, 0x9f,
+	0x91, 0xb7, 0xa1, 0x7a, 0xca, 0xa3, 0x2e, 0x4b, 0x92, 0xa1, 0xae, 0x11, 0x83, 0x7c, 0x02, 0xc5,
+	0xc3, 0xd0, 0x17, 0xba, 0xba, 0xaf, 0xe5, 0xce, 0x9a, 0xbe, 0xd0, 0x3a, 0xf1, 0x9d, 0x85, 0x24,
+	0xd9, 0x82, 0x22, 0xde, 0x8d, 0x79, 0xea, 0x93, 0x97, 0xc1, 0x22, 0x86, 0x6c, 0xcb, 0x97, 0xa9,
+	0xff, 0x0d, 0xd3, 0x59, 0x6a, 0xe7, 0x17, 0x56, 0xff, 0x1b, 0x36, 0xd2, 0xa0, 0x91, 0x64, 0x0f,
+	0xca, 0x67, 0xc2, 0xe5, 0x38, 0x9e, 0xa8, 0xec, 0xdd, 0xcb, 0xeb, 0xbf, 0x4a, 0x72, 0xa4, 0x25,
+	0xc5, 0x62, 0x10, 0xf6, 0x5e, 0xf9, 0xc2, 0x2c, 0xcd, 0x0c, 0x02, 0x8a, 0x65, 0x1c, 0x41, 0x12,
+	0xd1, 0xbb, 0x51, 0xc8, 0xcc, 0xf2, 0x4c, 0x34, 0x8a, 0x65, 0xd0, 0x48, 0x6e, 0x97, 0x61, 0x59,
+	0x36, 0x60, 0xfb, 0xaf, 0x06, 0xd4, 0x32, 0x31, 0x9e, 0xe3, 0xce, 0xbc, 0x0d, 0x45, 0x7c, 0x98,
+	0xea, 0xdc, 0x55, 0xe4, 0x8d, 0x61, 0xc2, 0xa5, 0x92, 0x8b, 0x15, 0x6e, 0xdf, 0x53, 0xf7, 0xb8,
+	0x41, 0x71, 0x89, 0x9c, 0x2f, 0xc5, 0x95, 0x0c, 0x77, 0x85, 0xe2, 0x92, 0xac, 0x43, 0xe5, 0x8c,
+	0x75, 0x07, 0xdc, 0x17, 0x57, 0x32, 0x80, 0xcd, 0x8d, 0x16, 0x6a, 0x49, 0x79, 0xf2, 0x62, 0x0d,
+	0x25, 0xec, 0x2f, 0xf0, 0x60, 0x8d, 0x0c, 0x24, 0x50, 0xdc, 0xc1, 0xf1, 0x1c, 0x2d, 0x6b, 0x50,
+	0xb9, 0xc6, 0x17, 0xd2, 0xde, 0xac, 0x17, 0xd2, 0x5e, 0xfa, 0x42, 0x1a, 0x4f, 0x08, 0x16, 0xcc,
+	0x4c, 0x80, 0xec, 0x27, 0x50, 0x1d, 0x1e, 0x1a, 0x7c, 0x9c, 0xee, 0x7b, 0x7a, 0xa7, 0xa5, 0x7d,
+	0x0f, 0x5d, 0xd9, 0x7b, 0xba, 0x2f, 0x77, 0xa9, 0x50, 0x5c, 0x0e, 0xdb, 0x53, 0x21, 0xd3, 0x9e,
+	0x36, 0xa1, 0xa1, 0x0e, 0x4a, 0xc6, 0x64, 0x1a, 0x5d, 0x26, 0xa9, 0xc9, 0xb8, 0x56, 0x6e, 0x04,
+	0x89, 0xb9, 0x94, 0xba, 0x11, 0x24, 0xf6, 0xff, 0x0d, 0xa8, 0x3d, 0x73, 0x47, 0x6f, 0xc6, 0xcf,
+	0xa1, 0xe4, 0xfd, 0xe8, 0x57, 0x83, 0x22, 0x71, 0x1a, 0x09, 0xd8, 0x4b, 0x16, 0xe8, 0x96, 0xab,
+	0x08, 0xe4, 0x26, 0xcf, 0x23, 0x2e, 0xb4, 0xfd, 0x8a, 0xc0, 0xfe, 0xec, 0x31, 0xe1, 0xfa, 0x81,
+	0x9c, 0xbe, 0xeb, 0x54, 0x53, 0xe8, 0xfe, 0x80, 0x07, 0x32, 0x65, 0x55, 0x8a, 0x4b, 0x62, 0x43,
+	0xd1, 0x0f, 0x7b, 0x91, 0x59, 0x1a, 0x4d, 0x69, 0x67, 0xd1, 0x80, 0x77, 0xd9, 0x61, 0xd8, 0x8b,
+	0xa8, 0xfc, 0x86, 0x35, 0x96, 0xe3, 0x38, 0x90, 0x98, 0xe5, 0x51, 0x8d, 0x55, 0x43, 0x83, 0xfe,
+	0x60, 0x37, 0xa1, 0xae, 0xfc, 0x56, 0xc5, 0x64, 0xe3, 0xdf, 0x55, 0xa8, 0x1e, 0x1d, 0x6d, 0x6f,
+	0x73, 0xdf, 0xeb, 0x33, 0xf2, 0x07, 0x03, 0xc8, 0xf5, 0xb7, 0x15, 0xf9, 0x28, 0xff, 0xe6, 0x4e,
+	0x7f, 0x20, 0x5a, 0x1f, 0x2f, 0x88, 0xd2, 0x6d, 0xf3, 0x2b, 0x58, 0x96, 0x23, 0x1b, 0x79, 0x7f,
+	0xce, 0x51, 0xdb, 0x6a, 0xcf, 0x16, 0xd4, 0xba, 0xbb, 0x50, 0x49, 0xc7, 0x1e, 0x72, 0x3f, 0xd7,
+	0xbc, 0xb1, 0xa9, 0xce, 0x7a, 0x30, 0x97, 0xac, 0xde, 0xe4, 0xb7, 0x50, 0xd6, 0xd3, 0x0c, 0xb9,
+	0x37, 0x03, 0x37, 0x9a, 0xab, 0xac, 0xfb, 0xf3, 0x88, 0x8e, 0xdc, 0x48, 0xa7, 0x96, 0x5c, 0x37,
+	0x26, 0x66, 0x22, 0xeb, 0xc1, 0x5c, 0xb2, 0x7a, 0x93, 0x67, 0x50, 0xc4, 0xf1, 0x86, 0xe4, 0xd5,
+	0xbb, 0xcc, 0xfc, 0x63, 0xe5, 0xa5, 0x6b, 0x6c, 0x2e, 0xfa, 0x0d, 0x94, 0xf4, 0x13, 0x31, 0xbf,
+	0x23, 0x64, 0xfe, 0xd3, 0xb1, 0xee, 0xcd, 0x21, 0x39, 0x52, 0xaf, 0x9f, 0x57, 0xed, 0x39, 0xfe,
+	0x58, 0x99, 0xad, 0x7e, 0xe2, 0x2f, 0x9c, 0x08, 0xea, 0xd9, 0x76, 0x4f, 0x9c, 0x1c, 0xe8, 0x94,
+	0x49, 0xc9, 0xea, 0xcc, 0x2d, 0xaf, 0x37, 0xfc, 0x16, 0x5a, 0x93, 0xa3, 0x00, 0xd9, 0xc8, 0x0d,
+	0xc7, 0xd4, 0xa1, 0xc3, 0x7a, 0xb8, 0x10, 0x46, 0x6f, 0xee, 0xaa, 0x51, 0x43, 0x8f, 0x13, 0x24,
+	0xbf, 0x73, 0x0e, 0x47, 0x12, 0x6b, 0x4e, 0xb9, 0xb6, 0xf1, 0xa1, 0x81, 0xe7, 0x0c, 0x2b, 0x52,
+	0xae, 0xee, 0x4c, 0xa9, 0xb6, 0xde, 0x9f, 0x29, 0xa7, 0x6c, 0xdf, 0xae, 0x7f, 0xf7, 0xfa, 0xae,
+	0xf1, 0xaf, 0xd7, 0x77, 0x8d, 0xff, 0xbe, 0xbe, 0x6b, 0x9c, 0x97, 0xe4, 0xff, 0xe5, 0x0f, 0x7f,
+	0x18, 0x00, 0xaf, 0xa8, 0x71, 0x50, 0x81, 0x18, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2220,6 +2356,8 @@ type LLBBridgeClient interface {
 	NewContainer(ctx context.Context, in *NewContainerRequest, opts ...grpc.CallOption) (*NewContainerResponse, error)
 	ReleaseContainer(ctx context.Context, in *ReleaseContainerRequest, opts ...grpc.CallOption) (*ReleaseContainerResponse, error)
 	ExecProcess(ctx context.Context, opts ...grpc.CallOption) (LLBBridge_ExecProcessClient, error)
+	// apicaps:CapGatewayWarnings
+	Warn(ctx context.Context, in *WarnRequest, opts ...grpc.CallOption) (*WarnResponse, error)
 }
 
 type lLBBridgeClient struct {
@@ -2351,6 +2489,15 @@ func (x *lLBBridgeExecProcessClient) Recv() (*ExecMessage, error) {
 	return m, nil
 }
 
+func (c *lLBBridgeClient) Warn(ctx context.Context, in *WarnRequest, opts ...grpc.CallOption) (*WarnResponse, error) {
+	out := new(WarnResponse)
+	err := c.cc.Invoke(ctx, "/moby.buildkit.v1.frontend.LLBBridge/Warn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LLBBridgeServer is the server API for LLBBridge service.
 type LLBBridgeServer interface {
 	// apicaps:CapResolveImage
@@ -2370,6 +2517,8 @@ type LLBBridgeServer interface {
 	NewContainer(context.Context, *NewContainerRequest) (*NewContainerResponse, error)
 	ReleaseContainer(context.Context, *ReleaseContainerRequest) (*ReleaseContainerResponse, error)
 	ExecProcess(LLBBridge_ExecProcessServer) error
+	// apicaps:CapGatewayWarnings
+	Warn(context.Context, *WarnRequest) (*WarnResponse, error)
 }
 
 // UnimplementedLLBBridgeServer can be embedded to have forward compatible implementations.
@@ -2409,6 +2558,9 @@ func (*UnimplementedLLBBridgeServer) ReleaseContainer(ctx context.Context, req *
 func (*UnimplementedLLBBridgeServer) ExecProcess(srv LLBBridge_ExecProcessServer) error {
 	return status.Errorf(codes.Unimplemented, "method ExecProcess not implemented")
 }
+func (*UnimplementedLLBBridgeServer) Warn(ctx context.Context, req *WarnRequest) (*WarnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Warn not implemented")
+}
 
 func RegisterLLBBridgeServer(s *grpc.Server, srv LLBBridgeServer) {
 	s.RegisterService(&_LLBBridge_serviceDesc, srv)
@@ -2620,6 +2772,24 @@ func (x *lLBBridgeExecProcessServer) Recv() (*ExecMessage, error) {
 	return m, nil
 }
 
+func _LLBBridge_Warn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLBBridgeServer).Warn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moby.buildkit.v1.frontend.LLBBridge/Warn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLBBridgeServer).Warn(ctx, req.(*WarnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LLBBridge_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "moby.buildkit.v1.frontend.LLBBridge",
 	HandlerType: (*LLBBridgeServer)(nil),
@@ -2664,6 +2834,10 @@ var _LLBBridge_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ReleaseContainer",
 			Handler:    _LLBBridge_ReleaseContainer_Handler,
 		},
+		{
+			MethodName: "Warn",
+			Handler:    _LLBBridge_Warn_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -4474,6 +4648,121 @@ func (m *ResizeMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *WarnRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WarnRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WarnRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Ranges) > 0 {
+		for iNdEx := len(m.Ranges) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Ranges[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGateway(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.Info != nil {
+		{
+			size, err := m.Info.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGateway(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Url) > 0 {
+		i -= len(m.Url)
+		copy(dAtA[i:], m.Url)
+		i = encodeVarintGateway(dAtA, i, uint64(len(m.Url)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Detail) > 0 {
+		for iNdEx := len(m.Detail) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Detail[iNdEx])
+			copy(dAtA[i:], m.Detail[iNdEx])
+			i = encodeVarintGateway(dAtA, i, uint64(len(m.Detail[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Short) > 0 {
+		i -= len(m.Short)
+		copy(dAtA[i:], m.Short)
+		i = encodeVarintGateway(dAtA, i, uint64(len(m.Short)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Level != 0 {
+		i = encodeVarintGateway(dAtA, i, uint64(m.Level))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintGateway(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WarnResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WarnResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WarnResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintGateway(dAtA []byte, offset int, v uint64) int {
 	offset -= sovGateway(v)
 	base := offset
@@ -5317,6 +5606,61 @@ func (m *ResizeMessage) Size() (n int) {
 	return n
 }
 
+func (m *WarnRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovGateway(uint64(l))
+	}
+	if m.Level != 0 {
+		n += 1 + sovGateway(uint64(m.Level))
+	}
+	l = len(m.Short)
+	if l > 0 {
+		n += 1 + l + sovGateway(uint64(l))
+	}
+	if len(m.Detail) > 0 {
+		for _, b := range m.Detail {
+			l = len(b)
+			n += 1 + l + sovGateway(uint64(l))
+		}
+	}
+	l = len(m.Url)
+	if l > 0 {
+		n += 1 + l + sovGateway(uint64(l))
+	}
+	if m.Info != nil {
+		l = m.Info.Size()
+		n += 1 + l + sovGateway(uint64(l))
+	}
+	if len(m.Ranges) > 0 {
+		for _, e := range m.Ranges {
+			l = e.Size()
+			n += 1 + l + sovGateway(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WarnResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovGateway(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -10043,6 +10387,327 @@ func (m *ResizeMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *WarnRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGateway
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WarnRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WarnRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGateway
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = github_com_opencontainers_go_digest.Digest(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			m.Level = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Level |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Short", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGateway
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Short = append(m.Short[:0], dAtA[iNdEx:postIndex]...)
+			if m.Short == nil {
+				m.Short = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detail", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGateway
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Detail = append(m.Detail, make([]byte, postIndex-iNdEx))
+			copy(m.Detail[len(m.Detail)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Url", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGateway
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Url = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Info", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGateway
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Info == nil {
+				m.Info = &pb.SourceInfo{}
+			}
+			if err := m.Info.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ranges", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGateway
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ranges = append(m.Ranges, &pb.Range{})
+			if err := m.Ranges[len(m.Ranges)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGateway(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WarnResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGateway
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WarnResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WarnResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGateway(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGateway
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipGateway(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
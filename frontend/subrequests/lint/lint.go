@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/frontend/subrequests"
+	"github.com/moby/buildkit/solver/pb"
+)
+
+const RequestLint = "frontend.lints.v0"
+
+var SubrequestLintDefinition = subrequests.Request{
+	Name:        RequestLint,
+	Version:     "1.0.0",
+	Type:        subrequests.TypeRPC,
+	Description: "Lint a Dockerfile",
+	Opts:        []subrequests.Named{},
+	Metadata: []subrequests.Named{
+		{Name: "result.json"},
+		{Name: "result.txt"},
+	},
+}
+
+// Warning is a violation of a lint rule.
+type Warning struct {
+	RuleName    string       `json:"ruleName"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Detail      string       `json:"detail,omitempty"`
+	Location    *pb.Location `json:"location,omitempty"`
+}
+
+// LintResults are the violations found in a Dockerfile.
+type LintResults struct {
+	Warnings []Warning `json:"warnings"`
+	// Sources are the files the locations point to, indexed by
+	// pb.Location.SourceIndex
+	Sources [][]byte `json:"sources,omitempty"`
+	// Error is set if the violations fail the check, e.g. in check mode or
+	// with error=true in the check directive of the Dockerfile.
+	Error string `json:"error,omitempty"`
+}
+
+// AddWarning records a violation of rule at location in the source with
+// index sourceIndex.
+func (results *LintResults) AddWarning(rulename, description, url, fmtmsg string, sourceIndex int, location []*pb.Range) {
+	results.Warnings = append(results.Warnings, Warning{
+		RuleName:    rulename,
+		Description: description,
+		URL:         url,
+		Detail:      fmtmsg,
+		Location: &pb.Location{
+			SourceIndex: int32(sourceIndex),
+			Ranges:      location,
+		},
+	})
+}
+
+// ToResult returns the lint results as the result of the subrequest.
+func (results LintResults) ToResult() (*client.Result, error) {
+	res := client.NewResult()
+	dt, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	res.AddMeta("result.json", dt)
+
+	b := bytes.NewBuffer(nil)
+	if err := PrintLintViolations(dt, b); err != nil {
+		return nil, err
+	}
+	res.AddMeta("result.txt", b.Bytes())
+	return res, nil
+}
+
+// PrintLintViolations pretty prints the result.json of a lint subrequest.
+func PrintLintViolations(dt []byte, w io.Writer) error {
+	var results LintResults
+	if err := json.Unmarshal(dt, &results); err != nil {
+		return err
+	}
+
+	for _, warning := range results.Warnings {
+		fmt.Fprintf(w, "%s", warning.RuleName)
+		if warning.URL != "" {
+			fmt.Fprintf(w, " - %s", warning.URL)
+		}
+		fmt.Fprintf(w, "\n%s\n", warning.Detail)
+
+		if warning.Location == nil || len(warning.Location.Ranges) == 0 {
+			fmt.Fprintln(w)
+			continue
+		}
+		var lines []string
+		if i := int(warning.Location.SourceIndex); i >= 0 && i < len(results.Sources) {
+			lines = strings.Split(string(results.Sources[i]), "\n")
+		}
+		for _, r := range warning.Location.Ranges {
+			for l := r.Start.Line; l <= r.End.Line; l++ {
+				if l < 1 || int(l) > len(lines) {
+					continue
+				}
+				fmt.Fprintf(w, "%4d | %s\n", l, lines[l-1])
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
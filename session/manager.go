@@ -97,6 +97,12 @@ func (sm *Manager) HandleConn(ctx context.Context, conn net.Conn, opts map[strin
 	return sm.handleConn(ctx, conn, opts)
 }
 
+// IDFromHeaders returns the session ID sent in the headers of a session
+// connection.
+func IDFromHeaders(opts map[string][]string) string {
+	return http.Header(canonicalHeaders(opts)).Get(headerSessionID)
+}
+
 // caller needs to take lock, this function will release it
 func (sm *Manager) handleConn(ctx context.Context, conn net.Conn, opts map[string][]string) error {
 	ctx, cancel := context.WithCancel(ctx)
@@ -0,0 +1,48 @@
+package errdefs
+
+import (
+	"context"
+	fmt "fmt"
+
+	"github.com/containerd/typeurl"
+	"github.com/moby/buildkit/util/grpcerrors"
+	digest "github.com/opencontainers/go-digest"
+)
+
+func init() {
+	typeurl.Register((*VertexCanceled)(nil), "github.com/moby/buildkit", "errdefs.VertexCanceled+json")
+}
+
+// VertexCanceledError is returned by builds that depend on a vertex that was
+// canceled with CancelVertex.
+type VertexCanceledError struct {
+	VertexCanceled
+	error
+}
+
+func (e *VertexCanceledError) Error() string {
+	msg := fmt.Sprintf("vertex %s was canceled", e.VertexCanceled.Digest)
+	if e.error != nil && e.error != context.Canceled {
+		msg += ": " + e.error.Error()
+	}
+	return msg
+}
+
+func (e *VertexCanceledError) Unwrap() error {
+	if e.error == nil {
+		return context.Canceled
+	}
+	return e.error
+}
+
+func (e *VertexCanceledError) ToProto() grpcerrors.TypedErrorProto {
+	return &e.VertexCanceled
+}
+
+func NewVertexCanceledError(dgst digest.Digest, err error) error {
+	return &VertexCanceledError{VertexCanceled: VertexCanceled{Digest: dgst.String()}, error: err}
+}
+
+func (v *VertexCanceled) WrapError(err error) error {
+	return &VertexCanceledError{error: err, VertexCanceled: *v}
+}
@@ -0,0 +1,56 @@
+package errdefs
+
+import (
+	"context"
+	fmt "fmt"
+	"time"
+
+	"github.com/containerd/typeurl"
+	"github.com/moby/buildkit/util/grpcerrors"
+	digest "github.com/opencontainers/go-digest"
+)
+
+func init() {
+	typeurl.Register((*VertexTimeout)(nil), "github.com/moby/buildkit", "errdefs.VertexTimeout+json")
+}
+
+// VertexTimeoutError is returned by a vertex that was stopped because its
+// timeout or the deadline of the solve was exceeded.
+type VertexTimeoutError struct {
+	VertexTimeout
+	error
+}
+
+func (e *VertexTimeoutError) Error() string {
+	var msg string
+	if e.VertexTimeout.Timeout > 0 {
+		msg = fmt.Sprintf("vertex %s exceeded timeout of %s", e.VertexTimeout.Digest, time.Duration(e.VertexTimeout.Timeout))
+	} else {
+		msg = fmt.Sprintf("vertex %s exceeded solve deadline", e.VertexTimeout.Digest)
+	}
+	if e.error != nil && e.error != context.DeadlineExceeded {
+		msg += ": " + e.error.Error()
+	}
+	return msg
+}
+
+func (e *VertexTimeoutError) Unwrap() error {
+	if e.error == nil {
+		return context.DeadlineExceeded
+	}
+	return e.error
+}
+
+func (e *VertexTimeoutError) ToProto() grpcerrors.TypedErrorProto {
+	return &e.VertexTimeout
+}
+
+// NewVertexTimeoutError wraps err of the vertex dgst that ran longer than
+// timeout. A timeout of 0 means the deadline of the solve was exceeded.
+func NewVertexTimeoutError(dgst digest.Digest, timeout time.Duration, err error) error {
+	return &VertexTimeoutError{VertexTimeout: VertexTimeout{Digest: dgst.String(), Timeout: int64(timeout)}, error: err}
+}
+
+func (v *VertexTimeout) WrapError(err error) error {
+	return &VertexTimeoutError{error: err, VertexTimeout: *v}
+}
@@ -40,6 +40,7 @@ type Solver struct {
 	updateCond *sync.Cond
 	s          *scheduler
 	index      *edgeIndex
+	nsIndex    map[string]*edgeIndex
 }
 
 type state struct {
@@ -64,6 +65,10 @@ type state struct {
 	cache     map[string]CacheManager
 	mainCache CacheManager
 	solver    *Solver
+	namespace string
+	// canceledEdges are the edges that failed because the vertex was
+	// canceled with CancelVertex.
+	canceledEdges map[Index]*edge
 }
 
 func (s *state) SessionIterator() session.Iterator {
@@ -147,10 +152,64 @@ func (s *state) getEdge(index Index) *edge {
 	}
 
 	e := newEdge(Edge{Index: index, Vertex: s.vtx}, s.op, s.index)
+	if err := s.canceledErr(); err != nil {
+		e.err = err
+		s.addCanceled(index, e)
+	}
 	s.edges[index] = e
 	return e
 }
 
+// canceledErr returns the error of CancelVertex if all the jobs of the vertex
+// canceled it. Must be called with s.mu held.
+func (s *state) canceledErr() error {
+	var err error
+	for j := range s.jobs {
+		if err = j.vertexCanceled(s.vtx.Digest()); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// cancel fails the current edges of the vertex that are not complete yet with
+// err. Edges merged from other vertexes are not canceled. Must be called with
+// the scheduler lock held.
+func (s *state) cancel(err error) []*edge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var edges []*edge
+	for index, e := range s.edges {
+		if e.edge.Vertex.Digest() != s.vtx.Digest() || e.isComplete() {
+			continue
+		}
+		e.err = err
+		s.addCanceled(index, e)
+		edges = append(edges, e)
+	}
+	return edges
+}
+
+func (s *state) addCanceled(index Index, e *edge) {
+	if s.canceledEdges == nil {
+		s.canceledEdges = map[Index]*edge{}
+	}
+	s.canceledEdges[index] = e
+}
+
+// clearCanceled drops the edges that failed because the vertex was canceled,
+// so that a job that didn't cancel the vertex loads new edges for it. Must be
+// called with s.mu held.
+func (s *state) clearCanceled() {
+	for index, e := range s.canceledEdges {
+		if s.edges[index] == e {
+			delete(s.edges, index)
+			e.release()
+		}
+	}
+	s.canceledEdges = nil
+}
+
 func (s *state) setEdge(index Index, newEdge *edge) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -238,11 +297,31 @@ type Job struct {
 
 	progressCloser func()
 	SessionID      string
+	// CacheNamespace isolates the cache of the vertexes loaded by the job
+	// from the jobs of other namespaces.
+	CacheNamespace string
+	// Priority of the job when sharing resources with other jobs. Operations
+	// of jobs with higher priority are started first.
+	Priority int
+	// Weight of the job when sharing resources with other jobs of the same
+	// priority. Values below 1 are treated as 1.
+	Weight int
+	// Deadline of the job. Operations loaded only by jobs with a deadline are
+	// stopped when the last of the deadlines is exceeded.
+	Deadline time.Time
+
+	canceledMu sync.Mutex
+	// canceled are the errors of the vertexes the job canceled with
+	// CancelVertex.
+	canceled map[digest.Digest]error
 }
 
 type SolverOpt struct {
 	ResolveOpFunc ResolveOpFunc
 	DefaultCache  CacheManager
+	// NamespacedCache returns the cache manager for a cache namespace. It is
+	// required for loading vertexes of jobs with a CacheNamespace.
+	NamespacedCache func(namespace string) (CacheManager, error)
 }
 
 func NewSolver(opts SolverOpt) *Solver {
@@ -254,6 +333,7 @@ func NewSolver(opts SolverOpt) *Solver {
 		actives: make(map[digest.Digest]*state),
 		opts:    opts,
 		index:   newEdgeIndex(),
+		nsIndex: make(map[string]*edgeIndex),
 	}
 	jl.s = newScheduler(jl)
 	jl.updateCond = sync.NewCond(jl.mu.RLocker())
@@ -331,7 +411,16 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 		inputs[i] = Edge{Index: e.Index, Vertex: v}
 	}
 
-	dgst := v.Digest()
+	var ns string
+	if j != nil {
+		ns = j.CacheNamespace
+	} else if parent != nil {
+		if parentState, ok := jl.actives[parent.Digest()]; ok {
+			ns = parentState.namespace
+		}
+	}
+
+	dgst := namespacedDigest(v.Digest(), ns)
 
 	dgstWithoutCache := digest.FromBytes([]byte(fmt.Sprintf("%s-ignorecache", dgst)))
 
@@ -356,6 +445,10 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 	}
 
 	if !ok {
+		mainCache, index, err := jl.namespace(ns)
+		if err != nil {
+			return nil, err
+		}
 		st = &state{
 			opts:         jl.opts,
 			jobs:         map[*Job]struct{}{},
@@ -367,11 +460,12 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 			vtx:          v,
 			clientVertex: initClientVertex(v),
 			edges:        map[Index]*edge{},
-			index:        jl.index,
-			mainCache:    jl.opts.DefaultCache,
+			index:        index,
+			mainCache:    mainCache,
 			cache:        map[string]CacheManager{},
 			solver:       jl,
 			origDigest:   origVtx.Digest(),
+			namespace:    ns,
 		}
 		jl.actives[dgst] = st
 	}
@@ -388,6 +482,9 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 	if j != nil {
 		if _, ok := st.jobs[j]; !ok {
 			st.jobs[j] = struct{}{}
+			if len(st.canceledEdges) > 0 && j.vertexCanceled(dgst) == nil {
+				st.clearCanceled()
+			}
 		}
 	}
 	st.mu.Unlock()
@@ -412,6 +509,28 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 	return v, nil
 }
 
+// namespace returns the cache manager and edge index for vertexes of a cache
+// namespace. Edges of different namespaces must not be merged even if they
+// have the same cache keys.
+func (jl *Solver) namespace(ns string) (CacheManager, *edgeIndex, error) {
+	if ns == "" {
+		return jl.opts.DefaultCache, jl.index, nil
+	}
+	if jl.opts.NamespacedCache == nil {
+		return nil, nil, errors.Errorf("cache namespaces are not supported")
+	}
+	cm, err := jl.opts.NamespacedCache(ns)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, ok := jl.nsIndex[ns]
+	if !ok {
+		index = newEdgeIndex()
+		jl.nsIndex[ns] = index
+	}
+	return cm, index, nil
+}
+
 func (jl *Solver) connectProgressFromState(target, src *state) {
 	for j := range src.jobs {
 		if _, ok := target.allPw[j.pw]; !ok {
@@ -507,6 +626,12 @@ func (j *Job) Build(ctx context.Context, e Edge) (CachedResult, BuildInfo, error
 	}
 	e.Vertex = v
 
+	// vertexes the job canceled keep failing for it even if another job
+	// loaded them again
+	if err := j.canceledInput(v, map[digest.Digest]struct{}{}); err != nil {
+		return nil, nil, err
+	}
+
 	res, err := j.list.s.build(ctx, e)
 	if err != nil {
 		return nil, nil, err
@@ -517,6 +642,90 @@ func (j *Job) Build(ctx context.Context, e Edge) (CachedResult, BuildInfo, error
 	return res, j.walkBuildInfo(ctx, e, make(BuildInfo)), nil
 }
 
+// CancelVertex cancels the vertex with digest dgst in job id. The edges of the
+// vertex and of the vertexes depending on it fail with a VertexCanceledError,
+// so only the dependants of the vertex fail while the other parts of the
+// build keep running. Edges that are shared with other jobs keep running for
+// them.
+func (jl *Solver) CancelVertex(id string, dgst digest.Digest) error {
+	jl.mu.RLock()
+	j, ok := jl.jobs[id]
+	if !ok {
+		jl.mu.RUnlock()
+		return errors.Errorf("no such job %s", id)
+	}
+	var loaded, shared bool
+	var states []*state
+	for _, st := range jl.actives {
+		if !jl.dependsOn(st.vtx, dgst, map[digest.Digest]struct{}{}) {
+			continue
+		}
+		st.mu.Lock()
+		_, inJob := st.jobs[j]
+		exclusive := len(st.jobs) == 1
+		st.mu.Unlock()
+		if !inJob {
+			continue
+		}
+		if st.vtx.Digest() == dgst || st.origDigest == dgst {
+			loaded = true
+		}
+		if !exclusive {
+			shared = true
+			continue
+		}
+		states = append(states, st)
+	}
+	jl.mu.RUnlock()
+
+	if !loaded {
+		return errors.Errorf("vertex %s is not running in job %s", dgst, id)
+	}
+
+	err := errdefs.NewVertexCanceledError(dgst, context.Canceled)
+
+	// edges are only modified by the scheduler while holding its lock
+	jl.s.mu.Lock()
+	defer jl.s.mu.Unlock()
+	var canceled bool
+	for _, st := range states {
+		j.setVertexCanceled(st.vtx.Digest(), err)
+		for _, e := range st.cancel(err) {
+			jl.s.signal(e)
+			canceled = true
+		}
+	}
+	if !canceled {
+		if shared {
+			return errors.Errorf("vertex %s and its running dependants are shared with other jobs", dgst)
+		}
+		return errors.Errorf("vertex %s is not running in job %s", dgst, id)
+	}
+	return nil
+}
+
+// dependsOn returns true if v or one of its inputs has digest dgst. Vertexes
+// can be referred to by their loaded or their original digest.
+func (jl *Solver) dependsOn(v Vertex, dgst digest.Digest, visited map[digest.Digest]struct{}) bool {
+	if _, ok := visited[v.Digest()]; ok {
+		return false
+	}
+	visited[v.Digest()] = struct{}{}
+
+	if v.Digest() == dgst {
+		return true
+	}
+	if st, ok := jl.actives[v.Digest()]; ok && st.origDigest == dgst {
+		return true
+	}
+	for _, inp := range v.Inputs() {
+		if jl.dependsOn(inp.Vertex, dgst, visited) {
+			return true
+		}
+	}
+	return false
+}
+
 func (j *Job) walkBuildInfo(ctx context.Context, e Edge, bi BuildInfo) BuildInfo {
 	for _, inp := range e.Vertex.Inputs() {
 		if st, ok := j.list.actives[inp.Vertex.Digest()]; ok {
@@ -535,6 +744,41 @@ func (j *Job) walkBuildInfo(ctx context.Context, e Edge, bi BuildInfo) BuildInfo
 	return bi
 }
 
+func (j *Job) setVertexCanceled(dgst digest.Digest, err error) {
+	j.canceledMu.Lock()
+	defer j.canceledMu.Unlock()
+	if j.canceled == nil {
+		j.canceled = map[digest.Digest]error{}
+	}
+	j.canceled[dgst] = err
+}
+
+// vertexCanceled returns the error of the loaded vertex with digest dgst if
+// the job canceled it.
+func (j *Job) vertexCanceled(dgst digest.Digest) error {
+	j.canceledMu.Lock()
+	defer j.canceledMu.Unlock()
+	return j.canceled[dgst]
+}
+
+// canceledInput returns the error of the first vertex in the graph of the
+// loaded vertex v that the job canceled.
+func (j *Job) canceledInput(v Vertex, visited map[digest.Digest]struct{}) error {
+	if _, ok := visited[v.Digest()]; ok {
+		return nil
+	}
+	visited[v.Digest()] = struct{}{}
+	if err := j.vertexCanceled(v.Digest()); err != nil {
+		return err
+	}
+	for _, in := range v.Inputs() {
+		if err := j.canceledInput(in.Vertex, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (j *Job) Discard() error {
 	defer j.progressCloser()
 
@@ -809,13 +1053,22 @@ func (s *sharedOp) Exec(ctx context.Context, inputs []Result) (outputs []Result,
 		if s.execRes != nil || s.execErr != nil {
 			return s.execRes, s.execErr
 		}
-		release, err := op.Acquire(ctx)
+		ctx = progress.WithProgress(ctx, s.st.mpw)
+		if deadline := jobsDeadline(s.st); !deadline.IsZero() {
+			var cancel func()
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+		release, err := op.Acquire(withScheduleInfo(ctx, s.st))
 		if err != nil {
-			return nil, errors.Wrap(err, "acquire op resources")
+			err = errors.Wrap(err, "acquire op resources")
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = errdefs.NewVertexTimeoutError(s.st.origDigest, 0, err)
+			}
+			return nil, err
 		}
 		defer release()
 
-		ctx = progress.WithProgress(ctx, s.st.mpw)
 		if s.st.mspan.Span != nil {
 			ctx = trace.ContextWithSpan(ctx, s.st.mspan)
 		}
@@ -829,9 +1082,26 @@ func (s *sharedOp) Exec(ctx context.Context, inputs []Result) (outputs []Result,
 			notifyCompleted(ctx, &s.st.clientVertex, retErr, false)
 		}()
 
-		res, err := op.Exec(ctx, s.st, inputs)
+		execCtx := ctx
+		timeout := s.st.vtx.Options().Timeout
+		if timeout > 0 {
+			var cancel func()
+			execCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		res, err := op.Exec(execCtx, s.st, inputs)
 		complete := true
-		if err != nil {
+		if err != nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			// timeouts are not cached so the vertex can be run again by
+			// other builds
+			complete = false
+			releaseError(err)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				timeout = 0
+			}
+			err = errdefs.NewVertexTimeoutError(s.st.origDigest, timeout, err)
+		} else if err != nil {
 			select {
 			case <-ctx.Done():
 				if strings.Contains(err.Error(), context.Canceled.Error()) {
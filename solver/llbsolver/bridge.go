@@ -8,18 +8,23 @@ import (
 	"time"
 
 	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution/reference"
 	"github.com/mitchellh/hashstructure"
 	"github.com/moby/buildkit/cache/remotecache"
+	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/frontend"
 	gw "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/errdefs"
 	llberrdefs "github.com/moby/buildkit/solver/llbsolver/errdefs"
 	"github.com/moby/buildkit/solver/pb"
+	srctypes "github.com/moby/buildkit/source/types"
 	"github.com/moby/buildkit/util/bklog"
 	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
@@ -45,6 +50,14 @@ func (b *llbBridge) loadResult(ctx context.Context, def *pb.Definition, cacheImp
 	if err != nil {
 		return nil, nil, err
 	}
+	srcPol, err := loadSourcePolicy(b.builder)
+	if err != nil {
+		return nil, nil, err
+	}
+	ns, err := loadCacheNamespace(b.builder)
+	if err != nil {
+		return nil, nil, err
+	}
 	var cms []solver.CacheManager
 	for _, im := range cacheImports {
 		cmID, err := cmKey(im)
@@ -84,7 +97,17 @@ func (b *llbBridge) loadResult(ctx context.Context, def *pb.Definition, cacheImp
 	}
 	dpc := &detectPrunedCacheID{}
 
-	edge, err := Load(def, dpc.Load, ValidateEntitlements(ent), WithCacheSources(cms), NormalizeRuntimePlatforms(), WithValidateCaps())
+	loadOpts := []LoadOpt{WithCacheNamespace(ns), dpc.Load, ValidateEntitlements(ent), WithCacheSources(cms), NormalizeRuntimePlatforms(), WithValidateCaps()}
+	usedCacheMounts, err := loadCacheMountSet(b.builder)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usedCacheMounts != nil {
+		// record the IDs before they are scoped to the cache namespace
+		loadOpts = append([]LoadOpt{usedCacheMounts.Load}, loadOpts...)
+	}
+
+	edge, err := Load(ctx, def, srcPol, loadOpts...)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to load LLB")
 	}
@@ -268,6 +291,10 @@ func (b *llbBridge) ResolveImageConfig(ctx context.Context, ref string, opt llb.
 	if err != nil {
 		return "", nil, err
 	}
+	ref, err = b.resolveImageSourcePolicy(ctx, ref)
+	if err != nil {
+		return "", nil, err
+	}
 	if opt.LogName == "" {
 		opt.LogName = fmt.Sprintf("resolve image config for %s", ref)
 	}
@@ -284,6 +311,67 @@ func (b *llbBridge) ResolveImageConfig(ctx context.Context, ref string, opt llb.
 	return dgst, config, err
 }
 
+func (b *llbBridge) Warn(ctx context.Context, dgst digest.Digest, msg string, opts frontend.WarnOpts) error {
+	return b.builder.InContext(ctx, func(ctx context.Context, g session.Group) error {
+		pw, ok, _ := progress.NewFromContext(ctx, progress.WithMetadata("vertex", dgst))
+		if !ok {
+			return nil
+		}
+		level := opts.Level
+		if level == 0 {
+			level = 1
+		}
+		pw.Write(identity.NewID(), client.VertexWarning{
+			Vertex:     dgst,
+			Level:      level,
+			Short:      []byte(msg),
+			Detail:     opts.Detail,
+			URL:        opts.URL,
+			SourceInfo: opts.SourceInfo,
+			Range:      opts.Range,
+		})
+		return pw.Close()
+	})
+}
+
+func (b *llbBridge) CacheNamespace() (string, error) {
+	return loadCacheNamespace(b.builder)
+}
+
+// resolveImageSourcePolicy applies the source policy of the build to an image
+// reference before its config is resolved, so that frontends resolve the same
+// image that the policy converts their sources to.
+func (b *llbBridge) resolveImageSourcePolicy(ctx context.Context, ref string) (string, error) {
+	srcPol, err := loadSourcePolicy(b.builder)
+	if err != nil {
+		return "", err
+	}
+	// match the policy against the same identifier llb.Image would create
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse image reference %s", ref)
+	}
+	op := &pb.Op{
+		Op: &pb.Op_Source{
+			Source: &pb.SourceOp{
+				Identifier: srctypes.DockerImageScheme + "://" + reference.TagNameOnly(named).String(),
+			},
+		},
+	}
+	mutated, err := srcPol.Evaluate(ctx, op)
+	if err != nil {
+		return "", errors.Wrap(err, "error evaluating the source policy")
+	}
+	if !mutated {
+		return ref, nil
+	}
+	ident := op.GetSource().Identifier
+	if !strings.HasPrefix(ident, srctypes.DockerImageScheme+"://") {
+		return "", errors.Errorf("source policy converted image %s to non-image source %s", ref, ident)
+	}
+	return strings.TrimPrefix(ident, srctypes.DockerImageScheme+"://"), nil
+}
+
 type lazyCacheManager struct {
 	id   string
 	main solver.CacheManager
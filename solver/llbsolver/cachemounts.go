@@ -0,0 +1,193 @@
+package llbsolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/remotecache"
+	"github.com/moby/buildkit/frontend"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/llbsolver/mounts"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/moby/buildkit/util/compression"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+)
+
+// cacheMountSet records the IDs of the cache mounts used by a build so that
+// their contents can be exported with the build cache.
+type cacheMountSet struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func (s *cacheMountSet) Load(op *pb.Op, _ *pb.OpMetadata, _ *solver.VertexOptions) error {
+	if op, ok := op.Op.(*pb.Op_Exec); ok {
+		for _, m := range op.Exec.GetMounts() {
+			// cache mounts based on another ref are not named by their ID only
+			if m.MountType != pb.MountType_CACHE || m.CacheOpt == nil || m.Input != pb.Empty {
+				continue
+			}
+			s.mu.Lock()
+			if s.ids == nil {
+				s.ids = map[string]struct{}{}
+			}
+			s.ids[m.CacheOpt.ID] = struct{}{}
+			s.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+func (s *cacheMountSet) list() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func loadCacheMountSet(b solver.Builder) (*cacheMountSet, error) {
+	var set *cacheMountSet
+	err := b.EachValue(context.TODO(), keyCacheMounts, func(v interface{}) error {
+		s, ok := v.(*cacheMountSet)
+		if !ok {
+			return errors.Errorf("invalid cache mount set %T", v)
+		}
+		set = s
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// ParseCacheMountsAttr returns if the cache mounts attribute is enabled in
+// the attributes of a cache exporter or importer.
+func ParseCacheMountsAttr(attrs map[string]string) (bool, error) {
+	v, ok := attrs[remotecache.AttrCacheMounts]
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s", remotecache.AttrCacheMounts)
+	}
+	return b, nil
+}
+
+// importCacheMounts seeds the cache mounts of the build with the contents
+// included in the imported caches that have cache mounts enabled. The
+// returned function removes the seeds again.
+func (s *Solver) importCacheMounts(ctx context.Context, j *solver.Job, imports []frontend.CacheOptionsEntry) (func(), error) {
+	var releasers []func()
+	release := func() {
+		for _, r := range releasers {
+			r()
+		}
+	}
+	for _, im := range imports {
+		enabled, err := ParseCacheMountsAttr(im.Attrs)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+		w, err := s.resolveWorker()
+		if err != nil {
+			release()
+			return nil, err
+		}
+		resolveCI, ok := s.resolveCacheImporterFuncs[im.Type]
+		if !ok {
+			release()
+			return nil, errors.Errorf("unknown cache importer: %s", im.Type)
+		}
+		if err := inBuilderContext(ctx, j, "importing cache mounts from "+im.Type, "", func(ctx context.Context, g session.Group) error {
+			ci, desc, err := resolveCI(ctx, g, im.Attrs)
+			if err != nil {
+				return err
+			}
+			cmi, ok := ci.(remotecache.CacheMountImporter)
+			if !ok {
+				return errors.Errorf("cache importer %s does not support importing cache mounts", im.Type)
+			}
+			remotes, err := cmi.ResolveCacheMounts(ctx, desc)
+			if err != nil {
+				return err
+			}
+			for id, remote := range remotes {
+				if err := mounts.ValidateCacheMountID(id); err != nil {
+					return err
+				}
+				ref, err := w.FromRemote(ctx, remote)
+				if err != nil {
+					return errors.Wrapf(err, "failed to load cache mount %q", id)
+				}
+				removeSeed := mounts.SeedCacheMount(w.CacheManager(), j.SessionID, mounts.NamespacedCacheMountID(j.CacheNamespace, id), ref)
+				releasers = append(releasers, func() {
+					removeSeed()
+					ref.Release(context.TODO())
+				})
+			}
+			return nil
+		}); err != nil {
+			// same as the build cache, missing cache mounts don't fail the build
+			bklog.G(ctx).Warnf("failed to import cache mounts from %s: %v", im.Type, err)
+		}
+	}
+	return release, nil
+}
+
+// exportCacheMounts adds the contents of the cache mounts in set to the cache
+// exporter e. The returned function releases the snapshots of the cache
+// mounts and needs to be called after e has been finalized.
+func exportCacheMounts(ctx context.Context, w worker.Worker, e remotecache.Exporter, set *cacheMountSet, ns string, g session.Group) (func(), error) {
+	cme, ok := e.(remotecache.CacheMountExporter)
+	if !ok {
+		return nil, errors.Errorf("cache exporter does not support exporting cache mounts")
+	}
+	var refs []cache.ImmutableRef
+	release := func() {
+		for _, ref := range refs {
+			ref.Release(context.TODO())
+		}
+	}
+	for _, id := range set.list() {
+		done := oneOffProgress(ctx, fmt.Sprintf("exporting cache mount %s", id))
+		ref, err := mounts.SnapshotCacheMount(ctx, w.CacheManager(), mounts.NamespacedCacheMountID(ns, id), g)
+		if err != nil {
+			release()
+			return nil, done(err)
+		}
+		if ref == nil {
+			bklog.G(ctx).Debugf("skipping export of unavailable cache mount %s", id)
+			done(nil)
+			continue
+		}
+		refs = append(refs, ref)
+		wref := &worker.WorkerRef{ImmutableRef: ref, Worker: w}
+		remote, err := wref.GetRemote(ctx, true, compression.New(compression.Default), g)
+		if err != nil {
+			release()
+			return nil, done(err)
+		}
+		if remote != nil {
+			cme.AddCacheMount(id, remote)
+		}
+		done(nil)
+	}
+	return release, nil
+}
@@ -0,0 +1,147 @@
+package mounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/pkg/errors"
+	copy "github.com/tonistiigi/fsutil/copy"
+)
+
+var cacheMountSeeds = &seeds{}
+
+type seed struct {
+	cm        cache.Manager
+	sessionID string
+	ref       cache.ImmutableRef
+}
+
+type seeds struct {
+	mu sync.Mutex
+	m  map[string][]*seed
+}
+
+// SeedCacheMount sets ref of cache manager cm as the initial contents of the
+// cache mount with id for the build running in the session with sessionID.
+// The seed is only used when the cache mount doesn't exist yet and it is
+// created by a vertex of that build. Cache mounts created from a seed are
+// copy-on-write layers on top of ref so ref itself is never modified. The
+// returned function removes the seed, ref is not released.
+func SeedCacheMount(cm cache.Manager, sessionID, id string, ref cache.ImmutableRef) func() {
+	s := &seed{cm: cm, sessionID: sessionID, ref: ref}
+	cacheMountSeeds.mu.Lock()
+	if cacheMountSeeds.m == nil {
+		cacheMountSeeds.m = map[string][]*seed{}
+	}
+	cacheMountSeeds.m[id] = append(cacheMountSeeds.m[id], s)
+	cacheMountSeeds.mu.Unlock()
+
+	return func() {
+		cacheMountSeeds.mu.Lock()
+		defer cacheMountSeeds.mu.Unlock()
+		seeds := cacheMountSeeds.m[id]
+		for i, s2 := range seeds {
+			if s2 == s {
+				seeds = append(seeds[:i], seeds[i+1:]...)
+				break
+			}
+		}
+		if len(seeds) == 0 {
+			delete(cacheMountSeeds.m, id)
+		} else {
+			cacheMountSeeds.m[id] = seeds
+		}
+	}
+}
+
+// get returns a clone of the most recent seed of the cache mount with id in
+// cm that was set by one of the sessions in g, or nil if there is none.
+func (s *seeds) get(cm cache.Manager, g session.Group, id string) cache.ImmutableRef {
+	sessionIDs := map[string]struct{}{}
+	for _, sid := range session.AllSessionIDs(g) {
+		sessionIDs[sid] = struct{}{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seeds := s.m[id]
+	for i := len(seeds) - 1; i >= 0; i-- {
+		if _, ok := sessionIDs[seeds[i].sessionID]; ok && seeds[i].cm == cm {
+			return seeds[i].ref.Clone()
+		}
+	}
+	return nil
+}
+
+// SnapshotCacheMount copies the current contents of the cache mount with id
+// to a new immutable ref. It returns nil if the cache mount doesn't exist or
+// is currently in use.
+func SnapshotCacheMount(ctx context.Context, cm cache.Manager, id string, g session.Group) (cache.ImmutableRef, error) {
+	cacheRefsLocker.Lock(id)
+	defer cacheRefsLocker.Unlock(id)
+
+	sis, err := SearchCacheDir(ctx, cm, id)
+	if err != nil {
+		return nil, err
+	}
+	var src cache.MutableRef
+	for _, si := range sis {
+		if mRef, err := cm.GetMutable(ctx, si.ID()); err == nil {
+			src = mRef
+			break
+		}
+	}
+	if src == nil {
+		return nil, nil
+	}
+	defer src.Release(context.TODO())
+
+	dst, err := cm.New(ctx, nil, g, cache.WithDescription(fmt.Sprintf("snapshot of cache mount %q", id)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if dst != nil {
+			dst.Release(context.TODO())
+		}
+	}()
+
+	srcMount, err := src.Mount(ctx, true, g)
+	if err != nil {
+		return nil, err
+	}
+	srcLm := snapshot.LocalMounter(srcMount)
+	srcDir, err := srcLm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	defer srcLm.Unmount()
+
+	dstMount, err := dst.Mount(ctx, false, g)
+	if err != nil {
+		return nil, err
+	}
+	dstLm := snapshot.LocalMounter(dstMount)
+	dstDir, err := dstLm.Mount()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := copy.Copy(ctx, srcDir, "/", dstDir, "/", copy.WithCopyInfo(copy.CopyInfo{CopyDirContents: true}), copy.AllowXAttrErrors); err != nil {
+		dstLm.Unmount()
+		return nil, errors.Wrapf(err, "failed to copy cache mount %q", id)
+	}
+	if err := dstLm.Unmount(); err != nil {
+		return nil, err
+	}
+
+	ref, err := dst.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dst = nil
+	return ref, nil
+}
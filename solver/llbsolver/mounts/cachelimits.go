@@ -0,0 +1,155 @@
+package mounts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/pkg/errors"
+)
+
+// pruneCacheMount applies the size and age limits of a cache mount to the
+// contents of ref.
+func pruneCacheMount(ctx context.Context, ref cache.MutableRef, limits *pb.CacheOpt) error {
+	if limits == nil || (limits.MaxSize <= 0 && limits.MaxAge <= 0) {
+		return nil
+	}
+	mountable, err := ref.Mount(ctx, false, nil)
+	if err != nil {
+		return err
+	}
+	lm := snapshot.LocalMounter(mountable)
+	dir, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	defer lm.Unmount()
+
+	removed, err := pruneCacheDir(dir, limits.MaxSize, time.Duration(limits.MaxAge), limits.PrunePolicy, time.Now())
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		bklog.G(ctx).Debugf("pruned %d bytes from cache mount %s", removed, ref.ID())
+	}
+	return nil
+}
+
+// strictestLimits returns the smallest size and the shortest age limit of a
+// and b. A limit that is not set in one of them doesn't relax the other.
+func strictestLimits(a, b *pb.CacheOpt) *pb.CacheOpt {
+	if a == nil || (a.MaxSize <= 0 && a.MaxAge <= 0) {
+		return b
+	}
+	if b == nil || (b.MaxSize <= 0 && b.MaxAge <= 0) {
+		return a
+	}
+	out := *a
+	if b.MaxSize > 0 && (out.MaxSize <= 0 || b.MaxSize < out.MaxSize) {
+		out.MaxSize = b.MaxSize
+		out.PrunePolicy = b.PrunePolicy
+	}
+	if b.MaxAge > 0 && (out.MaxAge <= 0 || b.MaxAge < out.MaxAge) {
+		out.MaxAge = b.MaxAge
+	}
+	return &out
+}
+
+type cacheFile struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// pruneCacheDir removes the files of dir that haven't been used for longer
+// than maxAge and reduces dir to maxSize bytes according to policy. It returns
+// the number of bytes removed.
+func pruneCacheDir(dir string, maxSize int64, maxAge time.Duration, policy pb.CachePrunePolicy, now time.Time) (int64, error) {
+	var files []cacheFile
+	var total int64
+	if err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		files = append(files, cacheFile{path: p, size: fi.Size(), lastUsed: lastUsed(fi)})
+		total += fi.Size()
+		return nil
+	}); err != nil {
+		return 0, errors.Wrapf(err, "failed to walk cache mount")
+	}
+
+	var removed int64
+	remove := func(f cacheFile) error {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+		removeEmptyParents(dir, filepath.Dir(f.path))
+		removed += f.size
+		total -= f.size
+		return nil
+	}
+
+	if maxAge > 0 {
+		kept := files[:0]
+		for _, f := range files {
+			if now.Sub(f.lastUsed) > maxAge {
+				if err := remove(f); err != nil {
+					return removed, err
+				}
+				continue
+			}
+			kept = append(kept, f)
+		}
+		files = kept
+	}
+
+	if maxSize <= 0 || total <= maxSize {
+		return removed, nil
+	}
+
+	if policy == pb.CachePrunePolicy_RESET {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return removed, errors.WithStack(err)
+		}
+		for _, e := range entries {
+			if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+				return removed, errors.WithStack(err)
+			}
+		}
+		return removed + total, nil
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].lastUsed.Before(files[j].lastUsed)
+	})
+	for _, f := range files {
+		if total <= maxSize {
+			break
+		}
+		if err := remove(f); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// removeEmptyParents removes p and its parents up to root as long as they are
+// empty.
+func removeEmptyParents(root, p string) {
+	for p != root && len(p) > len(root) {
+		if err := os.Remove(p); err != nil {
+			return
+		}
+		p = filepath.Dir(p)
+	}
+}
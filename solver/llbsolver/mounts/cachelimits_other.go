@@ -0,0 +1,12 @@
+// +build !linux,!darwin,!freebsd,!openbsd
+
+package mounts
+
+import (
+	"os"
+	"time"
+)
+
+func lastUsed(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
@@ -0,0 +1,125 @@
+package mounts
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCacheFiles(t *testing.T, dir string, now time.Time, files map[string]time.Duration) {
+	for p, age := range files {
+		fp := filepath.Join(dir, p)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fp), 0755))
+		require.NoError(t, ioutil.WriteFile(fp, make([]byte, 100), 0644))
+		tm := now.Add(-age)
+		require.NoError(t, os.Chtimes(fp, tm, tm))
+	}
+}
+
+func listCacheFiles(t *testing.T, dir string) []string {
+	var out []string
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			out = append(out, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return out
+}
+
+func TestPruneCacheDirMaxAge(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+	writeCacheFiles(t, dir, now, map[string]time.Duration{
+		"a":         time.Minute,
+		"old/b":     3 * time.Hour,
+		"mixed/c":   3 * time.Hour,
+		"mixed/d":   time.Minute,
+		"old/sub/e": 5 * time.Hour,
+	})
+
+	removed, err := pruneCacheDir(dir, 0, 2*time.Hour, pb.CachePrunePolicy_TRIM, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(300), removed)
+	require.Equal(t, []string{"a", "mixed", "mixed/d"}, listCacheFiles(t, dir))
+}
+
+func TestPruneCacheDirTrim(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+	writeCacheFiles(t, dir, now, map[string]time.Duration{
+		"a":     4 * time.Minute,
+		"b":     3 * time.Minute,
+		"sub/c": 2 * time.Minute,
+		"sub/d": time.Minute,
+	})
+
+	removed, err := pruneCacheDir(dir, 250, 0, pb.CachePrunePolicy_TRIM, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), removed)
+	require.Equal(t, []string{"sub", "sub/c", "sub/d"}, listCacheFiles(t, dir))
+
+	// within limits
+	removed, err = pruneCacheDir(dir, 250, 0, pb.CachePrunePolicy_TRIM, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), removed)
+	require.Equal(t, []string{"sub", "sub/c", "sub/d"}, listCacheFiles(t, dir))
+}
+
+func TestPruneCacheDirReset(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+	writeCacheFiles(t, dir, now, map[string]time.Duration{
+		"a":     time.Minute,
+		"sub/b": time.Minute,
+	})
+
+	removed, err := pruneCacheDir(dir, 300, 0, pb.CachePrunePolicy_RESET, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), removed)
+	require.Len(t, listCacheFiles(t, dir), 3)
+
+	removed, err = pruneCacheDir(dir, 150, 0, pb.CachePrunePolicy_RESET, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), removed)
+	require.Len(t, listCacheFiles(t, dir), 0)
+	_, err = os.Stat(dir)
+	require.NoError(t, err)
+}
+
+func TestStrictestLimits(t *testing.T) {
+	t.Parallel()
+	size := &pb.CacheOpt{MaxSize: 1000, PrunePolicy: pb.CachePrunePolicy_RESET}
+	age := &pb.CacheOpt{MaxSize: 2000, MaxAge: int64(time.Hour)}
+
+	require.Equal(t, size, strictestLimits(size, nil))
+	require.Equal(t, size, strictestLimits(nil, size))
+	require.Equal(t, size, strictestLimits(size, &pb.CacheOpt{}))
+
+	l := strictestLimits(age, size)
+	require.Equal(t, int64(1000), l.MaxSize)
+	require.Equal(t, pb.CachePrunePolicy_RESET, l.PrunePolicy)
+	require.Equal(t, int64(time.Hour), l.MaxAge)
+
+	l = strictestLimits(size, age)
+	require.Equal(t, int64(1000), l.MaxSize)
+	require.Equal(t, pb.CachePrunePolicy_RESET, l.PrunePolicy)
+	require.Equal(t, int64(time.Hour), l.MaxAge)
+	require.Equal(t, int64(2000), age.MaxSize)
+}
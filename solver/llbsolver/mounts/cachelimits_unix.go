@@ -0,0 +1,22 @@
+// +build linux darwin freebsd openbsd
+
+package mounts
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/containerd/continuity/fs"
+)
+
+// lastUsed returns the latest of the access and modification times of fi.
+func lastUsed(fi os.FileInfo) time.Time {
+	t := fi.ModTime()
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		if at := fs.StatATimeAsTime(st); at.After(t) {
+			t = at
+		}
+	}
+	return t
+}
@@ -51,8 +51,9 @@ func (mm *MountManager) getRefCacheDir(ctx context.Context, ref cache.ImmutableR
 		cacheMounts:     mm.cacheMounts,
 		cm:              mm.cm,
 		globalCacheRefs: sharedCacheRefs,
-		name:            fmt.Sprintf("cached mount %s from %s", m.Dest, mm.managerName),
+		name:            fmt.Sprintf("cached mount %s from %s with id %q", m.Dest, mm.managerName, id),
 		session:         s,
+		limits:          m.CacheOpt,
 	}
 	return g.getRefCacheDir(ctx, ref, id, sharing)
 }
@@ -64,6 +65,8 @@ type cacheRefGetter struct {
 	globalCacheRefs *cacheRefs
 	name            string
 	session         session.Group
+	// limits are applied to the mount when it is released
+	limits *pb.CacheOpt
 }
 
 func (g *cacheRefGetter) getRefCacheDir(ctx context.Context, ref cache.ImmutableRef, id string, sharing pb.CacheSharingOpt) (mref cache.MutableRef, err error) {
@@ -76,11 +79,12 @@ func (g *cacheRefGetter) getRefCacheDir(ctx context.Context, ref cache.Immutable
 	defer mu.Unlock()
 
 	if ref, ok := g.cacheMounts[key]; ok {
+		ref.setLimits(g.limits)
 		return ref.clone(), nil
 	}
 	defer func() {
 		if err == nil {
-			share := &cacheRefShare{MutableRef: mref, refs: map[*cacheRef]struct{}{}}
+			share := &cacheRefShare{MutableRef: mref, refs: map[*cacheRef]struct{}{}, limits: g.limits}
 			g.cacheMounts[key] = share
 			mref = share.clone()
 		}
@@ -88,7 +92,7 @@ func (g *cacheRefGetter) getRefCacheDir(ctx context.Context, ref cache.Immutable
 
 	switch sharing {
 	case pb.CacheSharingOpt_SHARED:
-		return g.globalCacheRefs.get(key, func() (cache.MutableRef, error) {
+		return g.globalCacheRefs.get(key, g.limits, func() (cache.MutableRef, error) {
 			return g.getRefCacheDirNoCache(ctx, key, ref, id, false)
 		})
 	case pb.CacheSharingOpt_PRIVATE:
@@ -116,6 +120,13 @@ func (g *cacheRefGetter) getRefCacheDirNoCache(ctx context.Context, key string,
 		for _, si := range sis {
 			if mRef, err := g.cm.GetMutable(ctx, si.ID()); err == nil {
 				bklog.G(ctx).Debugf("reusing ref for cache dir: %s", mRef.ID())
+				if mRef.GetCacheMountID() == "" {
+					// records created before the ID was tracked
+					if err := mRef.SetCacheMountID(id); err != nil {
+						mRef.Release(context.TODO())
+						return nil, err
+					}
+				}
 				return mRef, nil
 			} else if errors.Is(err, cache.ErrLocked) {
 				locked = true
@@ -134,6 +145,14 @@ func (g *cacheRefGetter) getRefCacheDirNoCache(ctx context.Context, key string,
 			break
 		}
 	}
+	if ref == nil {
+		// new cache mounts start from the imported contents if there are any
+		if seed := cacheMountSeeds.get(g.cm, g.session, id); seed != nil {
+			bklog.G(ctx).Debugf("creating cache dir %s from imported ref %s", id, seed.ID())
+			defer seed.Release(context.TODO())
+			ref = seed
+		}
+	}
 	mRef, err := makeMutable(ref)
 	if err != nil {
 		return nil, err
@@ -144,6 +163,10 @@ func (g *cacheRefGetter) getRefCacheDirNoCache(ctx context.Context, key string,
 		mRef.Release(context.TODO())
 		return nil, err
 	}
+	if err := mRef.SetCacheMountID(id); err != nil {
+		mRef.Release(context.TODO())
+		return nil, err
+	}
 	return mRef, nil
 }
 
@@ -432,7 +455,7 @@ func CacheMountsLocker() sync.Locker {
 	return &sharedCacheRefs.mu
 }
 
-func (r *cacheRefs) get(key string, fn func() (cache.MutableRef, error)) (cache.MutableRef, error) {
+func (r *cacheRefs) get(key string, limits *pb.CacheOpt, fn func() (cache.MutableRef, error)) (cache.MutableRef, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -442,6 +465,7 @@ func (r *cacheRefs) get(key string, fn func() (cache.MutableRef, error)) (cache.
 
 	share, ok := r.shares[key]
 	if ok {
+		share.setLimits(limits)
 		return share.clone(), nil
 	}
 
@@ -450,17 +474,26 @@ func (r *cacheRefs) get(key string, fn func() (cache.MutableRef, error)) (cache.
 		return nil, err
 	}
 
-	share = &cacheRefShare{MutableRef: mref, main: r, key: key, refs: map[*cacheRef]struct{}{}}
+	share = &cacheRefShare{MutableRef: mref, main: r, key: key, refs: map[*cacheRef]struct{}{}, limits: limits}
 	r.shares[key] = share
 	return share.clone(), nil
 }
 
 type cacheRefShare struct {
 	cache.MutableRef
-	mu   sync.Mutex
-	refs map[*cacheRef]struct{}
-	main *cacheRefs
-	key  string
+	mu     sync.Mutex
+	refs   map[*cacheRef]struct{}
+	main   *cacheRefs
+	key    string
+	limits *pb.CacheOpt
+}
+
+// setLimits merges the limits of another user of the mount into the limits of
+// the share. The strictest limits of all users are applied on release.
+func (r *cacheRefShare) setLimits(limits *pb.CacheOpt) {
+	r.mu.Lock()
+	r.limits = strictestLimits(r.limits, limits)
+	r.mu.Unlock()
 }
 
 func (r *cacheRefShare) clone() cache.MutableRef {
@@ -478,6 +511,12 @@ func (r *cacheRefShare) release(ctx context.Context) error {
 	if r.main != nil {
 		delete(r.main.shares, r.key)
 	}
+	// shared mounts are pruned by the global share holding the actual ref
+	if _, ok := r.MutableRef.(*cacheRef); !ok {
+		if err := pruneCacheMount(ctx, r.MutableRef, r.limits); err != nil {
+			bklog.G(ctx).Warnf("failed to apply limits of cache mount %s: %v", r.MutableRef.ID(), err)
+		}
+	}
 	return r.MutableRef.Release(ctx)
 }
 
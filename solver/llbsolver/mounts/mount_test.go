@@ -19,6 +19,7 @@ import (
 	"github.com/containerd/containerd/snapshots/native"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/snapshot"
 	containerdsnapshot "github.com/moby/buildkit/snapshot/containerd"
 	"github.com/moby/buildkit/solver/pb"
@@ -382,3 +383,52 @@ func TestCacheMountSharedRefsDeadlock(t *testing.T) {
 		require.FailNow(t, "deadlock on releasing while getting new ref")
 	}
 }
+
+func TestCacheMountSeedSession(t *testing.T) {
+	t.Parallel()
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:     snapshotter,
+		snapshotterName: "native",
+	})
+	require.NoError(t, err)
+
+	defer cleanup()
+
+	active, err := co.manager.New(ctx, nil, nil)
+	require.NoError(t, err)
+	ref, err := active.Commit(ctx)
+	require.NoError(t, err)
+	defer ref.Release(context.TODO())
+
+	remove := SeedCacheMount(co.manager, "session1", "seeded", ref)
+
+	// builds in other sessions don't see the seed
+	require.Nil(t, cacheMountSeeds.get(co.manager, session.NewGroup("session2"), "seeded"))
+	require.Nil(t, cacheMountSeeds.get(co.manager, nil, "seeded"))
+
+	seed := cacheMountSeeds.get(co.manager, session.NewGroup("session2", "session1"), "seeded")
+	require.NotNil(t, seed)
+	require.Equal(t, ref.ID(), seed.ID())
+	require.NoError(t, seed.Release(context.TODO()))
+
+	g1 := newRefGetter(co.manager, sharedCacheRefs)
+	g1.session = session.NewGroup("session2")
+	mref, err := g1.getRefCacheDir(ctx, nil, "seeded", pb.CacheSharingOpt_PRIVATE)
+	require.NoError(t, err)
+	iref, err := mref.Commit(ctx)
+	require.NoError(t, err)
+	require.Nil(t, iref.Parent())
+	require.NoError(t, iref.Release(context.TODO()))
+
+	remove()
+	require.Nil(t, cacheMountSeeds.get(co.manager, session.NewGroup("session1"), "seeded"))
+}
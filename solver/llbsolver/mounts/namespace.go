@@ -0,0 +1,57 @@
+package mounts
+
+import (
+	"strings"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// cacheMountNamespacePrefix starts the IDs of the cache mounts of builds in a
+// cache namespace. Builds can't set IDs with this prefix themselves so the
+// cache mounts of a namespace can't be used from outside of it.
+const cacheMountNamespacePrefix = "namespace/"
+
+// NamespacedCacheMountID returns the ID of cache mount id in cache namespace
+// ns.
+func NamespacedCacheMountID(ns, id string) string {
+	if ns == "" {
+		return id
+	}
+	return cacheMountNamespacePrefix + ns + "/" + id
+}
+
+// CacheMountNamespace returns the cache namespace of the cache mount with the
+// namespaced ID id.
+func CacheMountNamespace(id string) string {
+	if !strings.HasPrefix(id, cacheMountNamespacePrefix) {
+		return ""
+	}
+	rest := strings.TrimPrefix(id, cacheMountNamespacePrefix)
+	i := strings.Index(rest, "/")
+	if i < 0 {
+		return ""
+	}
+	return rest[:i]
+}
+
+// ValidateCacheMountID returns an error if id can't be set by a build.
+func ValidateCacheMountID(id string) error {
+	if strings.HasPrefix(id, cacheMountNamespacePrefix) {
+		return errors.Errorf("cache mount ID %q uses reserved prefix %q", id, cacheMountNamespacePrefix)
+	}
+	return nil
+}
+
+// NamespaceCacheMount validates the ID of cache mount m and scopes it to
+// cache namespace ns. Other mounts are not changed.
+func NamespaceCacheMount(m *pb.Mount, ns string) error {
+	if m.MountType != pb.MountType_CACHE || m.CacheOpt == nil {
+		return nil
+	}
+	if err := ValidateCacheMountID(m.CacheOpt.ID); err != nil {
+		return err
+	}
+	m.CacheOpt.ID = NamespacedCacheMountID(ns, m.CacheOpt.ID)
+	return nil
+}
@@ -0,0 +1,29 @@
+package mounts
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceCacheMount(t *testing.T) {
+	m := &pb.Mount{Dest: "/cache", MountType: pb.MountType_CACHE, CacheOpt: &pb.CacheOpt{ID: "go/pkg"}}
+	require.NoError(t, NamespaceCacheMount(m, "team-a"))
+	require.Equal(t, "namespace/team-a/go/pkg", m.CacheOpt.ID)
+	require.Equal(t, "team-a", CacheMountNamespace(m.CacheOpt.ID))
+
+	m = &pb.Mount{Dest: "/cache", MountType: pb.MountType_CACHE, CacheOpt: &pb.CacheOpt{ID: "team-a/go/pkg"}}
+	require.NoError(t, NamespaceCacheMount(m, ""))
+	require.Equal(t, "team-a/go/pkg", m.CacheOpt.ID)
+	require.Equal(t, "", CacheMountNamespace(m.CacheOpt.ID))
+
+	// the IDs of namespaced cache mounts can't be set directly
+	for _, ns := range []string{"", "team-b"} {
+		m = &pb.Mount{Dest: "/cache", MountType: pb.MountType_CACHE, CacheOpt: &pb.CacheOpt{ID: "namespace/team-a/go/pkg"}}
+		require.Error(t, NamespaceCacheMount(m, ns))
+	}
+
+	m = &pb.Mount{Dest: "/src", MountType: pb.MountType_BIND}
+	require.NoError(t, NamespaceCacheMount(m, "team-a"))
+}
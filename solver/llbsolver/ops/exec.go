@@ -12,8 +12,11 @@ import (
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/executor"
+	resourcestypes "github.com/moby/buildkit/executor/resources/types"
 	"github.com/moby/buildkit/frontend/gateway"
+	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/llbsolver"
@@ -21,13 +24,14 @@ import (
 	"github.com/moby/buildkit/solver/llbsolver/mounts"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/bklog"
+	"github.com/moby/buildkit/util/fairqueue"
+	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/progress/logs"
 	utilsystem "github.com/moby/buildkit/util/system"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
-	"golang.org/x/sync/semaphore"
 )
 
 const execCacheType = "buildkit.exec.v0"
@@ -40,10 +44,10 @@ type execOp struct {
 	w           worker.Worker
 	platform    *pb.Platform
 	numInputs   int
-	parallelism *semaphore.Weighted
+	parallelism *fairqueue.Queue
 }
 
-func NewExecOp(v solver.Vertex, op *pb.Op_Exec, platform *pb.Platform, cm cache.Manager, parallelism *semaphore.Weighted, sm *session.Manager, exec executor.Executor, w worker.Worker) (solver.Op, error) {
+func NewExecOp(v solver.Vertex, op *pb.Op_Exec, platform *pb.Platform, cm cache.Manager, parallelism *fairqueue.Queue, sm *session.Manager, exec executor.Executor, w worker.Worker) (solver.Op, error) {
 	if err := llbsolver.ValidateOp(&pb.Op{Op: op}); err != nil {
 		return nil, err
 	}
@@ -313,6 +317,7 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 		Cwd:            e.op.Meta.Cwd,
 		User:           e.op.Meta.User,
 		Hostname:       e.op.Meta.Hostname,
+		CDIDevices:     e.op.Meta.CdiDevices,
 		ReadonlyRootFS: p.ReadonlyRootFS,
 		ExtraHosts:     extraHosts,
 		NetMode:        e.op.Network,
@@ -332,12 +337,15 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 	defer stdout.Close()
 	defer stderr.Close()
 
-	execErr := e.exec.Run(ctx, "", p.Root, p.Mounts, executor.ProcessInfo{
+	usage, execErr := e.exec.Run(ctx, "", p.Root, p.Mounts, executor.ProcessInfo{
 		Meta:   meta,
 		Stdin:  nil,
 		Stdout: stdout,
 		Stderr: stderr,
 	}, nil)
+	if usage != nil {
+		writeResourceUsage(ctx, usage)
+	}
 
 	for i, out := range p.OutputRefs {
 		if mutable, ok := out.Ref.(cache.MutableRef); ok {
@@ -355,6 +363,20 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 	return results, errors.Wrapf(execErr, "process %q did not complete successfully", strings.Join(e.op.Meta.Args, " "))
 }
 
+func writeResourceUsage(ctx context.Context, u *resourcestypes.Usage) {
+	pw, _, _ := progress.NewFromContext(ctx)
+	defer pw.Close()
+	pw.Write(identity.NewID(), client.VertexResources{
+		CPUNanos:       u.CPUNanos,
+		CPUUserNanos:   u.CPUUserNanos,
+		CPUSystemNanos: u.CPUSystemNanos,
+		MemoryPeak:     u.MemoryPeak,
+		IOReadBytes:    u.IOReadBytes,
+		IOWriteBytes:   u.IOWriteBytes,
+		PidsPeak:       u.PidsPeak,
+	})
+}
+
 func proxyEnvList(p *pb.ProxyEnv) []string {
 	out := []string{}
 	if v := p.HttpProxy; v != "" {
@@ -376,14 +398,5 @@ func proxyEnvList(p *pb.ProxyEnv) []string {
 }
 
 func (e *execOp) Acquire(ctx context.Context) (solver.ReleaseFunc, error) {
-	if e.parallelism == nil {
-		return func() {}, nil
-	}
-	err := e.parallelism.Acquire(ctx, 1)
-	if err != nil {
-		return nil, err
-	}
-	return func() {
-		e.parallelism.Release(1)
-	}, nil
+	return acquireParallelism(ctx, e.parallelism)
 }
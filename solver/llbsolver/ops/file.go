@@ -18,12 +18,12 @@ import (
 	"github.com/moby/buildkit/solver/llbsolver/file"
 	"github.com/moby/buildkit/solver/llbsolver/ops/fileoptypes"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/fairqueue"
 	"github.com/moby/buildkit/util/flightcontrol"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
 const fileCacheType = "buildkit.file.v0"
@@ -34,10 +34,10 @@ type fileOp struct {
 	w           worker.Worker
 	solver      *FileOpSolver
 	numInputs   int
-	parallelism *semaphore.Weighted
+	parallelism *fairqueue.Queue
 }
 
-func NewFileOp(v solver.Vertex, op *pb.Op_File, cm cache.Manager, parallelism *semaphore.Weighted, w worker.Worker) (solver.Op, error) {
+func NewFileOp(v solver.Vertex, op *pb.Op_File, cm cache.Manager, parallelism *fairqueue.Queue, w worker.Worker) (solver.Op, error) {
 	if err := llbsolver.ValidateOp(&pb.Op{Op: op}); err != nil {
 		return nil, err
 	}
@@ -182,16 +182,7 @@ func (f *fileOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 }
 
 func (f *fileOp) Acquire(ctx context.Context) (solver.ReleaseFunc, error) {
-	if f.parallelism == nil {
-		return func() {}, nil
-	}
-	err := f.parallelism.Acquire(ctx, 1)
-	if err != nil {
-		return nil, err
-	}
-	return func() {
-		f.parallelism.Release(1)
-	}, nil
+	return acquireParallelism(ctx, f.parallelism)
 }
 
 func addSelector(m map[int][]llbsolver.Selector, idx int, sel string, wildcard, followLinks bool, includePatterns, excludePatterns []string) {
@@ -0,0 +1,41 @@
+package ops
+
+import (
+	"context"
+	"time"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/fairqueue"
+	"github.com/moby/buildkit/util/progress"
+)
+
+const queuedStatusID = "waiting for a free worker slot"
+
+// acquireParallelism waits for a slot of the parallelism limit of the worker.
+// While the operation is queued behind operations of other builds this is
+// shown as a status of the vertex.
+func acquireParallelism(ctx context.Context, q *fairqueue.Queue) (solver.ReleaseFunc, error) {
+	if q == nil {
+		return func() {}, nil
+	}
+	si := solver.ScheduleInfoFromContext(ctx)
+
+	var pw progress.Writer
+	var st progress.Status
+	release, err := q.Acquire(ctx, fairqueue.Group{ID: si.ID, Priority: si.Priority, Weight: si.Weight}, func() {
+		pw, _, _ = progress.NewFromContext(ctx)
+		now := time.Now()
+		st.Started = &now
+		pw.Write(queuedStatusID, st)
+	})
+	if pw != nil {
+		now := time.Now()
+		st.Completed = &now
+		pw.Write(queuedStatusID, st)
+		pw.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
@@ -10,9 +10,9 @@ import (
 	"github.com/moby/buildkit/solver/llbsolver"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/fairqueue"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
-	"golang.org/x/sync/semaphore"
 )
 
 const sourceCacheType = "buildkit.source.v0"
@@ -26,10 +26,10 @@ type sourceOp struct {
 	sessM       *session.Manager
 	w           worker.Worker
 	vtx         solver.Vertex
-	parallelism *semaphore.Weighted
+	parallelism *fairqueue.Queue
 }
 
-func NewSourceOp(vtx solver.Vertex, op *pb.Op_Source, platform *pb.Platform, sm *source.Manager, parallelism *semaphore.Weighted, sessM *session.Manager, w worker.Worker) (solver.Op, error) {
+func NewSourceOp(vtx solver.Vertex, op *pb.Op_Source, platform *pb.Platform, sm *source.Manager, parallelism *fairqueue.Queue, sessM *session.Manager, w worker.Worker) (solver.Op, error) {
 	if err := llbsolver.ValidateOp(&pb.Op{Op: op}); err != nil {
 		return nil, err
 	}
@@ -104,14 +104,5 @@ func (s *sourceOp) Exec(ctx context.Context, g session.Group, _ []solver.Result)
 }
 
 func (s *sourceOp) Acquire(ctx context.Context) (solver.ReleaseFunc, error) {
-	if s.parallelism == nil {
-		return func() {}, nil
-	}
-	err := s.parallelism.Acquire(ctx, 1)
-	if err != nil {
-		return nil, err
-	}
-	return func() {
-		s.parallelism.Release(1)
-	}, nil
+	return acquireParallelism(ctx, s.parallelism)
 }
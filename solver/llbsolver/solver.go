@@ -17,6 +17,8 @@ import (
 	"github.com/moby/buildkit/frontend/gateway"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/sourcepolicy"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
 	"github.com/moby/buildkit/util/buildinfo"
 	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/entitlements"
@@ -27,12 +29,20 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-const keyEntitlements = "llb.entitlements"
+const (
+	keyEntitlements = "llb.entitlements"
+	keySourcePolicy = "llb.sourcepolicy"
+	keyNamespace    = "llb.cachenamespace"
+	keyCacheMounts  = "llb.cachemounts"
+)
 
 type ExporterRequest struct {
 	Exporter        exporter.ExporterInstance
 	CacheExporter   remotecache.Exporter
 	CacheExportMode solver.CacheExportMode
+	// CacheExportMounts exports the contents of the cache mounts used by the
+	// build with the cache
+	CacheExportMounts bool
 }
 
 // ResolveWorkerFunc returns default worker for the temporary default non-distributed use cases
@@ -50,7 +60,7 @@ type Solver struct {
 	entitlements              []string
 }
 
-func New(wc *worker.Controller, f map[string]frontend.Frontend, cache solver.CacheManager, resolveCI map[string]remotecache.ResolveCacheImporterFunc, gatewayForwarder *controlgateway.GatewayForwarder, sm *session.Manager, ents []string) (*Solver, error) {
+func New(wc *worker.Controller, f map[string]frontend.Frontend, cache solver.CacheManager, namespacedCache func(string) (solver.CacheManager, error), resolveCI map[string]remotecache.ResolveCacheImporterFunc, gatewayForwarder *controlgateway.GatewayForwarder, sm *session.Manager, ents []string) (*Solver, error) {
 	s := &Solver{
 		workerController:          wc,
 		resolveWorker:             defaultResolver(wc),
@@ -63,8 +73,9 @@ func New(wc *worker.Controller, f map[string]frontend.Frontend, cache solver.Cac
 	}
 
 	s.solver = solver.NewSolver(solver.SolverOpt{
-		ResolveOpFunc: s.resolver(),
-		DefaultCache:  cache,
+		ResolveOpFunc:   s.resolver(),
+		DefaultCache:    cache,
+		NamespacedCache: namespacedCache,
 	})
 	return s, nil
 }
@@ -91,7 +102,34 @@ func (s *Solver) Bridge(b solver.Builder) frontend.FrontendLLBBridge {
 	}
 }
 
-func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req frontend.SolveRequest, exp ExporterRequest, ent []entitlements.Entitlement) (*client.SolveResponse, error) {
+// solveTimeoutGrace is the time between the deadline of the steps of a solve
+// and the cancellation of the whole solve. Steps stopped at the deadline fail
+// with a VertexTimeoutError before the solve is canceled.
+const solveTimeoutGrace = time.Second
+
+func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req frontend.SolveRequest, exp ExporterRequest, ent []entitlements.Entitlement, srcPol *spb.Policy, cacheNamespace string, priority, weight int, timeout time.Duration) (_ *client.SolveResponse, err error) {
+	var deadline time.Time
+	if timeout > 0 {
+		// the timeout also bounds the frontend and the export, not only the
+		// steps of the build
+		deadline = time.Now().Add(timeout)
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(solveTimeoutGrace))
+		defer cancel()
+		defer func() {
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = errors.Wrapf(err, "build exceeded timeout of %s", timeout)
+			}
+		}()
+	}
+
+	if err := sourcepolicy.Validate(srcPol); err != nil {
+		return nil, errors.Wrap(err, "invalid source policy")
+	}
+	if err := solver.ValidateCacheNamespace(cacheNamespace); err != nil {
+		return nil, err
+	}
+
 	j, err := s.solver.NewJob(id)
 	if err != nil {
 		return nil, err
@@ -105,7 +143,31 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 	}
 	j.SetValue(keyEntitlements, set)
 
+	if srcPol != nil {
+		j.SetValue(keySourcePolicy, srcPol)
+	}
+
+	if cacheNamespace != "" {
+		j.SetValue(keyNamespace, cacheNamespace)
+	}
+
+	var usedCacheMounts *cacheMountSet
+	if exp.CacheExporter != nil && exp.CacheExportMounts {
+		usedCacheMounts = &cacheMountSet{}
+		j.SetValue(keyCacheMounts, usedCacheMounts)
+	}
+
 	j.SessionID = sessionID
+	j.CacheNamespace = cacheNamespace
+	j.Priority = priority
+	j.Weight = weight
+	j.Deadline = deadline
+
+	releaseSeeds, err := s.importCacheMounts(ctx, j, req.CacheImports)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseSeeds()
 
 	var res *frontend.Result
 	if s.gatewayForwarder != nil && req.Definition == nil && req.Frontend == "" {
@@ -256,6 +318,17 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 				return prepareDone(err)
 			}
 			prepareDone(nil)
+			if usedCacheMounts != nil {
+				w, err := s.resolveWorker()
+				if err != nil {
+					return err
+				}
+				release, err := exportCacheMounts(ctx, w, e, usedCacheMounts, j.CacheNamespace, g)
+				if err != nil {
+					return err
+				}
+				defer release()
+			}
 			cacheExporterResponse, err = e.Finalize(ctx)
 			return err
 		}); err != nil {
@@ -295,7 +368,7 @@ func inlineCache(ctx context.Context, e remotecache.Exporter, res solver.CachedR
 			return nil, errors.Errorf("invalid reference: %T", res.Sys())
 		}
 
-		remote, err := workerRef.GetRemote(ctx, true, compression.Default, false, g)
+		remote, err := workerRef.GetRemote(ctx, true, compression.New(compression.Default), g)
 		if err != nil || remote == nil {
 			return nil, nil
 		}
@@ -327,6 +400,12 @@ func (s *Solver) Status(ctx context.Context, id string, statusChan chan *client.
 	return j.Status(ctx, statusChan)
 }
 
+// CancelVertex cancels the vertex with digest dgst and its dependants in job
+// id.
+func (s *Solver) CancelVertex(id string, dgst digest.Digest) error {
+	return s.solver.CancelVertex(id, dgst)
+}
+
 func defaultResolver(wc *worker.Controller) ResolveWorkerFunc {
 	return func() (worker.Worker, error) {
 		return wc.GetDefault()
@@ -416,6 +495,9 @@ func supportedEntitlements(ents []string) []entitlements.Entitlement {
 		if e == string(entitlements.EntitlementSecurityInsecure) {
 			out = append(out, entitlements.EntitlementSecurityInsecure)
 		}
+		if e == string(entitlements.EntitlementDevice) {
+			out = append(out, entitlements.EntitlementDevice)
+		}
 	}
 	return out
 }
@@ -437,3 +519,36 @@ func loadEntitlements(b solver.Builder) (entitlements.Set, error) {
 	}
 	return ent, nil
 }
+
+func loadSourcePolicy(b solver.Builder) (*sourcepolicy.Engine, error) {
+	var pols []*spb.Policy
+	err := b.EachValue(context.TODO(), keySourcePolicy, func(v interface{}) error {
+		p, ok := v.(*spb.Policy)
+		if !ok {
+			return errors.Errorf("invalid source policy %T", v)
+		}
+		pols = append(pols, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sourcepolicy.NewEngine(pols), nil
+}
+
+func loadCacheNamespace(b solver.Builder) (string, error) {
+	var ns string
+	err := b.EachValue(context.TODO(), keyNamespace, func(v interface{}) error {
+		n, ok := v.(string)
+		if !ok {
+			return errors.Errorf("invalid cache namespace %T", v)
+		}
+		// all jobs sharing a vertex are in the same namespace
+		ns = n
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return ns, nil
+}
@@ -1,11 +1,14 @@
 package llbsolver
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/llbsolver/mounts"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/util/entitlements"
@@ -68,6 +71,21 @@ func WithCacheSources(cms []solver.CacheManager) LoadOpt {
 	}
 }
 
+// WithCacheNamespace scopes the IDs of cache mounts to the cache namespace
+// ns so that builds in different namespaces don't share cache mounts.
+func WithCacheNamespace(ns string) LoadOpt {
+	return func(op *pb.Op, _ *pb.OpMetadata, _ *solver.VertexOptions) error {
+		if op, ok := op.Op.(*pb.Op_Exec); ok {
+			for _, m := range op.Exec.GetMounts() {
+				if err := mounts.NamespaceCacheMount(m, ns); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
 func NormalizeRuntimePlatforms() LoadOpt {
 	var defaultPlatform *pb.Platform
 	return func(op *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
@@ -110,6 +128,12 @@ func ValidateEntitlements(ent entitlements.Set) LoadOpt {
 					return errors.Errorf("%s is not allowed", entitlements.EntitlementSecurityInsecure)
 				}
 			}
+
+			if len(op.Exec.Meta.GetCdiDevices()) > 0 {
+				if !ent.Allowed(entitlements.EntitlementDevice) {
+					return errors.Errorf("%s is not allowed", entitlements.EntitlementDevice)
+				}
+			}
 		}
 		return nil
 	}
@@ -143,9 +167,15 @@ func (dpc *detectPrunedCacheID) Load(op *pb.Op, md *pb.OpMetadata, opt *solver.V
 	return nil
 }
 
-func Load(def *pb.Definition, opts ...LoadOpt) (solver.Edge, error) {
-	return loadLLB(def, func(dgst digest.Digest, pbOp *pb.Op, load func(digest.Digest) (solver.Vertex, error)) (solver.Vertex, error) {
-		opMetadata := def.Metadata[dgst]
+// SourcePolicyEvaluator evaluates source ops against a source policy. It
+// returns true if the op was mutated.
+type SourcePolicyEvaluator interface {
+	Evaluate(ctx context.Context, op *pb.Op) (bool, error)
+}
+
+func Load(ctx context.Context, def *pb.Definition, polEngine SourcePolicyEvaluator, opts ...LoadOpt) (solver.Edge, error) {
+	return loadLLB(ctx, def, polEngine, func(dgst, origDgst digest.Digest, pbOp *pb.Op, load func(digest.Digest) (solver.Vertex, error)) (solver.Vertex, error) {
+		opMetadata := def.Metadata[origDgst]
 		vtx, err := newVertex(dgst, pbOp, &opMetadata, load, opts...)
 		if err != nil {
 			return nil, err
@@ -162,6 +192,7 @@ func newVertex(dgst digest.Digest, op *pb.Op, opMeta *pb.OpMetadata, load func(d
 		if opMeta.ExportCache != nil {
 			opt.ExportCache = &opMeta.ExportCache.Value
 		}
+		opt.Timeout = time.Duration(opMeta.Timeout)
 	}
 	for _, fn := range opts {
 		if err := fn(op, opMeta, &opt); err != nil {
@@ -182,12 +213,17 @@ func newVertex(dgst digest.Digest, op *pb.Op, opMeta *pb.OpMetadata, load func(d
 
 // loadLLB loads LLB.
 // fn is executed sequentially.
-func loadLLB(def *pb.Definition, fn func(digest.Digest, *pb.Op, func(digest.Digest) (solver.Vertex, error)) (solver.Vertex, error)) (solver.Edge, error) {
+// If polEngine is set, source ops are evaluated against the source policy
+// first. Ops mutated by the policy get a new digest; fn receives both the new
+// and the original digest so that metadata can be looked up.
+func loadLLB(ctx context.Context, def *pb.Definition, polEngine SourcePolicyEvaluator, fn func(digest.Digest, digest.Digest, *pb.Op, func(digest.Digest) (solver.Vertex, error)) (solver.Vertex, error)) (solver.Edge, error) {
 	if len(def.Def) == 0 {
 		return solver.Edge{}, errors.New("invalid empty definition")
 	}
 
 	allOps := make(map[digest.Digest]*pb.Op)
+	mutatedDigests := make(map[digest.Digest]digest.Digest) // key: original, val: mutated
+	origDigests := make(map[digest.Digest]digest.Digest)    // key: mutated, val: original
 
 	var dgst digest.Digest
 
@@ -197,9 +233,34 @@ func loadLLB(def *pb.Definition, fn func(digest.Digest, *pb.Op, func(digest.Dige
 			return solver.Edge{}, errors.Wrap(err, "failed to parse llb proto op")
 		}
 		dgst = digest.FromBytes(dt)
+		if polEngine != nil {
+			mutated, err := polEngine.Evaluate(ctx, &op)
+			if err != nil {
+				return solver.Edge{}, errors.Wrap(err, "error evaluating the source policy")
+			}
+			if mutated {
+				dtMutated, err := op.Marshal()
+				if err != nil {
+					return solver.Edge{}, err
+				}
+				mutatedDgst := digest.FromBytes(dtMutated)
+				mutatedDigests[dgst] = mutatedDgst
+				origDigests[mutatedDgst] = dgst
+				dgst = mutatedDgst
+			}
+		}
 		allOps[dgst] = &op
 	}
 
+	// rewrite the inputs pointing to ops mutated by the source policy
+	for _, op := range allOps {
+		for _, inp := range op.Inputs {
+			if mutatedDgst, ok := mutatedDigests[inp.Digest]; ok {
+				inp.Digest = mutatedDgst
+			}
+		}
+	}
+
 	if len(allOps) < 2 {
 		return solver.Edge{}, errors.Errorf("invalid LLB with %d vertexes", len(allOps))
 	}
@@ -227,7 +288,12 @@ func loadLLB(def *pb.Definition, fn func(digest.Digest, *pb.Op, func(digest.Dige
 			return nil, err
 		}
 
-		v, err := fn(dgst, op, rec)
+		origDgst, ok := origDigests[dgst]
+		if !ok {
+			origDgst = dgst
+		}
+
+		v, err := fn(dgst, origDgst, op, rec)
 		if err != nil {
 			return nil, err
 		}
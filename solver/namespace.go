@@ -0,0 +1,125 @@
+package solver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+var cacheNamespaceRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateCacheNamespace returns an error if ns can't be used as a cache
+// namespace. An empty namespace is the default shared one.
+func ValidateCacheNamespace(ns string) error {
+	if ns == "" {
+		return nil
+	}
+	if !cacheNamespaceRe.MatchString(ns) {
+		return errors.Errorf("invalid cache namespace %q", ns)
+	}
+	return nil
+}
+
+// namespacedDigest returns the digest of a vertex that is loaded in a cache
+// namespace so that vertexes of different namespaces don't share state.
+func namespacedDigest(dgst digest.Digest, ns string) digest.Digest {
+	if ns == "" {
+		return dgst
+	}
+	return digest.FromBytes([]byte(fmt.Sprintf("%s-namespace-%s", dgst, ns)))
+}
+
+const namespacePrefix = "namespace/"
+
+// NewNamespacedCacheKeyStorage returns a CacheKeyStorage that stores the
+// keys of namespace ns in s, isolated from the keys of other namespaces. The
+// empty namespace uses the keys of s that are not in any namespace.
+// Results are shared with s so releasing a result removes it from all
+// namespaces.
+func NewNamespacedCacheKeyStorage(s CacheKeyStorage, ns string) CacheKeyStorage {
+	prefix := ""
+	if ns != "" {
+		prefix = namespacePrefix + ns + "/"
+	}
+	return &namespacedStorage{s: s, prefix: prefix}
+}
+
+type namespacedStorage struct {
+	s      CacheKeyStorage
+	prefix string
+}
+
+func (ns *namespacedStorage) Exists(id string) bool {
+	return ns.s.Exists(ns.prefix + id)
+}
+
+func (ns *namespacedStorage) Walk(fn func(id string) error) error {
+	return ns.s.Walk(ns.filter(fn))
+}
+
+func (ns *namespacedStorage) WalkResults(id string, fn func(CacheResult) error) error {
+	return ns.s.WalkResults(ns.prefix+id, fn)
+}
+
+func (ns *namespacedStorage) Load(id string, resultID string) (CacheResult, error) {
+	return ns.s.Load(ns.prefix+id, resultID)
+}
+
+func (ns *namespacedStorage) AddResult(id string, res CacheResult) error {
+	return ns.s.AddResult(ns.prefix+id, res)
+}
+
+func (ns *namespacedStorage) Release(resultID string) error {
+	return ns.s.Release(resultID)
+}
+
+func (ns *namespacedStorage) WalkIDsByResult(resultID string, fn func(string) error) error {
+	return ns.s.WalkIDsByResult(resultID, ns.filter(fn))
+}
+
+func (ns *namespacedStorage) AddLink(id string, link CacheInfoLink, target string) error {
+	return ns.s.AddLink(ns.prefix+id, link, ns.prefix+target)
+}
+
+func (ns *namespacedStorage) WalkLinks(id string, link CacheInfoLink, fn func(id string) error) error {
+	return ns.s.WalkLinks(ns.prefix+id, link, ns.filter(fn))
+}
+
+func (ns *namespacedStorage) HasLink(id string, link CacheInfoLink, target string) bool {
+	return ns.s.HasLink(ns.prefix+id, link, ns.prefix+target)
+}
+
+func (ns *namespacedStorage) WalkBacklinks(id string, fn func(id string, link CacheInfoLink) error) error {
+	return ns.s.WalkBacklinks(ns.prefix+id, func(id string, link CacheInfoLink) error {
+		id, ok := ns.trim(id)
+		if !ok {
+			return nil
+		}
+		return fn(id, link)
+	})
+}
+
+func (ns *namespacedStorage) filter(fn func(id string) error) func(id string) error {
+	return func(id string) error {
+		id, ok := ns.trim(id)
+		if !ok {
+			return nil
+		}
+		return fn(id)
+	}
+}
+
+// trim returns the id of a key of the namespace without the prefix and false
+// if the key belongs to another namespace.
+func (ns *namespacedStorage) trim(id string) (string, bool) {
+	if ns.prefix == "" {
+		return id, !strings.HasPrefix(id, namespacePrefix)
+	}
+	if !strings.HasPrefix(id, ns.prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(id, ns.prefix), true
+}
@@ -44,11 +44,13 @@ const (
 	CapExecMountBindReadWriteNoOuput apicaps.CapID = "exec.mount.bind.readwrite-nooutput"
 	CapExecMountCache                apicaps.CapID = "exec.mount.cache"
 	CapExecMountCacheSharing         apicaps.CapID = "exec.mount.cache.sharing"
+	CapExecMountCacheLimits          apicaps.CapID = "exec.mount.cache.limits"
 	CapExecMountSelector             apicaps.CapID = "exec.mount.selector"
 	CapExecMountTmpfs                apicaps.CapID = "exec.mount.tmpfs"
 	CapExecMountSecret               apicaps.CapID = "exec.mount.secret"
 	CapExecMountSSH                  apicaps.CapID = "exec.mount.ssh"
 	CapExecCgroupsMounted            apicaps.CapID = "exec.cgroup"
+	CapExecMetaCDI                   apicaps.CapID = "exec.meta.cdi"
 
 	CapExecMetaSecurityDeviceWhitelistV1 apicaps.CapID = "exec.meta.security.devices.v1"
 
@@ -62,6 +64,7 @@ const (
 	CapMetaIgnoreCache apicaps.CapID = "meta.ignorecache"
 	CapMetaDescription apicaps.CapID = "meta.description"
 	CapMetaExportCache apicaps.CapID = "meta.exportcache"
+	CapMetaTimeout     apicaps.CapID = "meta.timeout"
 
 	CapRemoteCacheGHA apicaps.CapID = "cache.gha"
 )
@@ -236,6 +239,12 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaCDI,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapExecMountBind,
 		Enabled: true,
@@ -260,6 +269,12 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMountCacheLimits,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapExecMountSelector,
 		Enabled: true,
@@ -342,6 +357,12 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapMetaTimeout,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapRemoteCacheGHA,
 		Enabled: true,
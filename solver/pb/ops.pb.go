@@ -117,6 +117,34 @@ func (MountType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{2}
 }
 
+// CachePrunePolicy defines how a cache mount exceeding its size limit is pruned
+type CachePrunePolicy int32
+
+const (
+	// TRIM removes the least recently used files until the mount fits the limit
+	CachePrunePolicy_TRIM CachePrunePolicy = 0
+	// RESET removes all the contents of the mount
+	CachePrunePolicy_RESET CachePrunePolicy = 1
+)
+
+var CachePrunePolicy_name = map[int32]string{
+	0: "TRIM",
+	1: "RESET",
+}
+
+var CachePrunePolicy_value = map[string]int32{
+	"TRIM":  0,
+	"RESET": 1,
+}
+
+func (x CachePrunePolicy) String() string {
+	return proto.EnumName(CachePrunePolicy_name, int32(x))
+}
+
+func (CachePrunePolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{3}
+}
+
 // CacheSharingOpt defines different sharing modes for cache mount
 type CacheSharingOpt int32
 
@@ -146,7 +174,7 @@ func (x CacheSharingOpt) String() string {
 }
 
 func (CacheSharingOpt) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{3}
+	return fileDescriptor_8de16154b2733812, []int{4}
 }
 
 // Op represents a vertex of the LLB DAG.
@@ -461,13 +489,14 @@ func (m *ExecOp) GetSecurity() SecurityMode {
 // Meta is unrelated to LLB metadata.
 // FIXME: rename (ExecContext? ExecArgs?)
 type Meta struct {
-	Args       []string  `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"`
-	Env        []string  `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`
-	Cwd        string    `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
-	User       string    `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
-	ProxyEnv   *ProxyEnv `protobuf:"bytes,5,opt,name=proxy_env,json=proxyEnv,proto3" json:"proxy_env,omitempty"`
-	ExtraHosts []*HostIP `protobuf:"bytes,6,rep,name=extraHosts,proto3" json:"extraHosts,omitempty"`
-	Hostname   string    `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Args       []string     `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"`
+	Env        []string     `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`
+	Cwd        string       `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	User       string       `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	ProxyEnv   *ProxyEnv    `protobuf:"bytes,5,opt,name=proxy_env,json=proxyEnv,proto3" json:"proxy_env,omitempty"`
+	ExtraHosts []*HostIP    `protobuf:"bytes,6,rep,name=extraHosts,proto3" json:"extraHosts,omitempty"`
+	Hostname   string       `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	CdiDevices []*CDIDevice `protobuf:"bytes,8,rep,name=cdiDevices,proto3" json:"cdiDevices,omitempty"`
 }
 
 func (m *Meta) Reset()         { *m = Meta{} }
@@ -548,6 +577,13 @@ func (m *Meta) GetHostname() string {
 	return ""
 }
 
+func (m *Meta) GetCdiDevices() []*CDIDevice {
+	if m != nil {
+		return m.CdiDevices
+	}
+	return nil
+}
+
 // Mount specifies how to mount an input Op as a filesystem.
 type Mount struct {
 	Input     InputIndex  `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
@@ -653,6 +689,14 @@ type CacheOpt struct {
 	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	// Sharing is the sharing mode for the mount
 	Sharing CacheSharingOpt `protobuf:"varint,2,opt,name=sharing,proto3,enum=pb.CacheSharingOpt" json:"sharing,omitempty"`
+	// MaxSize is the size in bytes the mount is limited to when it is
+	// released. 0 means no limit.
+	MaxSize int64 `protobuf:"varint,3,opt,name=maxSize,proto3" json:"maxSize,omitempty"`
+	// MaxAge in nanoseconds removes files that haven't been used for longer
+	// when the mount is released. 0 means no limit.
+	MaxAge int64 `protobuf:"varint,4,opt,name=maxAge,proto3" json:"maxAge,omitempty"`
+	// PrunePolicy defines how the mount is reduced when it exceeds MaxSize
+	PrunePolicy CachePrunePolicy `protobuf:"varint,5,opt,name=prunePolicy,proto3,enum=pb.CachePrunePolicy" json:"prunePolicy,omitempty"`
 }
 
 func (m *CacheOpt) Reset()         { *m = CacheOpt{} }
@@ -698,6 +742,27 @@ func (m *CacheOpt) GetSharing() CacheSharingOpt {
 	return CacheSharingOpt_SHARED
 }
 
+func (m *CacheOpt) GetMaxSize() int64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *CacheOpt) GetMaxAge() int64 {
+	if m != nil {
+		return m.MaxAge
+	}
+	return 0
+}
+
+func (m *CacheOpt) GetPrunePolicy() CachePrunePolicy {
+	if m != nil {
+		return m.PrunePolicy
+	}
+	return CachePrunePolicy_TRIM
+}
+
 // SecretOpt defines options describing secret mounts
 type SecretOpt struct {
 	// ID of secret. Used for quering the value.
@@ -1011,6 +1076,9 @@ type OpMetadata struct {
 	// WorkerConstraint worker_constraint = 3;
 	ExportCache *ExportCache                                         `protobuf:"bytes,4,opt,name=export_cache,json=exportCache,proto3" json:"export_cache,omitempty"`
 	Caps        map[github_com_moby_buildkit_util_apicaps.CapID]bool `protobuf:"bytes,5,rep,name=caps,proto3,castkey=github.com/moby/buildkit/util/apicaps.CapID" json:"caps" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// timeout is the maximum time in nanoseconds the Op is allowed to run.
+	// The Op fails when the timeout is exceeded. 0 means no timeout.
+	Timeout int64 `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
 }
 
 func (m *OpMetadata) Reset()         { *m = OpMetadata{} }
@@ -1070,6 +1138,13 @@ func (m *OpMetadata) GetCaps() map[github_com_moby_buildkit_util_apicaps.CapID]b
 	return nil
 }
 
+func (m *OpMetadata) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 // Source is a source mapping description for a file
 type Source struct {
 	Locations map[string]*Locations `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -1626,6 +1701,57 @@ func (m *HostIP) GetIP() string {
 	return ""
 }
 
+// CDIDevice specifies a CDI device to expose to the exec process.
+type CDIDevice struct {
+	// Fully qualified (kind=name) or unqualified device name
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Optional devices are skipped if not available on the host
+	Optional bool `protobuf:"varint,2,opt,name=Optional,proto3" json:"Optional,omitempty"`
+}
+
+func (m *CDIDevice) Reset()         { *m = CDIDevice{} }
+func (m *CDIDevice) String() string { return proto.CompactTextString(m) }
+func (*CDIDevice) ProtoMessage()    {}
+func (*CDIDevice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{24}
+}
+func (m *CDIDevice) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CDIDevice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *CDIDevice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CDIDevice.Merge(m, src)
+}
+func (m *CDIDevice) XXX_Size() int {
+	return m.Size()
+}
+func (m *CDIDevice) XXX_DiscardUnknown() {
+	xxx_messageInfo_CDIDevice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CDIDevice proto.InternalMessageInfo
+
+func (m *CDIDevice) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CDIDevice) GetOptional() bool {
+	if m != nil {
+		return m.Optional
+	}
+	return false
+}
+
 type FileOp struct {
 	Actions []*FileAction `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
 }
@@ -1634,7 +1760,7 @@ func (m *FileOp) Reset()         { *m = FileOp{} }
 func (m *FileOp) String() string { return proto.CompactTextString(m) }
 func (*FileOp) ProtoMessage()    {}
 func (*FileOp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{24}
+	return fileDescriptor_8de16154b2733812, []int{25}
 }
 func (m *FileOp) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1682,7 +1808,7 @@ func (m *FileAction) Reset()         { *m = FileAction{} }
 func (m *FileAction) String() string { return proto.CompactTextString(m) }
 func (*FileAction) ProtoMessage()    {}
 func (*FileAction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{25}
+	return fileDescriptor_8de16154b2733812, []int{26}
 }
 func (m *FileAction) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1809,7 +1935,7 @@ func (m *FileActionCopy) Reset()         { *m = FileActionCopy{} }
 func (m *FileActionCopy) String() string { return proto.CompactTextString(m) }
 func (*FileActionCopy) ProtoMessage()    {}
 func (*FileActionCopy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{26}
+	return fileDescriptor_8de16154b2733812, []int{27}
 }
 func (m *FileActionCopy) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1942,7 +2068,7 @@ func (m *FileActionMkFile) Reset()         { *m = FileActionMkFile{} }
 func (m *FileActionMkFile) String() string { return proto.CompactTextString(m) }
 func (*FileActionMkFile) ProtoMessage()    {}
 func (*FileActionMkFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{27}
+	return fileDescriptor_8de16154b2733812, []int{28}
 }
 func (m *FileActionMkFile) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2019,7 +2145,7 @@ func (m *FileActionMkDir) Reset()         { *m = FileActionMkDir{} }
 func (m *FileActionMkDir) String() string { return proto.CompactTextString(m) }
 func (*FileActionMkDir) ProtoMessage()    {}
 func (*FileActionMkDir) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{28}
+	return fileDescriptor_8de16154b2733812, []int{29}
 }
 func (m *FileActionMkDir) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2092,7 +2218,7 @@ func (m *FileActionRm) Reset()         { *m = FileActionRm{} }
 func (m *FileActionRm) String() string { return proto.CompactTextString(m) }
 func (*FileActionRm) ProtoMessage()    {}
 func (*FileActionRm) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{29}
+	return fileDescriptor_8de16154b2733812, []int{30}
 }
 func (m *FileActionRm) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2147,7 +2273,7 @@ func (m *ChownOpt) Reset()         { *m = ChownOpt{} }
 func (m *ChownOpt) String() string { return proto.CompactTextString(m) }
 func (*ChownOpt) ProtoMessage()    {}
 func (*ChownOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{30}
+	return fileDescriptor_8de16154b2733812, []int{31}
 }
 func (m *ChownOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2197,7 +2323,7 @@ func (m *UserOpt) Reset()         { *m = UserOpt{} }
 func (m *UserOpt) String() string { return proto.CompactTextString(m) }
 func (*UserOpt) ProtoMessage()    {}
 func (*UserOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{31}
+	return fileDescriptor_8de16154b2733812, []int{32}
 }
 func (m *UserOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2276,7 +2402,7 @@ func (m *NamedUserOpt) Reset()         { *m = NamedUserOpt{} }
 func (m *NamedUserOpt) String() string { return proto.CompactTextString(m) }
 func (*NamedUserOpt) ProtoMessage()    {}
 func (*NamedUserOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{32}
+	return fileDescriptor_8de16154b2733812, []int{33}
 }
 func (m *NamedUserOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2312,6 +2438,7 @@ func init() {
 	proto.RegisterEnum("pb.NetMode", NetMode_name, NetMode_value)
 	proto.RegisterEnum("pb.SecurityMode", SecurityMode_name, SecurityMode_value)
 	proto.RegisterEnum("pb.MountType", MountType_name, MountType_value)
+	proto.RegisterEnum("pb.CachePrunePolicy", CachePrunePolicy_name, CachePrunePolicy_value)
 	proto.RegisterEnum("pb.CacheSharingOpt", CacheSharingOpt_name, CacheSharingOpt_value)
 	proto.RegisterType((*Op)(nil), "pb.Op")
 	proto.RegisterType((*Platform)(nil), "pb.Platform")
@@ -2344,6 +2471,7 @@ func init() {
 	proto.RegisterType((*Definition)(nil), "pb.Definition")
 	proto.RegisterMapType((map[github_com_opencontainers_go_digest.Digest]OpMetadata)(nil), "pb.Definition.MetadataEntry")
 	proto.RegisterType((*HostIP)(nil), "pb.HostIP")
+	proto.RegisterType((*CDIDevice)(nil), "pb.CDIDevice")
 	proto.RegisterType((*FileOp)(nil), "pb.FileOp")
 	proto.RegisterType((*FileAction)(nil), "pb.FileAction")
 	proto.RegisterType((*FileActionCopy)(nil), "pb.FileActionCopy")
@@ -2358,149 +2486,157 @@ func init() {
 func init() { proto.RegisterFile("ops.proto", fileDescriptor_8de16154b2733812) }
 
 var fileDescriptor_8de16154b2733812 = []byte{
-	// 2267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x6e, 0x1c, 0xc7,
-	0xf1, 0xe7, 0x7e, 0xef, 0xd6, 0x2e, 0xa9, 0xfd, 0xb7, 0x65, 0x7b, 0xcd, 0xbf, 0x42, 0xd2, 0x63,
-	0xc7, 0xa0, 0x28, 0x69, 0x09, 0xd0, 0x80, 0x65, 0x18, 0x41, 0x10, 0xee, 0x87, 0xc0, 0xb5, 0x25,
-	0x2e, 0xd1, 0x2b, 0xc9, 0xb9, 0x09, 0xc3, 0xd9, 0x26, 0x39, 0xe0, 0xec, 0xf4, 0xa0, 0xa7, 0x57,
-	0xe2, 0x5e, 0x72, 0xf0, 0x13, 0x18, 0x08, 0x90, 0x5b, 0x12, 0xe4, 0x1d, 0x72, 0xcd, 0x31, 0x80,
-	0x8f, 0x3e, 0xe4, 0x60, 0xe4, 0xe0, 0x04, 0xd2, 0x3d, 0x4f, 0x90, 0x00, 0x41, 0x55, 0xf7, 0x7c,
-	0x2c, 0x45, 0x41, 0x12, 0x12, 0xe4, 0x34, 0xdd, 0x55, 0xbf, 0xaa, 0xae, 0xae, 0xaa, 0xae, 0xae,
-	0x1e, 0x68, 0xc8, 0x28, 0xee, 0x46, 0x4a, 0x6a, 0xc9, 0x8a, 0xd1, 0xf1, 0xfa, 0x9d, 0x53, 0x5f,
-	0x9f, 0xcd, 0x8f, 0xbb, 0x9e, 0x9c, 0xed, 0x9e, 0xca, 0x53, 0xb9, 0x4b, 0xac, 0xe3, 0xf9, 0x09,
-	0xcd, 0x68, 0x42, 0x23, 0x23, 0xe2, 0xfc, 0xa1, 0x08, 0xc5, 0x71, 0xc4, 0x3e, 0x84, 0xaa, 0x1f,
-	0x46, 0x73, 0x1d, 0x77, 0x0a, 0x5b, 0xa5, 0xed, 0xe6, 0x5e, 0xa3, 0x1b, 0x1d, 0x77, 0x47, 0x48,
-	0xe1, 0x96, 0xc1, 0xb6, 0xa0, 0x2c, 0x2e, 0x84, 0xd7, 0x29, 0x6e, 0x15, 0xb6, 0x9b, 0x7b, 0x80,
-	0x80, 0xe1, 0x85, 0xf0, 0xc6, 0xd1, 0xc1, 0x0a, 0x27, 0x0e, 0xfb, 0x04, 0xaa, 0xb1, 0x9c, 0x2b,
-	0x4f, 0x74, 0x4a, 0x84, 0x69, 0x21, 0x66, 0x42, 0x14, 0x42, 0x59, 0x2e, 0x6a, 0x3a, 0xf1, 0x03,
-	0xd1, 0x29, 0x67, 0x9a, 0xee, 0xf9, 0x81, 0xc1, 0x10, 0x87, 0x7d, 0x04, 0x95, 0xe3, 0xb9, 0x1f,
-	0x4c, 0x3b, 0x15, 0x82, 0x34, 0x11, 0xd2, 0x43, 0x02, 0x61, 0x0c, 0x8f, 0x6d, 0x43, 0x3d, 0x0a,
-	0x5c, 0x7d, 0x22, 0xd5, 0xac, 0x03, 0xd9, 0x82, 0x47, 0x96, 0xc6, 0x53, 0x2e, 0xbb, 0x0b, 0x4d,
-	0x4f, 0x86, 0xb1, 0x56, 0xae, 0x1f, 0xea, 0xb8, 0xd3, 0x24, 0xf0, 0xbb, 0x08, 0xfe, 0x5a, 0xaa,
-	0x73, 0xa1, 0xfa, 0x19, 0x93, 0xe7, 0x91, 0xbd, 0x32, 0x14, 0x65, 0xe4, 0xfc, 0xa6, 0x00, 0xf5,
-	0x44, 0x2b, 0x73, 0xa0, 0xb5, 0xaf, 0xbc, 0x33, 0x5f, 0x0b, 0x4f, 0xcf, 0x95, 0xe8, 0x14, 0xb6,
-	0x0a, 0xdb, 0x0d, 0xbe, 0x44, 0x63, 0x6b, 0x50, 0x1c, 0x4f, 0xc8, 0x51, 0x0d, 0x5e, 0x1c, 0x4f,
-	0x58, 0x07, 0x6a, 0x8f, 0x5d, 0xe5, 0xbb, 0xa1, 0x26, 0xcf, 0x34, 0x78, 0x32, 0x65, 0x37, 0xa0,
-	0x31, 0x9e, 0x3c, 0x16, 0x2a, 0xf6, 0x65, 0x48, 0xfe, 0x68, 0xf0, 0x8c, 0xc0, 0x36, 0x00, 0xc6,
-	0x93, 0x7b, 0xc2, 0x45, 0xa5, 0x71, 0xa7, 0xb2, 0x55, 0xda, 0x6e, 0xf0, 0x1c, 0xc5, 0xf9, 0x15,
-	0x54, 0x28, 0x46, 0xec, 0x4b, 0xa8, 0x4e, 0xfd, 0x53, 0x11, 0x6b, 0x63, 0x4e, 0x6f, 0xef, 0xbb,
-	0x1f, 0x37, 0x57, 0xfe, 0xfa, 0xe3, 0xe6, 0x4e, 0x2e, 0x19, 0x64, 0x24, 0x42, 0x4f, 0x86, 0xda,
-	0xf5, 0x43, 0xa1, 0xe2, 0xdd, 0x53, 0x79, 0xc7, 0x88, 0x74, 0x07, 0xf4, 0xe1, 0x56, 0x03, 0xbb,
-	0x09, 0x15, 0x3f, 0x9c, 0x8a, 0x0b, 0xb2, 0xbf, 0xd4, 0x7b, 0xc7, 0xaa, 0x6a, 0x8e, 0xe7, 0x3a,
-	0x9a, 0xeb, 0x11, 0xb2, 0xb8, 0x41, 0x38, 0xbf, 0x2b, 0x40, 0xd5, 0xe4, 0x00, 0xbb, 0x01, 0xe5,
-	0x99, 0xd0, 0x2e, 0xad, 0xdf, 0xdc, 0xab, 0xa3, 0x6f, 0x1f, 0x08, 0xed, 0x72, 0xa2, 0x62, 0x7a,
-	0xcd, 0xe4, 0x1c, 0x7d, 0x5f, 0xcc, 0xd2, 0xeb, 0x01, 0x52, 0xb8, 0x65, 0xb0, 0x9f, 0x42, 0x2d,
-	0x14, 0xfa, 0x99, 0x54, 0xe7, 0xe4, 0xa3, 0x35, 0x13, 0xf4, 0x43, 0xa1, 0x1f, 0xc8, 0xa9, 0xe0,
-	0x09, 0x8f, 0xdd, 0x86, 0x7a, 0x2c, 0xbc, 0xb9, 0xf2, 0xf5, 0x82, 0xfc, 0xb5, 0xb6, 0xd7, 0xa6,
-	0x2c, 0xb3, 0x34, 0x02, 0xa7, 0x08, 0xe7, 0xcf, 0x05, 0x28, 0xa3, 0x19, 0x8c, 0x41, 0xd9, 0x55,
-	0xa7, 0x26, 0xbb, 0x1b, 0x9c, 0xc6, 0xac, 0x0d, 0x25, 0x11, 0x3e, 0x25, 0x8b, 0x1a, 0x1c, 0x87,
-	0x48, 0xf1, 0x9e, 0x4d, 0x6d, 0x8c, 0x70, 0x88, 0x72, 0xf3, 0x58, 0x28, 0x1b, 0x1a, 0x1a, 0xb3,
-	0x9b, 0xd0, 0x88, 0x94, 0xbc, 0x58, 0x3c, 0x41, 0xe9, 0x4a, 0x2e, 0xf1, 0x90, 0x38, 0x0c, 0x9f,
-	0xf2, 0x7a, 0x64, 0x47, 0x6c, 0x07, 0x40, 0x5c, 0x68, 0xe5, 0x1e, 0xc8, 0x58, 0xc7, 0x9d, 0x2a,
-	0xed, 0x9d, 0xf2, 0x1d, 0x09, 0xa3, 0x23, 0x9e, 0xe3, 0xb2, 0x75, 0xa8, 0x9f, 0xc9, 0x58, 0x87,
-	0xee, 0x4c, 0x74, 0x6a, 0xb4, 0x5c, 0x3a, 0x77, 0xfe, 0x51, 0x84, 0x0a, 0xb9, 0x8b, 0x6d, 0x63,
-	0x74, 0xa2, 0xb9, 0x09, 0x74, 0xa9, 0xc7, 0x6c, 0x74, 0x80, 0xf2, 0x20, 0x0d, 0x0e, 0xe6, 0xc4,
-	0x3a, 0x7a, 0x2a, 0x10, 0x9e, 0x96, 0xca, 0xa6, 0x62, 0x3a, 0xc7, 0x6d, 0x4d, 0x31, 0x5b, 0xcc,
-	0x4e, 0x69, 0xcc, 0x6e, 0x41, 0x55, 0x52, 0x88, 0x69, 0xb3, 0xaf, 0x08, 0xbc, 0x85, 0xa0, 0x72,
-	0x25, 0xdc, 0xa9, 0x0c, 0x83, 0x05, 0xb9, 0xa0, 0xce, 0xd3, 0x39, 0xbb, 0x05, 0x0d, 0x8a, 0xe9,
-	0xc3, 0x45, 0x24, 0x3a, 0x55, 0x8a, 0xd1, 0x6a, 0x1a, 0x6f, 0x24, 0xf2, 0x8c, 0x8f, 0x87, 0xd8,
-	0x73, 0xbd, 0x33, 0x31, 0x8e, 0x74, 0xe7, 0x7a, 0xe6, 0xcb, 0xbe, 0xa5, 0xf1, 0x94, 0x8b, 0x6a,
-	0x63, 0xe1, 0x29, 0xa1, 0x11, 0xfa, 0x2e, 0x41, 0x57, 0x6d, 0xe8, 0x0d, 0x91, 0x67, 0x7c, 0xe6,
-	0x40, 0x75, 0x32, 0x39, 0x40, 0xe4, 0x7b, 0x59, 0x91, 0x31, 0x14, 0x6e, 0x39, 0x66, 0x0f, 0xf1,
-	0x3c, 0xd0, 0xa3, 0x41, 0xe7, 0x7d, 0xe3, 0xa0, 0x64, 0xee, 0x8c, 0xa0, 0x9e, 0x98, 0x80, 0xa7,
-	0x79, 0x34, 0xb0, 0xe7, 0xbc, 0x38, 0x1a, 0xb0, 0x3b, 0x50, 0x8b, 0xcf, 0x5c, 0xe5, 0x87, 0xa7,
-	0xe4, 0xd7, 0xb5, 0xbd, 0x77, 0x52, 0x8b, 0x27, 0x86, 0x8e, 0xab, 0x24, 0x18, 0x47, 0x42, 0x23,
-	0x35, 0xf1, 0x25, 0x5d, 0x6d, 0x28, 0xcd, 0xfd, 0x29, 0xe9, 0x59, 0xe5, 0x38, 0x44, 0xca, 0xa9,
-	0x6f, 0x72, 0x70, 0x95, 0xe3, 0x10, 0x83, 0x35, 0x93, 0x53, 0x53, 0x2e, 0x57, 0x39, 0x8d, 0xd1,
-	0x76, 0x19, 0x69, 0x5f, 0x86, 0x6e, 0x90, 0xf8, 0x3f, 0x99, 0x3b, 0x41, 0xb2, 0xf7, 0xff, 0xc9,
-	0x6a, 0xbf, 0x2e, 0x40, 0x3d, 0xa9, 0xf1, 0x58, 0xb0, 0xfc, 0xa9, 0x08, 0xb5, 0x7f, 0xe2, 0x0b,
-	0x65, 0x17, 0xce, 0x51, 0xd8, 0x1d, 0xa8, 0xb8, 0x5a, 0xab, 0xa4, 0x0c, 0xbc, 0x9f, 0xbf, 0x20,
-	0xba, 0xfb, 0xc8, 0x19, 0x86, 0x5a, 0x2d, 0xb8, 0x41, 0xad, 0x7f, 0x0e, 0x90, 0x11, 0xd1, 0xd6,
-	0x73, 0xb1, 0xb0, 0x5a, 0x71, 0xc8, 0xae, 0x43, 0xe5, 0xa9, 0x1b, 0xcc, 0x85, 0xcd, 0x6f, 0x33,
-	0xf9, 0xa2, 0xf8, 0x79, 0xc1, 0xf9, 0x53, 0x11, 0x6a, 0xf6, 0xc2, 0x60, 0xb7, 0xa1, 0x46, 0x17,
-	0x86, 0xb5, 0xe8, 0xea, 0x43, 0x93, 0x40, 0xd8, 0x6e, 0x7a, 0x13, 0xe6, 0x6c, 0xb4, 0xaa, 0xcc,
-	0x8d, 0x68, 0x6d, 0xcc, 0xee, 0xc5, 0xd2, 0x54, 0x9c, 0xd8, 0x2b, 0x6f, 0x0d, 0xd1, 0x03, 0x71,
-	0xe2, 0x87, 0x3e, 0xfa, 0x87, 0x23, 0x8b, 0xdd, 0x4e, 0x76, 0x5d, 0x26, 0x8d, 0xef, 0xe5, 0x35,
-	0xbe, 0xbc, 0xe9, 0x11, 0x34, 0x73, 0xcb, 0x5c, 0xb1, 0xeb, 0x8f, 0xf3, 0xbb, 0xb6, 0x4b, 0x92,
-	0x3a, 0x73, 0x5f, 0x67, 0x5e, 0xf8, 0x0f, 0xfc, 0xf7, 0x19, 0x40, 0xa6, 0xf2, 0xcd, 0x8b, 0x8e,
-	0xf3, 0x4d, 0x09, 0x60, 0x1c, 0x61, 0xc9, 0x9d, 0xba, 0x54, 0xf7, 0x5b, 0xfe, 0x69, 0x28, 0x95,
-	0x78, 0x42, 0xc7, 0x98, 0xe4, 0xeb, 0xbc, 0x69, 0x68, 0x74, 0x62, 0xd8, 0x3e, 0x34, 0xa7, 0x22,
-	0xf6, 0x94, 0x4f, 0x09, 0x65, 0x9d, 0xbe, 0x89, 0x7b, 0xca, 0xf4, 0x74, 0x07, 0x19, 0xc2, 0xf8,
-	0x2a, 0x2f, 0xc3, 0xf6, 0xa0, 0x25, 0x2e, 0x22, 0xa9, 0xb4, 0x5d, 0xc5, 0xf4, 0x15, 0xd7, 0x4c,
-	0x87, 0x82, 0x74, 0x5a, 0x89, 0x37, 0x45, 0x36, 0x61, 0x2e, 0x94, 0x3d, 0x37, 0x32, 0x97, 0x6a,
-	0x73, 0xaf, 0x73, 0x69, 0xbd, 0xbe, 0x1b, 0x19, 0xa7, 0xf5, 0x3e, 0xc5, 0xbd, 0x7e, 0xf3, 0xb7,
-	0xcd, 0x5b, 0xb9, 0x9b, 0x74, 0x26, 0x8f, 0x17, 0xbb, 0x94, 0x2f, 0xe7, 0xbe, 0xde, 0x9d, 0x6b,
-	0x3f, 0xd8, 0x75, 0x23, 0x1f, 0xd5, 0xa1, 0xe0, 0x68, 0xc0, 0x49, 0xf5, 0xfa, 0xcf, 0xa1, 0x7d,
-	0xd9, 0xee, 0xb7, 0x89, 0xc1, 0xfa, 0x5d, 0x68, 0xa4, 0x76, 0xbc, 0x4e, 0xb0, 0x9e, 0x0f, 0xde,
-	0x1f, 0x0b, 0x50, 0x35, 0xa7, 0x8a, 0xdd, 0x85, 0x46, 0x20, 0x3d, 0x17, 0x0d, 0x48, 0x5a, 0xbb,
-	0x0f, 0xb2, 0x43, 0xd7, 0xbd, 0x9f, 0xf0, 0x8c, 0x57, 0x33, 0x2c, 0x26, 0x99, 0x1f, 0x9e, 0xc8,
-	0xe4, 0x14, 0xac, 0x65, 0x42, 0xa3, 0xf0, 0x44, 0x72, 0xc3, 0x5c, 0xff, 0x0a, 0xd6, 0x96, 0x55,
-	0x5c, 0x61, 0xe7, 0x47, 0xcb, 0xe9, 0x4a, 0x35, 0x3b, 0x15, 0xca, 0x9b, 0x7d, 0x17, 0x1a, 0x29,
-	0x9d, 0xed, 0xbc, 0x6c, 0x78, 0x2b, 0x2f, 0x99, 0xb3, 0xd5, 0x09, 0x00, 0x32, 0xd3, 0xb0, 0x58,
-	0x61, 0x0f, 0x49, 0xf7, 0xa8, 0x31, 0x23, 0x9d, 0xd3, 0xbd, 0xe7, 0x6a, 0x97, 0x4c, 0x69, 0x71,
-	0x1a, 0xb3, 0x2e, 0xc0, 0x34, 0x3d, 0xb0, 0xaf, 0x38, 0xc6, 0x39, 0x84, 0x33, 0x86, 0x7a, 0x62,
-	0x04, 0xdb, 0x82, 0x66, 0x6c, 0x57, 0xc6, 0x8e, 0x09, 0x97, 0xab, 0xf0, 0x3c, 0x09, 0x3b, 0x1f,
-	0xe5, 0x86, 0xa7, 0x62, 0xa9, 0xf3, 0xe1, 0x48, 0xe1, 0x96, 0xe1, 0x7c, 0x0d, 0x15, 0x22, 0xe0,
-	0x31, 0x8b, 0xb5, 0xab, 0xb4, 0x6d, 0xa2, 0x4c, 0x53, 0x21, 0x63, 0x5a, 0xb6, 0x57, 0xc6, 0x44,
-	0xe4, 0x06, 0xc0, 0x3e, 0xc6, 0xd6, 0x65, 0x6a, 0x3d, 0x7a, 0x15, 0x0e, 0xd9, 0xce, 0xcf, 0xa0,
-	0x9e, 0x90, 0x71, 0xe7, 0xf7, 0xfd, 0x50, 0x58, 0x13, 0x69, 0x8c, 0xcd, 0x67, 0xff, 0xcc, 0x55,
-	0xae, 0xa7, 0x85, 0x69, 0x11, 0x2a, 0x3c, 0x23, 0x38, 0x1f, 0x41, 0x33, 0x77, 0x7a, 0x30, 0xdd,
-	0x1e, 0x53, 0x18, 0xcd, 0x19, 0x36, 0x13, 0xe7, 0xf7, 0xd8, 0x1a, 0x27, 0xdd, 0xce, 0x4f, 0x00,
-	0xce, 0xb4, 0x8e, 0x9e, 0x50, 0xfb, 0x63, 0x7d, 0xdf, 0x40, 0x0a, 0x21, 0xd8, 0x26, 0x34, 0x71,
-	0x12, 0x5b, 0xbe, 0xc9, 0x77, 0x92, 0x88, 0x0d, 0xe0, 0xff, 0xa1, 0x71, 0x92, 0x8a, 0x97, 0x6c,
-	0xe8, 0x12, 0xe9, 0x0f, 0xa0, 0x1e, 0x4a, 0xcb, 0x33, 0xdd, 0x58, 0x2d, 0x94, 0xa9, 0x9c, 0x1b,
-	0x04, 0x96, 0x57, 0x31, 0x72, 0x6e, 0x10, 0x10, 0xd3, 0xb9, 0x05, 0xff, 0xf7, 0x52, 0x93, 0xcf,
-	0xde, 0x83, 0xea, 0x89, 0x1f, 0x68, 0xba, 0x11, 0xb0, 0xfb, 0xb3, 0x33, 0xe7, 0x5f, 0x05, 0x80,
-	0x2c, 0xec, 0x98, 0xcc, 0x58, 0xda, 0x11, 0xd3, 0x32, 0xa5, 0x3c, 0x80, 0xfa, 0xcc, 0x16, 0x09,
-	0x1b, 0xd0, 0x1b, 0xcb, 0xa9, 0xd2, 0x4d, 0x6a, 0x88, 0x29, 0x1f, 0x7b, 0xb6, 0x7c, 0xbc, 0x4d,
-	0x23, 0x9e, 0xae, 0x40, 0x5d, 0x4c, 0xfe, 0x41, 0x05, 0xd9, 0x29, 0xe4, 0x96, 0xb3, 0xfe, 0x15,
-	0xac, 0x2e, 0x2d, 0xf9, 0x86, 0x17, 0x46, 0x56, 0xec, 0xf2, 0x47, 0xf0, 0x36, 0x54, 0x4d, 0x67,
-	0x8a, 0xf9, 0x82, 0x23, 0xab, 0x86, 0xc6, 0xd4, 0x4e, 0x1c, 0x25, 0xcf, 0x9a, 0xd1, 0x91, 0xb3,
-	0x07, 0x55, 0xf3, 0x6e, 0x63, 0xdb, 0x50, 0x73, 0x3d, 0x73, 0x56, 0x73, 0xf5, 0x02, 0x99, 0xfb,
-	0x44, 0xe6, 0x09, 0xdb, 0xf9, 0x4b, 0x11, 0x20, 0xa3, 0xbf, 0x45, 0x3b, 0xfb, 0x05, 0xac, 0xc5,
-	0xc2, 0x93, 0xe1, 0xd4, 0x55, 0x0b, 0xe2, 0xda, 0xf7, 0xc9, 0x55, 0x22, 0x97, 0x90, 0xb9, 0xd6,
-	0xb6, 0xf4, 0xfa, 0xd6, 0x76, 0x1b, 0xca, 0x9e, 0x8c, 0x16, 0xf6, 0x16, 0x61, 0xcb, 0x1b, 0xe9,
-	0xcb, 0x68, 0x81, 0xaf, 0x54, 0x44, 0xb0, 0x2e, 0x54, 0x67, 0xe7, 0xf4, 0x92, 0x35, 0xaf, 0x80,
-	0xeb, 0xcb, 0xd8, 0x07, 0xe7, 0x38, 0xc6, 0x77, 0xaf, 0x41, 0xb1, 0x5b, 0x50, 0x99, 0x9d, 0x4f,
-	0x7d, 0x45, 0x4d, 0x71, 0xd3, 0xb4, 0x8d, 0x79, 0xf8, 0xc0, 0x57, 0xf8, 0xba, 0x25, 0x0c, 0x73,
-	0xa0, 0xa8, 0x66, 0xf4, 0x10, 0x68, 0x9a, 0x27, 0x4e, 0xce, 0x9b, 0xb3, 0x83, 0x15, 0x5e, 0x54,
-	0xb3, 0x5e, 0x1d, 0xaa, 0xc6, 0xaf, 0xce, 0x3f, 0x4b, 0xb0, 0xb6, 0x6c, 0x25, 0xe6, 0x41, 0xac,
-	0xbc, 0x24, 0x0f, 0x62, 0xe5, 0xa5, 0x5d, 0x7f, 0x31, 0xd7, 0xf5, 0x3b, 0x50, 0x91, 0xcf, 0x42,
-	0xa1, 0xf2, 0x4f, 0xf6, 0xfe, 0x99, 0x7c, 0x16, 0x62, 0x0f, 0x6b, 0x58, 0x4b, 0x2d, 0x61, 0xc5,
-	0xb6, 0x84, 0x1f, 0xc3, 0xea, 0x89, 0x0c, 0x02, 0xf9, 0x6c, 0xb2, 0x98, 0x05, 0x7e, 0x78, 0x6e,
-	0xfb, 0xc2, 0x65, 0x22, 0xdb, 0x86, 0x6b, 0x53, 0x5f, 0xa1, 0x39, 0x7d, 0x19, 0x6a, 0x11, 0xd2,
-	0x23, 0x08, 0x71, 0x97, 0xc9, 0xec, 0x4b, 0xd8, 0x72, 0xb5, 0x16, 0xb3, 0x48, 0x3f, 0x0a, 0x23,
-	0xd7, 0x3b, 0x1f, 0x48, 0x8f, 0xce, 0xec, 0x2c, 0x72, 0xb5, 0x7f, 0xec, 0x07, 0xf8, 0xde, 0xab,
-	0x91, 0xe8, 0x6b, 0x71, 0xec, 0x13, 0x58, 0xf3, 0x94, 0x70, 0xb5, 0x18, 0x88, 0x58, 0x1f, 0xb9,
-	0xfa, 0xac, 0x53, 0x27, 0xc9, 0x4b, 0x54, 0xdc, 0x83, 0x8b, 0xd6, 0x7e, 0xed, 0x07, 0x53, 0xcf,
-	0x55, 0xd3, 0x4e, 0xc3, 0xec, 0x61, 0x89, 0xc8, 0xba, 0xc0, 0x88, 0x30, 0x9c, 0x45, 0x7a, 0x91,
-	0x42, 0x81, 0xa0, 0x57, 0x70, 0xb0, 0xaa, 0x6a, 0x7f, 0x26, 0x62, 0xed, 0xce, 0x22, 0xfa, 0xd5,
-	0x50, 0xe2, 0x19, 0x81, 0xdd, 0x84, 0xb6, 0x1f, 0x7a, 0xc1, 0x7c, 0x2a, 0x9e, 0x44, 0xb8, 0x11,
-	0x15, 0xc6, 0x9d, 0x16, 0xd5, 0xa0, 0x6b, 0x96, 0x7e, 0x64, 0xc9, 0x08, 0x15, 0x17, 0x97, 0xa0,
-	0xab, 0x06, 0x6a, 0xe9, 0x09, 0xd4, 0xf9, 0xb6, 0x00, 0xed, 0xcb, 0x89, 0x87, 0x61, 0x8b, 0x70,
-	0xf3, 0xf6, 0x08, 0xe3, 0x38, 0x0d, 0x65, 0x31, 0x17, 0xca, 0xe4, 0x52, 0x2c, 0xe5, 0x2e, 0xc5,
-	0x34, 0x2d, 0xca, 0xaf, 0x4e, 0x8b, 0xa5, 0x8d, 0x56, 0x2e, 0x6d, 0xd4, 0xf9, 0x6d, 0x01, 0xae,
-	0x5d, 0x4a, 0xee, 0x37, 0xb6, 0x68, 0x0b, 0x9a, 0x33, 0xf7, 0x5c, 0x1c, 0xb9, 0x8a, 0x52, 0xa6,
-	0x64, 0xba, 0xc6, 0x1c, 0xe9, 0xbf, 0x60, 0x5f, 0x08, 0xad, 0xfc, 0x89, 0xba, 0xd2, 0xb6, 0x24,
-	0x41, 0x0e, 0xa5, 0xbe, 0x27, 0xe7, 0xf6, 0xc2, 0x4d, 0x12, 0x24, 0x21, 0xbe, 0x9c, 0x46, 0xa5,
-	0x2b, 0xd2, 0xc8, 0x39, 0x84, 0x7a, 0x62, 0x20, 0xdb, 0xb4, 0x7f, 0x15, 0x0a, 0xd9, 0xdf, 0xad,
-	0x47, 0xb1, 0x50, 0x68, 0xbb, 0xf9, 0xc5, 0xf0, 0x21, 0x54, 0x4e, 0x95, 0x9c, 0x47, 0xb6, 0x62,
-	0x2f, 0x21, 0x0c, 0xc7, 0x99, 0x40, 0xcd, 0x52, 0xd8, 0x0e, 0x54, 0x8f, 0x17, 0x87, 0x49, 0xbf,
-	0x63, 0xcb, 0x05, 0xce, 0xa7, 0x16, 0x81, 0x35, 0xc8, 0x20, 0xd8, 0x75, 0x28, 0x1f, 0x2f, 0x46,
-	0x03, 0xf3, 0x06, 0xc4, 0x4a, 0x86, 0xb3, 0x5e, 0xd5, 0x18, 0xe4, 0xdc, 0x87, 0x56, 0x5e, 0x0e,
-	0x9d, 0x92, 0xeb, 0xa3, 0x68, 0x9c, 0x95, 0xec, 0xe2, 0x6b, 0x4a, 0xf6, 0xce, 0x36, 0xd4, 0xec,
-	0xff, 0x1b, 0xd6, 0x80, 0xca, 0xa3, 0xc3, 0xc9, 0xf0, 0x61, 0x7b, 0x85, 0xd5, 0xa1, 0x7c, 0x30,
-	0x9e, 0x3c, 0x6c, 0x17, 0x70, 0x74, 0x38, 0x3e, 0x1c, 0xb6, 0x8b, 0x3b, 0x37, 0xa1, 0x95, 0xff,
-	0x83, 0xc3, 0x9a, 0x50, 0x9b, 0xec, 0x1f, 0x0e, 0x7a, 0xe3, 0x5f, 0xb6, 0x57, 0x58, 0x0b, 0xea,
-	0xa3, 0xc3, 0xc9, 0xb0, 0xff, 0x88, 0x0f, 0xdb, 0x85, 0x9d, 0x5f, 0x40, 0x23, 0xfd, 0x91, 0x80,
-	0x1a, 0x7a, 0xa3, 0xc3, 0x41, 0x7b, 0x85, 0x01, 0x54, 0x27, 0xc3, 0x3e, 0x1f, 0xa2, 0xde, 0x1a,
-	0x94, 0x26, 0x93, 0x83, 0x76, 0x11, 0x57, 0xed, 0xef, 0xf7, 0x0f, 0x86, 0xed, 0x12, 0x0e, 0x1f,
-	0x3e, 0x38, 0xba, 0x37, 0x69, 0x97, 0x77, 0x3e, 0x83, 0x6b, 0x97, 0x1e, 0xeb, 0x24, 0x7d, 0xb0,
-	0xcf, 0x87, 0xa8, 0xa9, 0x09, 0xb5, 0x23, 0x3e, 0x7a, 0xbc, 0xff, 0x70, 0xd8, 0x2e, 0x20, 0xe3,
-	0xfe, 0xb8, 0xff, 0xd5, 0x70, 0xd0, 0x2e, 0xf6, 0x6e, 0x7c, 0xf7, 0x7c, 0xa3, 0xf0, 0xfd, 0xf3,
-	0x8d, 0xc2, 0x0f, 0xcf, 0x37, 0x0a, 0x7f, 0x7f, 0xbe, 0x51, 0xf8, 0xf6, 0xc5, 0xc6, 0xca, 0xf7,
-	0x2f, 0x36, 0x56, 0x7e, 0x78, 0xb1, 0xb1, 0x72, 0x5c, 0xa5, 0xff, 0xa9, 0x9f, 0xfe, 0x3b, 0x00,
-	0x00, 0xff, 0xff, 0x3a, 0xd6, 0xef, 0x88, 0x8f, 0x15, 0x00, 0x00,
+	// 2386 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0x17, 0x97, 0xdf, 0x8f, 0x94, 0xcc, 0x4e, 0x9c, 0x64, 0xa3, 0xba, 0x92, 0xb2, 0x49, 0x53,
+	0x59, 0xb6, 0x29, 0x80, 0x01, 0xe2, 0x20, 0x2d, 0x8a, 0x8a, 0x1f, 0x86, 0x98, 0x58, 0xa2, 0x30,
+	0x94, 0xed, 0xde, 0x8c, 0xd5, 0x72, 0x44, 0x2d, 0xb4, 0xdc, 0x59, 0xcc, 0x0e, 0x6d, 0xb1, 0x87,
+	0x1e, 0xfa, 0x17, 0x04, 0x28, 0xd0, 0x53, 0x3f, 0xd0, 0xbf, 0xa1, 0xbd, 0xf6, 0x9e, 0x63, 0x0e,
+	0x3d, 0x04, 0x3d, 0xa4, 0x85, 0x7d, 0xef, 0xa9, 0xc7, 0x16, 0x28, 0xde, 0xcc, 0xec, 0x07, 0x25,
+	0xbb, 0xb6, 0xd1, 0xa2, 0xa7, 0x9d, 0x79, 0xef, 0x37, 0x6f, 0xde, 0xbc, 0x79, 0x5f, 0xb3, 0x50,
+	0xe7, 0x51, 0xdc, 0x8e, 0x04, 0x97, 0x9c, 0x58, 0xd1, 0xc9, 0xfa, 0x9d, 0xa9, 0x2f, 0xcf, 0xe6,
+	0x27, 0x6d, 0x8f, 0xcf, 0x76, 0xa7, 0x7c, 0xca, 0x77, 0x15, 0xeb, 0x64, 0x7e, 0xaa, 0x66, 0x6a,
+	0xa2, 0x46, 0x7a, 0x89, 0xf3, 0x7b, 0x0b, 0xac, 0x51, 0x44, 0xde, 0x87, 0x8a, 0x1f, 0x46, 0x73,
+	0x19, 0xdb, 0x85, 0xad, 0xe2, 0x76, 0xa3, 0x53, 0x6f, 0x47, 0x27, 0xed, 0x21, 0x52, 0xa8, 0x61,
+	0x90, 0x2d, 0x28, 0xb1, 0x0b, 0xe6, 0xd9, 0xd6, 0x56, 0x61, 0xbb, 0xd1, 0x01, 0x04, 0x0c, 0x2e,
+	0x98, 0x37, 0x8a, 0xf6, 0x57, 0xa8, 0xe2, 0x90, 0x8f, 0xa0, 0x12, 0xf3, 0xb9, 0xf0, 0x98, 0x5d,
+	0x54, 0x98, 0x26, 0x62, 0xc6, 0x8a, 0xa2, 0x50, 0x86, 0x8b, 0x92, 0x4e, 0xfd, 0x80, 0xd9, 0xa5,
+	0x4c, 0xd2, 0x3d, 0x3f, 0xd0, 0x18, 0xc5, 0x21, 0x1f, 0x40, 0xf9, 0x64, 0xee, 0x07, 0x13, 0xbb,
+	0xac, 0x20, 0x0d, 0x84, 0x74, 0x91, 0xa0, 0x30, 0x9a, 0x47, 0xb6, 0xa1, 0x16, 0x05, 0xae, 0x3c,
+	0xe5, 0x62, 0x66, 0x43, 0xb6, 0xe1, 0x91, 0xa1, 0xd1, 0x94, 0x4b, 0xee, 0x42, 0xc3, 0xe3, 0x61,
+	0x2c, 0x85, 0xeb, 0x87, 0x32, 0xb6, 0x1b, 0x0a, 0xfc, 0x36, 0x82, 0x1f, 0x71, 0x71, 0xce, 0x44,
+	0x2f, 0x63, 0xd2, 0x3c, 0xb2, 0x5b, 0x02, 0x8b, 0x47, 0xce, 0xaf, 0x0a, 0x50, 0x4b, 0xa4, 0x12,
+	0x07, 0x9a, 0x7b, 0xc2, 0x3b, 0xf3, 0x25, 0xf3, 0xe4, 0x5c, 0x30, 0xbb, 0xb0, 0x55, 0xd8, 0xae,
+	0xd3, 0x25, 0x1a, 0x59, 0x03, 0x6b, 0x34, 0x56, 0x86, 0xaa, 0x53, 0x6b, 0x34, 0x26, 0x36, 0x54,
+	0x1f, 0xba, 0xc2, 0x77, 0x43, 0xa9, 0x2c, 0x53, 0xa7, 0xc9, 0x94, 0xdc, 0x80, 0xfa, 0x68, 0xfc,
+	0x90, 0x89, 0xd8, 0xe7, 0xa1, 0xb2, 0x47, 0x9d, 0x66, 0x04, 0xb2, 0x01, 0x30, 0x1a, 0xdf, 0x63,
+	0x2e, 0x0a, 0x8d, 0xed, 0xf2, 0x56, 0x71, 0xbb, 0x4e, 0x73, 0x14, 0xe7, 0xe7, 0x50, 0x56, 0x77,
+	0x44, 0x3e, 0x87, 0xca, 0xc4, 0x9f, 0xb2, 0x58, 0x6a, 0x75, 0xba, 0x9d, 0xaf, 0xbe, 0xdd, 0x5c,
+	0xf9, 0xcb, 0xb7, 0x9b, 0x3b, 0x39, 0x67, 0xe0, 0x11, 0x0b, 0x3d, 0x1e, 0x4a, 0xd7, 0x0f, 0x99,
+	0x88, 0x77, 0xa7, 0xfc, 0x8e, 0x5e, 0xd2, 0xee, 0xab, 0x0f, 0x35, 0x12, 0xc8, 0x4d, 0x28, 0xfb,
+	0xe1, 0x84, 0x5d, 0x28, 0xfd, 0x8b, 0xdd, 0xb7, 0x8c, 0xa8, 0xc6, 0x68, 0x2e, 0xa3, 0xb9, 0x1c,
+	0x22, 0x8b, 0x6a, 0x84, 0xf3, 0xdb, 0x02, 0x54, 0xb4, 0x0f, 0x90, 0x1b, 0x50, 0x9a, 0x31, 0xe9,
+	0xaa, 0xfd, 0x1b, 0x9d, 0x1a, 0xda, 0xf6, 0x80, 0x49, 0x97, 0x2a, 0x2a, 0xba, 0xd7, 0x8c, 0xcf,
+	0xd1, 0xf6, 0x56, 0xe6, 0x5e, 0x07, 0x48, 0xa1, 0x86, 0x41, 0xbe, 0x0f, 0xd5, 0x90, 0xc9, 0xa7,
+	0x5c, 0x9c, 0x2b, 0x1b, 0xad, 0xe9, 0x4b, 0x3f, 0x64, 0xf2, 0x80, 0x4f, 0x18, 0x4d, 0x78, 0xe4,
+	0x36, 0xd4, 0x62, 0xe6, 0xcd, 0x85, 0x2f, 0x17, 0xca, 0x5e, 0x6b, 0x9d, 0x96, 0xf2, 0x32, 0x43,
+	0x53, 0xe0, 0x14, 0xe1, 0xfc, 0xa3, 0x00, 0x25, 0x54, 0x83, 0x10, 0x28, 0xb9, 0x62, 0xaa, 0xbd,
+	0xbb, 0x4e, 0xd5, 0x98, 0xb4, 0xa0, 0xc8, 0xc2, 0x27, 0x4a, 0xa3, 0x3a, 0xc5, 0x21, 0x52, 0xbc,
+	0xa7, 0x13, 0x73, 0x47, 0x38, 0xc4, 0x75, 0xf3, 0x98, 0x09, 0x73, 0x35, 0x6a, 0x4c, 0x6e, 0x42,
+	0x3d, 0x12, 0xfc, 0x62, 0xf1, 0x18, 0x57, 0x97, 0x73, 0x8e, 0x87, 0xc4, 0x41, 0xf8, 0x84, 0xd6,
+	0x22, 0x33, 0x22, 0x3b, 0x00, 0xec, 0x42, 0x0a, 0x77, 0x9f, 0xc7, 0x32, 0xb6, 0x2b, 0x5b, 0xc5,
+	0xc4, 0xdf, 0x91, 0x30, 0x3c, 0xa2, 0x39, 0x2e, 0x59, 0x87, 0xda, 0x19, 0x8f, 0x65, 0xe8, 0xce,
+	0x98, 0x5d, 0x55, 0xdb, 0xa5, 0x73, 0x72, 0x07, 0xc0, 0x9b, 0xf8, 0x7d, 0xf6, 0xc4, 0xf7, 0x58,
+	0x6c, 0xd7, 0x94, 0x9c, 0x55, 0x94, 0xd3, 0xeb, 0x0f, 0x35, 0x95, 0xe6, 0x00, 0xce, 0xdf, 0x2d,
+	0x28, 0x2b, 0xeb, 0x92, 0x6d, 0xbc, 0xcc, 0x68, 0xae, 0xfd, 0xa2, 0xd8, 0x25, 0xe6, 0x32, 0x61,
+	0x18, 0xe6, 0xef, 0x12, 0x5d, 0x68, 0x1d, 0x0d, 0x1b, 0x30, 0x4f, 0x72, 0x61, 0x3c, 0x37, 0x9d,
+	0xa3, 0x15, 0x26, 0xe8, 0x5c, 0xda, 0x30, 0x6a, 0x4c, 0x6e, 0x41, 0x85, 0x2b, 0x8f, 0xb0, 0x4b,
+	0x2f, 0xf7, 0x13, 0x03, 0x41, 0xe1, 0x82, 0xb9, 0x13, 0x1e, 0x06, 0x0b, 0x65, 0xb1, 0x1a, 0x4d,
+	0xe7, 0xe4, 0x16, 0xd4, 0x95, 0x0b, 0x1c, 0x2f, 0x22, 0x66, 0x57, 0xd4, 0x95, 0xae, 0xa6, 0xee,
+	0x81, 0x44, 0x9a, 0xf1, 0x31, 0xe6, 0x3d, 0xd7, 0x3b, 0x63, 0xa3, 0x48, 0xda, 0xd7, 0x33, 0xd3,
+	0xf7, 0x0c, 0x8d, 0xa6, 0x5c, 0x14, 0x1b, 0x33, 0x4f, 0x30, 0x89, 0xd0, 0xb7, 0x15, 0x74, 0xd5,
+	0x78, 0x8a, 0x26, 0xd2, 0x8c, 0x4f, 0x1c, 0xa8, 0x8c, 0xc7, 0xfb, 0x88, 0x7c, 0x27, 0xcb, 0x49,
+	0x9a, 0x42, 0x0d, 0x47, 0x9f, 0x21, 0x9e, 0x07, 0x72, 0xd8, 0xb7, 0xdf, 0xd5, 0x06, 0x4a, 0xe6,
+	0xce, 0x1f, 0x0a, 0x50, 0x4b, 0x74, 0xc0, 0xe8, 0x1f, 0xf6, 0x4d, 0x5e, 0xb0, 0x86, 0x7d, 0x72,
+	0x07, 0xaa, 0xf1, 0x99, 0x2b, 0xfc, 0x70, 0xaa, 0x0c, 0xbb, 0xd6, 0x79, 0x2b, 0x55, 0x79, 0xac,
+	0xe9, 0xb8, 0x4d, 0x82, 0xc1, 0x64, 0x31, 0x73, 0x2f, 0xc6, 0xfe, 0xcf, 0x74, 0x1a, 0x2d, 0xd2,
+	0x64, 0x4a, 0xde, 0x81, 0xca, 0xcc, 0xbd, 0xd8, 0x9b, 0xea, 0xcc, 0x59, 0xa4, 0x66, 0x46, 0x3e,
+	0x81, 0x46, 0x24, 0xe6, 0x21, 0x3b, 0xe2, 0x81, 0xef, 0x69, 0x03, 0xaf, 0x75, 0xae, 0xa7, 0x9b,
+	0x1c, 0x65, 0x3c, 0x9a, 0x07, 0x3a, 0x1c, 0xea, 0xa9, 0x35, 0xae, 0x68, 0xdd, 0x82, 0xe2, 0xdc,
+	0x9f, 0x28, 0x8d, 0x57, 0x29, 0x0e, 0x91, 0x32, 0xf5, 0x75, 0x74, 0xac, 0x52, 0x1c, 0xa2, 0x5f,
+	0xcc, 0xf8, 0x44, 0xab, 0xb3, 0x4a, 0xd5, 0x18, 0xcd, 0xc4, 0x23, 0xe9, 0xf3, 0xd0, 0x0d, 0x92,
+	0xab, 0x4e, 0xe6, 0x4e, 0x90, 0x98, 0xf9, 0xff, 0xb2, 0xdb, 0x2f, 0x0b, 0x50, 0x4b, 0xaa, 0x0f,
+	0xa6, 0x52, 0x7f, 0xc2, 0x42, 0xe9, 0x9f, 0xfa, 0x4c, 0x98, 0x8d, 0x73, 0x14, 0x72, 0x07, 0xca,
+	0xae, 0x94, 0x22, 0x49, 0x50, 0xef, 0xe6, 0x4b, 0x57, 0x7b, 0x0f, 0x39, 0x83, 0x50, 0x8a, 0x05,
+	0xd5, 0xa8, 0xf5, 0x4f, 0x01, 0x32, 0x22, 0xea, 0x7a, 0xce, 0x16, 0x46, 0x2a, 0x0e, 0xc9, 0x75,
+	0x28, 0x3f, 0x71, 0x83, 0x39, 0x33, 0xa1, 0xa4, 0x27, 0x9f, 0x59, 0x9f, 0x16, 0x9c, 0x3f, 0x59,
+	0x50, 0x35, 0xa5, 0x8c, 0xdc, 0x86, 0xaa, 0x2a, 0x65, 0x4c, 0xfc, 0x87, 0xf8, 0x4c, 0x20, 0x64,
+	0x37, 0xad, 0xd1, 0x39, 0x1d, 0x8d, 0x28, 0x5d, 0xab, 0x8d, 0x8e, 0x59, 0xc5, 0x2e, 0x4e, 0xd8,
+	0xa9, 0x29, 0xc6, 0x6b, 0x88, 0xee, 0xb3, 0x53, 0x3f, 0xf4, 0xd1, 0x3e, 0x14, 0x59, 0xe4, 0x76,
+	0x72, 0xea, 0x92, 0x92, 0xf8, 0x4e, 0x5e, 0xe2, 0xd5, 0x43, 0x0f, 0xa1, 0x91, 0xdb, 0xe6, 0x05,
+	0xa7, 0xfe, 0x30, 0x7f, 0x6a, 0xb3, 0xa5, 0x12, 0xa7, 0x96, 0xe5, 0xac, 0xf0, 0x5f, 0xd8, 0xef,
+	0x13, 0x80, 0x4c, 0xe4, 0xeb, 0xe7, 0x37, 0xe7, 0xd7, 0x45, 0x80, 0x51, 0x84, 0xc5, 0x60, 0xe2,
+	0xaa, 0x8a, 0xd4, 0xf4, 0xa7, 0x21, 0x17, 0xec, 0xb1, 0xca, 0x18, 0x6a, 0x7d, 0x8d, 0x36, 0x34,
+	0x4d, 0x85, 0x0d, 0xd9, 0x83, 0xc6, 0x84, 0xc5, 0x9e, 0xf0, 0x95, 0x43, 0x19, 0xa3, 0x6f, 0xe2,
+	0x99, 0x32, 0x39, 0xed, 0x7e, 0x86, 0xd0, 0xb6, 0xca, 0xaf, 0x21, 0x1d, 0x68, 0xb2, 0x8b, 0x88,
+	0x0b, 0x69, 0x76, 0xd1, 0x1d, 0xcf, 0x35, 0xdd, 0x3b, 0x21, 0x5d, 0xed, 0x44, 0x1b, 0x2c, 0x9b,
+	0x10, 0x17, 0x4a, 0x9e, 0x1b, 0xe9, 0x72, 0xdf, 0xe8, 0xd8, 0x97, 0xf6, 0xeb, 0xb9, 0x91, 0x36,
+	0x5a, 0xf7, 0x63, 0x3c, 0xeb, 0x2f, 0xfe, 0xba, 0x79, 0x2b, 0x57, 0xe3, 0x67, 0xfc, 0x64, 0xb1,
+	0xab, 0xfc, 0xe5, 0xdc, 0x97, 0xbb, 0x73, 0xe9, 0x07, 0xbb, 0x6e, 0xe4, 0xa3, 0x38, 0x5c, 0x38,
+	0xec, 0x53, 0x25, 0x1a, 0x53, 0x8c, 0xf4, 0x67, 0x8c, 0xcf, 0xa5, 0x4a, 0xb8, 0x45, 0x9a, 0x4c,
+	0xd7, 0x7f, 0x0c, 0xad, 0xcb, 0x27, 0x7a, 0x93, 0xdb, 0x59, 0xbf, 0x0b, 0xf5, 0x54, 0xc3, 0x57,
+	0x2d, 0xac, 0xe5, 0xaf, 0xf5, 0x8f, 0x05, 0xa8, 0xe8, 0x78, 0x23, 0x77, 0xa1, 0x1e, 0x70, 0xcf,
+	0x45, 0x05, 0x92, 0x76, 0xf4, 0xbd, 0x2c, 0x1c, 0xdb, 0xf7, 0x13, 0x9e, 0xb6, 0x77, 0x86, 0x45,
+	0xf7, 0xf3, 0xc3, 0x53, 0x9e, 0xc4, 0xc7, 0x5a, 0xb6, 0x68, 0x18, 0x9e, 0x72, 0xaa, 0x99, 0xeb,
+	0x5f, 0xc0, 0xda, 0xb2, 0x88, 0x17, 0xe8, 0xf9, 0xc1, 0xb2, 0x23, 0xab, 0xc2, 0x91, 0x2e, 0xca,
+	0xab, 0x7d, 0x17, 0xea, 0x29, 0x9d, 0xec, 0x5c, 0x55, 0xbc, 0x99, 0x5f, 0x99, 0xd3, 0xd5, 0x09,
+	0x00, 0x32, 0xd5, 0x30, 0x8d, 0x61, 0xdf, 0xab, 0x6a, 0xbf, 0x56, 0x23, 0x9d, 0xab, 0xe2, 0xeb,
+	0x4a, 0x57, 0xa9, 0xd2, 0xa4, 0x6a, 0x4c, 0xda, 0x00, 0x93, 0x34, 0x94, 0x5f, 0x12, 0xe0, 0x39,
+	0x84, 0x33, 0x82, 0x5a, 0xa2, 0x04, 0xd9, 0x82, 0x46, 0x6c, 0x76, 0xc6, 0x2e, 0x0f, 0xb7, 0x2b,
+	0xd3, 0x3c, 0x09, 0xbb, 0x35, 0xe1, 0x86, 0x53, 0xb6, 0xd4, 0xad, 0x51, 0xa4, 0x50, 0xc3, 0x70,
+	0x1e, 0x41, 0x59, 0x11, 0x30, 0x00, 0x63, 0xe9, 0x0a, 0x69, 0x1a, 0x3f, 0xdd, 0x08, 0xf1, 0x58,
+	0x6d, 0xdb, 0x2d, 0xa1, 0x8b, 0x52, 0x0d, 0x20, 0x1f, 0x62, 0xbb, 0x35, 0xb1, 0xad, 0x97, 0xe2,
+	0x90, 0xed, 0xfc, 0x08, 0x6a, 0x09, 0x19, 0x4f, 0x7e, 0xdf, 0x0f, 0x99, 0x51, 0x51, 0x8d, 0xb1,
+	0x61, 0xee, 0x9d, 0xb9, 0xc2, 0xf5, 0x24, 0xd3, 0x7d, 0x4a, 0x99, 0x66, 0x04, 0xe7, 0x03, 0x68,
+	0xe4, 0xe2, 0x0a, 0xdd, 0xed, 0xa1, 0xba, 0x46, 0x1d, 0xdd, 0x7a, 0xe2, 0xfc, 0x0e, 0xdb, 0xf9,
+	0xa4, 0x43, 0xfb, 0x1e, 0xc0, 0x99, 0x94, 0xd1, 0x63, 0xd5, 0xb2, 0x19, 0xdb, 0xd7, 0x91, 0xa2,
+	0x10, 0x64, 0x13, 0x1a, 0x38, 0x89, 0x0d, 0x5f, 0xfb, 0xbb, 0x5a, 0x11, 0x6b, 0xc0, 0x77, 0xa1,
+	0x7e, 0x9a, 0x2e, 0x2f, 0x9a, 0xab, 0x4b, 0x56, 0xbf, 0x07, 0xb5, 0x90, 0x1b, 0x9e, 0xee, 0x20,
+	0xab, 0x21, 0x4f, 0xd7, 0xb9, 0x41, 0x60, 0x78, 0x65, 0xbd, 0xce, 0x0d, 0x02, 0xc5, 0x74, 0x6e,
+	0xc1, 0x77, 0xae, 0x3c, 0x4c, 0xb0, 0xfa, 0x9f, 0xfa, 0x81, 0x54, 0xb5, 0x02, 0x3b, 0x56, 0x33,
+	0x73, 0xfe, 0x55, 0x00, 0xc8, 0xae, 0x9d, 0xb4, 0x74, 0xd2, 0x47, 0x4c, 0x53, 0x27, 0xf9, 0x00,
+	0x6a, 0x33, 0x93, 0x3e, 0xcc, 0x85, 0xde, 0x58, 0x76, 0x95, 0x76, 0x92, 0x5d, 0x74, 0x62, 0xe9,
+	0x98, 0xc4, 0xf2, 0x26, 0x8f, 0x87, 0x74, 0x07, 0xd5, 0x4a, 0xe5, 0x1f, 0x81, 0x90, 0x45, 0x21,
+	0x35, 0x9c, 0xf5, 0x2f, 0x60, 0x75, 0x69, 0xcb, 0xd7, 0x2c, 0x25, 0x59, 0x1a, 0xcc, 0x87, 0xe0,
+	0x6d, 0xa8, 0xe8, 0x6e, 0x1a, 0xfd, 0x05, 0x47, 0x46, 0x8c, 0x1a, 0xab, 0x46, 0xe3, 0x28, 0x79,
+	0x8a, 0x0d, 0x8f, 0x9c, 0x1f, 0x42, 0x3d, 0xed, 0x99, 0x71, 0xc1, 0x61, 0x16, 0x72, 0x6a, 0x8c,
+	0xa1, 0x38, 0x4a, 0x3a, 0x0a, 0x9d, 0xa5, 0xd2, 0xb9, 0xd3, 0x81, 0x8a, 0x7e, 0xa8, 0x92, 0x6d,
+	0xa8, 0xba, 0x9e, 0x0e, 0xf4, 0x5c, 0xb2, 0x41, 0xe6, 0x9e, 0x22, 0xd3, 0x84, 0xed, 0xfc, 0xd9,
+	0x02, 0xc8, 0xe8, 0x6f, 0xd0, 0x90, 0x7f, 0x06, 0x6b, 0x31, 0xf3, 0x78, 0x38, 0x71, 0xc5, 0x42,
+	0x71, 0x6d, 0xeb, 0xa5, 0x4b, 0x2e, 0x21, 0x73, 0xcd, 0x79, 0xf1, 0xd5, 0xcd, 0xf9, 0x36, 0x94,
+	0x3c, 0x1e, 0x2d, 0x4c, 0x71, 0x22, 0xcb, 0x07, 0xe9, 0xf1, 0x68, 0x81, 0xcf, 0x72, 0x44, 0x90,
+	0x36, 0x54, 0x66, 0xe7, 0xea, 0xe9, 0xae, 0x9f, 0x3d, 0xd7, 0x97, 0xb1, 0x07, 0xe7, 0x38, 0xc6,
+	0x87, 0xbe, 0x46, 0x91, 0x5b, 0x50, 0x9e, 0x9d, 0x4f, 0x7c, 0xa1, 0xaa, 0x4c, 0x43, 0xf7, 0xbd,
+	0x79, 0x78, 0xdf, 0x17, 0xf8, 0x9c, 0x57, 0x18, 0xe2, 0x80, 0x25, 0x66, 0xea, 0xe5, 0xd3, 0xe8,
+	0xb4, 0x96, 0x91, 0x74, 0xb6, 0xbf, 0x42, 0x2d, 0x31, 0xeb, 0xd6, 0xa0, 0xa2, 0xed, 0xea, 0xfc,
+	0xb3, 0x08, 0x6b, 0xcb, 0x5a, 0xa2, 0x13, 0xc5, 0xc2, 0x4b, 0x9c, 0x28, 0x16, 0x5e, 0xfa, 0x6e,
+	0xb1, 0x72, 0xef, 0x16, 0x07, 0xca, 0xfc, 0x69, 0xc8, 0x44, 0xfe, 0x1f, 0x45, 0xef, 0x8c, 0x3f,
+	0x0d, 0xb1, 0x09, 0xd7, 0xac, 0xa5, 0x4e, 0xb3, 0x6c, 0x3a, 0xcd, 0x0f, 0x61, 0xf5, 0x94, 0x07,
+	0x01, 0x7f, 0x3a, 0x5e, 0xcc, 0x02, 0x3f, 0x3c, 0x37, 0xed, 0xe6, 0x32, 0x91, 0x6c, 0xc3, 0xb5,
+	0x89, 0x2f, 0x50, 0x9d, 0x1e, 0x0f, 0x25, 0x0b, 0xd5, 0xab, 0x0f, 0x71, 0x97, 0xc9, 0xe4, 0x73,
+	0xd8, 0x72, 0xa5, 0x64, 0xb3, 0x48, 0x3e, 0x08, 0x23, 0xd7, 0x3b, 0xef, 0x73, 0x4f, 0x05, 0xfc,
+	0x2c, 0x72, 0xa5, 0x7f, 0xe2, 0x07, 0xf8, 0xc0, 0xad, 0xaa, 0xa5, 0xaf, 0xc4, 0x91, 0x8f, 0x60,
+	0xcd, 0x13, 0xcc, 0x95, 0xac, 0xcf, 0x62, 0x79, 0xe4, 0xca, 0x33, 0xbb, 0xa6, 0x56, 0x5e, 0xa2,
+	0xe2, 0x19, 0x5c, 0xd4, 0xf6, 0x91, 0x1f, 0x4c, 0x3c, 0x57, 0x4c, 0xec, 0xba, 0x3e, 0xc3, 0x12,
+	0x91, 0xb4, 0x81, 0x28, 0xc2, 0x60, 0x16, 0xc9, 0x45, 0x0a, 0x05, 0x05, 0x7d, 0x01, 0x07, 0x53,
+	0x32, 0xb6, 0x0f, 0xb1, 0x74, 0x67, 0x91, 0xfa, 0xb7, 0x52, 0xa4, 0x19, 0x81, 0xdc, 0x84, 0x96,
+	0x1f, 0x7a, 0xc1, 0x7c, 0xc2, 0x1e, 0x47, 0x78, 0x10, 0x11, 0xc6, 0x76, 0x53, 0x25, 0xb0, 0x6b,
+	0x86, 0x7e, 0x64, 0xc8, 0x08, 0x65, 0x17, 0x97, 0xa0, 0xab, 0x1a, 0xca, 0x2e, 0x96, 0xa0, 0xce,
+	0x97, 0x05, 0x68, 0x5d, 0x76, 0x3c, 0xbc, 0xb6, 0x08, 0x0f, 0x6f, 0xc2, 0x19, 0xc7, 0xe9, 0x55,
+	0x5a, 0xb9, 0xab, 0x4c, 0x2a, 0x6a, 0x31, 0x57, 0x51, 0x53, 0xb7, 0x28, 0xbd, 0xdc, 0x2d, 0x96,
+	0x0e, 0x5a, 0xbe, 0x74, 0x50, 0xe7, 0x37, 0x05, 0xb8, 0x76, 0xc9, 0xb9, 0x5f, 0x5b, 0xa3, 0x2d,
+	0x68, 0xcc, 0xdc, 0x73, 0x76, 0xe4, 0x0a, 0xe5, 0x32, 0x45, 0xdd, 0x8c, 0xe6, 0x48, 0xff, 0x03,
+	0xfd, 0x42, 0x68, 0xe6, 0x23, 0xea, 0x85, 0xba, 0x25, 0x0e, 0x72, 0xc8, 0xe5, 0x3d, 0x3e, 0x37,
+	0xd5, 0xba, 0x46, 0x97, 0x89, 0x57, 0xdd, 0xa8, 0xf8, 0x02, 0x37, 0x72, 0x0e, 0xa1, 0x96, 0x28,
+	0x48, 0x36, 0xcd, 0x6f, 0x94, 0x42, 0xf6, 0x3b, 0xef, 0x41, 0xcc, 0x04, 0xea, 0xae, 0x18, 0xe4,
+	0x7d, 0x28, 0x4f, 0x05, 0x9f, 0x47, 0xb6, 0x75, 0x15, 0xa1, 0x39, 0xce, 0x18, 0xaa, 0x86, 0x42,
+	0x76, 0xa0, 0x72, 0xb2, 0x48, 0x33, 0xb7, 0x49, 0x17, 0x38, 0x9f, 0x18, 0x04, 0xe6, 0x20, 0x8d,
+	0x20, 0xd7, 0xa1, 0x74, 0xb2, 0x18, 0xf6, 0xf5, 0xd3, 0x12, 0x33, 0x19, 0xce, 0xba, 0x15, 0xad,
+	0x90, 0x73, 0x1f, 0x9a, 0xf9, 0x75, 0x68, 0x94, 0x5c, 0x13, 0xa6, 0xc6, 0x59, 0xca, 0xb6, 0x5e,
+	0x91, 0xb2, 0x77, 0xb6, 0xa1, 0x6a, 0x7e, 0x58, 0x91, 0x3a, 0x94, 0x1f, 0x1c, 0x8e, 0x07, 0xc7,
+	0xad, 0x15, 0x52, 0x83, 0xd2, 0xfe, 0x68, 0x7c, 0xdc, 0x2a, 0xe0, 0xe8, 0x70, 0x74, 0x38, 0x68,
+	0x59, 0x3b, 0x37, 0xa1, 0x99, 0xff, 0x65, 0x45, 0x1a, 0x50, 0x1d, 0xef, 0x1d, 0xf6, 0xbb, 0xa3,
+	0x9f, 0xb6, 0x56, 0x48, 0x13, 0x6a, 0xc3, 0xc3, 0xf1, 0xa0, 0xf7, 0x80, 0x0e, 0x5a, 0x85, 0x9d,
+	0x9f, 0x40, 0x3d, 0xfd, 0x15, 0x82, 0x12, 0xba, 0xc3, 0xc3, 0x7e, 0x6b, 0x85, 0x00, 0x54, 0xc6,
+	0x83, 0x1e, 0x1d, 0xa0, 0xdc, 0x2a, 0x14, 0xc7, 0xe3, 0xfd, 0x96, 0x85, 0xbb, 0xf6, 0xf6, 0x7a,
+	0xfb, 0x83, 0x56, 0x11, 0x87, 0xc7, 0x07, 0x47, 0xf7, 0xc6, 0xad, 0xd2, 0xce, 0x0f, 0xa0, 0x75,
+	0xf9, 0x47, 0x00, 0x0a, 0x3a, 0xa6, 0xc3, 0x83, 0xd6, 0x0a, 0x02, 0xe9, 0x00, 0x35, 0x2d, 0xec,
+	0x7c, 0x02, 0xd7, 0x2e, 0xfd, 0x96, 0x50, 0xdb, 0xec, 0xef, 0xd1, 0x01, 0x6e, 0xd9, 0x80, 0xea,
+	0x11, 0x1d, 0x3e, 0xdc, 0x3b, 0x1e, 0xb4, 0x0a, 0xc8, 0xb8, 0x3f, 0xea, 0x7d, 0x31, 0xe8, 0xb7,
+	0xac, 0xee, 0x8d, 0xaf, 0x9e, 0x6d, 0x14, 0xbe, 0x7e, 0xb6, 0x51, 0xf8, 0xe6, 0xd9, 0x46, 0xe1,
+	0x6f, 0xcf, 0x36, 0x0a, 0x5f, 0x3e, 0xdf, 0x58, 0xf9, 0xfa, 0xf9, 0xc6, 0xca, 0x37, 0xcf, 0x37,
+	0x56, 0x4e, 0x2a, 0xea, 0x4f, 0xf3, 0xc7, 0xff, 0x1e, 0x00, 0x58, 0x4c, 0x31, 0x99, 0xa9, 0x16,
+	0x00, 0x00,
 }
 
 func (m *Op) Marshal() (dAtA []byte, err error) {
@@ -2831,6 +2967,20 @@ func (m *Meta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.CdiDevices) > 0 {
+		for iNdEx := len(m.CdiDevices) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.CdiDevices[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
 	if len(m.Hostname) > 0 {
 		i -= len(m.Hostname)
 		copy(dAtA[i:], m.Hostname)
@@ -3032,6 +3182,21 @@ func (m *CacheOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.PrunePolicy != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.PrunePolicy))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.MaxAge != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.MaxAge))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.MaxSize != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.MaxSize))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.Sharing != 0 {
 		i = encodeVarintOps(dAtA, i, uint64(m.Sharing))
 		i--
@@ -3354,6 +3519,11 @@ func (m *OpMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Timeout != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Timeout))
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.Caps) > 0 {
 		keysForCaps := make([]string, 0, len(m.Caps))
 		for k := range m.Caps {
@@ -3935,6 +4105,46 @@ func (m *HostIP) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *CDIDevice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CDIDevice) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CDIDevice) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Optional {
+		i--
+		if m.Optional {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *FileOp) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4755,6 +4965,12 @@ func (m *Meta) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovOps(uint64(l))
 	}
+	if len(m.CdiDevices) > 0 {
+		for _, e := range m.CdiDevices {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -4816,6 +5032,15 @@ func (m *CacheOpt) Size() (n int) {
 	if m.Sharing != 0 {
 		n += 1 + sovOps(uint64(m.Sharing))
 	}
+	if m.MaxSize != 0 {
+		n += 1 + sovOps(uint64(m.MaxSize))
+	}
+	if m.MaxAge != 0 {
+		n += 1 + sovOps(uint64(m.MaxAge))
+	}
+	if m.PrunePolicy != 0 {
+		n += 1 + sovOps(uint64(m.PrunePolicy))
+	}
 	return n
 }
 
@@ -4968,6 +5193,9 @@ func (m *OpMetadata) Size() (n int) {
 			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
 		}
 	}
+	if m.Timeout != 0 {
+		n += 1 + sovOps(uint64(m.Timeout))
+	}
 	return n
 }
 
@@ -5182,6 +5410,22 @@ func (m *HostIP) Size() (n int) {
 	return n
 }
 
+func (m *CDIDevice) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Optional {
+		n += 2
+	}
+	return n
+}
+
 func (m *FileOp) Size() (n int) {
 	if m == nil {
 		return 0
@@ -6491,6 +6735,40 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			}
 			m.Hostname = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CdiDevices", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CdiDevices = append(m.CdiDevices, &CDIDevice{})
+			if err := m.CdiDevices[len(m.CdiDevices)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -6923,6 +7201,63 @@ func (m *CacheOpt) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSize", wireType)
+			}
+			m.MaxSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAge", wireType)
+			}
+			m.MaxAge = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxAge |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrunePolicy", wireType)
+			}
+			m.PrunePolicy = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PrunePolicy |= CachePrunePolicy(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -8228,6 +8563,25 @@ func (m *OpMetadata) Unmarshal(dAtA []byte) error {
 			}
 			m.Caps[github_com_moby_buildkit_util_apicaps.CapID(mapkey)] = mapvalue
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -9728,6 +10082,108 @@ func (m *HostIP) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *CDIDevice) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CDIDevice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CDIDevice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Optional", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Optional = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *FileOp) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
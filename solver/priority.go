@@ -0,0 +1,44 @@
+package solver
+
+import (
+	"context"
+)
+
+// ScheduleInfo describes the builds an operation is run for. Operations use it
+// to share resources like the parallelism limit of a worker fairly between
+// concurrent builds.
+type ScheduleInfo struct {
+	// ID identifies the build. Vertexes shared by several builds use the ID of
+	// the build with the highest priority.
+	ID string
+	// Priority is the highest priority of the builds the vertex is loaded by.
+	Priority int
+	// Weight is the weight of the build of ID.
+	Weight int
+}
+
+type scheduleInfoKey struct{}
+
+// ScheduleInfoFromContext returns the schedule info of the operation that is
+// acquiring its resources.
+func ScheduleInfoFromContext(ctx context.Context) ScheduleInfo {
+	if v, ok := ctx.Value(scheduleInfoKey{}).(ScheduleInfo); ok {
+		return v
+	}
+	return ScheduleInfo{}
+}
+
+func withScheduleInfo(ctx context.Context, st *state) context.Context {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var si ScheduleInfo
+	first := true
+	for j := range st.jobs {
+		if first || j.Priority > si.Priority || (j.Priority == si.Priority && j.id < si.ID) {
+			si = ScheduleInfo{ID: j.id, Priority: j.Priority, Weight: j.Weight}
+			first = false
+		}
+	}
+	return context.WithValue(ctx, scheduleInfoKey{}, si)
+}
@@ -7,12 +7,15 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver/errdefs"
 	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
@@ -1896,6 +1899,457 @@ func TestSubbuild(t *testing.T) {
 
 }
 
+func TestCacheNamespaces(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	storage := NewInMemoryCacheStorage()
+	results := NewInMemoryResultStorage()
+
+	cms := map[string]CacheManager{}
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewCacheManager(ctx, "main", NewNamespacedCacheKeyStorage(storage, ""), results),
+		NamespacedCache: func(ns string) (CacheManager, error) {
+			if cm, ok := cms[ns]; ok {
+				return cm, nil
+			}
+			cm := NewCacheManager(ctx, "main/"+ns, NewNamespacedCacheKeyStorage(storage, ns), results)
+			cms[ns] = cm
+			return cm, nil
+		},
+	})
+	defer s.Close()
+
+	build := func(id, ns, value string) (string, *vertex) {
+		j, err := s.NewJob(id)
+		require.NoError(t, err)
+		defer j.Discard()
+		j.CacheNamespace = ns
+
+		g := Edge{
+			Vertex: vtx(vtxOpt{
+				name:         "v0",
+				cacheKeySeed: "seed0",
+				value:        value,
+			}),
+		}
+		g.Vertex.(*vertex).setupCallCounters()
+
+		res, _, err := j.Build(ctx, g)
+		require.NoError(t, err)
+		return unwrap(res), g.Vertex.(*vertex)
+	}
+
+	res, _ := build("job0", "a", "result0")
+	require.Equal(t, "result0", res)
+
+	// same cache key in another namespace doesn't match
+	res, v := build("job1", "b", "result1")
+	require.Equal(t, "result1", res)
+	require.Equal(t, int64(1), *v.execCallCount)
+
+	res, v = build("job2", "", "result2")
+	require.Equal(t, "result2", res)
+	require.Equal(t, int64(1), *v.execCallCount)
+
+	// same namespace matches
+	res, v = build("job3", "a", "result3")
+	require.Equal(t, "result0", res)
+	require.Equal(t, int64(0), *v.execCallCount)
+
+	res, v = build("job4", "", "result4")
+	require.Equal(t, "result2", res)
+	require.Equal(t, int64(0), *v.execCallCount)
+}
+
+func TestScheduleInfo(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewInMemoryCacheManager(),
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j1.Discard()
+	j1.Priority = 10
+	j1.Weight = 2
+
+	var mu sync.Mutex
+	infos := map[string]ScheduleInfo{}
+	newVtx := func(name string) *vertex {
+		return vtx(vtxOpt{
+			name:         name,
+			cacheKeySeed: "seed-" + name,
+			acquireFunc: func(ctx context.Context) {
+				mu.Lock()
+				infos[name] = ScheduleInfoFromContext(ctx)
+				mu.Unlock()
+			},
+		})
+	}
+
+	_, _, err = j0.Build(ctx, Edge{Vertex: newVtx("v0")})
+	require.NoError(t, err)
+
+	_, _, err = j1.Build(ctx, Edge{Vertex: newVtx("v1")})
+	require.NoError(t, err)
+
+	// a vertex shared by both jobs is scheduled with the highest priority
+	shared := newVtx("v2")
+	shared.opt.cacheDelay = 100 * time.Millisecond
+
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		_, _, err := j0.Build(egctx, Edge{Vertex: shared})
+		return err
+	})
+	eg.Go(func() error {
+		_, _, err := j1.Build(egctx, Edge{Vertex: shared})
+		return err
+	})
+	require.NoError(t, eg.Wait())
+
+	require.Equal(t, ScheduleInfo{ID: "job0"}, infos["v0"])
+	require.Equal(t, ScheduleInfo{ID: "job1", Priority: 10, Weight: 2}, infos["v1"])
+	require.Equal(t, ScheduleInfo{ID: "job1", Priority: 10, Weight: 2}, infos["v2"])
+}
+
+func TestCancelVertex(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewInMemoryCacheManager(),
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j1.Discard()
+
+	started := make(chan struct{})
+	var once sync.Once
+	slow := vtx(vtxOpt{
+		name:         "slow",
+		cacheKeySeed: "seed-slow",
+		value:        "result-slow",
+		execDelay:    500 * time.Millisecond,
+		execPreFunc: func(context.Context) error {
+			once.Do(func() { close(started) })
+			return nil
+		},
+	})
+	slow.setupCallCounters()
+	dependant := vtx(vtxOpt{
+		name:         "dependant",
+		cacheKeySeed: "seed-dependant",
+		inputs:       []Edge{{Vertex: slow}},
+	})
+	other := vtx(vtxOpt{
+		name:         "other",
+		cacheKeySeed: "seed-other",
+		value:        "result-other",
+		execDelay:    200 * time.Millisecond,
+	})
+
+	var errDependant error
+	var resOther, resShared string
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		res, _, err := j1.Build(egctx, Edge{Vertex: slow})
+		if err != nil {
+			return err
+		}
+		resShared = unwrap(res)
+		return nil
+	})
+	<-started
+
+	eg.Go(func() error {
+		_, _, errDependant = j0.Build(ctx, Edge{Vertex: dependant})
+		return nil
+	})
+	eg.Go(func() error {
+		res, _, err := j0.Build(egctx, Edge{Vertex: other})
+		if err != nil {
+			return err
+		}
+		resOther = unwrap(res)
+		return nil
+	})
+
+	require.Eventually(t, func() bool {
+		return s.CancelVertex("job0", slow.Digest()) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Error(t, s.CancelVertex("job0", digest.FromBytes([]byte("unknown"))))
+	require.Error(t, s.CancelVertex("unknown", slow.Digest()))
+	require.NoError(t, eg.Wait())
+
+	// the dependant fails with a typed error
+	var cerr *errdefs.VertexCanceledError
+	require.True(t, errors.As(errDependant, &cerr), "unexpected error %+v", errDependant)
+	require.Equal(t, slow.Digest().String(), cerr.Digest)
+	require.True(t, errors.Is(errDependant, context.Canceled))
+
+	// other builds of the job keep running
+	require.Equal(t, "result-other", resOther)
+
+	// the vertex keeps running for the job sharing it
+	require.Equal(t, "result-slow", resShared)
+	require.Equal(t, int64(1), *slow.execCallCount)
+
+	require.Error(t, s.CancelVertex("job0", slow.Digest()))
+}
+
+func TestCancelVertexOp(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewInMemoryCacheManager(),
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j1.Discard()
+
+	started := make(chan struct{})
+	var once sync.Once
+	slow := vtx(vtxOpt{
+		name:         "slow",
+		cacheKeySeed: "seed-slow",
+		execDelay:    10 * time.Second,
+		execPreFunc: func(context.Context) error {
+			once.Do(func() { close(started) })
+			return nil
+		},
+	})
+	dependant := vtx(vtxOpt{
+		name:         "dependant",
+		cacheKeySeed: "seed-dependant",
+		inputs:       []Edge{{Vertex: slow}},
+	})
+	shared := vtx(vtxOpt{
+		name:         "shared",
+		cacheKeySeed: "seed-shared",
+		execDelay:    10 * time.Second,
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := j0.Build(ctx, Edge{Vertex: dependant})
+		errCh <- err
+	}()
+	<-started
+
+	// a vertex that all of its dependants share with another job can't be
+	// canceled
+	ctx2, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for _, j := range []*Job{j0, j1} {
+		j := j
+		go j.Build(ctx2, Edge{Vertex: shared})
+	}
+	require.Eventually(t, func() bool {
+		err := s.CancelVertex("job0", shared.Digest())
+		return err != nil && strings.Contains(err.Error(), "shared with other jobs")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the operation of a vertex used only by the job is stopped
+	start := time.Now()
+	require.NoError(t, s.CancelVertex("job0", slow.Digest()))
+	select {
+	case err = <-errCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("build was not canceled")
+	}
+	require.True(t, time.Since(start) < 5*time.Second)
+
+	var cerr *errdefs.VertexCanceledError
+	require.True(t, errors.As(err, &cerr), "unexpected error %+v", err)
+	require.Equal(t, slow.Digest().String(), cerr.Digest)
+
+	// the vertex fails for later builds of the job
+	_, _, err = j0.Build(ctx, Edge{Vertex: slow})
+	require.True(t, errors.As(err, &cerr), "unexpected error %+v", err)
+}
+
+func TestCancelVertexOtherJob(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewInMemoryCacheManager(),
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j1.Discard()
+
+	started := make(chan struct{})
+	var calls int64
+	slow := vtx(vtxOpt{
+		name:         "slow",
+		cacheKeySeed: "seed-slow",
+		value:        "result-slow",
+		execPreFunc: func(ctx context.Context) error {
+			if atomic.AddInt64(&calls, 1) > 1 {
+				return nil
+			}
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	dependant := vtx(vtxOpt{
+		name:         "dependant",
+		cacheKeySeed: "seed-dependant",
+		value:        "result-dependant",
+		inputs:       []Edge{{Vertex: slow}},
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := j0.Build(ctx, Edge{Vertex: dependant})
+		errCh <- err
+	}()
+	<-started
+
+	require.NoError(t, s.CancelVertex("job0", slow.Digest()))
+	select {
+	case err = <-errCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("build was not canceled")
+	}
+	var cerr *errdefs.VertexCanceledError
+	require.True(t, errors.As(err, &cerr), "unexpected error %+v", err)
+
+	// another job sharing the vertex still builds it
+	res, _, err := j1.Build(ctx, Edge{Vertex: dependant})
+	require.NoError(t, err)
+	require.Equal(t, "result-dependant", unwrap(res))
+	require.Equal(t, int64(2), atomic.LoadInt64(&calls))
+
+	// the vertex keeps failing for the job that canceled it
+	_, _, err = j0.Build(ctx, Edge{Vertex: dependant})
+	require.True(t, errors.As(err, &cerr), "unexpected error %+v", err)
+}
+
+func TestVertexTimeout(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewInMemoryCacheManager(),
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+
+	v := vtx(vtxOpt{
+		name:         "v0",
+		cacheKeySeed: "seed0",
+		execDelay:    10 * time.Second,
+		timeout:      100 * time.Millisecond,
+	})
+
+	_, _, err = j0.Build(ctx, Edge{Vertex: v})
+	require.Error(t, err)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var terr *errdefs.VertexTimeoutError
+	require.True(t, errors.As(err, &terr))
+	require.Equal(t, v.Digest().String(), terr.Digest)
+	require.Equal(t, int64(100*time.Millisecond), terr.Timeout)
+}
+
+func TestJobDeadline(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+		DefaultCache:  NewInMemoryCacheManager(),
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+	j0.Deadline = time.Now().Add(100 * time.Millisecond)
+
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j1.Discard()
+
+	v := vtx(vtxOpt{
+		name:         "v0",
+		cacheKeySeed: "seed0",
+		execDelay:    10 * time.Second,
+	})
+
+	_, _, err = j0.Build(ctx, Edge{Vertex: v})
+	require.Error(t, err)
+
+	var terr *errdefs.VertexTimeoutError
+	require.True(t, errors.As(err, &terr))
+	require.Equal(t, v.Digest().String(), terr.Digest)
+	require.Equal(t, int64(0), terr.Timeout)
+
+	// a vertex shared with a job without deadline keeps running
+	shared := vtx(vtxOpt{
+		name:         "v1",
+		cacheKeySeed: "seed1",
+		value:        "result1",
+		cacheDelay:   100 * time.Millisecond,
+		execDelay:    200 * time.Millisecond,
+	})
+
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		res, _, err := j0.Build(egctx, Edge{Vertex: shared})
+		if err != nil {
+			return err
+		}
+		require.Equal(t, "result1", unwrap(res))
+		return nil
+	})
+	eg.Go(func() error {
+		_, _, err := j1.Build(egctx, Edge{Vertex: shared})
+		return err
+	})
+	require.NoError(t, eg.Wait())
+}
+
 func TestCacheWithSelector(t *testing.T) {
 	t.Parallel()
 	ctx := context.TODO()
@@ -3434,12 +3888,14 @@ type vtxOpt struct {
 	cacheDelay       time.Duration
 	cachePreFunc     func(context.Context) error
 	execPreFunc      func(context.Context) error
+	acquireFunc      func(context.Context)
 	inputs           []Edge
 	value            string
 	slowCacheCompute map[int]ResultBasedCacheFunc
 	selectors        map[int]digest.Digest
 	cacheSource      CacheManager
 	ignoreCache      bool
+	timeout          time.Duration
 }
 
 func vtx(opt vtxOpt) *vertex {
@@ -3479,6 +3935,7 @@ func (v *vertex) Options() VertexOptions {
 	return VertexOptions{
 		CacheSources: cache,
 		IgnoreCache:  v.opt.ignoreCache,
+		Timeout:      v.opt.timeout,
 	}
 }
 
@@ -3576,6 +4033,9 @@ func (v *vertex) Exec(ctx context.Context, g session.Group, inputs []Result) (ou
 }
 
 func (v *vertex) Acquire(ctx context.Context) (ReleaseFunc, error) {
+	if f := v.opt.acquireFunc; f != nil {
+		f(ctx)
+	}
 	return func() {}, nil
 }
 
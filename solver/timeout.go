@@ -0,0 +1,24 @@
+package solver
+
+import (
+	"time"
+)
+
+// jobsDeadline returns the deadline for running the vertex of st. Vertexes
+// shared by several jobs keep running until the latest deadline of the jobs
+// and have no deadline if one of the jobs has none.
+func jobsDeadline(st *state) time.Time {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var deadline time.Time
+	for j := range st.jobs {
+		if j.Deadline.IsZero() {
+			return time.Time{}
+		}
+		if j.Deadline.After(deadline) {
+			deadline = j.Deadline
+		}
+	}
+	return deadline
+}
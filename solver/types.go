@@ -53,6 +53,8 @@ type VertexOptions struct {
 	CacheSources []CacheManager
 	Description  map[string]string // text values with no special meaning for solver
 	ExportCache  *bool
+	// Timeout is the maximum duration the vertex is allowed to run for
+	Timeout time.Duration
 	// WorkerConstraint
 }
 
@@ -0,0 +1,185 @@
+package sourcepolicy
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/solver/pb"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrSourceDenied is returned by the policy engine when a source is denied by the policy.
+	ErrSourceDenied = errors.New("source denied by policy")
+
+	// ErrTooManyOps is returned by the policy engine when there are too many converts for a single source op.
+	ErrTooManyOps = errors.New("too many operations")
+)
+
+// maxIterations is the maximum number of times a source op can be converted
+// before the engine gives up, preventing infinite conversion loops.
+const maxIterations = 20
+
+// Engine evaluates source ops against source policies.
+//
+// This is used to rewrite or deny source ops before they are solved.
+// Policies are applied in order. Within a policy the last matching ALLOW or
+// DENY rule decides if the source is allowed. A matching CONVERT rule rewrites
+// the source op and restarts the evaluation with the updated op.
+type Engine struct {
+	pol      []*spb.Policy
+	sources  map[string]*selectorCache
+	validErr error
+}
+
+// NewEngine creates a new source policy engine.
+func NewEngine(pol []*spb.Policy) *Engine {
+	e := &Engine{
+		pol:     pol,
+		sources: map[string]*selectorCache{},
+	}
+	for _, p := range pol {
+		if err := e.validate(p); err != nil {
+			e.validErr = err
+			break
+		}
+	}
+	return e
+}
+
+// Validate checks that the policy is well formed and that all of its
+// selectors can be compiled.
+func Validate(pol *spb.Policy) error {
+	if pol == nil {
+		return nil
+	}
+	return NewEngine([]*spb.Policy{pol}).validErr
+}
+
+func (e *Engine) validate(pol *spb.Policy) error {
+	if pol.Version != 0 && pol.Version != 1 {
+		return errors.Errorf("unsupported source policy version %d", pol.Version)
+	}
+	for i, rule := range pol.Rules {
+		if rule.Selector == nil || rule.Selector.Identifier == "" {
+			return errors.Errorf("invalid source policy rule %d: missing selector identifier", i)
+		}
+		switch rule.Action {
+		case spb.PolicyAction_ALLOW, spb.PolicyAction_DENY:
+		case spb.PolicyAction_CONVERT:
+			if rule.Updates == nil || (rule.Updates.Identifier == "" && len(rule.Updates.Attrs) == 0) {
+				return errors.Errorf("invalid source policy rule %d: missing updates for convert", i)
+			}
+		default:
+			return errors.Errorf("invalid source policy rule %d: unknown action %v", i, rule.Action)
+		}
+		if _, err := e.selectorCache(rule.Selector); err != nil {
+			return errors.Wrapf(err, "invalid source policy rule %d", i)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) selectorCache(src *spb.Selector) (*selectorCache, error) {
+	key := src.String()
+	if s, ok := e.sources[key]; ok {
+		return s, nil
+	}
+	s, err := newSelectorCache(src)
+	if err != nil {
+		return nil, err
+	}
+	e.sources[key] = s
+	return s, nil
+}
+
+// Evaluate evaluates a source operation against the policy.
+//
+// Policies are re-evaluated for each convert rule.
+// Evaluate will error if there are too many converts for a single source op
+// or if the source is denied by the policy.
+//
+// Evaluate returns true if the op was mutated by a convert rule.
+func (e *Engine) Evaluate(ctx context.Context, op *pb.Op) (bool, error) {
+	if len(e.pol) == 0 {
+		return false, nil
+	}
+	if e.validErr != nil {
+		return false, e.validErr
+	}
+
+	srcOp := op.GetSource()
+	if srcOp == nil {
+		return false, nil
+	}
+
+	var mutated bool
+	for i := 0; ; i++ {
+		if i > maxIterations {
+			return mutated, errors.Wrapf(ErrTooManyOps, "too many mutations on a single source %q", srcOp.Identifier)
+		}
+		mut, err := e.evaluatePolicies(ctx, srcOp)
+		if err != nil {
+			return mutated, err
+		}
+		if !mut {
+			return mutated, nil
+		}
+		mutated = true
+	}
+}
+
+func (e *Engine) evaluatePolicies(ctx context.Context, srcOp *pb.SourceOp) (bool, error) {
+	for _, pol := range e.pol {
+		mut, err := e.evaluatePolicy(ctx, pol, srcOp)
+		if err != nil || mut {
+			return mut, err
+		}
+	}
+	return false, nil
+}
+
+// evaluatePolicy evaluates a single policy against a source operation.
+// If the source is mutated the policy is short-circuited and `true` is returned.
+// If the source is denied an error is returned.
+func (e *Engine) evaluatePolicy(ctx context.Context, pol *spb.Policy, srcOp *pb.SourceOp) (bool, error) {
+	ident := srcOp.Identifier
+
+	var deny bool
+	for _, rule := range pol.Rules {
+		selector, err := e.selectorCache(rule.Selector)
+		if err != nil {
+			return false, err
+		}
+
+		matched, err := selector.Match(ident, srcOp.Attrs)
+		if err != nil {
+			return false, errors.Wrap(err, "error matching source policy")
+		}
+		if !matched {
+			continue
+		}
+
+		switch rule.Action {
+		case spb.PolicyAction_ALLOW:
+			deny = false
+		case spb.PolicyAction_DENY:
+			deny = true
+		case spb.PolicyAction_CONVERT:
+			mut, err := selector.Mutate(srcOp, rule.Updates)
+			if err != nil {
+				return false, errors.Wrap(err, "error applying source policy conversion")
+			}
+			if mut {
+				bklog.G(ctx).Debugf("source policy converted %q to %q", ident, srcOp.Identifier)
+				return true, nil
+			}
+		}
+	}
+
+	if deny {
+		return false, errors.Wrapf(ErrSourceDenied, "source %q denied by policy", ident)
+	}
+	return false, nil
+}
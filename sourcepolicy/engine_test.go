@@ -0,0 +1,316 @@
+package sourcepolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func sourceOp(ident string, attrs map[string]string) *pb.Op {
+	return &pb.Op{
+		Op: &pb.Op_Source{
+			Source: &pb.SourceOp{
+				Identifier: ident,
+				Attrs:      attrs,
+			},
+		},
+	}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		rules         []*spb.Rule
+		ref           string
+		attrs         map[string]string
+		expectedRef   string
+		expectedAttrs map[string]string
+		mutated       bool
+		expectedErr   error
+	}{
+		{
+			name:        "no rules",
+			ref:         "docker-image://docker.io/library/alpine:latest",
+			expectedRef: "docker-image://docker.io/library/alpine:latest",
+		},
+		{
+			name: "deny all",
+			rules: []*spb.Rule{
+				{
+					Action:   spb.PolicyAction_DENY,
+					Selector: &spb.Selector{Identifier: "*"},
+				},
+			},
+			ref:         "docker-image://docker.io/library/alpine:latest",
+			expectedErr: ErrSourceDenied,
+		},
+		{
+			name: "deny all with allow override",
+			rules: []*spb.Rule{
+				{
+					Action:   spb.PolicyAction_DENY,
+					Selector: &spb.Selector{Identifier: "*"},
+				},
+				{
+					Action:   spb.PolicyAction_ALLOW,
+					Selector: &spb.Selector{Identifier: "docker-image://docker.io/library/alpine:*"},
+				},
+			},
+			ref:         "docker-image://docker.io/library/alpine:latest",
+			expectedRef: "docker-image://docker.io/library/alpine:latest",
+		},
+		{
+			name: "allow does not apply to other sources",
+			rules: []*spb.Rule{
+				{
+					Action:   spb.PolicyAction_DENY,
+					Selector: &spb.Selector{Identifier: "*"},
+				},
+				{
+					Action:   spb.PolicyAction_ALLOW,
+					Selector: &spb.Selector{Identifier: "docker-image://docker.io/library/alpine:*"},
+				},
+			},
+			ref:         "docker-image://docker.io/library/busybox:latest",
+			expectedErr: ErrSourceDenied,
+		},
+		{
+			name: "convert exact",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{
+						Identifier: "docker-image://docker.io/library/alpine:latest",
+						MatchType:  spb.MatchType_EXACT,
+					},
+					Updates: &spb.Update{
+						Identifier: "docker-image://docker.io/library/alpine:3.14",
+					},
+				},
+			},
+			ref:         "docker-image://docker.io/library/alpine:latest",
+			expectedRef: "docker-image://docker.io/library/alpine:3.14",
+			mutated:     true,
+		},
+		{
+			name: "convert wildcard with match groups",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{
+						Identifier: "docker-image://docker.io/library/*:latest",
+					},
+					Updates: &spb.Update{
+						Identifier: "docker-image://mirror.example.com/library/${1}:latest",
+					},
+				},
+			},
+			ref:         "docker-image://docker.io/library/alpine:latest",
+			expectedRef: "docker-image://mirror.example.com/library/alpine:latest",
+			mutated:     true,
+		},
+		{
+			name: "convert regex",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{
+						Identifier: `^https://github\.com/([^/]+)/([^/]+)/archive/(.*)$`,
+						MatchType:  spb.MatchType_REGEX,
+					},
+					Updates: &spb.Update{
+						Identifier: "https://mirror.example.com/${1}/${2}/${3}",
+					},
+				},
+			},
+			ref:         "https://github.com/moby/buildkit/archive/v0.9.0.tar.gz",
+			expectedRef: "https://mirror.example.com/moby/buildkit/v0.9.0.tar.gz",
+			mutated:     true,
+		},
+		{
+			name: "convert then deny",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{
+						Identifier: "docker-image://docker.io/library/alpine:latest",
+					},
+					Updates: &spb.Update{
+						Identifier: "docker-image://docker.io/library/busybox:latest",
+					},
+				},
+				{
+					Action:   spb.PolicyAction_DENY,
+					Selector: &spb.Selector{Identifier: "docker-image://docker.io/library/busybox:*"},
+				},
+			},
+			ref:         "docker-image://docker.io/library/alpine:latest",
+			expectedErr: ErrSourceDenied,
+		},
+		{
+			name: "convert attrs",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{
+						Identifier: "https://example.com/foo",
+					},
+					Updates: &spb.Update{
+						Attrs: map[string]string{pb.AttrHTTPChecksum: "sha256:1234"},
+					},
+				},
+			},
+			ref:           "https://example.com/foo",
+			expectedRef:   "https://example.com/foo",
+			expectedAttrs: map[string]string{pb.AttrHTTPChecksum: "sha256:1234"},
+			mutated:       true,
+		},
+		{
+			name: "attr constraint equal",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_DENY,
+					Selector: &spb.Selector{
+						Identifier: "git://*",
+						Constraints: []*spb.AttrConstraint{
+							{
+								Key:       pb.AttrKeepGitDir,
+								Value:     "true",
+								Condition: spb.AttrMatch_EQUAL,
+							},
+						},
+					},
+				},
+			},
+			ref:         "git://github.com/moby/buildkit",
+			attrs:       map[string]string{pb.AttrKeepGitDir: "false"},
+			expectedRef: "git://github.com/moby/buildkit",
+		},
+		{
+			name: "attr constraint matches",
+			rules: []*spb.Rule{
+				{
+					Action: spb.PolicyAction_DENY,
+					Selector: &spb.Selector{
+						Identifier: "git://*",
+						Constraints: []*spb.AttrConstraint{
+							{
+								Key:       pb.AttrFullRemoteURL,
+								Value:     "^ssh://",
+								Condition: spb.AttrMatch_MATCHES,
+							},
+						},
+					},
+				},
+			},
+			ref:         "git://github.com/moby/buildkit",
+			attrs:       map[string]string{pb.AttrFullRemoteURL: "ssh://git@github.com/moby/buildkit"},
+			expectedErr: ErrSourceDenied,
+		},
+		{
+			name: "convert loop",
+			rules: []*spb.Rule{
+				{
+					Action:   spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{Identifier: "docker-image://a"},
+					Updates:  &spb.Update{Identifier: "docker-image://b"},
+				},
+				{
+					Action:   spb.PolicyAction_CONVERT,
+					Selector: &spb.Selector{Identifier: "docker-image://b"},
+					Updates:  &spb.Update{Identifier: "docker-image://a"},
+				},
+			},
+			ref:         "docker-image://a",
+			expectedErr: ErrTooManyOps,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pol := &spb.Policy{Version: 1, Rules: tc.rules}
+			require.NoError(t, Validate(pol))
+
+			op := sourceOp(tc.ref, tc.attrs)
+			mutated, err := NewEngine([]*spb.Policy{pol}).Evaluate(context.TODO(), op)
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.True(t, errors.Is(err, tc.expectedErr), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.mutated, mutated)
+			require.Equal(t, tc.expectedRef, op.GetSource().Identifier)
+			if tc.expectedAttrs != nil {
+				require.Equal(t, tc.expectedAttrs, op.GetSource().Attrs)
+			}
+		})
+	}
+}
+
+func TestEngineSkipsNonSourceOps(t *testing.T) {
+	t.Parallel()
+
+	pol := &spb.Policy{
+		Rules: []*spb.Rule{
+			{
+				Action:   spb.PolicyAction_DENY,
+				Selector: &spb.Selector{Identifier: "*"},
+			},
+		},
+	}
+	op := &pb.Op{
+		Op: &pb.Op_Exec{
+			Exec: &pb.ExecOp{Meta: &pb.Meta{Args: []string{"true"}}},
+		},
+	}
+	mutated, err := NewEngine([]*spb.Policy{pol}).Evaluate(context.TODO(), op)
+	require.NoError(t, err)
+	require.False(t, mutated)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Validate(nil))
+
+	err := Validate(&spb.Policy{Version: 2})
+	require.Error(t, err)
+
+	err = Validate(&spb.Policy{
+		Rules: []*spb.Rule{{Action: spb.PolicyAction_DENY}},
+	})
+	require.Error(t, err)
+
+	err = Validate(&spb.Policy{
+		Rules: []*spb.Rule{
+			{
+				Action: spb.PolicyAction_DENY,
+				Selector: &spb.Selector{
+					Identifier: "(",
+					MatchType:  spb.MatchType_REGEX,
+				},
+			},
+		},
+	})
+	require.Error(t, err)
+
+	err = Validate(&spb.Policy{
+		Rules: []*spb.Rule{
+			{
+				Action:   spb.PolicyAction_CONVERT,
+				Selector: &spb.Selector{Identifier: "docker-image://alpine"},
+			},
+		},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package sourcepolicy
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/moby/buildkit/solver/pb"
+	spb "github.com/moby/buildkit/sourcepolicy/pb"
+	"github.com/pkg/errors"
+)
+
+// selectorCache holds the compiled form of a policy selector.
+type selectorCache struct {
+	*spb.Selector
+	re          *regexp.Regexp
+	constraints []*attrConstraint
+}
+
+type attrConstraint struct {
+	*spb.AttrConstraint
+	re *regexp.Regexp
+}
+
+func newSelectorCache(src *spb.Selector) (*selectorCache, error) {
+	s := &selectorCache{Selector: src}
+
+	switch src.MatchType {
+	case spb.MatchType_EXACT:
+	case spb.MatchType_WILDCARD:
+		re, err := wildcardToRegexp(src.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		s.re = re
+	case spb.MatchType_REGEX:
+		re, err := regexp.Compile(src.Identifier)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regex %q", src.Identifier)
+		}
+		s.re = re
+	default:
+		return nil, errors.Errorf("unknown match type %v", src.MatchType)
+	}
+
+	for _, c := range src.Constraints {
+		if c == nil {
+			continue
+		}
+		ac := &attrConstraint{AttrConstraint: c}
+		switch c.Condition {
+		case spb.AttrMatch_EQUAL, spb.AttrMatch_NOTEQUAL:
+		case spb.AttrMatch_MATCHES:
+			re, err := regexp.Compile(c.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regex %q for attribute %q", c.Value, c.Key)
+			}
+			ac.re = re
+		default:
+			return nil, errors.Errorf("unknown attribute match condition %v", c.Condition)
+		}
+		s.constraints = append(s.constraints, ac)
+	}
+	return s, nil
+}
+
+// Match returns true if the source identifier and attributes satisfy the selector.
+func (s *selectorCache) Match(ref string, attrs map[string]string) (bool, error) {
+	for _, c := range s.constraints {
+		v, ok := attrs[c.Key]
+		switch c.Condition {
+		case spb.AttrMatch_EQUAL:
+			if !ok || v != c.Value {
+				return false, nil
+			}
+		case spb.AttrMatch_NOTEQUAL:
+			if ok && v == c.Value {
+				return false, nil
+			}
+		case spb.AttrMatch_MATCHES:
+			if !ok || !c.re.MatchString(v) {
+				return false, nil
+			}
+		default:
+			return false, errors.Errorf("unknown attribute match condition %v", c.Condition)
+		}
+	}
+
+	if s.re == nil {
+		return s.Identifier == ref, nil
+	}
+	return s.re.MatchString(ref), nil
+}
+
+// Mutate applies the updates of a convert rule to the source op.
+// For wildcard and regex selectors, ${N} in the updated identifier is
+// replaced with the matching group of the selector.
+// Mutate returns true if the source op was changed.
+func (s *selectorCache) Mutate(op *pb.SourceOp, upd *spb.Update) (bool, error) {
+	if upd == nil {
+		return false, nil
+	}
+	var mutated bool
+
+	if ident := upd.Identifier; ident != "" {
+		if s.re != nil {
+			m := s.re.FindStringSubmatchIndex(op.Identifier)
+			if m == nil {
+				return false, errors.Errorf("could not match %q against %q", op.Identifier, s.Identifier)
+			}
+			ident = string(s.re.ExpandString(nil, ident, op.Identifier, m))
+		}
+		if ident != op.Identifier {
+			op.Identifier = ident
+			mutated = true
+		}
+	}
+
+	for k, v := range upd.Attrs {
+		if cur, ok := op.Attrs[k]; ok && cur == v {
+			continue
+		}
+		if op.Attrs == nil {
+			op.Attrs = map[string]string{}
+		}
+		op.Attrs[k] = v
+		mutated = true
+	}
+	return mutated, nil
+}
+
+// wildcardToRegexp converts a wildcard pattern where "*" matches any number
+// of characters into an anchored regular expression with a capture group for
+// each wildcard.
+func wildcardToRegexp(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	re, err := regexp.Compile("^" + strings.Join(parts, "(.*)") + "$")
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid wildcard pattern %q", pattern)
+	}
+	return re, nil
+}
@@ -0,0 +1,3 @@
+package moby_buildkit_v1_sourcepolicy //nolint:golint
+
+//go:generate protoc -I=. --gogofaster_out=. policy.proto
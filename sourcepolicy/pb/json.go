@@ -0,0 +1,85 @@
+package moby_buildkit_v1_sourcepolicy //nolint:golint
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// MarshalJSON implements json.Marshaler with custom marshaling for PolicyAction.
+// It gives the string form of the enum value.
+func (a PolicyAction) MarshalJSON() ([]byte, error) {
+	return marshalEnum(int32(a), PolicyAction_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler with custom unmarshaling for PolicyAction.
+// It accepts both the string form and the numeric value.
+func (a *PolicyAction) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, PolicyAction_value, "policy action")
+	if err != nil {
+		return err
+	}
+	*a = PolicyAction(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler with custom marshaling for AttrMatch.
+// It gives the string form of the enum value.
+func (a AttrMatch) MarshalJSON() ([]byte, error) {
+	return marshalEnum(int32(a), AttrMatch_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler with custom unmarshaling for AttrMatch.
+// It accepts both the string form and the numeric value.
+func (a *AttrMatch) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, AttrMatch_value, "attribute match")
+	if err != nil {
+		return err
+	}
+	*a = AttrMatch(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler with custom marshaling for MatchType.
+// It gives the string form of the enum value.
+func (a MatchType) MarshalJSON() ([]byte, error) {
+	return marshalEnum(int32(a), MatchType_name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler with custom unmarshaling for MatchType.
+// It accepts both the string form and the numeric value.
+func (a *MatchType) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, MatchType_value, "match type")
+	if err != nil {
+		return err
+	}
+	*a = MatchType(v)
+	return nil
+}
+
+func marshalEnum(v int32, names map[int32]string) ([]byte, error) {
+	if s, ok := names[v]; ok {
+		return json.Marshal(s)
+	}
+	return json.Marshal(v)
+}
+
+func unmarshalEnum(data []byte, values map[string]int32, kind string) (int32, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if v, ok := values[s]; ok {
+			return v, nil
+		}
+		return 0, errors.Errorf("unknown %s %q", kind, s)
+	}
+	var n int32
+	if err := json.Unmarshal(data, &n); err != nil {
+		return 0, errors.Errorf("invalid %s %s", kind, data)
+	}
+	for _, v := range values {
+		if v == n {
+			return n, nil
+		}
+	}
+	return 0, errors.Errorf("unknown %s %d", kind, n)
+}
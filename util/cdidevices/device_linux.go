@@ -0,0 +1,36 @@
+package cdidevices
+
+import (
+	"os"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// hostDevice reads the type and device numbers of a device node on the host.
+func hostDevice(p string) (*specs.LinuxDevice, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(p, &st); err != nil {
+		return nil, errors.Wrapf(err, "failed to stat device %s", p)
+	}
+	var typ string
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFCHR:
+		typ = "c"
+	case unix.S_IFBLK:
+		typ = "b"
+	case unix.S_IFIFO:
+		typ = "p"
+	default:
+		return nil, errors.Errorf("%s is not a device node", p)
+	}
+	mode := os.FileMode(st.Mode &^ unix.S_IFMT)
+	return &specs.LinuxDevice{
+		Path:     p,
+		Type:     typ,
+		Major:    int64(unix.Major(uint64(st.Rdev))),
+		Minor:    int64(unix.Minor(uint64(st.Rdev))),
+		FileMode: &mode,
+	}, nil
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package cdidevices
+
+import (
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+func hostDevice(p string) (*specs.LinuxDevice, error) {
+	return nil, errors.Errorf("device nodes are not supported on this platform")
+}
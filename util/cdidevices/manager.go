@@ -0,0 +1,414 @@
+package cdidevices
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/moby/buildkit/solver/pb"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSpecDirs are the directories CDI specs are loaded from if none are
+// configured. Specs in later directories take precedence.
+var DefaultSpecDirs = []string{"/etc/cdi", "/var/run/cdi"}
+
+var (
+	kindRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.\-]*/[a-zA-Z0-9][a-zA-Z0-9_.\-]*$`)
+	nameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.:\-]*$`)
+)
+
+// supportedVersions are the CDI spec versions whose container edits are
+// known to this package.
+var supportedVersions = map[string]struct{}{
+	"0.3.0": {},
+	"0.4.0": {},
+	"0.5.0": {},
+	"0.6.0": {},
+	"0.7.0": {},
+	"0.8.0": {},
+}
+
+// Spec is a CDI specification file as defined by
+// https://github.com/cncf-tags/container-device-interface/blob/main/SPEC.md
+type Spec struct {
+	Version        string            `yaml:"cdiVersion"`
+	Kind           string            `yaml:"kind"`
+	Annotations    map[string]string `yaml:"annotations,omitempty"`
+	Devices        []Device          `yaml:"devices"`
+	ContainerEdits ContainerEdits    `yaml:"containerEdits,omitempty"`
+}
+
+// Device is a single device of a CDI specification.
+type Device struct {
+	Name           string            `yaml:"name"`
+	Annotations    map[string]string `yaml:"annotations,omitempty"`
+	ContainerEdits ContainerEdits    `yaml:"containerEdits"`
+}
+
+// ContainerEdits are the modifications applied to the container spec when a
+// device is injected. Hooks, additional GIDs and Intel RDT are parsed so that
+// specs using them can be rejected, they are not supported.
+type ContainerEdits struct {
+	Env            []string      `yaml:"env,omitempty"`
+	DeviceNodes    []*DeviceNode `yaml:"deviceNodes,omitempty"`
+	Mounts         []*Mount      `yaml:"mounts,omitempty"`
+	Hooks          []yaml.Node   `yaml:"hooks,omitempty"`
+	AdditionalGIDs []uint32      `yaml:"additionalGIDs,omitempty"`
+	IntelRdt       *yaml.Node    `yaml:"intelRdt,omitempty"`
+}
+
+// validate returns an error if the edits can't be applied completely.
+func (e ContainerEdits) validate() error {
+	var unsupported []string
+	if len(e.Hooks) > 0 {
+		unsupported = append(unsupported, "hooks")
+	}
+	if len(e.AdditionalGIDs) > 0 {
+		unsupported = append(unsupported, "additionalGIDs")
+	}
+	if e.IntelRdt != nil {
+		unsupported = append(unsupported, "intelRdt")
+	}
+	if len(unsupported) > 0 {
+		return errors.Errorf("unsupported container edits: %s", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// DeviceNode is a device node to create in the container. Type, major and
+// minor numbers are read from the host if not specified.
+type DeviceNode struct {
+	Path        string       `yaml:"path"`
+	HostPath    string       `yaml:"hostPath,omitempty"`
+	Type        string       `yaml:"type,omitempty"`
+	Major       int64        `yaml:"major,omitempty"`
+	Minor       int64        `yaml:"minor,omitempty"`
+	FileMode    *os.FileMode `yaml:"fileMode,omitempty"`
+	Permissions string       `yaml:"permissions,omitempty"`
+	UID         *uint32      `yaml:"uid,omitempty"`
+	GID         *uint32      `yaml:"gid,omitempty"`
+}
+
+// Mount is a host path to mount into the container.
+type Mount struct {
+	HostPath      string   `yaml:"hostPath"`
+	ContainerPath string   `yaml:"containerPath"`
+	Type          string   `yaml:"type,omitempty"`
+	Options       []string `yaml:"options,omitempty"`
+}
+
+type device struct {
+	kind  string
+	name  string
+	edits []ContainerEdits
+}
+
+func (d *device) qualifiedName() string {
+	return d.kind + "=" + d.name
+}
+
+// Manager keeps track of the CDI devices available on the host and injects
+// them into container specs.
+type Manager struct {
+	dirs []string
+
+	mu      sync.Mutex
+	devices map[string]*device
+	invalid []error
+}
+
+// NewManager returns a manager for CDI specs found in dirs.
+func NewManager(dirs []string) (*Manager, error) {
+	if len(dirs) == 0 {
+		dirs = DefaultSpecDirs
+	}
+	m := &Manager{dirs: dirs}
+	if err := m.Refresh(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Refresh reloads the CDI specs from the spec directories. Invalid spec files,
+// including specs with container edits that are not supported, are logged and
+// skipped.
+func (m *Manager) Refresh() error {
+	devices := map[string]*device{}
+	var invalid []error
+	for _, dir := range m.dirs {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to read CDI spec dir %s", dir)
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			switch filepath.Ext(f.Name()) {
+			case ".json", ".yaml", ".yml":
+			default:
+				continue
+			}
+			fp := filepath.Join(dir, f.Name())
+			spec, err := readSpec(fp)
+			if err != nil {
+				logrus.Warnf("ignoring CDI spec %s: %v", fp, err)
+				invalid = append(invalid, errors.Wrapf(err, "invalid spec %s", fp))
+				continue
+			}
+			for _, d := range spec.Devices {
+				dev := &device{
+					kind:  spec.Kind,
+					name:  d.Name,
+					edits: []ContainerEdits{spec.ContainerEdits, d.ContainerEdits},
+				}
+				devices[dev.qualifiedName()] = dev
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.devices = devices
+	m.invalid = invalid
+	m.mu.Unlock()
+	return nil
+}
+
+func readSpec(fp string) (*Spec, error) {
+	dt, err := os.ReadFile(fp)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var spec Spec
+	// JSON is a subset of YAML so both formats are handled here. Unknown
+	// fields are rejected as they could be container edits that would be
+	// silently skipped.
+	dec := yaml.NewDecoder(bytes.NewReader(dt))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return nil, errors.Wrap(err, "failed to parse spec")
+	}
+	if spec.Version == "" {
+		return nil, errors.New("missing cdiVersion")
+	}
+	if _, ok := supportedVersions[spec.Version]; !ok {
+		return nil, errors.Errorf("unsupported cdiVersion %q", spec.Version)
+	}
+	if !kindRe.MatchString(spec.Kind) {
+		return nil, errors.Errorf("invalid kind %q", spec.Kind)
+	}
+	if err := spec.ContainerEdits.validate(); err != nil {
+		return nil, err
+	}
+	for _, d := range spec.Devices {
+		if !nameRe.MatchString(d.Name) {
+			return nil, errors.Errorf("invalid device name %q", d.Name)
+		}
+		if err := d.ContainerEdits.validate(); err != nil {
+			return nil, errors.Wrapf(err, "device %s", d.Name)
+		}
+	}
+	return &spec, nil
+}
+
+// ListDevices returns the qualified names of all known devices.
+func (m *Manager) ListDevices() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.devices))
+	for k := range m.devices {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup resolves a qualified (kind=name) or unqualified device name.
+func (m *Manager) lookup(name string) (*device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if strings.Contains(name, "=") {
+		return m.devices[name], nil
+	}
+	var found *device
+	for _, d := range m.devices {
+		if d.name != name {
+			continue
+		}
+		if found != nil {
+			return nil, errors.Errorf("device name %q is ambiguous, use one of %s or %s", name, found.qualifiedName(), d.qualifiedName())
+		}
+		found = d
+	}
+	return found, nil
+}
+
+func (m *Manager) resolve(devs []*pb.CDIDevice) ([]*device, error) {
+	var out []*device
+	refreshed := false
+	for _, d := range devs {
+		dev, err := m.lookup(d.Name)
+		if err != nil {
+			return nil, err
+		}
+		if dev == nil && !refreshed {
+			// specs may have been added since the last refresh
+			if err := m.Refresh(); err != nil {
+				return nil, err
+			}
+			refreshed = true
+			if dev, err = m.lookup(d.Name); err != nil {
+				return nil, err
+			}
+		}
+		if dev == nil {
+			if d.Optional {
+				logrus.Debugf("skipping optional CDI device %s: not found", d.Name)
+				continue
+			}
+			return nil, m.notFound(d.Name)
+		}
+		out = append(out, dev)
+	}
+	return out, nil
+}
+
+// notFound returns the error for a device that is not available. Invalid
+// specs are listed as the device may be defined in one of them.
+func (m *Manager) notFound(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.invalid) == 0 {
+		return errors.Errorf("CDI device %s not found", name)
+	}
+	msgs := make([]string, len(m.invalid))
+	for i, err := range m.invalid {
+		msgs[i] = err.Error()
+	}
+	return errors.Errorf("CDI device %s not found, ignored %s", name, strings.Join(msgs, "; "))
+}
+
+// InjectDevices applies the container edits of the requested devices to the
+// spec. Optional devices that are not available are skipped.
+func (m *Manager) InjectDevices(s *specs.Spec, devs ...*pb.CDIDevice) error {
+	devices, err := m.resolve(devs)
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		for _, e := range d.edits {
+			if err := applyEdits(s, e); err != nil {
+				return errors.Wrapf(err, "failed to inject CDI device %s", d.qualifiedName())
+			}
+		}
+	}
+	return nil
+}
+
+func applyEdits(s *specs.Spec, e ContainerEdits) error {
+	if s.Process != nil {
+		for _, env := range e.Env {
+			s.Process.Env = replaceOrAppendEnv(s.Process.Env, env)
+		}
+	}
+	for _, dn := range e.DeviceNodes {
+		if err := addDeviceNode(s, dn); err != nil {
+			return err
+		}
+	}
+	for _, mnt := range e.Mounts {
+		typ := mnt.Type
+		if typ == "" {
+			typ = "bind"
+		}
+		opts := mnt.Options
+		if len(opts) == 0 && typ == "bind" {
+			opts = []string{"rbind"}
+		}
+		s.Mounts = append(s.Mounts, specs.Mount{
+			Destination: mnt.ContainerPath,
+			Type:        typ,
+			Source:      mnt.HostPath,
+			Options:     opts,
+		})
+	}
+	return nil
+}
+
+func addDeviceNode(s *specs.Spec, dn *DeviceNode) error {
+	if dn.Path == "" {
+		return errors.New("device node without path")
+	}
+	hostPath := dn.HostPath
+	if hostPath == "" {
+		hostPath = dn.Path
+	}
+	dev := specs.LinuxDevice{
+		Path:     dn.Path,
+		Type:     dn.Type,
+		Major:    dn.Major,
+		Minor:    dn.Minor,
+		FileMode: dn.FileMode,
+		UID:      dn.UID,
+		GID:      dn.GID,
+	}
+	if dev.Type == "" || (dev.Major == 0 && dev.Minor == 0) {
+		hd, err := hostDevice(hostPath)
+		if err != nil {
+			return err
+		}
+		dev.Type, dev.Major, dev.Minor = hd.Type, hd.Major, hd.Minor
+		if dev.FileMode == nil {
+			dev.FileMode = hd.FileMode
+		}
+	}
+	access := dn.Permissions
+	if access == "" {
+		access = "rwm"
+	}
+
+	if s.Linux == nil {
+		s.Linux = &specs.Linux{}
+	}
+	if s.Linux.Resources == nil {
+		s.Linux.Resources = &specs.LinuxResources{}
+	}
+	devices := s.Linux.Devices[:0]
+	for _, d := range s.Linux.Devices {
+		if d.Path != dev.Path {
+			devices = append(devices, d)
+		}
+	}
+	s.Linux.Devices = append(devices, dev)
+	major, minor := dev.Major, dev.Minor
+	s.Linux.Resources.Devices = append(s.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+		Allow:  true,
+		Type:   dev.Type,
+		Major:  &major,
+		Minor:  &minor,
+		Access: access,
+	})
+	return nil
+}
+
+func replaceOrAppendEnv(env []string, kv string) []string {
+	k := strings.SplitN(kv, "=", 2)[0]
+	for i, e := range env {
+		if strings.SplitN(e, "=", 2)[0] == k {
+			env[i] = kv
+			return env
+		}
+	}
+	return append(env, kv)
+}
@@ -0,0 +1,183 @@
+package cdidevices
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpecYAML = `
+cdiVersion: "0.6.0"
+kind: vendor1.com/device
+devices:
+- name: foo
+  containerEdits:
+    env:
+    - FOO=injected
+    deviceNodes:
+    - path: /dev/foo
+      hostPath: /dev/null
+- name: bar
+  containerEdits:
+    deviceNodes:
+    - path: /dev/bar
+      type: c
+      major: 1
+      minor: 5
+      permissions: rw
+containerEdits:
+  env:
+  - VENDOR1=true
+`
+
+const testSpecJSON = `{
+  "cdiVersion": "0.6.0",
+  "kind": "vendor2.com/device",
+  "devices": [
+    {
+      "name": "foo",
+      "containerEdits": {
+        "mounts": [{"hostPath": "/tmp", "containerPath": "/vendor2"}]
+      }
+    }
+  ]
+}`
+
+func newTestManager(t *testing.T) *Manager {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vendor1.yaml"), []byte(testSpecYAML), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vendor2.json"), []byte(testSpecJSON), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("kind: nope"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0600))
+
+	m, err := NewManager([]string{dir, filepath.Join(dir, "missing")})
+	require.NoError(t, err)
+	return m
+}
+
+func newTestSpec() *specs.Spec {
+	return &specs.Spec{
+		Process: &specs.Process{Env: []string{"PATH=/bin", "FOO=orig"}},
+		Linux:   &specs.Linux{Resources: &specs.LinuxResources{}},
+	}
+}
+
+func TestListDevices(t *testing.T) {
+	m := newTestManager(t)
+	require.Equal(t, []string{
+		"vendor1.com/device=bar",
+		"vendor1.com/device=foo",
+		"vendor2.com/device=foo",
+	}, m.ListDevices())
+}
+
+func TestInjectDevices(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("device nodes are only supported on linux")
+	}
+	m := newTestManager(t)
+
+	s := newTestSpec()
+	err := m.InjectDevices(s, &pb.CDIDevice{Name: "vendor1.com/device=foo"}, &pb.CDIDevice{Name: "bar"})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"PATH=/bin", "FOO=injected", "VENDOR1=true"}, s.Process.Env)
+
+	require.Len(t, s.Linux.Devices, 2)
+	require.Equal(t, "/dev/foo", s.Linux.Devices[0].Path)
+	require.Equal(t, "c", s.Linux.Devices[0].Type)
+	require.Equal(t, int64(1), s.Linux.Devices[0].Major)
+	require.Equal(t, int64(3), s.Linux.Devices[0].Minor)
+	require.NotNil(t, s.Linux.Devices[0].FileMode)
+	require.Equal(t, "/dev/bar", s.Linux.Devices[1].Path)
+	require.Equal(t, int64(5), s.Linux.Devices[1].Minor)
+
+	require.Len(t, s.Linux.Resources.Devices, 2)
+	require.Equal(t, "rwm", s.Linux.Resources.Devices[0].Access)
+	require.Equal(t, "rw", s.Linux.Resources.Devices[1].Access)
+	require.Equal(t, int64(5), *s.Linux.Resources.Devices[1].Minor)
+}
+
+func TestInjectDevicesMount(t *testing.T) {
+	m := newTestManager(t)
+
+	s := newTestSpec()
+	err := m.InjectDevices(s, &pb.CDIDevice{Name: "vendor2.com/device=foo"})
+	require.NoError(t, err)
+	require.Equal(t, []specs.Mount{{
+		Destination: "/vendor2",
+		Type:        "bind",
+		Source:      "/tmp",
+		Options:     []string{"rbind"},
+	}}, s.Mounts)
+}
+
+func TestInjectDevicesErrors(t *testing.T) {
+	m := newTestManager(t)
+
+	s := newTestSpec()
+	err := m.InjectDevices(s, &pb.CDIDevice{Name: "foo"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ambiguous")
+
+	err = m.InjectDevices(s, &pb.CDIDevice{Name: "vendor3.com/device=baz"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+
+	err = m.InjectDevices(s, &pb.CDIDevice{Name: "vendor3.com/device=baz", Optional: true})
+	require.NoError(t, err)
+	require.Len(t, s.Linux.Devices, 0)
+}
+
+func TestRefreshOnMissingDevice(t *testing.T) {
+	m := newTestManager(t)
+	dir := m.dirs[0]
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vendor3.json"), []byte(`{"cdiVersion": "0.6.0", "kind": "vendor3.com/device", "devices": [{"name": "baz", "containerEdits": {"env": ["BAZ=1"]}}]}`), 0600))
+
+	s := newTestSpec()
+	err := m.InjectDevices(s, &pb.CDIDevice{Name: "baz"})
+	require.NoError(t, err)
+	require.Contains(t, s.Process.Env, "BAZ=1")
+}
+
+func TestUnsupportedSpecs(t *testing.T) {
+	dir := t.TempDir()
+	for name, spec := range map[string]string{
+		"hooks.yaml": `
+cdiVersion: "0.6.0"
+kind: vendor4.com/gpu
+devices:
+- name: gpu0
+  containerEdits:
+    hooks:
+    - hookName: createContainer
+      path: /usr/bin/nvidia-ctk
+`,
+		"gids.json":    `{"cdiVersion": "0.7.0", "kind": "vendor5.com/device", "devices": [{"name": "foo", "containerEdits": {}}], "containerEdits": {"additionalGIDs": [44]}}`,
+		"rdt.json":     `{"cdiVersion": "0.7.0", "kind": "vendor6.com/device", "devices": [{"name": "foo", "containerEdits": {"intelRdt": {"closID": "foo"}}}]}`,
+		"version.json": `{"cdiVersion": "1.0.0", "kind": "vendor7.com/device", "devices": [{"name": "foo", "containerEdits": {}}]}`,
+		"unknown.json": `{"cdiVersion": "0.6.0", "kind": "vendor8.com/device", "devices": [{"name": "foo", "containerEdits": {"netDevices": [{"name": "eth1"}]}}]}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(spec), 0600))
+	}
+
+	m, err := NewManager([]string{dir})
+	require.NoError(t, err)
+	require.Empty(t, m.ListDevices())
+
+	err = m.InjectDevices(newTestSpec(), &pb.CDIDevice{Name: "vendor4.com/gpu=gpu0"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+	require.Contains(t, err.Error(), "unsupported container edits: hooks")
+
+	for _, name := range []string{"gids.json", "rdt.json", "version.json", "unknown.json"} {
+		_, err := readSpec(filepath.Join(dir, name))
+		require.Error(t, err, name)
+	}
+}
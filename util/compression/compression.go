@@ -2,6 +2,7 @@ package compression
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 
@@ -30,6 +31,10 @@ const (
 	// Zstd is used for Zstandard data.
 	Zstd
 
+	// ZstdChunked is used for Zstandard data with a table of contents that
+	// allows lazy pulling of the layer.
+	ZstdChunked
+
 	// UnknownCompression means not supported yet.
 	UnknownCompression Type = -1
 )
@@ -41,6 +46,59 @@ const (
 
 var Default = Gzip
 
+// Config selects the compression of the layer blobs of an export.
+type Config struct {
+	Type Type
+	// Force converts existing blobs that use a different compression.
+	Force bool
+	// Level is the compression level. The default level of the compression
+	// type is used if it is nil.
+	Level *int
+}
+
+// New returns the configuration for compressing layers with t.
+func New(t Type) Config {
+	return Config{Type: t}
+}
+
+// Parse returns the compression type with name t.
+func Parse(t string) (Type, error) {
+	switch t {
+	case "uncompressed":
+		return Uncompressed, nil
+	case "gzip":
+		return Gzip, nil
+	case "estargz":
+		return EStargz, nil
+	case "zstd":
+		return Zstd, nil
+	case "zstd:chunked":
+		return ZstdChunked, nil
+	default:
+		return UnknownCompression, errors.Errorf("unsupported layer compression type: %v", t)
+	}
+}
+
+// CheckLevel returns an error if level is not a valid compression level of
+// the compression type.
+func (ct Type) CheckLevel(level int) error {
+	var min, max int
+	switch ct {
+	case Uncompressed:
+		return errors.Errorf("compression level is not supported for %s", ct)
+	case Gzip, EStargz:
+		min, max = gzip.HuffmanOnly, gzip.BestCompression
+	case Zstd, ZstdChunked:
+		min, max = 0, 22
+	default:
+		return errors.Errorf("unknown compression type %s", ct)
+	}
+	if level < min || level > max {
+		return errors.Errorf("invalid compression level %d for %s, expected %d to %d", level, ct, min, max)
+	}
+	return nil
+}
+
 func (ct Type) String() string {
 	switch ct {
 	case Uncompressed:
@@ -51,6 +109,8 @@ func (ct Type) String() string {
 		return "estargz"
 	case Zstd:
 		return "zstd"
+	case ZstdChunked:
+		return "zstd:chunked"
 	default:
 		return "unknown"
 	}
@@ -62,7 +122,7 @@ func (ct Type) DefaultMediaType() string {
 		return ocispecs.MediaTypeImageLayer
 	case Gzip, EStargz:
 		return ocispecs.MediaTypeImageLayerGzip
-	case Zstd:
+	case Zstd, ZstdChunked:
 		return mediaTypeImageLayerZstd
 	default:
 		return ocispecs.MediaTypeImageLayer + "+unknown"
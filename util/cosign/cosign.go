@@ -0,0 +1,238 @@
+// Package cosign creates image signatures in the format used by sigstore
+// cosign so that they can be verified with `cosign verify`.
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MediaTypeSimpleSigning is the media type of the signed payload layer.
+	MediaTypeSimpleSigning = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// AnnotationSignature is the layer annotation holding the base64 encoded
+	// signature of the payload.
+	AnnotationSignature = "dev.cosignproject.cosign/signature"
+
+	signatureType = "cosign container image signature"
+)
+
+// SignatureTag returns the tag that cosign looks up for the signatures of the
+// manifest with digest dgst.
+func SignatureTag(dgst digest.Digest) string {
+	return strings.Replace(dgst.String(), ":", "-", 1) + ".sig"
+}
+
+// LoadPrivateKey parses a PEM encoded ECDSA, RSA or Ed25519 private key.
+// Encrypted cosign keys are not supported.
+func LoadPrivateKey(dt []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(dt)
+	if block == nil {
+		return nil, errors.New("no PEM data found in signing key")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "ENCRYPTED COSIGN PRIVATE KEY", "ENCRYPTED SIGSTORE PRIVATE KEY":
+		return nil, errors.New("encrypted cosign keys are not supported, use an unencrypted PKCS#8 key")
+	default:
+		return nil, errors.Errorf("unsupported signing key type %q", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing key")
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, errors.Errorf("unsupported signing key %T", key)
+	}
+}
+
+type payload struct {
+	Critical critical          `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+type critical struct {
+	Identity identity `json:"identity"`
+	Image    image    `json:"image"`
+	Type     string   `json:"type"`
+}
+
+type identity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+type image struct {
+	DockerManifestDigest digest.Digest `json:"docker-manifest-digest"`
+}
+
+// Payload returns the simple signing payload for the manifest with digest
+// dgst pushed to repository ref.
+func Payload(ref reference.Named, dgst digest.Digest, optional map[string]string) ([]byte, error) {
+	dt, err := json.Marshal(payload{
+		Critical: critical{
+			Identity: identity{DockerReference: reference.TrimNamed(ref).String()},
+			Image:    image{DockerManifestDigest: dgst},
+			Type:     signatureType,
+		},
+		Optional: optional,
+	})
+	return dt, errors.Wrap(err, "failed to marshal signature payload")
+}
+
+// Sign signs payload with signer the way cosign verifies it.
+func Sign(signer crypto.Signer, payload []byte) ([]byte, error) {
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		return signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	h := sha256.Sum256(payload)
+	return signer.Sign(rand.Reader, h[:], crypto.SHA256)
+}
+
+// Verify checks that sig is a signature of payload made with the private key
+// of pub.
+func Verify(pub crypto.PublicKey, payload, sig []byte) error {
+	h := sha256.Sum256(payload)
+	var ok bool
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(pub, h[:], sig)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(pub, crypto.SHA256, h[:], sig) == nil
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, payload, sig)
+	default:
+		return errors.Errorf("unsupported public key %T", pub)
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// Blob is a blob of the signature image.
+type Blob struct {
+	Descriptor ocispecs.Descriptor
+	Data       []byte
+}
+
+// Signature is the image that cosign stores signatures in.
+type Signature struct {
+	Manifest Blob
+	Config   Blob
+	Layers   []Blob
+}
+
+// NewSignature returns the signature image for payload signed with sig. The
+// layers of prev, the signature manifest the registry already has for the
+// image, are kept so that the signatures of other signers and earlier pushes
+// are not replaced. The signature is only appended if prev doesn't contain it
+// yet.
+func NewSignature(prev *ocispecs.Manifest, payload, sig []byte) (*Signature, error) {
+	layer := Blob{
+		Descriptor: ocispecs.Descriptor{
+			MediaType: MediaTypeSimpleSigning,
+			Digest:    digest.FromBytes(payload),
+			Size:      int64(len(payload)),
+			Annotations: map[string]string{
+				AnnotationSignature: base64.StdEncoding.EncodeToString(sig),
+			},
+		},
+		Data: payload,
+	}
+
+	var layers []ocispecs.Descriptor
+	if prev != nil {
+		layers = append(layers, prev.Layers...)
+	}
+	if !hasLayer(layers, layer.Descriptor) {
+		layers = append(layers, layer.Descriptor)
+	}
+
+	var img ocispecs.Image
+	img.RootFS.Type = "layers"
+	for _, l := range layers {
+		img.RootFS.DiffIDs = append(img.RootFS.DiffIDs, l.Digest)
+	}
+	configData, err := json.Marshal(img)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signature config")
+	}
+	config := Blob{
+		Descriptor: ocispecs.Descriptor{
+			MediaType: ocispecs.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(configData),
+			Size:      int64(len(configData)),
+		},
+		Data: configData,
+	}
+
+	mfst := struct {
+		MediaType string `json:"mediaType,omitempty"`
+		ocispecs.Manifest
+	}{
+		MediaType: ocispecs.MediaTypeImageManifest,
+		Manifest: ocispecs.Manifest{
+			Versioned: specs.Versioned{
+				SchemaVersion: 2,
+			},
+			Config: config.Descriptor,
+			Layers: layers,
+		},
+	}
+	mfstData, err := json.Marshal(mfst)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signature manifest")
+	}
+
+	return &Signature{
+		Manifest: Blob{
+			Descriptor: ocispecs.Descriptor{
+				MediaType: ocispecs.MediaTypeImageManifest,
+				Digest:    digest.FromBytes(mfstData),
+				Size:      int64(len(mfstData)),
+			},
+			Data: mfstData,
+		},
+		Config: config,
+		Layers: []Blob{layer},
+	}, nil
+}
+
+func hasLayer(layers []ocispecs.Descriptor, desc ocispecs.Descriptor) bool {
+	for _, l := range layers {
+		if l.Digest == desc.Digest && l.Annotations[AnnotationSignature] == desc.Annotations[AnnotationSignature] {
+			return true
+		}
+	}
+	return false
+}
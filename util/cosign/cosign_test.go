@@ -0,0 +1,91 @@
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, key := range []crypto.Signer{ecKey, edKey} {
+		dt, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+
+		signer, err := LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: dt}))
+		require.NoError(t, err)
+
+		ref, err := reference.ParseNormalizedNamed("example.com/foo/bar:latest")
+		require.NoError(t, err)
+		dgst := digest.FromString("manifest")
+
+		payload, err := Payload(ref, dgst, map[string]string{"commit": "abc"})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"critical":{"identity":{"docker-reference":"example.com/foo/bar"},"image":{"docker-manifest-digest":"`+dgst.String()+`"},"type":"cosign container image signature"},"optional":{"commit":"abc"}}`, string(payload))
+
+		sig, err := Sign(signer, payload)
+		require.NoError(t, err)
+		require.NoError(t, Verify(key.Public(), payload, sig))
+		require.Error(t, Verify(key.Public(), []byte("other"), sig))
+
+		s, err := NewSignature(nil, payload, sig)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(s.Layers))
+		require.Equal(t, MediaTypeSimpleSigning, s.Layers[0].Descriptor.MediaType)
+		require.Equal(t, base64.StdEncoding.EncodeToString(sig), s.Layers[0].Descriptor.Annotations[AnnotationSignature])
+
+		var mfst ocispecs.Manifest
+		require.NoError(t, json.Unmarshal(s.Manifest.Data, &mfst))
+		require.Equal(t, s.Config.Descriptor, mfst.Config)
+		require.Equal(t, s.Layers[0].Descriptor, mfst.Layers[0])
+		require.Equal(t, digest.FromBytes(s.Manifest.Data), s.Manifest.Descriptor.Digest)
+
+		// signatures already in the registry are kept
+		other := ocispecs.Descriptor{
+			MediaType:   MediaTypeSimpleSigning,
+			Digest:      digest.FromString("other"),
+			Size:        5,
+			Annotations: map[string]string{AnnotationSignature: "b3RoZXI="},
+		}
+		s, err = NewSignature(&ocispecs.Manifest{Layers: []ocispecs.Descriptor{other}}, payload, sig)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(s.Manifest.Data, &mfst))
+		require.Equal(t, []ocispecs.Descriptor{other, s.Layers[0].Descriptor}, mfst.Layers)
+		var img ocispecs.Image
+		require.NoError(t, json.Unmarshal(s.Config.Data, &img))
+		require.Equal(t, []digest.Digest{other.Digest, s.Layers[0].Descriptor.Digest}, img.RootFS.DiffIDs)
+
+		// the same signature is not appended twice
+		prev := mfst
+		s, err = NewSignature(&prev, payload, sig)
+		require.NoError(t, err)
+		var merged ocispecs.Manifest
+		require.NoError(t, json.Unmarshal(s.Manifest.Data, &merged))
+		require.Equal(t, prev.Layers, merged.Layers)
+	}
+
+	require.Equal(t, "sha256-"+digest.FromString("manifest").Hex()+".sig", SignatureTag(digest.FromString("manifest")))
+
+	_, err = LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED COSIGN PRIVATE KEY", Bytes: []byte("foo")}))
+	require.Error(t, err)
+	_, err = LoadPrivateKey([]byte("foo"))
+	require.Error(t, err)
+}
@@ -0,0 +1,158 @@
+// Package fairqueue limits the number of operations running in parallel and
+// hands out free slots fairly between the builds waiting for them.
+package fairqueue
+
+import (
+	"context"
+	"sync"
+)
+
+// Group identifies the build an operation is run for.
+type Group struct {
+	// ID is shared by all operations of the same build.
+	ID string
+	// Priority of the build. Operations of builds with higher priority are
+	// always started first.
+	Priority int
+	// Weight of the build. Builds of the same priority get slots in
+	// proportion to their weight. Values below 1 are treated as 1.
+	Weight int
+}
+
+func (g Group) weight() int {
+	if g.Weight < 1 {
+		return 1
+	}
+	return g.Weight
+}
+
+// Queue is a counting semaphore. When a slot becomes free it is given to the
+// waiting operation with the highest priority. Between builds of the same
+// priority, the one with the fewest running operations for its weight goes
+// first so that a large build can't starve smaller ones. Operations of the
+// same build are started in order.
+type Queue struct {
+	mu      sync.Mutex
+	size    int
+	cur     int
+	running map[string]int
+	waiters []*waiter
+	seq     uint64
+}
+
+type waiter struct {
+	group Group
+	seq   uint64
+	ready chan struct{}
+}
+
+// New returns a queue that runs at most size operations in parallel.
+func New(size int) *Queue {
+	return &Queue{
+		size:    size,
+		running: map[string]int{},
+	}
+}
+
+// Acquire waits for a free slot for an operation of group g. If the operation
+// needs to wait, queued is called before blocking. The returned function
+// releases the slot.
+func (q *Queue) Acquire(ctx context.Context, g Group, queued func()) (func(), error) {
+	q.mu.Lock()
+	if q.cur < q.size && len(q.waiters) == 0 {
+		q.take(g)
+		q.mu.Unlock()
+		return q.releaseFunc(g), nil
+	}
+	w := &waiter{group: g, seq: q.seq, ready: make(chan struct{})}
+	q.seq++
+	q.waiters = append(q.waiters, w)
+	q.mu.Unlock()
+
+	if queued != nil {
+		queued()
+	}
+
+	select {
+	case <-w.ready:
+		return q.releaseFunc(g), nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-w.ready:
+			// the slot was handed out while canceling
+			q.mu.Unlock()
+			q.release(g)
+		default:
+			q.remove(w)
+			q.mu.Unlock()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Waiting returns the number of operations waiting for a slot.
+func (q *Queue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiters)
+}
+
+func (q *Queue) releaseFunc(g Group) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.release(g)
+		})
+	}
+}
+
+func (q *Queue) release(g Group) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cur--
+	if q.running[g.ID]--; q.running[g.ID] <= 0 {
+		delete(q.running, g.ID)
+	}
+	q.dispatch()
+}
+
+func (q *Queue) take(g Group) {
+	q.cur++
+	q.running[g.ID]++
+}
+
+func (q *Queue) remove(w *waiter) {
+	for i, w2 := range q.waiters {
+		if w2 == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *Queue) dispatch() {
+	for q.cur < q.size && len(q.waiters) > 0 {
+		next := q.waiters[0]
+		for _, w := range q.waiters[1:] {
+			if q.before(w, next) {
+				next = w
+			}
+		}
+		q.remove(next)
+		q.take(next.group)
+		close(next.ready)
+	}
+}
+
+// before returns true if w should get a slot before w2.
+func (q *Queue) before(w, w2 *waiter) bool {
+	if w.group.Priority != w2.group.Priority {
+		return w.group.Priority > w2.group.Priority
+	}
+	// compare running/weight without dividing
+	if r, r2 := q.running[w.group.ID]*w2.group.weight(), q.running[w2.group.ID]*w.group.weight(); r != r2 {
+		return r < r2
+	}
+	return w.seq < w2.seq
+}
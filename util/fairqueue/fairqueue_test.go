@@ -0,0 +1,156 @@
+package fairqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueFairShare(t *testing.T) {
+	t.Parallel()
+
+	q := New(2)
+	ctx := context.TODO()
+
+	big := Group{ID: "big"}
+	small := Group{ID: "small"}
+
+	r1, err := q.Acquire(ctx, big, nil)
+	require.NoError(t, err)
+	r2, err := q.Acquire(ctx, big, nil)
+	require.NoError(t, err)
+
+	started := make(chan string, 10)
+	acquire := func(g Group) {
+		queued := make(chan struct{})
+		go func() {
+			release, err := q.Acquire(ctx, g, func() { close(queued) })
+			if err != nil {
+				return
+			}
+			started <- g.ID
+			release()
+		}()
+		<-queued
+	}
+
+	// more operations of the big build are queued before the small one
+	acquire(big)
+	acquire(big)
+	acquire(small)
+	require.Equal(t, 3, q.Waiting())
+
+	// the small build has nothing running so it gets the first free slot
+	r1()
+	require.Equal(t, "small", recv(t, started))
+
+	r2()
+	require.Equal(t, "big", recv(t, started))
+	require.Equal(t, "big", recv(t, started))
+	require.Equal(t, 0, q.Waiting())
+}
+
+func TestQueueWeight(t *testing.T) {
+	t.Parallel()
+
+	q := New(3)
+	ctx := context.TODO()
+
+	heavy := Group{ID: "heavy", Weight: 2}
+	light := Group{ID: "light"}
+
+	r1, err := q.Acquire(ctx, heavy, nil)
+	require.NoError(t, err)
+	defer r1()
+	r2, err := q.Acquire(ctx, light, nil)
+	require.NoError(t, err)
+	defer r2()
+	r3, err := q.Acquire(ctx, Group{ID: "other"}, nil)
+	require.NoError(t, err)
+
+	started := make(chan string, 10)
+	for _, g := range []Group{light, heavy} {
+		g := g
+		queued := make(chan struct{})
+		go func() {
+			release, err := q.Acquire(ctx, g, func() { close(queued) })
+			if err != nil {
+				return
+			}
+			started <- g.ID
+			release()
+		}()
+		<-queued
+	}
+
+	// both builds run one operation but the heavy one has twice the share
+	r3()
+	require.Equal(t, "heavy", recv(t, started))
+	require.Equal(t, "light", recv(t, started))
+}
+
+func TestQueuePriority(t *testing.T) {
+	t.Parallel()
+
+	q := New(1)
+	ctx := context.TODO()
+
+	r, err := q.Acquire(ctx, Group{ID: "a"}, nil)
+	require.NoError(t, err)
+
+	started := make(chan string, 10)
+	for _, g := range []Group{{ID: "low"}, {ID: "high", Priority: 10}} {
+		g := g
+		queued := make(chan struct{})
+		go func() {
+			release, err := q.Acquire(ctx, g, func() { close(queued) })
+			if err != nil {
+				return
+			}
+			started <- g.ID
+			release()
+		}()
+		<-queued
+	}
+
+	r()
+	require.Equal(t, "high", recv(t, started))
+	require.Equal(t, "low", recv(t, started))
+}
+
+func TestQueueCancel(t *testing.T) {
+	t.Parallel()
+
+	q := New(1)
+
+	r, err := q.Acquire(context.TODO(), Group{ID: "a"}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	errCh := make(chan error)
+	go func() {
+		_, err := q.Acquire(ctx, Group{ID: "b"}, cancel)
+		errCh <- err
+	}()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.Equal(t, 0, q.Waiting())
+
+	// releasing twice doesn't free more slots than acquired
+	r()
+	r()
+	r, err = q.Acquire(context.TODO(), Group{ID: "c"}, nil)
+	require.NoError(t, err)
+	r()
+}
+
+func recv(t *testing.T, ch chan string) string {
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for operation to start")
+	}
+	return ""
+}
@@ -10,10 +10,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/containerd/console"
 	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver/errdefs"
 	"github.com/morikuni/aec"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/tonistiigi/units"
@@ -70,6 +72,8 @@ func DisplaySolveStatus(ctx context.Context, phase string, c console.Console, w
 			if done {
 				disp.print(t.displayInfo(), width, height, true)
 				t.printErrorLogs(c)
+				t.printWarnings(c)
+				t.printResourceUsage(c)
 				return nil
 			} else if displayLimiter.Allow() {
 				ticker.Stop()
@@ -81,6 +85,8 @@ func DisplaySolveStatus(ctx context.Context, phase string, c console.Console, w
 				printer.print(t)
 				if done {
 					t.printErrorLogs(w)
+					t.printWarnings(w)
+					t.printResourceUsage(w)
 					return nil
 				}
 				ticker.Stop()
@@ -119,6 +125,7 @@ type trace struct {
 	nextIndex     int
 	updates       map[digest.Digest]struct{}
 	modeConsole   bool
+	warnings      []client.VertexWarning
 }
 
 type vertex struct {
@@ -137,6 +144,7 @@ type vertex struct {
 	lastBlockTime *time.Time
 	count         int
 	statusUpdates map[string]struct{}
+	resources     *client.VertexResources
 
 	jobs      []*job
 	jobCached bool
@@ -290,6 +298,16 @@ func (t *trace) update(s *client.SolveStatus, termWidth int) {
 		t.updates[v.Digest] = struct{}{}
 		v.update(1)
 	}
+	for _, r := range s.Resources {
+		v, ok := t.byDigest[r.Vertex]
+		if !ok {
+			continue // shouldn't happen
+		}
+		v.resources = r
+	}
+	for _, w := range s.Warnings {
+		t.warnings = append(t.warnings, *w)
+	}
 }
 
 func (t *trace) printErrorLogs(f io.Writer) {
@@ -316,6 +334,74 @@ func (t *trace) printErrorLogs(f io.Writer) {
 	}
 }
 
+// printWarnings prints the warnings reported by frontends together with the
+// lines of the source they point to.
+func (t *trace) printWarnings(f io.Writer) {
+	if len(t.warnings) == 0 {
+		return
+	}
+	fmt.Fprintln(f, "------")
+	if len(t.warnings) == 1 {
+		fmt.Fprintln(f, " 1 warning found:")
+	} else {
+		fmt.Fprintf(f, " %d warnings found:\n", len(t.warnings))
+	}
+	for _, w := range t.warnings {
+		fmt.Fprintf(f, " - %s\n", w.Short)
+		for _, d := range w.Detail {
+			fmt.Fprintf(f, "%s\n", d)
+		}
+		if w.URL != "" {
+			fmt.Fprintf(f, "More info: %s\n", w.URL)
+		}
+		if w.SourceInfo != nil && len(w.Range) > 0 {
+			src := errdefs.Source{
+				Info:   w.SourceInfo,
+				Ranges: w.Range,
+			}
+			src.Print(f)
+		}
+		fmt.Fprintln(f)
+	}
+	fmt.Fprintln(f, "------")
+}
+
+// printResourceUsage prints a summary table of the resources used by the
+// processes of each vertex. Nothing is printed if no usage was reported.
+func (t *trace) printResourceUsage(f io.Writer) {
+	var vertexes []*vertex
+	for _, v := range t.vertexes {
+		if v.resources != nil {
+			vertexes = append(vertexes, v)
+		}
+	}
+	if len(vertexes) == 0 {
+		return
+	}
+	fmt.Fprintln(f, "------")
+	fmt.Fprintln(f, " resource usage:")
+	tw := tabwriter.NewWriter(f, 1, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, " #\tCPU\tMEMORY PEAK\tIO READ\tIO WRITE\tPIDS PEAK\tNAME")
+	for _, v := range vertexes {
+		r := v.resources
+		name := strings.Replace(v.Name, "\t", " ", -1)
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		fmt.Fprintf(tw, " #%d\t%.2fs\t%.2f\t%.2f\t%.2f\t%d\t%s\n",
+			v.index,
+			time.Duration(r.CPUNanos).Seconds(),
+			units.Bytes(r.MemoryPeak),
+			units.Bytes(r.IOReadBytes),
+			units.Bytes(r.IOWriteBytes),
+			r.PidsPeak,
+			name,
+		)
+	}
+	tw.Flush()
+	fmt.Fprintln(f, "------")
+}
+
 func (t *trace) displayInfo() (d displayInfo) {
 	d.startTime = time.Now()
 	if t.localTimeDiff != 0 {
@@ -51,6 +51,9 @@ func ResetTime(in Writer) Writer {
 					for _, v := range st.Logs {
 						v.Timestamp = v.Timestamp.Add(-*w.diff)
 					}
+					for _, v := range st.Resources {
+						v.Timestamp = v.Timestamp.Add(-*w.diff)
+					}
 				}
 				in.Status() <- st
 			}
@@ -0,0 +1,140 @@
+package push
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const distributionSourcePrefix = "containerd.io/distribution.source."
+
+// progressPusher reports the upload progress of every blob pushed through it
+// and falls back to a regular upload if mounting a blob from another
+// repository fails.
+type progressPusher struct {
+	remotes.Pusher
+}
+
+func (p *progressPusher) Push(ctx context.Context, desc ocispecs.Descriptor) (content.Writer, error) {
+	if isManifest(desc.MediaType) {
+		return p.Pusher.Push(ctx, desc)
+	}
+
+	pw, _, _ := progress.NewFromContext(ctx)
+	now := time.Now()
+	st := progress.Status{
+		Action:  "pushing",
+		Total:   int(desc.Size),
+		Started: &now,
+	}
+	pw.Write(desc.Digest.String(), st)
+
+	w, err := p.Pusher.Push(ctx, desc)
+	if err != nil && !errdefs.IsAlreadyExists(err) && hasDistributionSource(desc) {
+		// the registry may refuse the cross-repository mount for reasons other
+		// than authorization, upload the blob instead
+		logrus.Debugf("failed to mount %s from another repository, retrying without mount: %v", desc.Digest, err)
+		w, err = p.Pusher.Push(ctx, withoutDistributionSource(desc))
+	}
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			now := time.Now()
+			st.Action = "exists"
+			st.Current = st.Total
+			st.Completed = &now
+			pw.Write(desc.Digest.String(), st)
+		}
+		pw.Close()
+		return nil, err
+	}
+	if ws, err := w.Status(); err == nil && ws.Offset > 0 {
+		// resumed upload
+		st.Current = int(ws.Offset)
+		pw.Write(desc.Digest.String(), st)
+	}
+	return &progressWriter{Writer: w, pw: pw, id: desc.Digest.String(), st: st}, nil
+}
+
+type progressWriter struct {
+	content.Writer
+	pw progress.Writer
+	id string
+
+	mu   sync.Mutex
+	st   progress.Status
+	once sync.Once
+}
+
+func (w *progressWriter) Write(dt []byte) (int, error) {
+	n, err := w.Writer.Write(dt)
+	w.mu.Lock()
+	w.st.Current += n
+	w.pw.Write(w.id, w.st)
+	w.mu.Unlock()
+	return n, err
+}
+
+func (w *progressWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	err := w.Writer.Commit(ctx, size, expected, opts...)
+	if err == nil || errdefs.IsAlreadyExists(err) {
+		w.mu.Lock()
+		now := time.Now()
+		w.st.Action = "pushed"
+		w.st.Current = w.st.Total
+		w.st.Completed = &now
+		w.pw.Write(w.id, w.st)
+		w.mu.Unlock()
+	}
+	w.close()
+	return err
+}
+
+func (w *progressWriter) Close() error {
+	w.close()
+	return w.Writer.Close()
+}
+
+func (w *progressWriter) close() {
+	w.once.Do(func() {
+		w.pw.Close()
+	})
+}
+
+func isManifest(mt string) bool {
+	switch mt {
+	case images.MediaTypeDockerSchema2Manifest, ocispecs.MediaTypeImageManifest,
+		images.MediaTypeDockerSchema2ManifestList, ocispecs.MediaTypeImageIndex:
+		return true
+	}
+	return false
+}
+
+func hasDistributionSource(desc ocispecs.Descriptor) bool {
+	for k := range desc.Annotations {
+		if strings.HasPrefix(k, distributionSourcePrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutDistributionSource(desc ocispecs.Descriptor) ocispecs.Descriptor {
+	annotations := make(map[string]string, len(desc.Annotations))
+	for k, v := range desc.Annotations {
+		if !strings.HasPrefix(k, distributionSourcePrefix) {
+			annotations[k] = v
+		}
+	}
+	desc.Annotations = annotations
+	return desc
+}
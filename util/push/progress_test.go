@@ -0,0 +1,112 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressPusher(t *testing.T) {
+	t.Parallel()
+
+	pr, ctx, cancel := progress.NewContext(context.TODO())
+
+	dt := []byte("layer data")
+	layer := ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageLayerGzip,
+		Digest:    digest.FromBytes(dt),
+		Size:      int64(len(dt)),
+		Annotations: map[string]string{
+			"containerd.io/distribution.source.example.com": "foo/bar",
+		},
+	}
+	existing := ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageConfig,
+		Digest:    digest.FromString("config"),
+		Size:      6,
+	}
+
+	tp := &testPusher{exists: map[digest.Digest]struct{}{existing.Digest: {}}}
+	p := &progressPusher{Pusher: tp}
+
+	w, err := p.Push(ctx, layer)
+	require.NoError(t, err)
+	_, err = w.Write(dt)
+	require.NoError(t, err)
+	require.NoError(t, w.Commit(ctx, layer.Size, layer.Digest))
+	require.NoError(t, w.Close())
+	require.Equal(t, 2, tp.calls)
+	require.Equal(t, dt, tp.buf.Bytes())
+
+	_, err = p.Push(ctx, existing)
+	require.True(t, errdefs.IsAlreadyExists(err))
+	cancel()
+
+	statuses := map[string]progress.Status{}
+	for {
+		ps, err := pr.Read(context.TODO())
+		if err != nil {
+			break
+		}
+		for _, p := range ps {
+			statuses[p.ID] = p.Sys.(progress.Status)
+		}
+	}
+	st := statuses[layer.Digest.String()]
+	require.Equal(t, "pushed", st.Action)
+	require.Equal(t, len(dt), st.Current)
+	require.NotNil(t, st.Completed)
+
+	st = statuses[existing.Digest.String()]
+	require.Equal(t, "exists", st.Action)
+	require.NotNil(t, st.Completed)
+}
+
+type testPusher struct {
+	calls  int
+	exists map[digest.Digest]struct{}
+	buf    bytes.Buffer
+}
+
+func (p *testPusher) Push(ctx context.Context, desc ocispecs.Descriptor) (content.Writer, error) {
+	p.calls++
+	if _, ok := p.exists[desc.Digest]; ok {
+		return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "content %v on remote", desc.Digest)
+	}
+	if hasDistributionSource(desc) {
+		return nil, errors.New("mount not allowed")
+	}
+	return &testWriter{buf: &p.buf}, nil
+}
+
+type testWriter struct {
+	content.Writer
+	buf *bytes.Buffer
+}
+
+func (w *testWriter) Write(dt []byte) (int, error) {
+	return w.buf.Write(dt)
+}
+
+func (w *testWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	if digest.FromBytes(w.buf.Bytes()) != expected {
+		return errors.New("digest mismatch")
+	}
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	return nil
+}
+
+func (w *testWriter) Status() (content.Status, error) {
+	return content.Status{}, nil
+}
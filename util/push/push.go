@@ -11,9 +11,11 @@ import (
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/docker/distribution/reference"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/flightcontrol"
 	"github.com/moby/buildkit/util/imageutil"
 	"github.com/moby/buildkit/util/progress"
@@ -28,7 +30,27 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func Push(ctx context.Context, sm *session.Manager, sid string, provider content.Provider, manager content.Manager, dgst digest.Digest, ref string, insecure bool, hosts docker.RegistryHosts, byDigest bool, annotations map[digest.Digest]map[string]string) error {
+// Opts control how an image is pushed.
+type Opts struct {
+	// Insecure allows pushing to the registry over plain HTTP.
+	Insecure bool
+	// Hosts configures the registries. Defaults to the public ones.
+	Hosts docker.RegistryHosts
+	// ByDigest pushes the image without updating the tag of the ref.
+	ByDigest bool
+	// Annotations are added to the descriptors of the pushed blobs, mostly to
+	// set their distribution source.
+	Annotations map[digest.Digest]map[string]string
+	// Retry controls how uploads failing with a temporary error are retried.
+	Retry retryhandler.Opts
+}
+
+// Push pushes the image with root dgst to ref. Blob uploads failing with a
+// temporary error are retried according to opts.Retry and continue from the
+// data the registry already received. Blobs already in the registry, or
+// mountable from another repository of the same registry, are not uploaded
+// again.
+func Push(ctx context.Context, sm *session.Manager, sid string, provider content.Provider, manager content.Manager, dgst digest.Digest, ref string, opts Opts) error {
 	desc := ocispecs.Descriptor{
 		Digest: dgst,
 	}
@@ -36,11 +58,11 @@ func Push(ctx context.Context, sm *session.Manager, sid string, provider content
 	if err != nil {
 		return err
 	}
-	if byDigest && !reference.IsNameOnly(parsed) {
+	if opts.ByDigest && !reference.IsNameOnly(parsed) {
 		return errors.Errorf("can't push tagged ref %s by digest", parsed.String())
 	}
 
-	if byDigest {
+	if opts.ByDigest {
 		ref = parsed.Name()
 	} else {
 		// add digest to ref, this is what containderd uses to choose root manifest from all manifests
@@ -51,25 +73,16 @@ func Push(ctx context.Context, sm *session.Manager, sid string, provider content
 		ref = r.String()
 	}
 
-	scope := "push"
-	if insecure {
-		insecureTrue := true
-		httpTrue := true
-		hosts = resolver.NewRegistryConfig(map[string]resolverconfig.RegistryConfig{
-			reference.Domain(parsed): {
-				Insecure:  &insecureTrue,
-				PlainHTTP: &httpTrue,
-			},
-		})
-		scope += ":insecure"
-	}
-
-	resolver := resolver.DefaultPool.GetResolver(hosts, ref, scope, sm, session.NewGroup(sid))
+	resolver := getResolver(sm, sid, parsed, ref, opts.Insecure, opts.Hosts)
 
 	pusher, err := resolver.Pusher(ctx, ref)
 	if err != nil {
 		return err
 	}
+	pusher, err = newResumablePusher(pusher, resolver.HostsFunc, ref)
+	if err != nil {
+		return err
+	}
 
 	var m sync.Mutex
 	manifestStack := []ocispecs.Descriptor{}
@@ -87,14 +100,14 @@ func Push(ctx context.Context, sm *session.Manager, sid string, provider content
 		}
 	})
 
-	pushHandler := retryhandler.New(limited.PushHandler(pusher, provider, ref), logs.LoggerFromContext(ctx))
+	pushHandler := retryhandler.NewWithOpts(limited.PushHandler(&progressPusher{Pusher: pusher}, provider, ref), logs.LoggerFromContext(ctx), opts.Retry)
 	pushUpdateSourceHandler, err := updateDistributionSourceHandler(manager, pushHandler, ref)
 	if err != nil {
 		return err
 	}
 
 	handlers := append([]images.Handler{},
-		images.HandlerFunc(annotateDistributionSourceHandler(manager, annotations, childrenHandler(provider))),
+		images.HandlerFunc(annotateDistributionSourceHandler(manager, opts.Annotations, childrenHandler(provider))),
 		filterHandler,
 		dedupeHandler(pushUpdateSourceHandler),
 	)
@@ -128,6 +141,124 @@ func Push(ctx context.Context, sm *session.Manager, sid string, provider content
 	return mfstDone(nil)
 }
 
+// Upload pushes the blobs and manifests of the image with root dgst to the
+// repository of ref by digest. No tag is updated, use Tag to point the tag of
+// ref to the uploaded image.
+func Upload(ctx context.Context, sm *session.Manager, sid string, provider content.Provider, manager content.Manager, dgst digest.Digest, ref string, opts Opts) error {
+	parsed, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return err
+	}
+	opts.ByDigest = true
+	return Push(ctx, sm, sid, provider, manager, dgst, parsed.Name(), opts)
+}
+
+// ResolveTag returns the descriptor of the manifest the tag of ref points to,
+// or nil if the tag does not exist in the registry.
+func ResolveTag(ctx context.Context, sm *session.Manager, sid string, ref string, opts Opts) (*ocispecs.Descriptor, error) {
+	parsed, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, err
+	}
+	ref = reference.TagNameOnly(parsed).String()
+
+	_, desc, err := getResolver(sm, sid, parsed, ref, opts.Insecure, opts.Hosts).Resolve(ctx, ref)
+	if err != nil {
+		if errors.Is(err, errdefs.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &desc, nil
+}
+
+// FetchTag returns the descriptor and the content of the manifest the tag of
+// ref points to, or nil if the tag does not exist in the registry.
+func FetchTag(ctx context.Context, sm *session.Manager, sid string, ref string, opts Opts) (*ocispecs.Descriptor, []byte, error) {
+	parsed, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	ref = reference.TagNameOnly(parsed).String()
+
+	resolver := getResolver(sm, sid, parsed, ref, opts.Insecure, opts.Hosts)
+	_, desc, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		if errors.Is(err, errdefs.ErrNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	fetcher, err := resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	dt, err := content.ReadBlob(ctx, contentutil.FromFetcher(fetcher), desc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &desc, dt, nil
+}
+
+// Tag points the tag of ref to the manifest desc that was uploaded to the
+// repository before. The manifest is read from provider. If provider is nil,
+// the manifest is fetched from the repository instead.
+func Tag(ctx context.Context, sm *session.Manager, sid string, provider content.Provider, desc ocispecs.Descriptor, ref string, opts Opts) error {
+	parsed, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return err
+	}
+	if reference.IsNameOnly(parsed) {
+		parsed = reference.TagNameOnly(parsed)
+	}
+	tagged, ok := parsed.(reference.NamedTagged)
+	if !ok {
+		return errors.Errorf("can't tag ref %s without tag", parsed.String())
+	}
+	tagDone := oneOffProgress(ctx, fmt.Sprintf("pointing %s to %s", reference.FamiliarString(tagged), desc.Digest))
+
+	// containerd pushes the manifest to the tag if the ref also contains the
+	// digest of the manifest
+	r, err := reference.WithDigest(tagged, desc.Digest)
+	if err != nil {
+		return tagDone(errors.Wrapf(err, "failed to combine ref %s with digest %s", ref, desc.Digest))
+	}
+	ref = r.String()
+
+	resolver := getResolver(sm, sid, parsed, ref, opts.Insecure, opts.Hosts)
+	if provider == nil {
+		fetcher, err := resolver.Fetcher(ctx, ref)
+		if err != nil {
+			return tagDone(err)
+		}
+		provider = contentutil.FromFetcher(fetcher)
+	}
+
+	pusher, err := resolver.Pusher(ctx, ref)
+	if err != nil {
+		return tagDone(err)
+	}
+	_, err = retryhandler.NewWithOpts(remotes.PushHandler(pusher, provider), logs.LoggerFromContext(ctx), opts.Retry)(ctx, desc)
+	return tagDone(err)
+}
+
+func getResolver(sm *session.Manager, sid string, parsed reference.Named, ref string, insecure bool, hosts docker.RegistryHosts) *resolver.Resolver {
+	scope := "push"
+	if insecure {
+		insecureTrue := true
+		httpTrue := true
+		hosts = resolver.NewRegistryConfig(map[string]resolverconfig.RegistryConfig{
+			reference.Domain(parsed): {
+				Insecure:  &insecureTrue,
+				PlainHTTP: &httpTrue,
+			},
+		})
+		scope += ":insecure"
+	}
+
+	return resolver.DefaultPool.GetResolver(hosts, ref, scope, sm, session.NewGroup(sid))
+}
+
 func annotateDistributionSourceHandler(manager content.Manager, annotations map[digest.Digest]map[string]string, f images.HandlerFunc) func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
 	return func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
 		children, err := f(ctx, desc)
@@ -148,7 +279,7 @@ func annotateDistributionSourceHandler(manager content.Manager, annotations map[
 
 			if m, ok := annotations[child.Digest]; ok {
 				for k, v := range m {
-					if !strings.HasPrefix(k, "containerd.io/distribution.source.") {
+					if !strings.HasPrefix(k, distributionSourcePrefix) {
 						continue
 					}
 					if child.Annotations == nil {
@@ -167,7 +298,7 @@ func annotateDistributionSourceHandler(manager content.Manager, annotations map[
 			}
 
 			for k, v := range info.Labels {
-				if !strings.HasPrefix(k, "containerd.io/distribution.source.") {
+				if !strings.HasPrefix(k, distributionSourcePrefix) {
 					continue
 				}
 
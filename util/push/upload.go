@@ -0,0 +1,359 @@
+package push
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/reference"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// resumablePusher uploads blobs through upload sessions that are kept when an
+// attempt fails. When a blob is pushed again, the upload continues from the
+// offset the registry reports for the session instead of starting over. If
+// the registry can't report the state of the session, the upload starts over.
+// Manifests are pushed with the wrapped pusher.
+type resumablePusher struct {
+	remotes.Pusher
+	refspec reference.Spec
+	host    docker.RegistryHost
+	repo    string
+
+	mu       sync.Mutex
+	sessions map[digest.Digest]*url.URL
+}
+
+func newResumablePusher(p remotes.Pusher, hosts docker.RegistryHosts, ref string) (remotes.Pusher, error) {
+	refspec, err := reference.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	domain := refspec.Hostname()
+	hs, err := hosts(domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hs {
+		if h.Capabilities.Has(docker.HostCapabilityPush) {
+			return &resumablePusher{
+				Pusher:   p,
+				refspec:  refspec,
+				host:     h,
+				repo:     strings.TrimPrefix(refspec.Locator, domain+"/"),
+				sessions: map[digest.Digest]*url.URL{},
+			}, nil
+		}
+	}
+	return p, nil
+}
+
+func (p *resumablePusher) Push(ctx context.Context, desc ocispecs.Descriptor) (content.Writer, error) {
+	if isManifest(desc.MediaType) {
+		return p.Pusher.Push(ctx, desc)
+	}
+	ctx, err := docker.ContextWithRepositoryScope(ctx, p.refspec, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if u := p.session(desc.Digest); u != nil {
+		offset, u, err := p.status(ctx, u)
+		if err == nil {
+			p.setSession(desc.Digest, u)
+			return p.writer(ctx, desc, u, offset), nil
+		}
+		logrus.Debugf("failed to resume upload of %s, starting over: %v", desc.Digest, err)
+		p.setSession(desc.Digest, nil)
+	}
+
+	resp, err := p.do(ctx, http.MethodHead, p.url("blobs", desc.Digest.String()), nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "content %v on remote", desc.Digest)
+	}
+
+	u := p.url("blobs", "uploads") + "/"
+	if from := mountCandidate(p.refspec, desc); from != "" {
+		ctx = docker.ContextWithAppendPullRepositoryScope(ctx, from)
+		u += "?" + url.Values{"mount": {desc.Digest.String()}, "from": {from}}.Encode()
+	}
+	resp, err = p.do(ctx, http.MethodPost, u, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "content %v on remote", desc.Digest)
+	case http.StatusAccepted:
+	default:
+		return nil, remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+	loc, err := p.location(resp)
+	if err != nil {
+		return nil, err
+	}
+	p.setSession(desc.Digest, loc)
+	return p.writer(ctx, desc, loc, 0), nil
+}
+
+// status returns the number of bytes the registry received for the upload
+// session u and the location to continue the upload at.
+func (p *resumablePusher) status(ctx context.Context, u *url.URL) (int64, *url.URL, error) {
+	resp, err := p.do(ctx, http.MethodGet, u.String(), nil, 0, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return 0, nil, remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+	var offset int64
+	if r := resp.Header.Get("Range"); r != "" {
+		i := strings.IndexByte(r, '-')
+		if i < 0 {
+			return 0, nil, errors.Errorf("invalid range %q", r)
+		}
+		end, err := strconv.ParseInt(r[i+1:], 10, 64)
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "invalid range %q", r)
+		}
+		offset = end + 1
+	}
+	if resp.Header.Get("Location") != "" {
+		if u, err = p.location(resp); err != nil {
+			return 0, nil, err
+		}
+	}
+	return offset, u, nil
+}
+
+func (p *resumablePusher) writer(ctx context.Context, desc ocispecs.Descriptor, u *url.URL, offset int64) *uploadWriter {
+	w := &uploadWriter{
+		p:      p,
+		desc:   desc,
+		offset: offset,
+		respC:  make(chan uploadResponse, 1),
+	}
+	if offset >= desc.Size {
+		w.respC <- uploadResponse{location: u}
+		return w
+	}
+
+	pr, pw := io.Pipe()
+	w.pw = pw
+	go func() {
+		header := http.Header{
+			"Content-Type":  {"application/octet-stream"},
+			"Content-Range": {strconv.FormatInt(offset, 10) + "-" + strconv.FormatInt(desc.Size-1, 10)},
+		}
+		resp, err := p.do(ctx, http.MethodPatch, u.String(), pr, desc.Size-offset, header)
+		if err == nil {
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusNoContent {
+				if u, err = p.location(resp); err == nil {
+					p.setSession(desc.Digest, u)
+				}
+			} else {
+				err = remoteserrors.NewUnexpectedStatusErr(resp)
+			}
+		}
+		if err != nil {
+			pr.CloseWithError(err)
+		}
+		w.respC <- uploadResponse{location: u, err: err}
+	}()
+	return w
+}
+
+func (p *resumablePusher) do(ctx context.Context, method, u string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	client := p.host.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	for retried := false; ; retried = true {
+		req, err := http.NewRequest(method, u, body)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		for k, v := range p.host.Header {
+			req.Header[k] = append(req.Header[k], v...)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if body != nil {
+			req.ContentLength = size
+		}
+		// like containerd, don't send credentials to an upload location on
+		// another host
+		authorize := p.host.Authorizer != nil && req.URL.Host == p.host.Host && req.URL.Scheme == p.host.Scheme
+		if authorize {
+			if err := p.host.Authorizer.Authorize(ctx, req); err != nil {
+				return nil, errors.Wrap(err, "failed to authorize")
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && authorize && body == nil && !retried {
+			if err := p.host.Authorizer.AddResponses(ctx, []*http.Response{resp}); err == nil {
+				resp.Body.Close()
+				continue
+			}
+		}
+		return resp, nil
+	}
+}
+
+func (p *resumablePusher) url(ps ...string) string {
+	return p.host.Scheme + "://" + p.host.Host + strings.TrimSuffix(p.host.Path, "/") + "/" + p.repo + "/" + strings.Join(ps, "/")
+}
+
+func (p *resumablePusher) location(resp *http.Response) (*url.URL, error) {
+	loc := resp.Header.Get("Location")
+	if loc == "" {
+		return nil, errors.Errorf("missing upload location in response from %s", resp.Request.URL)
+	}
+	u, err := resp.Request.URL.Parse(loc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse location %v", loc)
+	}
+	return u, nil
+}
+
+func (p *resumablePusher) session(dgst digest.Digest) *url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sessions[dgst]
+}
+
+func (p *resumablePusher) setSession(dgst digest.Digest, u *url.URL) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if u == nil {
+		delete(p.sessions, dgst)
+		return
+	}
+	p.sessions[dgst] = u
+}
+
+// mountCandidate returns the repository of the same registry the blob can be
+// mounted from.
+func mountCandidate(refspec reference.Spec, desc ocispecs.Descriptor) string {
+	domain := refspec.Hostname()
+	repo := strings.TrimPrefix(refspec.Locator, domain+"/")
+	for _, from := range strings.Split(desc.Annotations[distributionSourcePrefix+domain], ",") {
+		if from != "" && from != repo {
+			return from
+		}
+	}
+	return ""
+}
+
+type uploadResponse struct {
+	location *url.URL
+	err      error
+}
+
+// uploadWriter streams a blob to an upload session in a single PATCH request
+// and closes the session with the digest on commit. Its status starts at the
+// offset the upload was resumed from, so that content.Copy skips the bytes
+// the registry already has.
+type uploadWriter struct {
+	p      *resumablePusher
+	desc   ocispecs.Descriptor
+	offset int64
+	pw     *io.PipeWriter
+	respC  chan uploadResponse
+
+	written int64
+}
+
+func (w *uploadWriter) Write(dt []byte) (int, error) {
+	if w.pw == nil {
+		return 0, errors.Errorf("upload of %s is already complete", w.desc.Digest)
+	}
+	n, err := w.pw.Write(dt)
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *uploadWriter) Status() (content.Status, error) {
+	return content.Status{
+		Ref:      w.desc.Digest.String(),
+		Offset:   w.offset + w.written,
+		Total:    w.desc.Size,
+		Expected: w.desc.Digest,
+	}, nil
+}
+
+func (w *uploadWriter) Digest() digest.Digest {
+	return w.desc.Digest
+}
+
+func (w *uploadWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	if w.pw != nil {
+		w.pw.Close()
+	}
+	r := <-w.respC
+	if r.err != nil {
+		return r.err
+	}
+	if expected == "" {
+		expected = w.desc.Digest
+	}
+	u := *r.location
+	q := u.Query()
+	q.Set("digest", expected.String())
+	u.RawQuery = q.Encode()
+
+	ctx, err := docker.ContextWithRepositoryScope(ctx, w.p.refspec, true)
+	if err != nil {
+		return err
+	}
+	resp, err := w.p.do(ctx, http.MethodPut, u.String(), nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusNoContent, http.StatusOK:
+	default:
+		return remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+	w.p.setSession(w.desc.Digest, nil)
+	return nil
+}
+
+func (w *uploadWriter) Close() error {
+	if w.pw != nil {
+		w.pw.CloseWithError(errors.New("upload closed before commit"))
+	}
+	return nil
+}
+
+func (w *uploadWriter) Truncate(size int64) error {
+	if size != w.offset+w.written {
+		return errors.New("cannot truncate remote upload")
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/resolver/retryhandler"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumablePusher(t *testing.T) {
+	t.Parallel()
+
+	dt := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	desc := ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageLayerGzip,
+		Digest:    digest.FromBytes(dt),
+		Size:      int64(len(dt)),
+	}
+
+	reg := &testRegistry{failAfter: len(dt) / 4, uploads: map[string][]byte{}}
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	hosts := func(string) ([]docker.RegistryHost, error) {
+		return []docker.RegistryHost{{
+			Client:       srv.Client(),
+			Host:         u.Host,
+			Scheme:       "http",
+			Path:         "/v2",
+			Capabilities: docker.HostCapabilityPush | docker.HostCapabilityResolve,
+		}}, nil
+	}
+	p, err := newResumablePusher(nil, hosts, u.Host+"/test/repo")
+	require.NoError(t, err)
+
+	buf := contentutil.NewBuffer()
+	require.NoError(t, content.WriteBlob(context.TODO(), buf, "layer", bytes.NewReader(dt), desc))
+
+	h := retryhandler.NewWithOpts(remotes.PushHandler(p, buf), nil, retryhandler.Opts{MaxRetries: 1, InitialBackoff: time.Millisecond})
+	_, err = h(context.TODO(), desc)
+	require.NoError(t, err)
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	require.Equal(t, dt, reg.blobs[desc.Digest])
+	require.Equal(t, []int{0, len(dt) / 4}, reg.patchOffsets)
+}
+
+// testRegistry implements the blob upload API of a registry. The first PATCH
+// request fails after failAfter bytes were received.
+type testRegistry struct {
+	failAfter int
+
+	mu           sync.Mutex
+	next         int
+	uploads      map[string][]byte
+	blobs        map[digest.Digest][]byte
+	patchOffsets []int
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	const prefix = "/v2/test/repo/blobs/"
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	p := strings.TrimPrefix(req.URL.Path, prefix)
+
+	switch {
+	case req.Method == http.MethodHead:
+		if _, ok := r.blobs[digest.Digest(p)]; ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case req.Method == http.MethodPost && p == "uploads/":
+		id := strconv.Itoa(r.next)
+		r.next++
+		r.uploads[id] = nil
+		w.Header().Set("Location", "/v2/test/repo/blobs/uploads/"+id)
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasPrefix(p, "uploads/"):
+		id := strings.TrimPrefix(p, "uploads/")
+		data, ok := r.uploads[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch req.Method {
+		case http.MethodGet:
+			if len(data) > 0 {
+				w.Header().Set("Range", fmt.Sprintf("0-%d", len(data)-1))
+			}
+			w.Header().Set("Location", req.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPatch:
+			var start, end int
+			if _, err := fmt.Sscanf(req.Header.Get("Content-Range"), "%d-%d", &start, &end); err != nil || start != len(data) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			r.patchOffsets = append(r.patchOffsets, start)
+			rd := io.Reader(req.Body)
+			fail := len(r.patchOffsets) == 1
+			if fail {
+				rd = io.LimitReader(rd, int64(r.failAfter))
+			}
+			dt, err := io.ReadAll(rd)
+			r.uploads[id] = append(data, dt...)
+			if fail || err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Location", req.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPut:
+			dgst := digest.Digest(req.URL.Query().Get("digest"))
+			if digest.FromBytes(data) != dgst {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.blobs == nil {
+				r.blobs = map[digest.Digest][]byte{}
+			}
+			r.blobs[dgst] = data
+			delete(r.uploads, id)
+			w.WriteHeader(http.StatusCreated)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
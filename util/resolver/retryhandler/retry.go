@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"syscall"
 	"time"
 
@@ -14,10 +15,35 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Opts control how often and how fast a failed request is retried.
+type Opts struct {
+	// MaxRetries is the number of times a request is retried after the first
+	// attempt failed with a temporary error.
+	MaxRetries int
+	// InitialBackoff is the time to wait before the first retry. It is
+	// doubled for every following retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time to wait between two attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultOpts retries a request three times, waiting 1s, 2s and 4s.
+var DefaultOpts = Opts{
+	MaxRetries:     3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
 func New(f images.HandlerFunc, logger func([]byte)) images.HandlerFunc {
+	return NewWithOpts(f, logger, DefaultOpts)
+}
+
+// NewWithOpts returns a handler that retries f with backoff on temporary
+// errors.
+func NewWithOpts(f images.HandlerFunc, logger func([]byte), opts Opts) images.HandlerFunc {
 	return func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
-		backoff := time.Second
-		for {
+		backoff := opts.InitialBackoff
+		for retries := 0; ; retries++ {
 			descs, err := f(ctx, desc)
 			if err != nil {
 				select {
@@ -35,28 +61,35 @@ func New(f images.HandlerFunc, logger func([]byte)) images.HandlerFunc {
 				return descs, nil
 			}
 			// backoff logic
-			if backoff >= 8*time.Second {
+			if retries >= opts.MaxRetries {
 				return nil, err
 			}
+			if opts.MaxBackoff > 0 && backoff > opts.MaxBackoff {
+				backoff = opts.MaxBackoff
+			}
 			if logger != nil {
 				logger([]byte(fmt.Sprintf("retrying in %v\n", backoff)))
 			}
-			time.Sleep(backoff)
+			select {
+			case <-ctx.Done():
+				return nil, err
+			case <-time.After(backoff):
+			}
 			backoff *= 2
 		}
 	}
 }
 
 func retryError(err error) bool {
-	// Retry on 5xx errors
+	// Retry on 5xx errors and when rate limited
 	var errUnexpectedStatus remoteserrors.ErrUnexpectedStatus
 	if errors.As(err, &errUnexpectedStatus) &&
-		errUnexpectedStatus.StatusCode >= 500 &&
-		errUnexpectedStatus.StatusCode <= 599 {
+		(errUnexpectedStatus.StatusCode >= 500 && errUnexpectedStatus.StatusCode <= 599 ||
+			errUnexpectedStatus.StatusCode == http.StatusTooManyRequests) {
 		return true
 	}
 
-	if errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) {
 		return true
 	}
 	// catches TLS timeout or other network-related temporary errors
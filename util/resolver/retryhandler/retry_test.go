@@ -0,0 +1,65 @@
+package retryhandler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	opts := Opts{MaxRetries: 2, InitialBackoff: time.Millisecond}
+
+	var calls int
+	h := NewWithOpts(func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.Wrap(io.ErrUnexpectedEOF, "upload failed")
+		}
+		return []ocispecs.Descriptor{desc}, nil
+	}, nil, opts)
+	descs, err := h(context.TODO(), ocispecs.Descriptor{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, 3, calls)
+
+	calls = 0
+	var logs []string
+	h = NewWithOpts(func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
+		calls++
+		return nil, remoteserrors.ErrUnexpectedStatus{StatusCode: http.StatusTooManyRequests}
+	}, func(dt []byte) {
+		logs = append(logs, string(dt))
+	}, opts)
+	_, err = h(context.TODO(), ocispecs.Descriptor{})
+	require.Error(t, err)
+	require.Equal(t, 3, calls)
+	require.Equal(t, "retrying in 1ms\n", logs[1])
+	require.Equal(t, "retrying in 2ms\n", logs[3])
+
+	calls = 0
+	h = NewWithOpts(func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
+		calls++
+		return nil, remoteserrors.ErrUnexpectedStatus{StatusCode: http.StatusForbidden}
+	}, nil, opts)
+	_, err = h(context.TODO(), ocispecs.Descriptor{})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+
+	calls = 0
+	h = NewWithOpts(func(ctx context.Context, desc ocispecs.Descriptor) ([]ocispecs.Descriptor, error) {
+		calls++
+		return nil, io.EOF
+	}, nil, Opts{})
+	_, err = h(context.TODO(), ocispecs.Descriptor{})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+}
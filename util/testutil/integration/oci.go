@@ -27,18 +27,27 @@ func InitOCIWorker() {
 	if s := os.Getenv("BUILDKIT_INTEGRATION_SNAPSHOTTER"); s != "" {
 		Register(&oci{snapshotter: s})
 	}
+
+	// the user with subuid/subgid ranges is defined in Dockerfile
+	if s := os.Getenv("BUILDKIT_INTEGRATION_USERNS_REMAP"); s != "" {
+		Register(&oci{usernsRemap: s})
+	}
 }
 
 type oci struct {
 	uid         int
 	gid         int
 	snapshotter string
+	usernsRemap string
 }
 
 func (s *oci) Name() string {
 	if s.uid != 0 {
 		return "oci-rootless"
 	}
+	if s.usernsRemap != "" {
+		return "oci-userns-remap"
+	}
 	if s.snapshotter != "" {
 		return fmt.Sprintf("oci-snapshotter-%s", s.snapshotter)
 	}
@@ -60,6 +69,11 @@ func (s *oci) New(ctx context.Context, cfg *BackendConfig) (Backend, func() erro
 			fmt.Sprintf("--oci-worker-snapshotter=%s", s.snapshotter))
 	}
 
+	if s.usernsRemap != "" {
+		buildkitdArgs = append(buildkitdArgs,
+			fmt.Sprintf("--oci-worker-userns-remap=%s", s.usernsRemap))
+	}
+
 	if s.uid != 0 {
 		if s.gid == 0 {
 			return nil, nil, errors.Errorf("unsupported id pair: uid=%d, gid=%d", s.uid, s.gid)
@@ -0,0 +1,190 @@
+package usernslayers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/containerd/containerd/archive"
+	"github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/diff"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/mount"
+	"github.com/docker/docker/pkg/idtools"
+	digest "github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	log "github.com/moby/buildkit/util/bklog"
+)
+
+const uncompressed = "containerd.io/uncompressed"
+
+var emptyDesc = ocispecs.Descriptor{}
+
+// NewWalkingDiffWithIdentityMapping returns a differ that writes layers with
+// the ownership of the files mapped back from the host IDs of idmap to the
+// IDs inside the user namespace. If idmap is nil, d is returned.
+func NewWalkingDiffWithIdentityMapping(store content.Store, d diff.Comparer, idmap *idtools.IdentityMapping) diff.Comparer {
+	if idmap == nil {
+		return d
+	}
+	return &usernsDiffer{store: store, idmap: idmap}
+}
+
+type usernsDiffer struct {
+	store content.Store
+	idmap *idtools.IdentityMapping
+}
+
+// Compare creates a diff between the given mounts and uploads the result
+// to the content store.
+func (s *usernsDiffer) Compare(ctx context.Context, lower, upper []mount.Mount, opts ...diff.Opt) (d ocispecs.Descriptor, err error) {
+	var config diff.Config
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return emptyDesc, err
+		}
+	}
+
+	var isCompressed bool
+	if config.Compressor != nil {
+		if config.MediaType == "" {
+			return emptyDesc, errors.New("media type must be explicitly specified when using custom compressor")
+		}
+		isCompressed = true
+	} else {
+		if config.MediaType == "" {
+			config.MediaType = ocispecs.MediaTypeImageLayerGzip
+		}
+		switch config.MediaType {
+		case ocispecs.MediaTypeImageLayer:
+		case ocispecs.MediaTypeImageLayerGzip:
+			isCompressed = true
+		default:
+			return emptyDesc, errors.Wrapf(errdefs.ErrNotImplemented, "unsupported diff media type: %v", config.MediaType)
+		}
+	}
+
+	var ocidesc ocispecs.Descriptor
+	if err := mount.WithTempMount(ctx, lower, func(lowerRoot string) error {
+		return mount.WithTempMount(ctx, upper, func(upperRoot string) (err error) {
+			var newReference bool
+			if config.Reference == "" {
+				newReference = true
+				config.Reference = uniqueRef()
+			}
+
+			cw, err := s.store.Writer(ctx,
+				content.WithRef(config.Reference),
+				content.WithDescriptor(ocispecs.Descriptor{
+					MediaType: config.MediaType, // most contentstore implementations just ignore this
+				}))
+			if err != nil {
+				return errors.Wrap(err, "failed to open writer")
+			}
+			committed := false
+			defer func() {
+				if err != nil && !committed {
+					cw.Close()
+					if newReference {
+						if err := s.store.Abort(ctx, config.Reference); err != nil {
+							log.G(ctx).WithField("ref", config.Reference).Warnf("failed to delete diff upload")
+						}
+					}
+				}
+			}()
+			if !newReference {
+				if err := cw.Truncate(0); err != nil {
+					return err
+				}
+			}
+
+			if isCompressed {
+				dgstr := digest.SHA256.Digester()
+				var compressed io.WriteCloser
+				if config.Compressor != nil {
+					compressed, err = config.Compressor(cw, config.MediaType)
+				} else {
+					compressed, err = compression.CompressStream(cw, compression.Gzip)
+				}
+				if err != nil {
+					return errors.Wrap(err, "failed to get compressed stream")
+				}
+				err = s.writeDiff(ctx, io.MultiWriter(compressed, dgstr.Hash()), lowerRoot, upperRoot)
+				compressed.Close()
+				if err != nil {
+					return errors.Wrap(err, "failed to write compressed diff")
+				}
+				if config.Labels == nil {
+					config.Labels = map[string]string{}
+				}
+				config.Labels[uncompressed] = dgstr.Digest().String()
+			} else {
+				if err := s.writeDiff(ctx, cw, lowerRoot, upperRoot); err != nil {
+					return errors.Wrap(err, "failed to write diff")
+				}
+			}
+
+			var commitopts []content.Opt
+			if config.Labels != nil {
+				commitopts = append(commitopts, content.WithLabels(config.Labels))
+			}
+
+			dgst := cw.Digest()
+			if err := cw.Commit(ctx, 0, dgst, commitopts...); err != nil {
+				if !errdefs.IsAlreadyExists(err) {
+					return errors.Wrap(err, "failed to commit")
+				}
+			}
+			committed = true
+
+			info, err := s.store.Info(ctx, dgst)
+			if err != nil {
+				return errors.Wrap(err, "failed to get info from content store")
+			}
+			if info.Labels == nil {
+				info.Labels = make(map[string]string)
+			}
+			// Set uncompressed label if digest already existed without label
+			if _, ok := info.Labels[uncompressed]; !ok && config.Labels[uncompressed] != "" {
+				info.Labels[uncompressed] = config.Labels[uncompressed]
+				if _, err := s.store.Update(ctx, info, "labels."+uncompressed); err != nil {
+					return errors.Wrap(err, "error setting uncompressed label")
+				}
+			}
+
+			ocidesc = ocispecs.Descriptor{
+				MediaType: config.MediaType,
+				Size:      info.Size,
+				Digest:    info.Digest,
+			}
+			return nil
+		})
+	}); err != nil {
+		return emptyDesc, err
+	}
+
+	return ocidesc, nil
+}
+
+func (s *usernsDiffer) writeDiff(ctx context.Context, w io.Writer, lowerRoot, upperRoot string) error {
+	tw := NewWriter(w, s.idmap)
+	if err := archive.WriteDiff(ctx, tw, lowerRoot, upperRoot); err != nil {
+		tw.Close()
+		return err
+	}
+	return tw.Close()
+}
+
+func uniqueRef() string {
+	t := time.Now()
+	var b [3]byte
+	// Ignore read failures, just decreases uniqueness
+	rand.Read(b[:])
+	return fmt.Sprintf("%d-%s", t.UnixNano(), base64.URLEncoding.EncodeToString(b[:]))
+}
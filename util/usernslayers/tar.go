@@ -0,0 +1,92 @@
+package usernslayers
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+// Writer rewrites the ownership of the entries of a layer tar stream from the
+// remapped host IDs to the IDs inside the user namespace.
+type Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+// NewWriter returns a Writer that writes the rewritten tar stream to w.
+// Close must be called to flush the stream.
+func NewWriter(w io.Writer, idmap *idtools.IdentityMapping) *Writer {
+	pr, pw := io.Pipe()
+	tw := &Writer{pw: pw, done: make(chan error, 1)}
+	go func() {
+		err := rewrite(w, pr, func(h *tar.Header) error {
+			uid, gid, err := idmap.ToContainer(idtools.Identity{UID: h.Uid, GID: h.Gid})
+			if err != nil {
+				return errors.Wrapf(err, "failed to map ownership of %s", h.Name)
+			}
+			h.Uid, h.Gid = uid, gid
+			return nil
+		})
+		if err != nil {
+			pr.CloseWithError(err)
+		} else {
+			// consume the padding after the tar footer so the producer doesn't block
+			_, err = io.Copy(ioutil.Discard, pr)
+		}
+		tw.done <- err
+	}()
+	return tw
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+// Close finishes the stream and returns the first error that occurred while
+// rewriting it.
+func (w *Writer) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
+
+func rewrite(w io.Writer, r io.Reader, f func(*tar.Header) error) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := f(h); err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return errors.WithStack(err)
+		}
+		if h.Size > 0 {
+			if _, err := io.Copy(tw, tr); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return errors.WithStack(tw.Close())
+}
+
+// toHostFilter returns an archive filter that maps the ownership of the
+// entries of a layer to the remapped host IDs.
+func toHostFilter(idmap *idtools.IdentityMapping) func(*tar.Header) (bool, error) {
+	return func(h *tar.Header) (bool, error) {
+		id, err := idmap.ToHost(idtools.Identity{UID: h.Uid, GID: h.Gid})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to map ownership of %s", h.Name)
+		}
+		h.Uid, h.Gid = id.UID, id.GID
+		return true, nil
+	}
+}
@@ -0,0 +1,88 @@
+package usernslayers
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterMapsOwnership(t *testing.T) {
+	t.Parallel()
+
+	idmap := idtools.NewIDMappingsFromMaps(
+		[]idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		[]idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 65536}},
+	)
+
+	var in bytes.Buffer
+	tw := tar.NewWriter(&in)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755, Uid: 100000, Gid: 200000}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dir/file", Typeflag: tar.TypeReg, Mode: 0644, Uid: 101000, Gid: 201001, Size: 4}))
+	_, err := tw.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	var out bytes.Buffer
+	w := NewWriter(&out, idmap)
+	_, err = io.Copy(w, &in)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	tr := tar.NewReader(&out)
+	h, err := tr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "dir/", h.Name)
+	require.Equal(t, 0, h.Uid)
+	require.Equal(t, 0, h.Gid)
+
+	h, err = tr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "dir/file", h.Name)
+	require.Equal(t, 1000, h.Uid)
+	require.Equal(t, 1001, h.Gid)
+	dt, err := ioutil.ReadAll(tr)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(dt))
+
+	_, err = tr.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestWriterUnmappedOwnership(t *testing.T) {
+	t.Parallel()
+
+	idmap := idtools.NewIDMappingsFromMaps(
+		[]idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		[]idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+	)
+
+	var in bytes.Buffer
+	tw := tar.NewWriter(&in)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "file", Typeflag: tar.TypeReg, Mode: 0644}))
+	require.NoError(t, tw.Close())
+
+	w := NewWriter(ioutil.Discard, idmap)
+	io.Copy(w, &in)
+	require.Error(t, w.Close())
+}
+
+func TestToHostFilter(t *testing.T) {
+	t.Parallel()
+
+	idmap := idtools.NewIDMappingsFromMaps(
+		[]idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		[]idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 65536}},
+	)
+
+	h := &tar.Header{Name: "file", Uid: 1000, Gid: 1001}
+	ok, err := toHostFilter(idmap)(h)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 101000, h.Uid)
+	require.Equal(t, 201001, h.Gid)
+}
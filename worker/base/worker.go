@@ -42,13 +42,13 @@ import (
 	"github.com/moby/buildkit/source/local"
 	"github.com/moby/buildkit/util/archutil"
 	"github.com/moby/buildkit/util/bklog"
+	"github.com/moby/buildkit/util/fairqueue"
 	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/progress/controller"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
-	"golang.org/x/sync/semaphore"
 )
 
 const labelCreatedAt = "buildkit/createdat"
@@ -72,8 +72,11 @@ type WorkerOpt struct {
 	IdentityMapping *idtools.IdentityMapping
 	LeaseManager    leases.Manager
 	GarbageCollect  func(context.Context) (gc.Stats, error)
-	ParallelismSem  *semaphore.Weighted
+	ParallelismSem  *fairqueue.Queue
 	MetadataStore   *metadata.Store
+	// RemoteSnapshotter is set if the snapshotter can lazily mount image
+	// layers from the registry.
+	RemoteSnapshotter bool
 }
 
 // Worker is a local worker instance with dedicated snapshotter, cache, and so on.
@@ -94,14 +97,15 @@ func NewWorker(ctx context.Context, opt WorkerOpt) (*Worker, error) {
 	})
 
 	cm, err := cache.NewManager(cache.ManagerOpt{
-		Snapshotter:     opt.Snapshotter,
-		PruneRefChecker: imageRefChecker,
-		Applier:         opt.Applier,
-		GarbageCollect:  opt.GarbageCollect,
-		LeaseManager:    opt.LeaseManager,
-		ContentStore:    opt.ContentStore,
-		Differ:          opt.Differ,
-		MetadataStore:   opt.MetadataStore,
+		Snapshotter:       opt.Snapshotter,
+		PruneRefChecker:   imageRefChecker,
+		Applier:           opt.Applier,
+		GarbageCollect:    opt.GarbageCollect,
+		LeaseManager:      opt.LeaseManager,
+		ContentStore:      opt.ContentStore,
+		Differ:            opt.Differ,
+		MetadataStore:     opt.MetadataStore,
+		RemoteSnapshotter: opt.RemoteSnapshotter,
 	})
 	if err != nil {
 		return nil, err
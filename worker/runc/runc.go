@@ -15,15 +15,18 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/executor/oci"
+	"github.com/moby/buildkit/executor/resources"
 	"github.com/moby/buildkit/executor/runcexecutor"
 	containerdsnapshot "github.com/moby/buildkit/snapshot/containerd"
+	"github.com/moby/buildkit/util/cdidevices"
+	"github.com/moby/buildkit/util/fairqueue"
 	"github.com/moby/buildkit/util/leaseutil"
 	"github.com/moby/buildkit/util/network/netproviders"
+	"github.com/moby/buildkit/util/usernslayers"
 	"github.com/moby/buildkit/util/winlayers"
 	"github.com/moby/buildkit/worker/base"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	bolt "go.etcd.io/bbolt"
-	"golang.org/x/sync/semaphore"
 )
 
 // SnapshotterFactory instantiates a snapshotter
@@ -33,13 +36,19 @@ type SnapshotterFactory struct {
 }
 
 // NewWorkerOpt creates a WorkerOpt.
-func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, processMode oci.ProcessMode, labels map[string]string, idmap *idtools.IdentityMapping, nopt netproviders.Opt, dns *oci.DNSConfig, binary, apparmorProfile string, parallelismSem *semaphore.Weighted, traceSocket string) (base.WorkerOpt, error) {
+func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, processMode oci.ProcessMode, labels map[string]string, idmap *idtools.IdentityMapping, nopt netproviders.Opt, dns *oci.DNSConfig, binary, apparmorProfile string, parallelismSem *fairqueue.Queue, traceSocket string, cdiManager *cdidevices.Manager) (base.WorkerOpt, error) {
 	var opt base.WorkerOpt
 	name := "runc-" + snFactory.Name
 	root = filepath.Join(root, name)
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return opt, err
 	}
+	if idmap != nil {
+		// the remapped root user needs to traverse to the container rootfs
+		if err := os.Chmod(root, 0711); err != nil {
+			return opt, err
+		}
+	}
 
 	np, err := netproviders.Providers(nopt)
 	if err != nil {
@@ -65,6 +74,8 @@ func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, proc
 		DNS:             dns,
 		ApparmorProfile: apparmorProfile,
 		TracingSocket:   traceSocket,
+		ResourceMonitor: resources.NewMonitor(),
+		CDIManager:      cdiManager,
 	}, np)
 	if err != nil {
 		return opt, err
@@ -127,8 +138,8 @@ func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, proc
 		Executor:        exe,
 		Snapshotter:     snap,
 		ContentStore:    c,
-		Applier:         winlayers.NewFileSystemApplierWithWindows(c, apply.NewFileSystemApplier(c)),
-		Differ:          winlayers.NewWalkingDiffWithWindows(c, walking.NewWalkingDiff(c)),
+		Applier:         winlayers.NewFileSystemApplierWithWindows(c, usernslayers.NewFileSystemApplierWithIdentityMapping(c, apply.NewFileSystemApplier(c), idmap)),
+		Differ:          winlayers.NewWalkingDiffWithWindows(c, usernslayers.NewWalkingDiffWithIdentityMapping(c, walking.NewWalkingDiff(c), idmap)),
 		ImageStore:      nil, // explicitly
 		Platforms:       []ocispecs.Platform{platforms.Normalize(platforms.DefaultSpec())},
 		IdentityMapping: idmap,